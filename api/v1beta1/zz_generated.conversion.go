@@ -26,6 +26,36 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AffinityApplyConfiguration)(nil), (*v1beta2.AffinityApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__AffinityApplyConfiguration_To_v1beta2_AffinityApplyConfiguration(a.(*AffinityApplyConfiguration), b.(*v1beta2.AffinityApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.AffinityApplyConfiguration)(nil), (*AffinityApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AffinityApplyConfiguration_To__AffinityApplyConfiguration(a.(*v1beta2.AffinityApplyConfiguration), b.(*AffinityApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BackupHook)(nil), (*v1beta2.BackupHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__BackupHook_To_v1beta2_BackupHook(a.(*BackupHook), b.(*v1beta2.BackupHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.BackupHook)(nil), (*BackupHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_BackupHook_To__BackupHook(a.(*v1beta2.BackupHook), b.(*BackupHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BackupHooks)(nil), (*v1beta2.BackupHooks)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__BackupHooks_To_v1beta2_BackupHooks(a.(*BackupHooks), b.(*v1beta2.BackupHooks), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.BackupHooks)(nil), (*BackupHooks)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_BackupHooks_To__BackupHooks(a.(*v1beta2.BackupHooks), b.(*BackupHooks), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*BackupPolicy)(nil), (*v1beta2.BackupPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__BackupPolicy_To_v1beta2_BackupPolicy(a.(*BackupPolicy), b.(*v1beta2.BackupPolicy), scope)
 	}); err != nil {
@@ -56,6 +86,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*BackupSourcePolicy)(nil), (*v1beta2.BackupSourcePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__BackupSourcePolicy_To_v1beta2_BackupSourcePolicy(a.(*BackupSourcePolicy), b.(*v1beta2.BackupSourcePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.BackupSourcePolicy)(nil), (*BackupSourcePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_BackupSourcePolicy_To__BackupSourcePolicy(a.(*v1beta2.BackupSourcePolicy), b.(*BackupSourcePolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*BackupStatus)(nil), (*v1beta2.BackupStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__BackupStatus_To_v1beta2_BackupStatus(a.(*BackupStatus), b.(*v1beta2.BackupStatus), scope)
 	}); err != nil {
@@ -66,6 +106,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*BinlogArchiveSpec)(nil), (*v1beta2.BinlogArchiveSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__BinlogArchiveSpec_To_v1beta2_BinlogArchiveSpec(a.(*BinlogArchiveSpec), b.(*v1beta2.BinlogArchiveSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.BinlogArchiveSpec)(nil), (*BinlogArchiveSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_BinlogArchiveSpec_To__BinlogArchiveSpec(a.(*v1beta2.BinlogArchiveSpec), b.(*BinlogArchiveSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*BucketConfig)(nil), (*v1beta2.BucketConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__BucketConfig_To_v1beta2_BucketConfig(a.(*BucketConfig), b.(*v1beta2.BucketConfig), scope)
 	}); err != nil {
@@ -76,6 +126,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*BucketTLSConfig)(nil), (*v1beta2.BucketTLSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__BucketTLSConfig_To_v1beta2_BucketTLSConfig(a.(*BucketTLSConfig), b.(*v1beta2.BucketTLSConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.BucketTLSConfig)(nil), (*BucketTLSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_BucketTLSConfig_To__BucketTLSConfig(a.(*v1beta2.BucketTLSConfig), b.(*BucketTLSConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CABundleSource)(nil), (*v1beta2.CABundleSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__CABundleSource_To_v1beta2_CABundleSource(a.(*CABundleSource), b.(*v1beta2.CABundleSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.CABundleSource)(nil), (*CABundleSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_CABundleSource_To__CABundleSource(a.(*v1beta2.CABundleSource), b.(*CABundleSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CompressionConfig)(nil), (*v1beta2.CompressionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__CompressionConfig_To_v1beta2_CompressionConfig(a.(*CompressionConfig), b.(*v1beta2.CompressionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.CompressionConfig)(nil), (*CompressionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_CompressionConfig_To__CompressionConfig(a.(*v1beta2.CompressionConfig), b.(*CompressionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EncryptionConfig)(nil), (*v1beta2.EncryptionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__EncryptionConfig_To_v1beta2_EncryptionConfig(a.(*EncryptionConfig), b.(*v1beta2.EncryptionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.EncryptionConfig)(nil), (*EncryptionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_EncryptionConfig_To__EncryptionConfig(a.(*v1beta2.EncryptionConfig), b.(*EncryptionConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EnvFromSourceApplyConfiguration)(nil), (*v1beta2.EnvFromSourceApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__EnvFromSourceApplyConfiguration_To_v1beta2_EnvFromSourceApplyConfiguration(a.(*EnvFromSourceApplyConfiguration), b.(*v1beta2.EnvFromSourceApplyConfiguration), scope)
 	}); err != nil {
@@ -96,6 +186,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*FilterConfig)(nil), (*v1beta2.FilterConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__FilterConfig_To_v1beta2_FilterConfig(a.(*FilterConfig), b.(*v1beta2.FilterConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.FilterConfig)(nil), (*FilterConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_FilterConfig_To__FilterConfig(a.(*v1beta2.FilterConfig), b.(*FilterConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HTTPHeader)(nil), (*v1beta2.HTTPHeader)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__HTTPHeader_To_v1beta2_HTTPHeader(a.(*HTTPHeader), b.(*v1beta2.HTTPHeader), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.HTTPHeader)(nil), (*HTTPHeader)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_HTTPHeader_To__HTTPHeader(a.(*v1beta2.HTTPHeader), b.(*HTTPHeader), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HTTPHeaderSource)(nil), (*v1beta2.HTTPHeaderSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__HTTPHeaderSource_To_v1beta2_HTTPHeaderSource(a.(*HTTPHeaderSource), b.(*v1beta2.HTTPHeaderSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.HTTPHeaderSource)(nil), (*HTTPHeaderSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_HTTPHeaderSource_To__HTTPHeaderSource(a.(*v1beta2.HTTPHeaderSource), b.(*HTTPHeaderSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HTTPHook)(nil), (*v1beta2.HTTPHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__HTTPHook_To_v1beta2_HTTPHook(a.(*HTTPHook), b.(*v1beta2.HTTPHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.HTTPHook)(nil), (*HTTPHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_HTTPHook_To__HTTPHook(a.(*v1beta2.HTTPHook), b.(*HTTPHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*JobConfig)(nil), (*v1beta2.JobConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__JobConfig_To_v1beta2_JobConfig(a.(*JobConfig), b.(*v1beta2.JobConfig), scope)
 	}); err != nil {
@@ -106,6 +236,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*JobPodTemplate)(nil), (*v1beta2.JobPodTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__JobPodTemplate_To_v1beta2_JobPodTemplate(a.(*JobPodTemplate), b.(*v1beta2.JobPodTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.JobPodTemplate)(nil), (*JobPodTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_JobPodTemplate_To__JobPodTemplate(a.(*v1beta2.JobPodTemplate), b.(*JobPodTemplate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MySQLClusterCondition)(nil), (*v1beta2.MySQLClusterCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__MySQLClusterCondition_To_v1beta2_MySQLClusterCondition(a.(*MySQLClusterCondition), b.(*v1beta2.MySQLClusterCondition), scope)
 	}); err != nil {
@@ -136,6 +276,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ObjectLockConfig)(nil), (*v1beta2.ObjectLockConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__ObjectLockConfig_To_v1beta2_ObjectLockConfig(a.(*ObjectLockConfig), b.(*v1beta2.ObjectLockConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.ObjectLockConfig)(nil), (*ObjectLockConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ObjectLockConfig_To__ObjectLockConfig(a.(*v1beta2.ObjectLockConfig), b.(*ObjectLockConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ObjectMeta)(nil), (*v1beta2.ObjectMeta)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__ObjectMeta_To_v1beta2_ObjectMeta(a.(*ObjectMeta), b.(*v1beta2.ObjectMeta), scope)
 	}); err != nil {
@@ -166,6 +316,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PodSecurityContextApplyConfiguration)(nil), (*v1beta2.PodSecurityContextApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__PodSecurityContextApplyConfiguration_To_v1beta2_PodSecurityContextApplyConfiguration(a.(*PodSecurityContextApplyConfiguration), b.(*v1beta2.PodSecurityContextApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.PodSecurityContextApplyConfiguration)(nil), (*PodSecurityContextApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PodSecurityContextApplyConfiguration_To__PodSecurityContextApplyConfiguration(a.(*v1beta2.PodSecurityContextApplyConfiguration), b.(*PodSecurityContextApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PodSpecApplyConfiguration)(nil), (*v1beta2.PodSpecApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__PodSpecApplyConfiguration_To_v1beta2_PodSpecApplyConfiguration(a.(*PodSpecApplyConfiguration), b.(*v1beta2.PodSpecApplyConfiguration), scope)
 	}); err != nil {
@@ -191,6 +351,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RestorableGap)(nil), (*v1beta2.RestorableGap)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__RestorableGap_To_v1beta2_RestorableGap(a.(*RestorableGap), b.(*v1beta2.RestorableGap), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.RestorableGap)(nil), (*RestorableGap)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_RestorableGap_To__RestorableGap(a.(*v1beta2.RestorableGap), b.(*RestorableGap), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RestoreDrillAssertion)(nil), (*v1beta2.RestoreDrillAssertion)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__RestoreDrillAssertion_To_v1beta2_RestoreDrillAssertion(a.(*RestoreDrillAssertion), b.(*v1beta2.RestoreDrillAssertion), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.RestoreDrillAssertion)(nil), (*RestoreDrillAssertion)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_RestoreDrillAssertion_To__RestoreDrillAssertion(a.(*v1beta2.RestoreDrillAssertion), b.(*RestoreDrillAssertion), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RestoreDrillRequest)(nil), (*v1beta2.RestoreDrillRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__RestoreDrillRequest_To_v1beta2_RestoreDrillRequest(a.(*RestoreDrillRequest), b.(*v1beta2.RestoreDrillRequest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.RestoreDrillRequest)(nil), (*RestoreDrillRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_RestoreDrillRequest_To__RestoreDrillRequest(a.(*v1beta2.RestoreDrillRequest), b.(*RestoreDrillRequest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RestoreDrillSpec)(nil), (*v1beta2.RestoreDrillSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__RestoreDrillSpec_To_v1beta2_RestoreDrillSpec(a.(*RestoreDrillSpec), b.(*v1beta2.RestoreDrillSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.RestoreDrillSpec)(nil), (*RestoreDrillSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_RestoreDrillSpec_To__RestoreDrillSpec(a.(*v1beta2.RestoreDrillSpec), b.(*RestoreDrillSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RestoreDrillStatus)(nil), (*v1beta2.RestoreDrillStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__RestoreDrillStatus_To_v1beta2_RestoreDrillStatus(a.(*RestoreDrillStatus), b.(*v1beta2.RestoreDrillStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.RestoreDrillStatus)(nil), (*RestoreDrillStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_RestoreDrillStatus_To__RestoreDrillStatus(a.(*v1beta2.RestoreDrillStatus), b.(*RestoreDrillStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RestoreSpec)(nil), (*v1beta2.RestoreSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__RestoreSpec_To_v1beta2_RestoreSpec(a.(*RestoreSpec), b.(*v1beta2.RestoreSpec), scope)
 	}); err != nil {
@@ -201,6 +411,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RestoreStatus)(nil), (*v1beta2.RestoreStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__RestoreStatus_To_v1beta2_RestoreStatus(a.(*RestoreStatus), b.(*v1beta2.RestoreStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.RestoreStatus)(nil), (*RestoreStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_RestoreStatus_To__RestoreStatus(a.(*v1beta2.RestoreStatus), b.(*RestoreStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RetentionPolicy)(nil), (*v1beta2.RetentionPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__RetentionPolicy_To_v1beta2_RetentionPolicy(a.(*RetentionPolicy), b.(*v1beta2.RetentionPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.RetentionPolicy)(nil), (*RetentionPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_RetentionPolicy_To__RetentionPolicy(a.(*v1beta2.RetentionPolicy), b.(*RetentionPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SecondaryBucket)(nil), (*v1beta2.SecondaryBucket)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__SecondaryBucket_To_v1beta2_SecondaryBucket(a.(*SecondaryBucket), b.(*v1beta2.SecondaryBucket), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.SecondaryBucket)(nil), (*SecondaryBucket)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SecondaryBucket_To__SecondaryBucket(a.(*v1beta2.SecondaryBucket), b.(*SecondaryBucket), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SecurityContextApplyConfiguration)(nil), (*v1beta2.SecurityContextApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__SecurityContextApplyConfiguration_To_v1beta2_SecurityContextApplyConfiguration(a.(*SecurityContextApplyConfiguration), b.(*v1beta2.SecurityContextApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.SecurityContextApplyConfiguration)(nil), (*SecurityContextApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SecurityContextApplyConfiguration_To__SecurityContextApplyConfiguration(a.(*v1beta2.SecurityContextApplyConfiguration), b.(*SecurityContextApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ServiceSpecApplyConfiguration)(nil), (*v1beta2.ServiceSpecApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__ServiceSpecApplyConfiguration_To_v1beta2_ServiceSpecApplyConfiguration(a.(*ServiceSpecApplyConfiguration), b.(*v1beta2.ServiceSpecApplyConfiguration), scope)
 	}); err != nil {
@@ -221,6 +471,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SnapshotSpec)(nil), (*v1beta2.SnapshotSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__SnapshotSpec_To_v1beta2_SnapshotSpec(a.(*SnapshotSpec), b.(*v1beta2.SnapshotSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.SnapshotSpec)(nil), (*SnapshotSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SnapshotSpec_To__SnapshotSpec(a.(*v1beta2.SnapshotSpec), b.(*SnapshotSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TolerationApplyConfiguration)(nil), (*v1beta2.TolerationApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__TolerationApplyConfiguration_To_v1beta2_TolerationApplyConfiguration(a.(*TolerationApplyConfiguration), b.(*v1beta2.TolerationApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.TolerationApplyConfiguration)(nil), (*TolerationApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_TolerationApplyConfiguration_To__TolerationApplyConfiguration(a.(*v1beta2.TolerationApplyConfiguration), b.(*TolerationApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VolumeApplyConfiguration)(nil), (*v1beta2.VolumeApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__VolumeApplyConfiguration_To_v1beta2_VolumeApplyConfiguration(a.(*VolumeApplyConfiguration), b.(*v1beta2.VolumeApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.VolumeApplyConfiguration)(nil), (*VolumeApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_VolumeApplyConfiguration_To__VolumeApplyConfiguration(a.(*v1beta2.VolumeApplyConfiguration), b.(*VolumeApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VolumeMountApplyConfiguration)(nil), (*v1beta2.VolumeMountApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert__VolumeMountApplyConfiguration_To_v1beta2_VolumeMountApplyConfiguration(a.(*VolumeMountApplyConfiguration), b.(*v1beta2.VolumeMountApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta2.VolumeMountApplyConfiguration)(nil), (*VolumeMountApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_VolumeMountApplyConfiguration_To__VolumeMountApplyConfiguration(a.(*v1beta2.VolumeMountApplyConfiguration), b.(*VolumeMountApplyConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VolumeSourceApplyConfiguration)(nil), (*v1beta2.VolumeSourceApplyConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert__VolumeSourceApplyConfiguration_To_v1beta2_VolumeSourceApplyConfiguration(a.(*VolumeSourceApplyConfiguration), b.(*v1beta2.VolumeSourceApplyConfiguration), scope)
 	}); err != nil {
@@ -259,6 +549,80 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert__AffinityApplyConfiguration_To_v1beta2_AffinityApplyConfiguration(in *AffinityApplyConfiguration, out *v1beta2.AffinityApplyConfiguration, s conversion.Scope) error {
+	out.NodeAffinity = (*v1.NodeAffinityApplyConfiguration)(unsafe.Pointer(in.NodeAffinity))
+	out.PodAffinity = (*v1.PodAffinityApplyConfiguration)(unsafe.Pointer(in.PodAffinity))
+	out.PodAntiAffinity = (*v1.PodAntiAffinityApplyConfiguration)(unsafe.Pointer(in.PodAntiAffinity))
+	return nil
+}
+
+// Convert__AffinityApplyConfiguration_To_v1beta2_AffinityApplyConfiguration is an autogenerated conversion function.
+func Convert__AffinityApplyConfiguration_To_v1beta2_AffinityApplyConfiguration(in *AffinityApplyConfiguration, out *v1beta2.AffinityApplyConfiguration, s conversion.Scope) error {
+	return autoConvert__AffinityApplyConfiguration_To_v1beta2_AffinityApplyConfiguration(in, out, s)
+}
+
+func autoConvert_v1beta2_AffinityApplyConfiguration_To__AffinityApplyConfiguration(in *v1beta2.AffinityApplyConfiguration, out *AffinityApplyConfiguration, s conversion.Scope) error {
+	out.NodeAffinity = (*v1.NodeAffinityApplyConfiguration)(unsafe.Pointer(in.NodeAffinity))
+	out.PodAffinity = (*v1.PodAffinityApplyConfiguration)(unsafe.Pointer(in.PodAffinity))
+	out.PodAntiAffinity = (*v1.PodAntiAffinityApplyConfiguration)(unsafe.Pointer(in.PodAntiAffinity))
+	return nil
+}
+
+// Convert_v1beta2_AffinityApplyConfiguration_To__AffinityApplyConfiguration is an autogenerated conversion function.
+func Convert_v1beta2_AffinityApplyConfiguration_To__AffinityApplyConfiguration(in *v1beta2.AffinityApplyConfiguration, out *AffinityApplyConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta2_AffinityApplyConfiguration_To__AffinityApplyConfiguration(in, out, s)
+}
+
+func autoConvert__BackupHook_To_v1beta2_BackupHook(in *BackupHook, out *v1beta2.BackupHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.SQL = *(*[]string)(unsafe.Pointer(&in.SQL))
+	out.HTTP = (*v1beta2.HTTPHook)(unsafe.Pointer(in.HTTP))
+	out.FailurePolicy = v1beta2.HookFailurePolicy(in.FailurePolicy)
+	out.Timeout = in.Timeout
+	return nil
+}
+
+// Convert__BackupHook_To_v1beta2_BackupHook is an autogenerated conversion function.
+func Convert__BackupHook_To_v1beta2_BackupHook(in *BackupHook, out *v1beta2.BackupHook, s conversion.Scope) error {
+	return autoConvert__BackupHook_To_v1beta2_BackupHook(in, out, s)
+}
+
+func autoConvert_v1beta2_BackupHook_To__BackupHook(in *v1beta2.BackupHook, out *BackupHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.SQL = *(*[]string)(unsafe.Pointer(&in.SQL))
+	out.HTTP = (*HTTPHook)(unsafe.Pointer(in.HTTP))
+	out.FailurePolicy = HookFailurePolicy(in.FailurePolicy)
+	out.Timeout = in.Timeout
+	return nil
+}
+
+// Convert_v1beta2_BackupHook_To__BackupHook is an autogenerated conversion function.
+func Convert_v1beta2_BackupHook_To__BackupHook(in *v1beta2.BackupHook, out *BackupHook, s conversion.Scope) error {
+	return autoConvert_v1beta2_BackupHook_To__BackupHook(in, out, s)
+}
+
+func autoConvert__BackupHooks_To_v1beta2_BackupHooks(in *BackupHooks, out *v1beta2.BackupHooks, s conversion.Scope) error {
+	out.PreBackup = *(*[]v1beta2.BackupHook)(unsafe.Pointer(&in.PreBackup))
+	out.PostBackup = *(*[]v1beta2.BackupHook)(unsafe.Pointer(&in.PostBackup))
+	return nil
+}
+
+// Convert__BackupHooks_To_v1beta2_BackupHooks is an autogenerated conversion function.
+func Convert__BackupHooks_To_v1beta2_BackupHooks(in *BackupHooks, out *v1beta2.BackupHooks, s conversion.Scope) error {
+	return autoConvert__BackupHooks_To_v1beta2_BackupHooks(in, out, s)
+}
+
+func autoConvert_v1beta2_BackupHooks_To__BackupHooks(in *v1beta2.BackupHooks, out *BackupHooks, s conversion.Scope) error {
+	out.PreBackup = *(*[]BackupHook)(unsafe.Pointer(&in.PreBackup))
+	out.PostBackup = *(*[]BackupHook)(unsafe.Pointer(&in.PostBackup))
+	return nil
+}
+
+// Convert_v1beta2_BackupHooks_To__BackupHooks is an autogenerated conversion function.
+func Convert_v1beta2_BackupHooks_To__BackupHooks(in *v1beta2.BackupHooks, out *BackupHooks, s conversion.Scope) error {
+	return autoConvert_v1beta2_BackupHooks_To__BackupHooks(in, out, s)
+}
+
 func autoConvert__BackupPolicy_To_v1beta2_BackupPolicy(in *BackupPolicy, out *v1beta2.BackupPolicy, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert__BackupPolicySpec_To_v1beta2_BackupPolicySpec(&in.Spec, &out.Spec, s); err != nil {
@@ -309,6 +673,7 @@ func Convert_v1beta2_BackupPolicyList_To__BackupPolicyList(in *v1beta2.BackupPol
 
 func autoConvert__BackupPolicySpec_To_v1beta2_BackupPolicySpec(in *BackupPolicySpec, out *v1beta2.BackupPolicySpec, s conversion.Scope) error {
 	out.Schedule = in.Schedule
+	out.BinlogSchedule = in.BinlogSchedule
 	if err := Convert__JobConfig_To_v1beta2_JobConfig(&in.JobConfig, &out.JobConfig, s); err != nil {
 		return err
 	}
@@ -318,6 +683,13 @@ func autoConvert__BackupPolicySpec_To_v1beta2_BackupPolicySpec(in *BackupPolicyS
 	out.BackoffLimit = (*int32)(unsafe.Pointer(in.BackoffLimit))
 	out.SuccessfulJobsHistoryLimit = (*int32)(unsafe.Pointer(in.SuccessfulJobsHistoryLimit))
 	out.FailedJobsHistoryLimit = (*int32)(unsafe.Pointer(in.FailedJobsHistoryLimit))
+	out.Retention = (*v1beta2.RetentionPolicy)(unsafe.Pointer(in.Retention))
+	out.BinlogArchive = (*v1beta2.BinlogArchiveSpec)(unsafe.Pointer(in.BinlogArchive))
+	out.Source = (*v1beta2.BackupSourcePolicy)(unsafe.Pointer(in.Source))
+	out.Hooks = (*v1beta2.BackupHooks)(unsafe.Pointer(in.Hooks))
+	out.Snapshot = (*v1beta2.SnapshotSpec)(unsafe.Pointer(in.Snapshot))
+	out.SecondaryBuckets = *(*[]v1beta2.SecondaryBucket)(unsafe.Pointer(&in.SecondaryBuckets))
+	out.RestoreDrill = (*v1beta2.RestoreDrillSpec)(unsafe.Pointer(in.RestoreDrill))
 	return nil
 }
 
@@ -328,6 +700,7 @@ func Convert__BackupPolicySpec_To_v1beta2_BackupPolicySpec(in *BackupPolicySpec,
 
 func autoConvert_v1beta2_BackupPolicySpec_To__BackupPolicySpec(in *v1beta2.BackupPolicySpec, out *BackupPolicySpec, s conversion.Scope) error {
 	out.Schedule = in.Schedule
+	out.BinlogSchedule = in.BinlogSchedule
 	if err := Convert_v1beta2_JobConfig_To__JobConfig(&in.JobConfig, &out.JobConfig, s); err != nil {
 		return err
 	}
@@ -337,6 +710,13 @@ func autoConvert_v1beta2_BackupPolicySpec_To__BackupPolicySpec(in *v1beta2.Backu
 	out.BackoffLimit = (*int32)(unsafe.Pointer(in.BackoffLimit))
 	out.SuccessfulJobsHistoryLimit = (*int32)(unsafe.Pointer(in.SuccessfulJobsHistoryLimit))
 	out.FailedJobsHistoryLimit = (*int32)(unsafe.Pointer(in.FailedJobsHistoryLimit))
+	out.Retention = (*RetentionPolicy)(unsafe.Pointer(in.Retention))
+	out.BinlogArchive = (*BinlogArchiveSpec)(unsafe.Pointer(in.BinlogArchive))
+	out.Source = (*BackupSourcePolicy)(unsafe.Pointer(in.Source))
+	out.Hooks = (*BackupHooks)(unsafe.Pointer(in.Hooks))
+	out.Snapshot = (*SnapshotSpec)(unsafe.Pointer(in.Snapshot))
+	out.SecondaryBuckets = *(*[]SecondaryBucket)(unsafe.Pointer(&in.SecondaryBuckets))
+	out.RestoreDrill = (*RestoreDrillSpec)(unsafe.Pointer(in.RestoreDrill))
 	return nil
 }
 
@@ -345,6 +725,32 @@ func Convert_v1beta2_BackupPolicySpec_To__BackupPolicySpec(in *v1beta2.BackupPol
 	return autoConvert_v1beta2_BackupPolicySpec_To__BackupPolicySpec(in, out, s)
 }
 
+func autoConvert__BackupSourcePolicy_To_v1beta2_BackupSourcePolicy(in *BackupSourcePolicy, out *v1beta2.BackupSourcePolicy, s conversion.Scope) error {
+	out.Index = (*int32)(unsafe.Pointer(in.Index))
+	out.Zone = in.Zone
+	out.ExcludePrimary = in.ExcludePrimary
+	out.PreferLeastLag = in.PreferLeastLag
+	return nil
+}
+
+// Convert__BackupSourcePolicy_To_v1beta2_BackupSourcePolicy is an autogenerated conversion function.
+func Convert__BackupSourcePolicy_To_v1beta2_BackupSourcePolicy(in *BackupSourcePolicy, out *v1beta2.BackupSourcePolicy, s conversion.Scope) error {
+	return autoConvert__BackupSourcePolicy_To_v1beta2_BackupSourcePolicy(in, out, s)
+}
+
+func autoConvert_v1beta2_BackupSourcePolicy_To__BackupSourcePolicy(in *v1beta2.BackupSourcePolicy, out *BackupSourcePolicy, s conversion.Scope) error {
+	out.Index = (*int32)(unsafe.Pointer(in.Index))
+	out.Zone = in.Zone
+	out.ExcludePrimary = in.ExcludePrimary
+	out.PreferLeastLag = in.PreferLeastLag
+	return nil
+}
+
+// Convert_v1beta2_BackupSourcePolicy_To__BackupSourcePolicy is an autogenerated conversion function.
+func Convert_v1beta2_BackupSourcePolicy_To__BackupSourcePolicy(in *v1beta2.BackupSourcePolicy, out *BackupSourcePolicy, s conversion.Scope) error {
+	return autoConvert_v1beta2_BackupSourcePolicy_To__BackupSourcePolicy(in, out, s)
+}
+
 func autoConvert__BackupStatus_To_v1beta2_BackupStatus(in *BackupStatus, out *v1beta2.BackupStatus, s conversion.Scope) error {
 	out.Time = in.Time
 	out.Elapsed = in.Elapsed
@@ -352,9 +758,15 @@ func autoConvert__BackupStatus_To_v1beta2_BackupStatus(in *BackupStatus, out *v1
 	out.SourceUUID = in.SourceUUID
 	out.BinlogFilename = in.BinlogFilename
 	out.GTIDSet = in.GTIDSet
+	out.BinlogBackupTime = (*metav1.Time)(unsafe.Pointer(in.BinlogBackupTime))
+	out.VolumeSnapshotName = in.VolumeSnapshotName
 	out.DumpSize = in.DumpSize
 	out.BinlogSize = in.BinlogSize
 	out.WorkDirUsage = in.WorkDirUsage
+	out.UploadThroughput = in.UploadThroughput
+	out.EarliestRestorableTime = (*metav1.Time)(unsafe.Pointer(in.EarliestRestorableTime))
+	out.LatestRestorableTime = (*metav1.Time)(unsafe.Pointer(in.LatestRestorableTime))
+	out.RestorableGaps = *(*[]v1beta2.RestorableGap)(unsafe.Pointer(&in.RestorableGaps))
 	out.Warnings = *(*[]string)(unsafe.Pointer(&in.Warnings))
 	return nil
 }
@@ -371,9 +783,15 @@ func autoConvert_v1beta2_BackupStatus_To__BackupStatus(in *v1beta2.BackupStatus,
 	out.SourceUUID = in.SourceUUID
 	out.BinlogFilename = in.BinlogFilename
 	out.GTIDSet = in.GTIDSet
+	out.BinlogBackupTime = (*metav1.Time)(unsafe.Pointer(in.BinlogBackupTime))
+	out.VolumeSnapshotName = in.VolumeSnapshotName
 	out.DumpSize = in.DumpSize
 	out.BinlogSize = in.BinlogSize
 	out.WorkDirUsage = in.WorkDirUsage
+	out.UploadThroughput = in.UploadThroughput
+	out.EarliestRestorableTime = (*metav1.Time)(unsafe.Pointer(in.EarliestRestorableTime))
+	out.LatestRestorableTime = (*metav1.Time)(unsafe.Pointer(in.LatestRestorableTime))
+	out.RestorableGaps = *(*[]RestorableGap)(unsafe.Pointer(&in.RestorableGaps))
 	out.Warnings = *(*[]string)(unsafe.Pointer(&in.Warnings))
 	return nil
 }
@@ -383,11 +801,43 @@ func Convert_v1beta2_BackupStatus_To__BackupStatus(in *v1beta2.BackupStatus, out
 	return autoConvert_v1beta2_BackupStatus_To__BackupStatus(in, out, s)
 }
 
+func autoConvert__BinlogArchiveSpec_To_v1beta2_BinlogArchiveSpec(in *BinlogArchiveSpec, out *v1beta2.BinlogArchiveSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert__BinlogArchiveSpec_To_v1beta2_BinlogArchiveSpec is an autogenerated conversion function.
+func Convert__BinlogArchiveSpec_To_v1beta2_BinlogArchiveSpec(in *BinlogArchiveSpec, out *v1beta2.BinlogArchiveSpec, s conversion.Scope) error {
+	return autoConvert__BinlogArchiveSpec_To_v1beta2_BinlogArchiveSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_BinlogArchiveSpec_To__BinlogArchiveSpec(in *v1beta2.BinlogArchiveSpec, out *BinlogArchiveSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_v1beta2_BinlogArchiveSpec_To__BinlogArchiveSpec is an autogenerated conversion function.
+func Convert_v1beta2_BinlogArchiveSpec_To__BinlogArchiveSpec(in *v1beta2.BinlogArchiveSpec, out *BinlogArchiveSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_BinlogArchiveSpec_To__BinlogArchiveSpec(in, out, s)
+}
+
 func autoConvert__BucketConfig_To_v1beta2_BucketConfig(in *BucketConfig, out *v1beta2.BucketConfig, s conversion.Scope) error {
 	out.BucketName = in.BucketName
 	out.Region = in.Region
 	out.EndpointURL = in.EndpointURL
 	out.UsePathStyle = in.UsePathStyle
+	out.BackendType = in.BackendType
+	out.AccountName = in.AccountName
+	out.Volume = (*v1beta2.VolumeSourceApplyConfiguration)(unsafe.Pointer(in.Volume))
+	out.UploadConcurrency = in.UploadConcurrency
+	out.PartSize = (*resource.Quantity)(unsafe.Pointer(in.PartSize))
+	out.RateLimit = (*resource.Quantity)(unsafe.Pointer(in.RateLimit))
+	out.ServerSideEncryption = in.ServerSideEncryption
+	out.KMSKeyID = in.KMSKeyID
+	out.StorageClass = in.StorageClass
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
+	out.ObjectLock = (*v1beta2.ObjectLockConfig)(unsafe.Pointer(in.ObjectLock))
+	out.TLS = (*v1beta2.BucketTLSConfig)(unsafe.Pointer(in.TLS))
 	return nil
 }
 
@@ -401,6 +851,18 @@ func autoConvert_v1beta2_BucketConfig_To__BucketConfig(in *v1beta2.BucketConfig,
 	out.Region = in.Region
 	out.EndpointURL = in.EndpointURL
 	out.UsePathStyle = in.UsePathStyle
+	out.BackendType = in.BackendType
+	out.AccountName = in.AccountName
+	out.Volume = (*VolumeSourceApplyConfiguration)(unsafe.Pointer(in.Volume))
+	out.UploadConcurrency = in.UploadConcurrency
+	out.PartSize = (*resource.Quantity)(unsafe.Pointer(in.PartSize))
+	out.RateLimit = (*resource.Quantity)(unsafe.Pointer(in.RateLimit))
+	out.ServerSideEncryption = in.ServerSideEncryption
+	out.KMSKeyID = in.KMSKeyID
+	out.StorageClass = in.StorageClass
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
+	out.ObjectLock = (*ObjectLockConfig)(unsafe.Pointer(in.ObjectLock))
+	out.TLS = (*BucketTLSConfig)(unsafe.Pointer(in.TLS))
 	return nil
 }
 
@@ -409,6 +871,96 @@ func Convert_v1beta2_BucketConfig_To__BucketConfig(in *v1beta2.BucketConfig, out
 	return autoConvert_v1beta2_BucketConfig_To__BucketConfig(in, out, s)
 }
 
+func autoConvert__BucketTLSConfig_To_v1beta2_BucketTLSConfig(in *BucketTLSConfig, out *v1beta2.BucketTLSConfig, s conversion.Scope) error {
+	out.CABundle = (*v1beta2.CABundleSource)(unsafe.Pointer(in.CABundle))
+	out.ClientCertSecretName = in.ClientCertSecretName
+	return nil
+}
+
+// Convert__BucketTLSConfig_To_v1beta2_BucketTLSConfig is an autogenerated conversion function.
+func Convert__BucketTLSConfig_To_v1beta2_BucketTLSConfig(in *BucketTLSConfig, out *v1beta2.BucketTLSConfig, s conversion.Scope) error {
+	return autoConvert__BucketTLSConfig_To_v1beta2_BucketTLSConfig(in, out, s)
+}
+
+func autoConvert_v1beta2_BucketTLSConfig_To__BucketTLSConfig(in *v1beta2.BucketTLSConfig, out *BucketTLSConfig, s conversion.Scope) error {
+	out.CABundle = (*CABundleSource)(unsafe.Pointer(in.CABundle))
+	out.ClientCertSecretName = in.ClientCertSecretName
+	return nil
+}
+
+// Convert_v1beta2_BucketTLSConfig_To__BucketTLSConfig is an autogenerated conversion function.
+func Convert_v1beta2_BucketTLSConfig_To__BucketTLSConfig(in *v1beta2.BucketTLSConfig, out *BucketTLSConfig, s conversion.Scope) error {
+	return autoConvert_v1beta2_BucketTLSConfig_To__BucketTLSConfig(in, out, s)
+}
+
+func autoConvert__CABundleSource_To_v1beta2_CABundleSource(in *CABundleSource, out *v1beta2.CABundleSource, s conversion.Scope) error {
+	out.ConfigMapName = in.ConfigMapName
+	out.SecretName = in.SecretName
+	out.Key = in.Key
+	return nil
+}
+
+// Convert__CABundleSource_To_v1beta2_CABundleSource is an autogenerated conversion function.
+func Convert__CABundleSource_To_v1beta2_CABundleSource(in *CABundleSource, out *v1beta2.CABundleSource, s conversion.Scope) error {
+	return autoConvert__CABundleSource_To_v1beta2_CABundleSource(in, out, s)
+}
+
+func autoConvert_v1beta2_CABundleSource_To__CABundleSource(in *v1beta2.CABundleSource, out *CABundleSource, s conversion.Scope) error {
+	out.ConfigMapName = in.ConfigMapName
+	out.SecretName = in.SecretName
+	out.Key = in.Key
+	return nil
+}
+
+// Convert_v1beta2_CABundleSource_To__CABundleSource is an autogenerated conversion function.
+func Convert_v1beta2_CABundleSource_To__CABundleSource(in *v1beta2.CABundleSource, out *CABundleSource, s conversion.Scope) error {
+	return autoConvert_v1beta2_CABundleSource_To__CABundleSource(in, out, s)
+}
+
+func autoConvert__CompressionConfig_To_v1beta2_CompressionConfig(in *CompressionConfig, out *v1beta2.CompressionConfig, s conversion.Scope) error {
+	out.Dump = in.Dump
+	out.Binlog = in.Binlog
+	return nil
+}
+
+// Convert__CompressionConfig_To_v1beta2_CompressionConfig is an autogenerated conversion function.
+func Convert__CompressionConfig_To_v1beta2_CompressionConfig(in *CompressionConfig, out *v1beta2.CompressionConfig, s conversion.Scope) error {
+	return autoConvert__CompressionConfig_To_v1beta2_CompressionConfig(in, out, s)
+}
+
+func autoConvert_v1beta2_CompressionConfig_To__CompressionConfig(in *v1beta2.CompressionConfig, out *CompressionConfig, s conversion.Scope) error {
+	out.Dump = in.Dump
+	out.Binlog = in.Binlog
+	return nil
+}
+
+// Convert_v1beta2_CompressionConfig_To__CompressionConfig is an autogenerated conversion function.
+func Convert_v1beta2_CompressionConfig_To__CompressionConfig(in *v1beta2.CompressionConfig, out *CompressionConfig, s conversion.Scope) error {
+	return autoConvert_v1beta2_CompressionConfig_To__CompressionConfig(in, out, s)
+}
+
+func autoConvert__EncryptionConfig_To_v1beta2_EncryptionConfig(in *EncryptionConfig, out *v1beta2.EncryptionConfig, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.KeyID = in.KeyID
+	return nil
+}
+
+// Convert__EncryptionConfig_To_v1beta2_EncryptionConfig is an autogenerated conversion function.
+func Convert__EncryptionConfig_To_v1beta2_EncryptionConfig(in *EncryptionConfig, out *v1beta2.EncryptionConfig, s conversion.Scope) error {
+	return autoConvert__EncryptionConfig_To_v1beta2_EncryptionConfig(in, out, s)
+}
+
+func autoConvert_v1beta2_EncryptionConfig_To__EncryptionConfig(in *v1beta2.EncryptionConfig, out *EncryptionConfig, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.KeyID = in.KeyID
+	return nil
+}
+
+// Convert_v1beta2_EncryptionConfig_To__EncryptionConfig is an autogenerated conversion function.
+func Convert_v1beta2_EncryptionConfig_To__EncryptionConfig(in *v1beta2.EncryptionConfig, out *EncryptionConfig, s conversion.Scope) error {
+	return autoConvert_v1beta2_EncryptionConfig_To__EncryptionConfig(in, out, s)
+}
+
 func autoConvert__EnvFromSourceApplyConfiguration_To_v1beta2_EnvFromSourceApplyConfiguration(in *EnvFromSourceApplyConfiguration, out *v1beta2.EnvFromSourceApplyConfiguration, s conversion.Scope) error {
 	out.Prefix = (*string)(unsafe.Pointer(in.Prefix))
 	out.ConfigMapRef = (*v1.ConfigMapEnvSourceApplyConfiguration)(unsafe.Pointer(in.ConfigMapRef))
@@ -457,6 +1009,100 @@ func Convert_v1beta2_EnvVarApplyConfiguration_To__EnvVarApplyConfiguration(in *v
 	return autoConvert_v1beta2_EnvVarApplyConfiguration_To__EnvVarApplyConfiguration(in, out, s)
 }
 
+func autoConvert__FilterConfig_To_v1beta2_FilterConfig(in *FilterConfig, out *v1beta2.FilterConfig, s conversion.Scope) error {
+	out.IncludeSchemas = *(*[]string)(unsafe.Pointer(&in.IncludeSchemas))
+	out.ExcludeSchemas = *(*[]string)(unsafe.Pointer(&in.ExcludeSchemas))
+	out.IncludeTables = *(*[]string)(unsafe.Pointer(&in.IncludeTables))
+	out.ExcludeTables = *(*[]string)(unsafe.Pointer(&in.ExcludeTables))
+	return nil
+}
+
+// Convert__FilterConfig_To_v1beta2_FilterConfig is an autogenerated conversion function.
+func Convert__FilterConfig_To_v1beta2_FilterConfig(in *FilterConfig, out *v1beta2.FilterConfig, s conversion.Scope) error {
+	return autoConvert__FilterConfig_To_v1beta2_FilterConfig(in, out, s)
+}
+
+func autoConvert_v1beta2_FilterConfig_To__FilterConfig(in *v1beta2.FilterConfig, out *FilterConfig, s conversion.Scope) error {
+	out.IncludeSchemas = *(*[]string)(unsafe.Pointer(&in.IncludeSchemas))
+	out.ExcludeSchemas = *(*[]string)(unsafe.Pointer(&in.ExcludeSchemas))
+	out.IncludeTables = *(*[]string)(unsafe.Pointer(&in.IncludeTables))
+	out.ExcludeTables = *(*[]string)(unsafe.Pointer(&in.ExcludeTables))
+	return nil
+}
+
+// Convert_v1beta2_FilterConfig_To__FilterConfig is an autogenerated conversion function.
+func Convert_v1beta2_FilterConfig_To__FilterConfig(in *v1beta2.FilterConfig, out *FilterConfig, s conversion.Scope) error {
+	return autoConvert_v1beta2_FilterConfig_To__FilterConfig(in, out, s)
+}
+
+func autoConvert__HTTPHeader_To_v1beta2_HTTPHeader(in *HTTPHeader, out *v1beta2.HTTPHeader, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert__HTTPHeaderSource_To_v1beta2_HTTPHeaderSource(&in.ValueFrom, &out.ValueFrom, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert__HTTPHeader_To_v1beta2_HTTPHeader is an autogenerated conversion function.
+func Convert__HTTPHeader_To_v1beta2_HTTPHeader(in *HTTPHeader, out *v1beta2.HTTPHeader, s conversion.Scope) error {
+	return autoConvert__HTTPHeader_To_v1beta2_HTTPHeader(in, out, s)
+}
+
+func autoConvert_v1beta2_HTTPHeader_To__HTTPHeader(in *v1beta2.HTTPHeader, out *HTTPHeader, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert_v1beta2_HTTPHeaderSource_To__HTTPHeaderSource(&in.ValueFrom, &out.ValueFrom, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_HTTPHeader_To__HTTPHeader is an autogenerated conversion function.
+func Convert_v1beta2_HTTPHeader_To__HTTPHeader(in *v1beta2.HTTPHeader, out *HTTPHeader, s conversion.Scope) error {
+	return autoConvert_v1beta2_HTTPHeader_To__HTTPHeader(in, out, s)
+}
+
+func autoConvert__HTTPHeaderSource_To_v1beta2_HTTPHeaderSource(in *HTTPHeaderSource, out *v1beta2.HTTPHeaderSource, s conversion.Scope) error {
+	out.SecretKeyRef = in.SecretKeyRef
+	return nil
+}
+
+// Convert__HTTPHeaderSource_To_v1beta2_HTTPHeaderSource is an autogenerated conversion function.
+func Convert__HTTPHeaderSource_To_v1beta2_HTTPHeaderSource(in *HTTPHeaderSource, out *v1beta2.HTTPHeaderSource, s conversion.Scope) error {
+	return autoConvert__HTTPHeaderSource_To_v1beta2_HTTPHeaderSource(in, out, s)
+}
+
+func autoConvert_v1beta2_HTTPHeaderSource_To__HTTPHeaderSource(in *v1beta2.HTTPHeaderSource, out *HTTPHeaderSource, s conversion.Scope) error {
+	out.SecretKeyRef = in.SecretKeyRef
+	return nil
+}
+
+// Convert_v1beta2_HTTPHeaderSource_To__HTTPHeaderSource is an autogenerated conversion function.
+func Convert_v1beta2_HTTPHeaderSource_To__HTTPHeaderSource(in *v1beta2.HTTPHeaderSource, out *HTTPHeaderSource, s conversion.Scope) error {
+	return autoConvert_v1beta2_HTTPHeaderSource_To__HTTPHeaderSource(in, out, s)
+}
+
+func autoConvert__HTTPHook_To_v1beta2_HTTPHook(in *HTTPHook, out *v1beta2.HTTPHook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Headers = *(*[]v1beta2.HTTPHeader)(unsafe.Pointer(&in.Headers))
+	return nil
+}
+
+// Convert__HTTPHook_To_v1beta2_HTTPHook is an autogenerated conversion function.
+func Convert__HTTPHook_To_v1beta2_HTTPHook(in *HTTPHook, out *v1beta2.HTTPHook, s conversion.Scope) error {
+	return autoConvert__HTTPHook_To_v1beta2_HTTPHook(in, out, s)
+}
+
+func autoConvert_v1beta2_HTTPHook_To__HTTPHook(in *v1beta2.HTTPHook, out *HTTPHook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Headers = *(*[]HTTPHeader)(unsafe.Pointer(&in.Headers))
+	return nil
+}
+
+// Convert_v1beta2_HTTPHook_To__HTTPHook is an autogenerated conversion function.
+func Convert_v1beta2_HTTPHook_To__HTTPHook(in *v1beta2.HTTPHook, out *HTTPHook, s conversion.Scope) error {
+	return autoConvert_v1beta2_HTTPHook_To__HTTPHook(in, out, s)
+}
+
 func autoConvert__JobConfig_To_v1beta2_JobConfig(in *JobConfig, out *v1beta2.JobConfig, s conversion.Scope) error {
 	out.ServiceAccountName = in.ServiceAccountName
 	if err := Convert__BucketConfig_To_v1beta2_BucketConfig(&in.BucketConfig, &out.BucketConfig, s); err != nil {
@@ -470,6 +1116,11 @@ func autoConvert__JobConfig_To_v1beta2_JobConfig(in *JobConfig, out *v1beta2.Job
 	out.MaxMemory = (*resource.Quantity)(unsafe.Pointer(in.MaxMemory))
 	out.EnvFrom = *(*[]v1beta2.EnvFromSourceApplyConfiguration)(unsafe.Pointer(&in.EnvFrom))
 	out.Env = *(*[]v1beta2.EnvVarApplyConfiguration)(unsafe.Pointer(&in.Env))
+	out.Encryption = (*v1beta2.EncryptionConfig)(unsafe.Pointer(in.Encryption))
+	out.Filter = (*v1beta2.FilterConfig)(unsafe.Pointer(in.Filter))
+	out.Compression = (*v1beta2.CompressionConfig)(unsafe.Pointer(in.Compression))
+	out.Streaming = in.Streaming
+	out.PodTemplate = (*v1beta2.JobPodTemplate)(unsafe.Pointer(in.PodTemplate))
 	return nil
 }
 
@@ -491,6 +1142,11 @@ func autoConvert_v1beta2_JobConfig_To__JobConfig(in *v1beta2.JobConfig, out *Job
 	out.MaxMemory = (*resource.Quantity)(unsafe.Pointer(in.MaxMemory))
 	out.EnvFrom = *(*[]EnvFromSourceApplyConfiguration)(unsafe.Pointer(&in.EnvFrom))
 	out.Env = *(*[]EnvVarApplyConfiguration)(unsafe.Pointer(&in.Env))
+	out.Encryption = (*EncryptionConfig)(unsafe.Pointer(in.Encryption))
+	out.Filter = (*FilterConfig)(unsafe.Pointer(in.Filter))
+	out.Compression = (*CompressionConfig)(unsafe.Pointer(in.Compression))
+	out.Streaming = in.Streaming
+	out.PodTemplate = (*JobPodTemplate)(unsafe.Pointer(in.PodTemplate))
 	return nil
 }
 
@@ -499,6 +1155,46 @@ func Convert_v1beta2_JobConfig_To__JobConfig(in *v1beta2.JobConfig, out *JobConf
 	return autoConvert_v1beta2_JobConfig_To__JobConfig(in, out, s)
 }
 
+func autoConvert__JobPodTemplate_To_v1beta2_JobPodTemplate(in *JobPodTemplate, out *v1beta2.JobPodTemplate, s conversion.Scope) error {
+	if err := Convert__ObjectMeta_To_v1beta2_ObjectMeta(&in.ObjectMeta, &out.ObjectMeta, s); err != nil {
+		return err
+	}
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Affinity = (*v1beta2.AffinityApplyConfiguration)(unsafe.Pointer(in.Affinity))
+	out.Tolerations = *(*[]v1beta2.TolerationApplyConfiguration)(unsafe.Pointer(&in.Tolerations))
+	out.PriorityClassName = in.PriorityClassName
+	out.SecurityContext = (*v1beta2.PodSecurityContextApplyConfiguration)(unsafe.Pointer(in.SecurityContext))
+	out.ContainerSecurityContext = (*v1beta2.SecurityContextApplyConfiguration)(unsafe.Pointer(in.ContainerSecurityContext))
+	out.Volumes = *(*[]v1beta2.VolumeApplyConfiguration)(unsafe.Pointer(&in.Volumes))
+	out.VolumeMounts = *(*[]v1beta2.VolumeMountApplyConfiguration)(unsafe.Pointer(&in.VolumeMounts))
+	return nil
+}
+
+// Convert__JobPodTemplate_To_v1beta2_JobPodTemplate is an autogenerated conversion function.
+func Convert__JobPodTemplate_To_v1beta2_JobPodTemplate(in *JobPodTemplate, out *v1beta2.JobPodTemplate, s conversion.Scope) error {
+	return autoConvert__JobPodTemplate_To_v1beta2_JobPodTemplate(in, out, s)
+}
+
+func autoConvert_v1beta2_JobPodTemplate_To__JobPodTemplate(in *v1beta2.JobPodTemplate, out *JobPodTemplate, s conversion.Scope) error {
+	if err := Convert_v1beta2_ObjectMeta_To__ObjectMeta(&in.ObjectMeta, &out.ObjectMeta, s); err != nil {
+		return err
+	}
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Affinity = (*AffinityApplyConfiguration)(unsafe.Pointer(in.Affinity))
+	out.Tolerations = *(*[]TolerationApplyConfiguration)(unsafe.Pointer(&in.Tolerations))
+	out.PriorityClassName = in.PriorityClassName
+	out.SecurityContext = (*PodSecurityContextApplyConfiguration)(unsafe.Pointer(in.SecurityContext))
+	out.ContainerSecurityContext = (*SecurityContextApplyConfiguration)(unsafe.Pointer(in.ContainerSecurityContext))
+	out.Volumes = *(*[]VolumeApplyConfiguration)(unsafe.Pointer(&in.Volumes))
+	out.VolumeMounts = *(*[]VolumeMountApplyConfiguration)(unsafe.Pointer(&in.VolumeMounts))
+	return nil
+}
+
+// Convert_v1beta2_JobPodTemplate_To__JobPodTemplate is an autogenerated conversion function.
+func Convert_v1beta2_JobPodTemplate_To__JobPodTemplate(in *v1beta2.JobPodTemplate, out *JobPodTemplate, s conversion.Scope) error {
+	return autoConvert_v1beta2_JobPodTemplate_To__JobPodTemplate(in, out, s)
+}
+
 func autoConvert__MySQLCluster_To_v1beta2_MySQLCluster(in *MySQLCluster, out *v1beta2.MySQLCluster, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert__MySQLClusterSpec_To_v1beta2_MySQLClusterSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -642,6 +1338,9 @@ func autoConvert__MySQLClusterStatus_To_v1beta2_MySQLClusterStatus(in *MySQLClus
 		return err
 	}
 	out.RestoredTime = (*metav1.Time)(unsafe.Pointer(in.RestoredTime))
+	out.Restore = (*v1beta2.RestoreStatus)(unsafe.Pointer(in.Restore))
+	out.RestoreDrill = (*v1beta2.RestoreDrillStatus)(unsafe.Pointer(in.RestoreDrill))
+	out.RestoreDrillRequest = (*v1beta2.RestoreDrillRequest)(unsafe.Pointer(in.RestoreDrillRequest))
 	out.Cloned = in.Cloned
 	if err := Convert__ReconcileInfo_To_v1beta2_ReconcileInfo(&in.ReconcileInfo, &out.ReconcileInfo, s); err != nil {
 		return err
@@ -664,6 +1363,9 @@ func autoConvert_v1beta2_MySQLClusterStatus_To__MySQLClusterStatus(in *v1beta2.M
 		return err
 	}
 	out.RestoredTime = (*metav1.Time)(unsafe.Pointer(in.RestoredTime))
+	out.Restore = (*RestoreStatus)(unsafe.Pointer(in.Restore))
+	out.RestoreDrill = (*RestoreDrillStatus)(unsafe.Pointer(in.RestoreDrill))
+	out.RestoreDrillRequest = (*RestoreDrillRequest)(unsafe.Pointer(in.RestoreDrillRequest))
 	out.Cloned = in.Cloned
 	if err := Convert_v1beta2_ReconcileInfo_To__ReconcileInfo(&in.ReconcileInfo, &out.ReconcileInfo, s); err != nil {
 		return err
@@ -676,6 +1378,28 @@ func Convert_v1beta2_MySQLClusterStatus_To__MySQLClusterStatus(in *v1beta2.MySQL
 	return autoConvert_v1beta2_MySQLClusterStatus_To__MySQLClusterStatus(in, out, s)
 }
 
+func autoConvert__ObjectLockConfig_To_v1beta2_ObjectLockConfig(in *ObjectLockConfig, out *v1beta2.ObjectLockConfig, s conversion.Scope) error {
+	out.Mode = in.Mode
+	out.RetainFor = in.RetainFor
+	return nil
+}
+
+// Convert__ObjectLockConfig_To_v1beta2_ObjectLockConfig is an autogenerated conversion function.
+func Convert__ObjectLockConfig_To_v1beta2_ObjectLockConfig(in *ObjectLockConfig, out *v1beta2.ObjectLockConfig, s conversion.Scope) error {
+	return autoConvert__ObjectLockConfig_To_v1beta2_ObjectLockConfig(in, out, s)
+}
+
+func autoConvert_v1beta2_ObjectLockConfig_To__ObjectLockConfig(in *v1beta2.ObjectLockConfig, out *ObjectLockConfig, s conversion.Scope) error {
+	out.Mode = in.Mode
+	out.RetainFor = in.RetainFor
+	return nil
+}
+
+// Convert_v1beta2_ObjectLockConfig_To__ObjectLockConfig is an autogenerated conversion function.
+func Convert_v1beta2_ObjectLockConfig_To__ObjectLockConfig(in *v1beta2.ObjectLockConfig, out *ObjectLockConfig, s conversion.Scope) error {
+	return autoConvert_v1beta2_ObjectLockConfig_To__ObjectLockConfig(in, out, s)
+}
+
 func autoConvert__ObjectMeta_To_v1beta2_ObjectMeta(in *ObjectMeta, out *v1beta2.ObjectMeta, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
@@ -764,6 +1488,44 @@ func Convert_v1beta2_PersistentVolumeClaimSpecApplyConfiguration_To__PersistentV
 	return autoConvert_v1beta2_PersistentVolumeClaimSpecApplyConfiguration_To__PersistentVolumeClaimSpecApplyConfiguration(in, out, s)
 }
 
+func autoConvert__PodSecurityContextApplyConfiguration_To_v1beta2_PodSecurityContextApplyConfiguration(in *PodSecurityContextApplyConfiguration, out *v1beta2.PodSecurityContextApplyConfiguration, s conversion.Scope) error {
+	out.SELinuxOptions = (*v1.SELinuxOptionsApplyConfiguration)(unsafe.Pointer(in.SELinuxOptions))
+	out.WindowsOptions = (*v1.WindowsSecurityContextOptionsApplyConfiguration)(unsafe.Pointer(in.WindowsOptions))
+	out.RunAsUser = (*int64)(unsafe.Pointer(in.RunAsUser))
+	out.RunAsGroup = (*int64)(unsafe.Pointer(in.RunAsGroup))
+	out.RunAsNonRoot = (*bool)(unsafe.Pointer(in.RunAsNonRoot))
+	out.SupplementalGroups = *(*[]int64)(unsafe.Pointer(&in.SupplementalGroups))
+	out.FSGroup = (*int64)(unsafe.Pointer(in.FSGroup))
+	out.Sysctls = *(*[]v1.SysctlApplyConfiguration)(unsafe.Pointer(&in.Sysctls))
+	out.FSGroupChangePolicy = (*corev1.PodFSGroupChangePolicy)(unsafe.Pointer(in.FSGroupChangePolicy))
+	out.SeccompProfile = (*v1.SeccompProfileApplyConfiguration)(unsafe.Pointer(in.SeccompProfile))
+	return nil
+}
+
+// Convert__PodSecurityContextApplyConfiguration_To_v1beta2_PodSecurityContextApplyConfiguration is an autogenerated conversion function.
+func Convert__PodSecurityContextApplyConfiguration_To_v1beta2_PodSecurityContextApplyConfiguration(in *PodSecurityContextApplyConfiguration, out *v1beta2.PodSecurityContextApplyConfiguration, s conversion.Scope) error {
+	return autoConvert__PodSecurityContextApplyConfiguration_To_v1beta2_PodSecurityContextApplyConfiguration(in, out, s)
+}
+
+func autoConvert_v1beta2_PodSecurityContextApplyConfiguration_To__PodSecurityContextApplyConfiguration(in *v1beta2.PodSecurityContextApplyConfiguration, out *PodSecurityContextApplyConfiguration, s conversion.Scope) error {
+	out.SELinuxOptions = (*v1.SELinuxOptionsApplyConfiguration)(unsafe.Pointer(in.SELinuxOptions))
+	out.WindowsOptions = (*v1.WindowsSecurityContextOptionsApplyConfiguration)(unsafe.Pointer(in.WindowsOptions))
+	out.RunAsUser = (*int64)(unsafe.Pointer(in.RunAsUser))
+	out.RunAsGroup = (*int64)(unsafe.Pointer(in.RunAsGroup))
+	out.RunAsNonRoot = (*bool)(unsafe.Pointer(in.RunAsNonRoot))
+	out.SupplementalGroups = *(*[]int64)(unsafe.Pointer(&in.SupplementalGroups))
+	out.FSGroup = (*int64)(unsafe.Pointer(in.FSGroup))
+	out.Sysctls = *(*[]v1.SysctlApplyConfiguration)(unsafe.Pointer(&in.Sysctls))
+	out.FSGroupChangePolicy = (*corev1.PodFSGroupChangePolicy)(unsafe.Pointer(in.FSGroupChangePolicy))
+	out.SeccompProfile = (*v1.SeccompProfileApplyConfiguration)(unsafe.Pointer(in.SeccompProfile))
+	return nil
+}
+
+// Convert_v1beta2_PodSecurityContextApplyConfiguration_To__PodSecurityContextApplyConfiguration is an autogenerated conversion function.
+func Convert_v1beta2_PodSecurityContextApplyConfiguration_To__PodSecurityContextApplyConfiguration(in *v1beta2.PodSecurityContextApplyConfiguration, out *PodSecurityContextApplyConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta2_PodSecurityContextApplyConfiguration_To__PodSecurityContextApplyConfiguration(in, out, s)
+}
+
 func autoConvert__PodSpecApplyConfiguration_To_v1beta2_PodSpecApplyConfiguration(in *PodSpecApplyConfiguration, out *v1beta2.PodSpecApplyConfiguration, s conversion.Scope) error {
 	out.Volumes = *(*[]v1.VolumeApplyConfiguration)(unsafe.Pointer(&in.Volumes))
 	out.InitContainers = *(*[]v1.ContainerApplyConfiguration)(unsafe.Pointer(&in.InitContainers))
@@ -902,10 +1664,139 @@ func Convert_v1beta2_ReconcileInfo_To__ReconcileInfo(in *v1beta2.ReconcileInfo,
 	return autoConvert_v1beta2_ReconcileInfo_To__ReconcileInfo(in, out, s)
 }
 
+func autoConvert__RestorableGap_To_v1beta2_RestorableGap(in *RestorableGap, out *v1beta2.RestorableGap, s conversion.Scope) error {
+	out.Start = in.Start
+	out.End = in.End
+	return nil
+}
+
+// Convert__RestorableGap_To_v1beta2_RestorableGap is an autogenerated conversion function.
+func Convert__RestorableGap_To_v1beta2_RestorableGap(in *RestorableGap, out *v1beta2.RestorableGap, s conversion.Scope) error {
+	return autoConvert__RestorableGap_To_v1beta2_RestorableGap(in, out, s)
+}
+
+func autoConvert_v1beta2_RestorableGap_To__RestorableGap(in *v1beta2.RestorableGap, out *RestorableGap, s conversion.Scope) error {
+	out.Start = in.Start
+	out.End = in.End
+	return nil
+}
+
+// Convert_v1beta2_RestorableGap_To__RestorableGap is an autogenerated conversion function.
+func Convert_v1beta2_RestorableGap_To__RestorableGap(in *v1beta2.RestorableGap, out *RestorableGap, s conversion.Scope) error {
+	return autoConvert_v1beta2_RestorableGap_To__RestorableGap(in, out, s)
+}
+
+func autoConvert__RestoreDrillAssertion_To_v1beta2_RestoreDrillAssertion(in *RestoreDrillAssertion, out *v1beta2.RestoreDrillAssertion, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Query = in.Query
+	out.Expect = (*string)(unsafe.Pointer(in.Expect))
+	out.Min = (*int64)(unsafe.Pointer(in.Min))
+	out.Max = (*int64)(unsafe.Pointer(in.Max))
+	return nil
+}
+
+// Convert__RestoreDrillAssertion_To_v1beta2_RestoreDrillAssertion is an autogenerated conversion function.
+func Convert__RestoreDrillAssertion_To_v1beta2_RestoreDrillAssertion(in *RestoreDrillAssertion, out *v1beta2.RestoreDrillAssertion, s conversion.Scope) error {
+	return autoConvert__RestoreDrillAssertion_To_v1beta2_RestoreDrillAssertion(in, out, s)
+}
+
+func autoConvert_v1beta2_RestoreDrillAssertion_To__RestoreDrillAssertion(in *v1beta2.RestoreDrillAssertion, out *RestoreDrillAssertion, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Query = in.Query
+	out.Expect = (*string)(unsafe.Pointer(in.Expect))
+	out.Min = (*int64)(unsafe.Pointer(in.Min))
+	out.Max = (*int64)(unsafe.Pointer(in.Max))
+	return nil
+}
+
+// Convert_v1beta2_RestoreDrillAssertion_To__RestoreDrillAssertion is an autogenerated conversion function.
+func Convert_v1beta2_RestoreDrillAssertion_To__RestoreDrillAssertion(in *v1beta2.RestoreDrillAssertion, out *RestoreDrillAssertion, s conversion.Scope) error {
+	return autoConvert_v1beta2_RestoreDrillAssertion_To__RestoreDrillAssertion(in, out, s)
+}
+
+func autoConvert__RestoreDrillRequest_To_v1beta2_RestoreDrillRequest(in *RestoreDrillRequest, out *v1beta2.RestoreDrillRequest, s conversion.Scope) error {
+	out.RestorePoint = in.RestorePoint
+	return nil
+}
+
+// Convert__RestoreDrillRequest_To_v1beta2_RestoreDrillRequest is an autogenerated conversion function.
+func Convert__RestoreDrillRequest_To_v1beta2_RestoreDrillRequest(in *RestoreDrillRequest, out *v1beta2.RestoreDrillRequest, s conversion.Scope) error {
+	return autoConvert__RestoreDrillRequest_To_v1beta2_RestoreDrillRequest(in, out, s)
+}
+
+func autoConvert_v1beta2_RestoreDrillRequest_To__RestoreDrillRequest(in *v1beta2.RestoreDrillRequest, out *RestoreDrillRequest, s conversion.Scope) error {
+	out.RestorePoint = in.RestorePoint
+	return nil
+}
+
+// Convert_v1beta2_RestoreDrillRequest_To__RestoreDrillRequest is an autogenerated conversion function.
+func Convert_v1beta2_RestoreDrillRequest_To__RestoreDrillRequest(in *v1beta2.RestoreDrillRequest, out *RestoreDrillRequest, s conversion.Scope) error {
+	return autoConvert_v1beta2_RestoreDrillRequest_To__RestoreDrillRequest(in, out, s)
+}
+
+func autoConvert__RestoreDrillSpec_To_v1beta2_RestoreDrillSpec(in *RestoreDrillSpec, out *v1beta2.RestoreDrillSpec, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Assertions = *(*[]v1beta2.RestoreDrillAssertion)(unsafe.Pointer(&in.Assertions))
+	out.Timeout = in.Timeout
+	return nil
+}
+
+// Convert__RestoreDrillSpec_To_v1beta2_RestoreDrillSpec is an autogenerated conversion function.
+func Convert__RestoreDrillSpec_To_v1beta2_RestoreDrillSpec(in *RestoreDrillSpec, out *v1beta2.RestoreDrillSpec, s conversion.Scope) error {
+	return autoConvert__RestoreDrillSpec_To_v1beta2_RestoreDrillSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_RestoreDrillSpec_To__RestoreDrillSpec(in *v1beta2.RestoreDrillSpec, out *RestoreDrillSpec, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Assertions = *(*[]RestoreDrillAssertion)(unsafe.Pointer(&in.Assertions))
+	out.Timeout = in.Timeout
+	return nil
+}
+
+// Convert_v1beta2_RestoreDrillSpec_To__RestoreDrillSpec is an autogenerated conversion function.
+func Convert_v1beta2_RestoreDrillSpec_To__RestoreDrillSpec(in *v1beta2.RestoreDrillSpec, out *RestoreDrillSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_RestoreDrillSpec_To__RestoreDrillSpec(in, out, s)
+}
+
+func autoConvert__RestoreDrillStatus_To_v1beta2_RestoreDrillStatus(in *RestoreDrillStatus, out *v1beta2.RestoreDrillStatus, s conversion.Scope) error {
+	out.Time = in.Time
+	out.Elapsed = in.Elapsed
+	out.Succeeded = in.Succeeded
+	out.RestorePoint = (*metav1.Time)(unsafe.Pointer(in.RestorePoint))
+	out.FailedAssertions = *(*[]string)(unsafe.Pointer(&in.FailedAssertions))
+	out.Message = in.Message
+	out.LastSuccessTime = (*metav1.Time)(unsafe.Pointer(in.LastSuccessTime))
+	return nil
+}
+
+// Convert__RestoreDrillStatus_To_v1beta2_RestoreDrillStatus is an autogenerated conversion function.
+func Convert__RestoreDrillStatus_To_v1beta2_RestoreDrillStatus(in *RestoreDrillStatus, out *v1beta2.RestoreDrillStatus, s conversion.Scope) error {
+	return autoConvert__RestoreDrillStatus_To_v1beta2_RestoreDrillStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_RestoreDrillStatus_To__RestoreDrillStatus(in *v1beta2.RestoreDrillStatus, out *RestoreDrillStatus, s conversion.Scope) error {
+	out.Time = in.Time
+	out.Elapsed = in.Elapsed
+	out.Succeeded = in.Succeeded
+	out.RestorePoint = (*metav1.Time)(unsafe.Pointer(in.RestorePoint))
+	out.FailedAssertions = *(*[]string)(unsafe.Pointer(&in.FailedAssertions))
+	out.Message = in.Message
+	out.LastSuccessTime = (*metav1.Time)(unsafe.Pointer(in.LastSuccessTime))
+	return nil
+}
+
+// Convert_v1beta2_RestoreDrillStatus_To__RestoreDrillStatus is an autogenerated conversion function.
+func Convert_v1beta2_RestoreDrillStatus_To__RestoreDrillStatus(in *v1beta2.RestoreDrillStatus, out *RestoreDrillStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_RestoreDrillStatus_To__RestoreDrillStatus(in, out, s)
+}
+
 func autoConvert__RestoreSpec_To_v1beta2_RestoreSpec(in *RestoreSpec, out *v1beta2.RestoreSpec, s conversion.Scope) error {
 	out.SourceName = in.SourceName
 	out.SourceNamespace = in.SourceNamespace
 	out.RestorePoint = in.RestorePoint
+	out.StopGTID = in.StopGTID
+	out.VolumeSnapshotName = in.VolumeSnapshotName
+	out.SecondaryBucketName = in.SecondaryBucketName
 	if err := Convert__JobConfig_To_v1beta2_JobConfig(&in.JobConfig, &out.JobConfig, s); err != nil {
 		return err
 	}
@@ -921,6 +1812,9 @@ func autoConvert_v1beta2_RestoreSpec_To__RestoreSpec(in *v1beta2.RestoreSpec, ou
 	out.SourceName = in.SourceName
 	out.SourceNamespace = in.SourceNamespace
 	out.RestorePoint = in.RestorePoint
+	out.StopGTID = in.StopGTID
+	out.VolumeSnapshotName = in.VolumeSnapshotName
+	out.SecondaryBucketName = in.SecondaryBucketName
 	if err := Convert_v1beta2_JobConfig_To__JobConfig(&in.JobConfig, &out.JobConfig, s); err != nil {
 		return err
 	}
@@ -932,6 +1826,136 @@ func Convert_v1beta2_RestoreSpec_To__RestoreSpec(in *v1beta2.RestoreSpec, out *R
 	return autoConvert_v1beta2_RestoreSpec_To__RestoreSpec(in, out, s)
 }
 
+func autoConvert__RestoreStatus_To_v1beta2_RestoreStatus(in *RestoreStatus, out *v1beta2.RestoreStatus, s conversion.Scope) error {
+	out.Phase = v1beta2.RestorePhase(in.Phase)
+	out.StartTime = (*metav1.Time)(unsafe.Pointer(in.StartTime))
+	out.DumpKey = in.DumpKey
+	out.BinlogKey = in.BinlogKey
+	out.Segments = in.Segments
+	out.DownloadedBytes = in.DownloadedBytes
+	out.LoadedBytes = in.LoadedBytes
+	out.GTIDSet = in.GTIDSet
+	out.Message = in.Message
+	return nil
+}
+
+// Convert__RestoreStatus_To_v1beta2_RestoreStatus is an autogenerated conversion function.
+func Convert__RestoreStatus_To_v1beta2_RestoreStatus(in *RestoreStatus, out *v1beta2.RestoreStatus, s conversion.Scope) error {
+	return autoConvert__RestoreStatus_To_v1beta2_RestoreStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_RestoreStatus_To__RestoreStatus(in *v1beta2.RestoreStatus, out *RestoreStatus, s conversion.Scope) error {
+	out.Phase = RestorePhase(in.Phase)
+	out.StartTime = (*metav1.Time)(unsafe.Pointer(in.StartTime))
+	out.DumpKey = in.DumpKey
+	out.BinlogKey = in.BinlogKey
+	out.Segments = in.Segments
+	out.DownloadedBytes = in.DownloadedBytes
+	out.LoadedBytes = in.LoadedBytes
+	out.GTIDSet = in.GTIDSet
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1beta2_RestoreStatus_To__RestoreStatus is an autogenerated conversion function.
+func Convert_v1beta2_RestoreStatus_To__RestoreStatus(in *v1beta2.RestoreStatus, out *RestoreStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_RestoreStatus_To__RestoreStatus(in, out, s)
+}
+
+func autoConvert__RetentionPolicy_To_v1beta2_RetentionPolicy(in *RetentionPolicy, out *v1beta2.RetentionPolicy, s conversion.Scope) error {
+	out.KeepLast = in.KeepLast
+	out.KeepFor = (*metav1.Duration)(unsafe.Pointer(in.KeepFor))
+	out.KeepDaily = in.KeepDaily
+	out.KeepWeekly = in.KeepWeekly
+	out.KeepMonthly = in.KeepMonthly
+	return nil
+}
+
+// Convert__RetentionPolicy_To_v1beta2_RetentionPolicy is an autogenerated conversion function.
+func Convert__RetentionPolicy_To_v1beta2_RetentionPolicy(in *RetentionPolicy, out *v1beta2.RetentionPolicy, s conversion.Scope) error {
+	return autoConvert__RetentionPolicy_To_v1beta2_RetentionPolicy(in, out, s)
+}
+
+func autoConvert_v1beta2_RetentionPolicy_To__RetentionPolicy(in *v1beta2.RetentionPolicy, out *RetentionPolicy, s conversion.Scope) error {
+	out.KeepLast = in.KeepLast
+	out.KeepFor = (*metav1.Duration)(unsafe.Pointer(in.KeepFor))
+	out.KeepDaily = in.KeepDaily
+	out.KeepWeekly = in.KeepWeekly
+	out.KeepMonthly = in.KeepMonthly
+	return nil
+}
+
+// Convert_v1beta2_RetentionPolicy_To__RetentionPolicy is an autogenerated conversion function.
+func Convert_v1beta2_RetentionPolicy_To__RetentionPolicy(in *v1beta2.RetentionPolicy, out *RetentionPolicy, s conversion.Scope) error {
+	return autoConvert_v1beta2_RetentionPolicy_To__RetentionPolicy(in, out, s)
+}
+
+func autoConvert__SecondaryBucket_To_v1beta2_SecondaryBucket(in *SecondaryBucket, out *v1beta2.SecondaryBucket, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert__BucketConfig_To_v1beta2_BucketConfig(&in.BucketConfig, &out.BucketConfig, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert__SecondaryBucket_To_v1beta2_SecondaryBucket is an autogenerated conversion function.
+func Convert__SecondaryBucket_To_v1beta2_SecondaryBucket(in *SecondaryBucket, out *v1beta2.SecondaryBucket, s conversion.Scope) error {
+	return autoConvert__SecondaryBucket_To_v1beta2_SecondaryBucket(in, out, s)
+}
+
+func autoConvert_v1beta2_SecondaryBucket_To__SecondaryBucket(in *v1beta2.SecondaryBucket, out *SecondaryBucket, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert_v1beta2_BucketConfig_To__BucketConfig(&in.BucketConfig, &out.BucketConfig, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_SecondaryBucket_To__SecondaryBucket is an autogenerated conversion function.
+func Convert_v1beta2_SecondaryBucket_To__SecondaryBucket(in *v1beta2.SecondaryBucket, out *SecondaryBucket, s conversion.Scope) error {
+	return autoConvert_v1beta2_SecondaryBucket_To__SecondaryBucket(in, out, s)
+}
+
+func autoConvert__SecurityContextApplyConfiguration_To_v1beta2_SecurityContextApplyConfiguration(in *SecurityContextApplyConfiguration, out *v1beta2.SecurityContextApplyConfiguration, s conversion.Scope) error {
+	out.Capabilities = (*v1.CapabilitiesApplyConfiguration)(unsafe.Pointer(in.Capabilities))
+	out.Privileged = (*bool)(unsafe.Pointer(in.Privileged))
+	out.SELinuxOptions = (*v1.SELinuxOptionsApplyConfiguration)(unsafe.Pointer(in.SELinuxOptions))
+	out.WindowsOptions = (*v1.WindowsSecurityContextOptionsApplyConfiguration)(unsafe.Pointer(in.WindowsOptions))
+	out.RunAsUser = (*int64)(unsafe.Pointer(in.RunAsUser))
+	out.RunAsGroup = (*int64)(unsafe.Pointer(in.RunAsGroup))
+	out.RunAsNonRoot = (*bool)(unsafe.Pointer(in.RunAsNonRoot))
+	out.ReadOnlyRootFilesystem = (*bool)(unsafe.Pointer(in.ReadOnlyRootFilesystem))
+	out.AllowPrivilegeEscalation = (*bool)(unsafe.Pointer(in.AllowPrivilegeEscalation))
+	out.ProcMount = (*corev1.ProcMountType)(unsafe.Pointer(in.ProcMount))
+	out.SeccompProfile = (*v1.SeccompProfileApplyConfiguration)(unsafe.Pointer(in.SeccompProfile))
+	return nil
+}
+
+// Convert__SecurityContextApplyConfiguration_To_v1beta2_SecurityContextApplyConfiguration is an autogenerated conversion function.
+func Convert__SecurityContextApplyConfiguration_To_v1beta2_SecurityContextApplyConfiguration(in *SecurityContextApplyConfiguration, out *v1beta2.SecurityContextApplyConfiguration, s conversion.Scope) error {
+	return autoConvert__SecurityContextApplyConfiguration_To_v1beta2_SecurityContextApplyConfiguration(in, out, s)
+}
+
+func autoConvert_v1beta2_SecurityContextApplyConfiguration_To__SecurityContextApplyConfiguration(in *v1beta2.SecurityContextApplyConfiguration, out *SecurityContextApplyConfiguration, s conversion.Scope) error {
+	out.Capabilities = (*v1.CapabilitiesApplyConfiguration)(unsafe.Pointer(in.Capabilities))
+	out.Privileged = (*bool)(unsafe.Pointer(in.Privileged))
+	out.SELinuxOptions = (*v1.SELinuxOptionsApplyConfiguration)(unsafe.Pointer(in.SELinuxOptions))
+	out.WindowsOptions = (*v1.WindowsSecurityContextOptionsApplyConfiguration)(unsafe.Pointer(in.WindowsOptions))
+	out.RunAsUser = (*int64)(unsafe.Pointer(in.RunAsUser))
+	out.RunAsGroup = (*int64)(unsafe.Pointer(in.RunAsGroup))
+	out.RunAsNonRoot = (*bool)(unsafe.Pointer(in.RunAsNonRoot))
+	out.ReadOnlyRootFilesystem = (*bool)(unsafe.Pointer(in.ReadOnlyRootFilesystem))
+	out.AllowPrivilegeEscalation = (*bool)(unsafe.Pointer(in.AllowPrivilegeEscalation))
+	out.ProcMount = (*corev1.ProcMountType)(unsafe.Pointer(in.ProcMount))
+	out.SeccompProfile = (*v1.SeccompProfileApplyConfiguration)(unsafe.Pointer(in.SeccompProfile))
+	return nil
+}
+
+// Convert_v1beta2_SecurityContextApplyConfiguration_To__SecurityContextApplyConfiguration is an autogenerated conversion function.
+func Convert_v1beta2_SecurityContextApplyConfiguration_To__SecurityContextApplyConfiguration(in *v1beta2.SecurityContextApplyConfiguration, out *SecurityContextApplyConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta2_SecurityContextApplyConfiguration_To__SecurityContextApplyConfiguration(in, out, s)
+}
+
 func autoConvert__ServiceSpecApplyConfiguration_To_v1beta2_ServiceSpecApplyConfiguration(in *ServiceSpecApplyConfiguration, out *v1beta2.ServiceSpecApplyConfiguration, s conversion.Scope) error {
 	out.Ports = *(*[]v1.ServicePortApplyConfiguration)(unsafe.Pointer(&in.Ports))
 	out.Selector = *(*map[string]string)(unsafe.Pointer(&in.Selector))
@@ -1014,6 +2038,106 @@ func Convert_v1beta2_ServiceTemplate_To__ServiceTemplate(in *v1beta2.ServiceTemp
 	return autoConvert_v1beta2_ServiceTemplate_To__ServiceTemplate(in, out, s)
 }
 
+func autoConvert__SnapshotSpec_To_v1beta2_SnapshotSpec(in *SnapshotSpec, out *v1beta2.SnapshotSpec, s conversion.Scope) error {
+	out.VolumeSnapshotClassName = in.VolumeSnapshotClassName
+	return nil
+}
+
+// Convert__SnapshotSpec_To_v1beta2_SnapshotSpec is an autogenerated conversion function.
+func Convert__SnapshotSpec_To_v1beta2_SnapshotSpec(in *SnapshotSpec, out *v1beta2.SnapshotSpec, s conversion.Scope) error {
+	return autoConvert__SnapshotSpec_To_v1beta2_SnapshotSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_SnapshotSpec_To__SnapshotSpec(in *v1beta2.SnapshotSpec, out *SnapshotSpec, s conversion.Scope) error {
+	out.VolumeSnapshotClassName = in.VolumeSnapshotClassName
+	return nil
+}
+
+// Convert_v1beta2_SnapshotSpec_To__SnapshotSpec is an autogenerated conversion function.
+func Convert_v1beta2_SnapshotSpec_To__SnapshotSpec(in *v1beta2.SnapshotSpec, out *SnapshotSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_SnapshotSpec_To__SnapshotSpec(in, out, s)
+}
+
+func autoConvert__TolerationApplyConfiguration_To_v1beta2_TolerationApplyConfiguration(in *TolerationApplyConfiguration, out *v1beta2.TolerationApplyConfiguration, s conversion.Scope) error {
+	out.Key = (*string)(unsafe.Pointer(in.Key))
+	out.Operator = (*corev1.TolerationOperator)(unsafe.Pointer(in.Operator))
+	out.Value = (*string)(unsafe.Pointer(in.Value))
+	out.Effect = (*corev1.TaintEffect)(unsafe.Pointer(in.Effect))
+	out.TolerationSeconds = (*int64)(unsafe.Pointer(in.TolerationSeconds))
+	return nil
+}
+
+// Convert__TolerationApplyConfiguration_To_v1beta2_TolerationApplyConfiguration is an autogenerated conversion function.
+func Convert__TolerationApplyConfiguration_To_v1beta2_TolerationApplyConfiguration(in *TolerationApplyConfiguration, out *v1beta2.TolerationApplyConfiguration, s conversion.Scope) error {
+	return autoConvert__TolerationApplyConfiguration_To_v1beta2_TolerationApplyConfiguration(in, out, s)
+}
+
+func autoConvert_v1beta2_TolerationApplyConfiguration_To__TolerationApplyConfiguration(in *v1beta2.TolerationApplyConfiguration, out *TolerationApplyConfiguration, s conversion.Scope) error {
+	out.Key = (*string)(unsafe.Pointer(in.Key))
+	out.Operator = (*corev1.TolerationOperator)(unsafe.Pointer(in.Operator))
+	out.Value = (*string)(unsafe.Pointer(in.Value))
+	out.Effect = (*corev1.TaintEffect)(unsafe.Pointer(in.Effect))
+	out.TolerationSeconds = (*int64)(unsafe.Pointer(in.TolerationSeconds))
+	return nil
+}
+
+// Convert_v1beta2_TolerationApplyConfiguration_To__TolerationApplyConfiguration is an autogenerated conversion function.
+func Convert_v1beta2_TolerationApplyConfiguration_To__TolerationApplyConfiguration(in *v1beta2.TolerationApplyConfiguration, out *TolerationApplyConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta2_TolerationApplyConfiguration_To__TolerationApplyConfiguration(in, out, s)
+}
+
+func autoConvert__VolumeApplyConfiguration_To_v1beta2_VolumeApplyConfiguration(in *VolumeApplyConfiguration, out *v1beta2.VolumeApplyConfiguration, s conversion.Scope) error {
+	out.Name = (*string)(unsafe.Pointer(in.Name))
+	out.VolumeSourceApplyConfiguration = in.VolumeSourceApplyConfiguration
+	return nil
+}
+
+// Convert__VolumeApplyConfiguration_To_v1beta2_VolumeApplyConfiguration is an autogenerated conversion function.
+func Convert__VolumeApplyConfiguration_To_v1beta2_VolumeApplyConfiguration(in *VolumeApplyConfiguration, out *v1beta2.VolumeApplyConfiguration, s conversion.Scope) error {
+	return autoConvert__VolumeApplyConfiguration_To_v1beta2_VolumeApplyConfiguration(in, out, s)
+}
+
+func autoConvert_v1beta2_VolumeApplyConfiguration_To__VolumeApplyConfiguration(in *v1beta2.VolumeApplyConfiguration, out *VolumeApplyConfiguration, s conversion.Scope) error {
+	out.Name = (*string)(unsafe.Pointer(in.Name))
+	out.VolumeSourceApplyConfiguration = in.VolumeSourceApplyConfiguration
+	return nil
+}
+
+// Convert_v1beta2_VolumeApplyConfiguration_To__VolumeApplyConfiguration is an autogenerated conversion function.
+func Convert_v1beta2_VolumeApplyConfiguration_To__VolumeApplyConfiguration(in *v1beta2.VolumeApplyConfiguration, out *VolumeApplyConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta2_VolumeApplyConfiguration_To__VolumeApplyConfiguration(in, out, s)
+}
+
+func autoConvert__VolumeMountApplyConfiguration_To_v1beta2_VolumeMountApplyConfiguration(in *VolumeMountApplyConfiguration, out *v1beta2.VolumeMountApplyConfiguration, s conversion.Scope) error {
+	out.Name = (*string)(unsafe.Pointer(in.Name))
+	out.ReadOnly = (*bool)(unsafe.Pointer(in.ReadOnly))
+	out.MountPath = (*string)(unsafe.Pointer(in.MountPath))
+	out.SubPath = (*string)(unsafe.Pointer(in.SubPath))
+	out.MountPropagation = (*corev1.MountPropagationMode)(unsafe.Pointer(in.MountPropagation))
+	out.SubPathExpr = (*string)(unsafe.Pointer(in.SubPathExpr))
+	return nil
+}
+
+// Convert__VolumeMountApplyConfiguration_To_v1beta2_VolumeMountApplyConfiguration is an autogenerated conversion function.
+func Convert__VolumeMountApplyConfiguration_To_v1beta2_VolumeMountApplyConfiguration(in *VolumeMountApplyConfiguration, out *v1beta2.VolumeMountApplyConfiguration, s conversion.Scope) error {
+	return autoConvert__VolumeMountApplyConfiguration_To_v1beta2_VolumeMountApplyConfiguration(in, out, s)
+}
+
+func autoConvert_v1beta2_VolumeMountApplyConfiguration_To__VolumeMountApplyConfiguration(in *v1beta2.VolumeMountApplyConfiguration, out *VolumeMountApplyConfiguration, s conversion.Scope) error {
+	out.Name = (*string)(unsafe.Pointer(in.Name))
+	out.ReadOnly = (*bool)(unsafe.Pointer(in.ReadOnly))
+	out.MountPath = (*string)(unsafe.Pointer(in.MountPath))
+	out.SubPath = (*string)(unsafe.Pointer(in.SubPath))
+	out.MountPropagation = (*corev1.MountPropagationMode)(unsafe.Pointer(in.MountPropagation))
+	out.SubPathExpr = (*string)(unsafe.Pointer(in.SubPathExpr))
+	return nil
+}
+
+// Convert_v1beta2_VolumeMountApplyConfiguration_To__VolumeMountApplyConfiguration is an autogenerated conversion function.
+func Convert_v1beta2_VolumeMountApplyConfiguration_To__VolumeMountApplyConfiguration(in *v1beta2.VolumeMountApplyConfiguration, out *VolumeMountApplyConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta2_VolumeMountApplyConfiguration_To__VolumeMountApplyConfiguration(in, out, s)
+}
+
 func autoConvert__VolumeSourceApplyConfiguration_To_v1beta2_VolumeSourceApplyConfiguration(in *VolumeSourceApplyConfiguration, out *v1beta2.VolumeSourceApplyConfiguration, s conversion.Scope) error {
 	out.HostPath = (*v1.HostPathVolumeSourceApplyConfiguration)(unsafe.Pointer(in.HostPath))
 	out.EmptyDir = (*v1.EmptyDirVolumeSourceApplyConfiguration)(unsafe.Pointer(in.EmptyDir))
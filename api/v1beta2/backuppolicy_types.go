@@ -1,8 +1,14 @@
 package v1beta2
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cybozu-go/moco/pkg/constants"
 	cron "github.com/robfig/cron/v3"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -32,6 +38,15 @@ type BackupPolicySpec struct {
 	// See https://en.wikipedia.org/wiki/Cron
 	Schedule string `json:"schedule"`
 
+	// BinlogSchedule is the schedule in Cron format for binlog-only backups.
+	// If specified, MOCO creates another CronJob that uploads the binary logs
+	// since the last backup without taking a full dump.  This allows taking
+	// expensive full dumps less frequently while archiving binary logs often.
+	// Binlog-only backups never run concurrently with each other, and the schedule
+	// should not overlap with that of full backups.
+	// +optional
+	BinlogSchedule string `json:"binlogSchedule,omitempty"`
+
 	// Specifies parameters for backup Pod.
 	JobConfig JobConfig `json:"jobConfig"`
 
@@ -82,6 +97,299 @@ type BackupPolicySpec struct {
 	// +nullable
 	// +optional
 	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+
+	// Retention specifies which backups to keep in the bucket and the secondary buckets.
+	// If not specified, MOCO does not remove any backups.
+	// +optional
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+
+	// BinlogArchive enables continuous archiving of binary logs.
+	// If specified, MOCO runs a Deployment that uploads binary logs to
+	// the bucket periodically, in addition to the scheduled backups.
+	// +optional
+	BinlogArchive *BinlogArchiveSpec `json:"binlogArchive,omitempty"`
+
+	// Source specifies how to choose the instance to take backups from.
+	// If not specified, MOCO prefers the last source instance, then other
+	// ready replicas, and falls back to the primary instance.
+	// +optional
+	Source *BackupSourcePolicy `json:"source,omitempty"`
+
+	// Hooks specifies actions to be run before and after each backup.
+	// +optional
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+
+	// Snapshot makes backups take CSI VolumeSnapshots of the data volume of
+	// a replica instance instead of full dumps.  Binary logs are still uploaded
+	// to the bucket, so data can be restored to a point after a snapshot.
+	// +optional
+	Snapshot *SnapshotSpec `json:"snapshot,omitempty"`
+
+	// SecondaryBuckets are the buckets to which the backup Job copies finished
+	// backups, e.g. in another region or provider for disaster recovery.
+	// The copies are verified with the checksums recorded in the manifests.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SecondaryBuckets []SecondaryBucket `json:"secondaryBuckets,omitempty"`
+
+	// RestoreDrill periodically restores the latest backup into a scratch
+	// MySQLCluster and runs assertions against it to verify that backups are usable.
+	// The result is recorded in `status.restoreDrill` of MySQLCluster.
+	// +optional
+	RestoreDrill *RestoreDrillSpec `json:"restoreDrill,omitempty"`
+}
+
+// RestoreDrillSpec specifies a periodic restore drill.
+//
+// A drill creates a MySQLCluster named `moco-drill-<name>` with one instance
+// in the same namespace, restores data to the latest restorable point, runs
+// the assertions on the instance, and deletes the MySQLCluster and its volumes.
+type RestoreDrillSpec struct {
+	// Schedule is the schedule in Cron format for restore drills.
+	Schedule string `json:"schedule"`
+
+	// Assertions are run in order on the restored instance.
+	// A drill succeeds if the restoration finishes and all assertions pass.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Assertions []RestoreDrillAssertion `json:"assertions,omitempty"`
+
+	// Timeout is the time limit for a drill from the creation of the scratch
+	// MySQLCluster to the end of the assertions.
+	// +kubebuilder:default="6h"
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// RestoreDrillAssertion is a SQL query whose result is checked after a restoration.
+//
+// The query is executed by the backup user in a read-only transaction, and the
+// last column of the first row is compared.  NULL is compared as "NULL".
+// If none of Expect, Min, or Max is specified, the query only needs to succeed.
+type RestoreDrillAssertion struct {
+	// Name is the name of the assertion used in the status and events.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Query is a SQL query such as `SELECT COUNT(*) FROM db.t` or `CHECKSUM TABLE db.t`.
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+
+	// Expect is the expected value of the result.
+	// +optional
+	Expect *string `json:"expect,omitempty"`
+
+	// Min is the minimum value of the result as an integer.
+	// +optional
+	Min *int64 `json:"min,omitempty"`
+
+	// Max is the maximum value of the result as an integer.
+	// +optional
+	Max *int64 `json:"max,omitempty"`
+}
+
+// SecondaryBucket is a bucket to which backups are copied.
+//
+// The bucket is accessed with the credentials given to the Job by the
+// environment variables, and the backups are encrypted with the same key
+// as the primary bucket.
+type SecondaryBucket struct {
+	// Name identifies the bucket in `spec.restore.secondaryBucketName` of MySQLCluster.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// BucketConfig specifies how to access the bucket.
+	// "file" backend and `tls` are not supported.
+	BucketConfig BucketConfig `json:"bucketConfig"`
+}
+
+// SnapshotSpec specifies how to take snapshot backups.
+//
+// A snapshot backup holds a global read lock on a replica instance, which
+// also pauses its replication, until the snapshot is cut.  The GTID set
+// executed at that point is recorded with the snapshot.
+// VolumeSnapshots are created in the namespace of the MySQLCluster.
+type SnapshotSpec struct {
+	// VolumeSnapshotClassName is the name of the VolumeSnapshotClass.
+	// If not specified, the default class is used.
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// BackupHooks is a set of hooks run around a backup.
+type BackupHooks struct {
+	// PreBackup hooks are run in order on the chosen source instance
+	// before taking a full dump.
+	// +optional
+	PreBackup []BackupHook `json:"preBackup,omitempty"`
+
+	// PostBackup hooks are run in order after the backup files are uploaded
+	// and before the status is updated.
+	// +optional
+	PostBackup []BackupHook `json:"postBackup,omitempty"`
+}
+
+// HookFailurePolicy is the action taken when a hook fails.
+// +kubebuilder:validation:Enum=Abort;Warn
+type HookFailurePolicy string
+
+const (
+	// HookAbort fails the backup if the hook fails.
+	HookAbort = HookFailurePolicy("Abort")
+
+	// HookWarn records the failure in the warnings of the backup and continues.
+	HookWarn = HookFailurePolicy("Warn")
+)
+
+// BackupHook is an action run before or after a backup.
+// At least one of SQL or HTTP must be specified.  If both are specified,
+// SQL statements are executed first.
+type BackupHook struct {
+	// Name is the name of the hook used in logs and warnings.
+	Name string `json:"name"`
+
+	// SQL is a list of statements executed in order on the backup source instance.
+	// The statements are executed by the backup user in a single session.
+	// The source is usually a replica running with `super_read_only`, so
+	// statements that write data fail unless the primary is the source.
+	// +optional
+	SQL []string `json:"sql,omitempty"`
+
+	// HTTP sends the metadata of the backup to an HTTP endpoint.
+	// +optional
+	HTTP *HTTPHook `json:"http,omitempty"`
+
+	// FailurePolicy is the action taken when the hook fails.
+	// +kubebuilder:default=Abort
+	// +optional
+	FailurePolicy HookFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// Timeout is the time limit to run the hook.
+	// +kubebuilder:default="1m"
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// HTTPHook is an HTTP callback.
+// The metadata of the backup is sent in a JSON body with POST method.
+// A response with a status code other than 2xx is a failure.
+type HTTPHook struct {
+	// URL is the URL of the endpoint.  The scheme must be http or https.
+	URL string `json:"url"`
+
+	// Headers are added to the request.
+	// +optional
+	Headers []HTTPHeader `json:"headers,omitempty"`
+}
+
+// HTTPHeader is an HTTP header whose value is read from a Secret.
+// The value is given to the backup Job Pod as an environment variable
+// so that it does not appear in the command line or the Job spec.
+type HTTPHeader struct {
+	// Name is the name of the header.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ValueFrom is the source of the value of the header.
+	ValueFrom HTTPHeaderSource `json:"valueFrom"`
+}
+
+// HTTPHeaderSource is the source of the value of an HTTP header.
+type HTTPHeaderSource struct {
+	// SecretKeyRef selects a key of a Secret in the namespace of the MySQLCluster.
+	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef"`
+}
+
+// HookHeaderEnvName returns the name of the environment variable that holds
+// the value of the header-th header of the hook-th hook in phase.
+// phase is either "preBackup" or "postBackup".
+func HookHeaderEnvName(phase string, hook, header int) string {
+	return fmt.Sprintf("MOCO_HOOK_%s_%d_HEADER_%d", strings.ToUpper(phase), hook, header)
+}
+
+// BackupSourcePolicy is the policy to choose the backup source instance.
+type BackupSourcePolicy struct {
+	// Index pins backups to the instance of this index.
+	// Backups fail if the instance is not ready.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Index *int32 `json:"index,omitempty"`
+
+	// Zone restricts the source to instances running on Nodes in this zone,
+	// that is, Nodes labeled with `topology.kubernetes.io/zone=<zone>`.
+	// moco-controller records the zone in the `moco.cybozu.com/zone` annotation of Pods.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// ExcludePrimary makes backups fail instead of falling back to the
+	// primary instance when no replica instance can be the source.
+	// +optional
+	ExcludePrimary bool `json:"excludePrimary,omitempty"`
+
+	// PreferLeastLag chooses the replica instance with the lowest replication lag.
+	// Otherwise, the last source instance is preferred.
+	// +optional
+	PreferLeastLag bool `json:"preferLeastLag,omitempty"`
+}
+
+// BinlogArchiveSpec specifies how to archive binary logs continuously.
+//
+// Binary logs are read from a replica instance and uploaded as segments.
+// Segments are used with a full dump to restore data to a point after
+// the last backup.
+type BinlogArchiveSpec struct {
+	// Interval is the interval to upload binary logs.  e.g. "5m"
+	// This is the maximum amount of transactions that can be lost
+	// if the whole cluster is lost.
+	// +kubebuilder:default="5m"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// RetentionPolicy is a set of rules to decide which backups to keep.
+// A backup is kept if any of the rules keeps it.  Other backups are deleted
+// after a successful backup.
+//
+// The most recent backup is always kept.
+// A backup consists of a full dump and binlogs taken until the next backup,
+// and they are kept or deleted together.
+type RetentionPolicy struct {
+	// KeepLast keeps the last N backups.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepLast int32 `json:"keepLast,omitempty"`
+
+	// KeepFor keeps backups so that the data can be restored to any point
+	// within the given duration.  e.g. "720h"
+	// +optional
+	KeepFor *metav1.Duration `json:"keepFor,omitempty"`
+
+	// KeepDaily keeps the last backup of each day for the last N days that have backups.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepDaily int32 `json:"keepDaily,omitempty"`
+
+	// KeepWeekly keeps the last backup of each week for the last N weeks that have backups.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepWeekly int32 `json:"keepWeekly,omitempty"`
+
+	// KeepMonthly keeps the last backup of each month for the last N months that have backups.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepMonthly int32 `json:"keepMonthly,omitempty"`
+}
+
+// FindSecondaryBucket returns the secondary bucket named `name`, or nil.
+func (s *BackupPolicySpec) FindSecondaryBucket(name string) *SecondaryBucket {
+	for i := range s.SecondaryBuckets {
+		if s.SecondaryBuckets[i].Name == name {
+			return &s.SecondaryBuckets[i]
+		}
+	}
+	return nil
 }
 
 func (s *BackupPolicySpec) validate() field.ErrorList {
@@ -91,6 +399,129 @@ func (s *BackupPolicySpec) validate() field.ErrorList {
 	if _, err := cron.ParseStandard(s.Schedule); err != nil {
 		allErrs = append(allErrs, field.Invalid(p.Child("schedule"), s.Schedule, err.Error()))
 	}
+	if s.BinlogSchedule != "" {
+		if _, err := cron.ParseStandard(s.BinlogSchedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(p.Child("binlogSchedule"), s.BinlogSchedule, err.Error()))
+		}
+	}
+	allErrs = append(allErrs, s.JobConfig.validate(p.Child("jobConfig"))...)
+	if e := s.JobConfig.Encryption; e != nil && e.KeyID == "" {
+		allErrs = append(allErrs, field.Required(p.Child("jobConfig", "encryption", "keyID"), "keyID is required for backup"))
+	}
+
+	if r := s.Retention; r != nil {
+		pp := p.Child("retention")
+		if r.KeepLast == 0 && r.KeepFor == nil && r.KeepDaily == 0 && r.KeepWeekly == 0 && r.KeepMonthly == 0 {
+			allErrs = append(allErrs, field.Required(pp, "at least one rule must be specified"))
+		}
+		if r.KeepFor != nil && r.KeepFor.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(pp.Child("keepFor"), r.KeepFor.Duration.String(), "must be positive"))
+		}
+	}
+
+	if a := s.BinlogArchive; a != nil && a.Interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(p.Child("binlogArchive", "interval"), a.Interval.Duration.String(), "must be positive"))
+	}
+
+	if src := s.Source; src != nil && src.Index != nil && src.Zone != "" {
+		allErrs = append(allErrs, field.Forbidden(p.Child("source", "zone"), "zone cannot be specified with index"))
+	}
+
+	if s.Snapshot != nil {
+		if s.JobConfig.Filter != nil {
+			allErrs = append(allErrs, field.Forbidden(p.Child("jobConfig", "filter"), "filter cannot be used with snapshot"))
+		}
+		if s.JobConfig.Streaming {
+			allErrs = append(allErrs, field.Forbidden(p.Child("jobConfig", "streaming"), "streaming cannot be used with snapshot"))
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, b := range s.SecondaryBuckets {
+		pp := p.Child("secondaryBuckets").Index(i)
+		if names[b.Name] {
+			allErrs = append(allErrs, field.Duplicate(pp.Child("name"), b.Name))
+		}
+		names[b.Name] = true
+		allErrs = append(allErrs, b.BucketConfig.validate(pp.Child("bucketConfig"))...)
+		if b.BucketConfig.BackendType == constants.BackendTypeFile {
+			allErrs = append(allErrs, field.NotSupported(pp.Child("bucketConfig", "backendType"), b.BucketConfig.BackendType,
+				[]string{constants.BackendTypeS3, constants.BackendTypeGCS, constants.BackendTypeAzure}))
+		}
+		if b.BucketConfig.TLS != nil {
+			allErrs = append(allErrs, field.Forbidden(pp.Child("bucketConfig", "tls"), "tls is not supported for secondary buckets"))
+		}
+	}
+
+	if d := s.RestoreDrill; d != nil {
+		pp := p.Child("restoreDrill")
+		if _, err := cron.ParseStandard(d.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(pp.Child("schedule"), d.Schedule, err.Error()))
+		}
+		if d.Timeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(pp.Child("timeout"), d.Timeout.Duration.String(), "must be positive"))
+		}
+		names := make(map[string]bool)
+		for i, a := range d.Assertions {
+			ppp := pp.Child("assertions").Index(i)
+			if names[a.Name] {
+				allErrs = append(allErrs, field.Duplicate(ppp.Child("name"), a.Name))
+			}
+			names[a.Name] = true
+			if a.Min != nil && a.Max != nil && *a.Min > *a.Max {
+				allErrs = append(allErrs, field.Invalid(ppp.Child("max"), *a.Max, "must not be less than min"))
+			}
+		}
+	}
+
+	if h := s.Hooks; h != nil {
+		for i, hook := range h.PreBackup {
+			allErrs = append(allErrs, hook.validate(p.Child("hooks", "preBackup").Index(i))...)
+		}
+		for i, hook := range h.PostBackup {
+			allErrs = append(allErrs, hook.validate(p.Child("hooks", "postBackup").Index(i))...)
+		}
+	}
+
+	return allErrs
+}
+
+func (h BackupHook) validate(p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if h.Name == "" {
+		allErrs = append(allErrs, field.Required(p.Child("name"), "name is required"))
+	}
+	if len(h.SQL) == 0 && h.HTTP == nil {
+		allErrs = append(allErrs, field.Required(p, "either sql or http must be specified"))
+	}
+	if h.HTTP != nil {
+		u, err := url.Parse(h.HTTP.URL)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(p.Child("http", "url"), h.HTTP.URL, err.Error()))
+		case u.Scheme != "http" && u.Scheme != "https":
+			allErrs = append(allErrs, field.Invalid(p.Child("http", "url"), h.HTTP.URL, "scheme must be http or https"))
+		case u.Host == "":
+			allErrs = append(allErrs, field.Invalid(p.Child("http", "url"), h.HTTP.URL, "host is required"))
+		}
+		for i, hdr := range h.HTTP.Headers {
+			pp := p.Child("http", "headers").Index(i)
+			if hdr.Name == "" {
+				allErrs = append(allErrs, field.Required(pp.Child("name"), "name is required"))
+			}
+			ref := hdr.ValueFrom.SecretKeyRef
+			if ref.Name == "" {
+				allErrs = append(allErrs, field.Required(pp.Child("valueFrom", "secretKeyRef", "name"), "name is required"))
+			}
+			if ref.Key == "" {
+				allErrs = append(allErrs, field.Required(pp.Child("valueFrom", "secretKeyRef", "key"), "key is required"))
+			}
+		}
+	}
+	if h.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(p.Child("timeout"), h.Timeout.Duration.String(), "must be positive"))
+	}
 
 	return allErrs
 }
@@ -9,15 +9,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-var apiReader client.Reader
-
 func (r *BackupPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	apiReader = mgr.GetAPIReader()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&backupPolicyValidator{reader: mgr.GetAPIReader()}).
 		Complete()
 }
 
@@ -25,10 +23,17 @@ func (r *BackupPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 //+kubebuilder:webhook:path=/validate-moco-cybozu-com-v1beta2-backuppolicy,mutating=false,failurePolicy=fail,sideEffects=None,matchPolicy=Equivalent,groups=moco.cybozu.com,resources=backuppolicies,verbs=create;update;delete,versions=v1beta2,name=vbackuppolicy.kb.io,admissionReviewVersions=v1
 
-var _ webhook.Validator = &BackupPolicy{}
+// backupPolicyValidator validates BackupPolicy.
+// It lists MySQLClusters that refer to the policy with `reader`.
+type backupPolicyValidator struct {
+	reader client.Reader
+}
+
+var _ admission.CustomValidator = &backupPolicyValidator{}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *BackupPolicy) ValidateCreate() error {
+// ValidateCreate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *backupPolicyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	r := obj.(*BackupPolicy)
 	errs := r.Spec.validate()
 	if len(errs) == 0 {
 		return nil
@@ -37,15 +42,16 @@ func (r *BackupPolicy) ValidateCreate() error {
 	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "BackupPolicy"}, r.Name, errs)
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *BackupPolicy) ValidateUpdate(old runtime.Object) error {
-	return r.ValidateCreate()
+// ValidateUpdate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *backupPolicyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return v.ValidateCreate(ctx, newObj)
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *BackupPolicy) ValidateDelete() error {
+// ValidateDelete implements admission.CustomValidator so a webhook will be registered for the type
+func (v *backupPolicyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	r := obj.(*BackupPolicy)
 	clusters := &MySQLClusterList{}
-	if err := apiReader.List(context.Background(), clusters, client.InNamespace(r.Namespace)); err != nil {
+	if err := v.reader.List(ctx, clusters, client.InNamespace(r.Namespace)); err != nil {
 		return err
 	}
 
@@ -2,12 +2,16 @@ package v1beta2_test
 
 import (
 	"context"
+	"time"
 
 	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -72,6 +76,66 @@ var _ = Describe("BackupPolicy Webhook", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("should create BackupPolicy with binlogSchedule", func() {
+		r := makeBackupPolicy()
+		r.Spec.BinlogSchedule = "*/10 * * * *"
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with invalid binlogSchedule", func() {
+		r := makeBackupPolicy()
+		r.Spec.BinlogSchedule = "every 10 minutes"
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with restoreDrill", func() {
+		r := makeBackupPolicy()
+		r.Spec.RestoreDrill = &mocov1beta2.RestoreDrillSpec{
+			Schedule: "0 12 * * 6",
+			Assertions: []mocov1beta2.RestoreDrillAssertion{
+				{Name: "count", Query: "SELECT COUNT(*) FROM db.t", Min: pointer.Int64(1), Max: pointer.Int64(100)},
+				{Name: "checksum", Query: "CHECKSUM TABLE db.t", Expect: pointer.String("12345")},
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Spec.RestoreDrill.Timeout.Duration).To(Equal(6 * time.Hour))
+	})
+
+	It("should deny BackupPolicy with invalid restoreDrill schedule", func() {
+		r := makeBackupPolicy()
+		r.Spec.RestoreDrill = &mocov1beta2.RestoreDrillSpec{Schedule: "every saturday"}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with duplicate restoreDrill assertions", func() {
+		r := makeBackupPolicy()
+		r.Spec.RestoreDrill = &mocov1beta2.RestoreDrillSpec{
+			Schedule: "0 12 * * 6",
+			Assertions: []mocov1beta2.RestoreDrillAssertion{
+				{Name: "count", Query: "SELECT COUNT(*) FROM db.t"},
+				{Name: "count", Query: "SELECT COUNT(*) FROM db.u"},
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with restoreDrill assertion whose min exceeds max", func() {
+		r := makeBackupPolicy()
+		r.Spec.RestoreDrill = &mocov1beta2.RestoreDrillSpec{
+			Schedule: "0 12 * * 6",
+			Assertions: []mocov1beta2.RestoreDrillAssertion{
+				{Name: "count", Query: "SELECT COUNT(*) FROM db.t", Min: pointer.Int64(10), Max: pointer.Int64(1)},
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("should deny BackupPolicy with invalid backoffLimit", func() {
 		r := makeBackupPolicy()
 		r.Spec.BackoffLimit = pointer.Int32(-1)
@@ -93,6 +157,415 @@ var _ = Describe("BackupPolicy Webhook", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("should create BackupPolicy with file backend", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.BackendType = "file"
+		r.Spec.JobConfig.BucketConfig.Volume = &mocov1beta2.VolumeSourceApplyConfiguration{
+			PersistentVolumeClaim: &corev1ac.PersistentVolumeClaimVolumeSourceApplyConfiguration{
+				ClaimName: pointer.String("backup"),
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with file backend but without volume", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.BackendType = "file"
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with azure backend", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.BackendType = "azure"
+		r.Spec.JobConfig.BucketConfig.AccountName = "myaccount"
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with azure backend but without accountName", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.BackendType = "azure"
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with invalid backendType", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.BackendType = "invalid"
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with retention", func() {
+		r := makeBackupPolicy()
+		r.Spec.Retention = &mocov1beta2.RetentionPolicy{
+			KeepLast: 3,
+			KeepFor:  &metav1.Duration{Duration: 24 * time.Hour},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with empty retention", func() {
+		r := makeBackupPolicy()
+		r.Spec.Retention = &mocov1beta2.RetentionPolicy{}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with invalid retention", func() {
+		r := makeBackupPolicy()
+		r.Spec.Retention = &mocov1beta2.RetentionPolicy{KeepLast: -1}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+
+		r = makeBackupPolicy()
+		r.Spec.Retention = &mocov1beta2.RetentionPolicy{
+			KeepFor: &metav1.Duration{Duration: -time.Hour},
+		}
+		err = k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with encryption", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.Encryption = &mocov1beta2.EncryptionConfig{
+			SecretName: "backup-keys",
+			KeyID:      "key1",
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with encryption but without keyID", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.Encryption = &mocov1beta2.EncryptionConfig{
+			SecretName: "backup-keys",
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with filter", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.Filter = &mocov1beta2.FilterConfig{
+			IncludeSchemas: []string{"foo", "bar"},
+			ExcludeTables:  []string{"foo.cache"},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with invalid table filter", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.Filter = &mocov1beta2.FilterConfig{
+			ExcludeTables: []string{"cache"},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with upload parameters", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.UploadConcurrency = 4
+		partSize := resource.MustParse("16Mi")
+		r.Spec.JobConfig.BucketConfig.PartSize = &partSize
+		rateLimit := resource.MustParse("100Mi")
+		r.Spec.JobConfig.BucketConfig.RateLimit = &rateLimit
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with too small partSize", func() {
+		r := makeBackupPolicy()
+		partSize := resource.MustParse("1Mi")
+		r.Spec.JobConfig.BucketConfig.PartSize = &partSize
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with zero rateLimit", func() {
+		r := makeBackupPolicy()
+		rateLimit := resource.MustParse("0")
+		r.Spec.JobConfig.BucketConfig.RateLimit = &rateLimit
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with S3 object parameters", func() {
+		r := makeBackupPolicy()
+		bc := &r.Spec.JobConfig.BucketConfig
+		bc.ServerSideEncryption = "aws:kms"
+		bc.KMSKeyID = "arn:aws:kms:us-east-1:123456789012:key/test"
+		bc.StorageClass = "STANDARD_IA"
+		bc.Tags = map[string]string{"team": "db"}
+		bc.ObjectLock = &mocov1beta2.ObjectLockConfig{Mode: "COMPLIANCE", RetainFor: metav1.Duration{Duration: 720 * time.Hour}}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with kmsKeyID without aws:kms encryption", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.ServerSideEncryption = "AES256"
+		r.Spec.JobConfig.BucketConfig.KMSKeyID = "key"
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with invalid objectLock", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.ObjectLock = &mocov1beta2.ObjectLockConfig{Mode: "GOVERNANCE"}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+
+		r = makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.ObjectLock = &mocov1beta2.ObjectLockConfig{Mode: "FOREVER", RetainFor: metav1.Duration{Duration: time.Hour}}
+		err = k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with S3 object parameters for other backends", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.BackendType = "gcs"
+		r.Spec.JobConfig.BucketConfig.StorageClass = "NEARLINE"
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with TLS parameters", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.TLS = &mocov1beta2.BucketTLSConfig{
+			CABundle:             &mocov1beta2.CABundleSource{ConfigMapName: "bucket-ca"},
+			ClientCertSecretName: "bucket-client-cert",
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Spec.JobConfig.BucketConfig.TLS.CABundle.Key).To(Equal("ca.crt"))
+	})
+
+	It("should deny BackupPolicy with invalid caBundle", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.TLS = &mocov1beta2.BucketTLSConfig{
+			CABundle: &mocov1beta2.CABundleSource{},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+
+		r = makeBackupPolicy()
+		r.Spec.JobConfig.BucketConfig.TLS = &mocov1beta2.BucketTLSConfig{
+			CABundle: &mocov1beta2.CABundleSource{ConfigMapName: "bucket-ca", SecretName: "bucket-ca"},
+		}
+		err = k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with podTemplate", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.PodTemplate = &mocov1beta2.JobPodTemplate{
+			NodeSelector:      map[string]string{"node-role": "backup"},
+			PriorityClassName: "backup",
+			Volumes: []mocov1beta2.VolumeApplyConfiguration{{
+				Name:                           pointer.String("tmp"),
+				VolumeSourceApplyConfiguration: corev1ac.VolumeSourceApplyConfiguration{EmptyDir: corev1ac.EmptyDirVolumeSource()},
+			}},
+			VolumeMounts: []mocov1beta2.VolumeMountApplyConfiguration{{
+				Name:      pointer.String("tmp"),
+				MountPath: pointer.String("/tmp"),
+			}},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with reserved volume names in podTemplate", func() {
+		r := makeBackupPolicy()
+		r.Spec.JobConfig.PodTemplate = &mocov1beta2.JobPodTemplate{
+			Volumes: []mocov1beta2.VolumeApplyConfiguration{{
+				Name:                           pointer.String("work"),
+				VolumeSourceApplyConfiguration: corev1ac.VolumeSourceApplyConfiguration{EmptyDir: corev1ac.EmptyDirVolumeSource()},
+			}},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+
+		r = makeBackupPolicy()
+		r.Spec.JobConfig.PodTemplate = &mocov1beta2.JobPodTemplate{
+			VolumeMounts: []mocov1beta2.VolumeMountApplyConfiguration{{
+				Name:      pointer.String("tmp"),
+				MountPath: pointer.String("/work"),
+			}},
+		}
+		err = k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with binlogArchive", func() {
+		r := makeBackupPolicy()
+		r.Spec.BinlogArchive = &mocov1beta2.BinlogArchiveSpec{}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Spec.BinlogArchive.Interval.Duration).To(Equal(5 * time.Minute))
+	})
+
+	It("should deny BackupPolicy with invalid binlogArchive interval", func() {
+		r := makeBackupPolicy()
+		r.Spec.BinlogArchive = &mocov1beta2.BinlogArchiveSpec{
+			Interval: metav1.Duration{Duration: -time.Minute},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with source policy", func() {
+		r := makeBackupPolicy()
+		r.Spec.Source = &mocov1beta2.BackupSourcePolicy{
+			Zone:           "zone-a",
+			ExcludePrimary: true,
+			PreferLeastLag: true,
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with both index and zone", func() {
+		r := makeBackupPolicy()
+		r.Spec.Source = &mocov1beta2.BackupSourcePolicy{
+			Index: pointer.Int32(1),
+			Zone:  "zone-a",
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with negative source index", func() {
+		r := makeBackupPolicy()
+		r.Spec.Source = &mocov1beta2.BackupSourcePolicy{
+			Index: pointer.Int32(-1),
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with hooks", func() {
+		r := makeBackupPolicy()
+		r.Spec.Hooks = &mocov1beta2.BackupHooks{
+			PreBackup: []mocov1beta2.BackupHook{
+				{Name: "flush", SQL: []string{"FLUSH TABLES"}},
+			},
+			PostBackup: []mocov1beta2.BackupHook{
+				{
+					Name:          "notify",
+					HTTP:          &mocov1beta2.HTTPHook{URL: "https://catalog.example.com/backups"},
+					FailurePolicy: mocov1beta2.HookWarn,
+				},
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Spec.Hooks.PreBackup[0].FailurePolicy).To(Equal(mocov1beta2.HookAbort))
+		Expect(r.Spec.Hooks.PreBackup[0].Timeout.Duration).To(Equal(time.Minute))
+	})
+
+	It("should deny BackupPolicy with a hook without action", func() {
+		r := makeBackupPolicy()
+		r.Spec.Hooks = &mocov1beta2.BackupHooks{
+			PreBackup: []mocov1beta2.BackupHook{{Name: "nothing"}},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with an invalid hook URL", func() {
+		r := makeBackupPolicy()
+		r.Spec.Hooks = &mocov1beta2.BackupHooks{
+			PostBackup: []mocov1beta2.BackupHook{
+				{Name: "notify", HTTP: &mocov1beta2.HTTPHook{URL: "ftp://example.com/"}},
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with a hook header without a Secret key", func() {
+		r := makeBackupPolicy()
+		hdr := mocov1beta2.HTTPHeader{Name: "Authorization"}
+		hdr.ValueFrom.SecretKeyRef.Name = "catalog"
+		r.Spec.Hooks = &mocov1beta2.BackupHooks{
+			PostBackup: []mocov1beta2.BackupHook{
+				{Name: "notify", HTTP: &mocov1beta2.HTTPHook{URL: "https://example.com/", Headers: []mocov1beta2.HTTPHeader{hdr}}},
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with an invalid hook failurePolicy", func() {
+		r := makeBackupPolicy()
+		r.Spec.Hooks = &mocov1beta2.BackupHooks{
+			PreBackup: []mocov1beta2.BackupHook{
+				{Name: "flush", SQL: []string{"FLUSH TABLES"}, FailurePolicy: "Ignore"},
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with snapshot", func() {
+		r := makeBackupPolicy()
+		r.Spec.Snapshot = &mocov1beta2.SnapshotSpec{VolumeSnapshotClassName: "csi-snapclass"}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with snapshot and filter", func() {
+		r := makeBackupPolicy()
+		r.Spec.Snapshot = &mocov1beta2.SnapshotSpec{}
+		r.Spec.JobConfig.Filter = &mocov1beta2.FilterConfig{
+			IncludeSchemas: []string{"foo"},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create BackupPolicy with secondary buckets", func() {
+		r := makeBackupPolicy()
+		r.Spec.SecondaryBuckets = []mocov1beta2.SecondaryBucket{
+			{Name: "dr", BucketConfig: mocov1beta2.BucketConfig{BucketName: "dr-bucket", Region: "us-west-2"}},
+			{Name: "gcs", BucketConfig: mocov1beta2.BucketConfig{BucketName: "gcs-bucket", BackendType: "gcs"}},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny BackupPolicy with invalid secondary buckets", func() {
+		r := makeBackupPolicy()
+		r.Spec.SecondaryBuckets = []mocov1beta2.SecondaryBucket{
+			{Name: "dr", BucketConfig: mocov1beta2.BucketConfig{BucketName: "dr-bucket"}},
+			{Name: "dr", BucketConfig: mocov1beta2.BucketConfig{BucketName: "dr-bucket2"}},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+
+		r = makeBackupPolicy()
+		r.Spec.SecondaryBuckets = []mocov1beta2.SecondaryBucket{
+			{Name: "dr", BucketConfig: mocov1beta2.BucketConfig{BucketName: "/backup", BackendType: "file"}},
+		}
+		err = k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+
+		r = makeBackupPolicy()
+		r.Spec.SecondaryBuckets = []mocov1beta2.SecondaryBucket{
+			{Name: "dr", BucketConfig: mocov1beta2.BucketConfig{
+				BucketName: "dr-bucket",
+				TLS:        &mocov1beta2.BucketTLSConfig{ClientCertSecretName: "client-cert"},
+			}},
+		}
+		err = k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("should delete BackupPolicy", func() {
 		cluster := makeMySQLCluster()
 		cluster.Spec.BackupPolicyName = pointer.String("no-test")
@@ -2,8 +2,12 @@ package v1beta2
 
 import (
 	"encoding/json"
+	"strings"
 
+	"github.com/cybozu-go/moco/pkg/constants"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 )
 
@@ -22,7 +26,11 @@ type JobConfig struct {
 	//
 	// The recommended volume source is a generic ephemeral volume.
 	// https://kubernetes.io/docs/concepts/storage/ephemeral-volumes/#generic-ephemeral-volumes
-	WorkVolume VolumeSourceApplyConfiguration `json:"workVolume"`
+	//
+	// If not specified, an emptyDir volume is used.  This is suitable only for
+	// backups with Streaming enabled or for small databases.
+	// +optional
+	WorkVolume VolumeSourceApplyConfiguration `json:"workVolume,omitempty"`
 
 	// Threads is the number of threads used for backup or restoration.
 	// +kubebuilder:validation:Minimum=1
@@ -60,6 +68,134 @@ type JobConfig struct {
 	//
 	// +optional
 	Env []EnvVarApplyConfiguration `json:"env,omitempty"`
+
+	// Encryption specifies how to encrypt backup files before uploading them.
+	// If not specified, backup files are stored unencrypted.
+	// +optional
+	Encryption *EncryptionConfig `json:"encryption,omitempty"`
+
+	// Filter specifies schemas and tables to back up or restore.
+	// If not specified, all schemas and tables are processed.
+	// +optional
+	Filter *FilterConfig `json:"filter,omitempty"`
+
+	// Compression specifies the compression levels of backup files.
+	// This is ignored for restoration.
+	// +optional
+	Compression *CompressionConfig `json:"compression,omitempty"`
+
+	// Streaming makes backups upload the files of the full dump while the dump is
+	// being taken, so that the working directory holds only the files not uploaded yet.
+	// Restoration finds backups taken in either way, but it still needs room for
+	// the whole dump in the working directory.
+	// This is ignored for restoration.
+	// +optional
+	Streaming bool `json:"streaming,omitempty"`
+
+	// PodTemplate specifies the settings merged into the Pods of backup and
+	// restore Jobs and the binlog archiver.
+	// +optional
+	PodTemplate *JobPodTemplate `json:"podTemplate,omitempty"`
+}
+
+// JobPodTemplate is a set of settings merged into the Pods created by MOCO
+// for backup and restoration.  The settings made by MOCO take precedence.
+type JobPodTemplate struct {
+	// Standard object's metadata.  The name in this metadata is ignored.
+	// +optional
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// NodeSelector is the node selector of the Pod.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity is the scheduling constraints of the Pod.
+	// +optional
+	Affinity *AffinityApplyConfiguration `json:"affinity,omitempty"`
+
+	// Tolerations are the tolerations of the Pod.
+	// +optional
+	Tolerations []TolerationApplyConfiguration `json:"tolerations,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the Pod.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// SecurityContext is the security context of the Pod.
+	// +optional
+	SecurityContext *PodSecurityContextApplyConfiguration `json:"securityContext,omitempty"`
+
+	// ContainerSecurityContext is the security context of the container.
+	// `readOnlyRootFilesystem`, `runAsUser`, and `runAsGroup` set by MOCO cannot be changed.
+	// +optional
+	ContainerSecurityContext *SecurityContextApplyConfiguration `json:"containerSecurityContext,omitempty"`
+
+	// Volumes are additional volumes of the Pod.
+	// +optional
+	Volumes []VolumeApplyConfiguration `json:"volumes,omitempty"`
+
+	// VolumeMounts are additional volume mounts of the container.
+	// +optional
+	VolumeMounts []VolumeMountApplyConfiguration `json:"volumeMounts,omitempty"`
+}
+
+// EncryptionConfig is a set of parameters to encrypt backup files on the client side.
+type EncryptionConfig struct {
+	// SecretName is the name of a Secret in the same namespace that holds encryption keys.
+	// Each key of the Secret is a key ID, and the value is a 256-bit key
+	// in raw bytes or base64 encoding.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// KeyID is the ID of the key used to encrypt new backup files.
+	// This is required for backup.
+	// Backup files are decrypted with the key recorded in each file,
+	// so keep old keys in the Secret after changing KeyID to rotate keys.
+	// +optional
+	KeyID string `json:"keyID,omitempty"`
+}
+
+// FilterConfig specifies schemas and tables to back up or restore.
+// Tables are specified in the form of "schema.table".
+//
+// For restoration, changes in binary logs are also filtered.
+// Row events are filtered by tables, and other statements are filtered by their default schema.
+type FilterConfig struct {
+	// IncludeSchemas is the list of schemas to be processed.
+	// If empty, all schemas are processed.
+	// +optional
+	IncludeSchemas []string `json:"includeSchemas,omitempty"`
+
+	// ExcludeSchemas is the list of schemas not to be processed.
+	// +optional
+	ExcludeSchemas []string `json:"excludeSchemas,omitempty"`
+
+	// IncludeTables is the list of tables to be processed.
+	// If empty, all tables in the processed schemas are processed.
+	// +optional
+	IncludeTables []string `json:"includeTables,omitempty"`
+
+	// ExcludeTables is the list of tables not to be processed.
+	// +optional
+	ExcludeTables []string `json:"excludeTables,omitempty"`
+}
+
+// CompressionConfig specifies the compression levels of backup files.
+// The levels are those of zstd.
+type CompressionConfig struct {
+	// Dump is the compression level of the full dump.
+	// Since MySQL shell compresses the data of the dump by itself,
+	// compressing the dump again saves only a little space.
+	// The default is "fastest".
+	// +kubebuilder:validation:Enum=none;fastest;default;better;best
+	// +optional
+	Dump string `json:"dump,omitempty"`
+
+	// Binlog is the compression level of binlog files.
+	// The default is "default".
+	// +kubebuilder:validation:Enum=fastest;default;better;best
+	// +optional
+	Binlog string `json:"binlog,omitempty"`
 }
 
 // VolumeSourceApplyConfiguration is the type defined to implement the DeepCopy method.
@@ -125,6 +261,10 @@ type BucketConfig struct {
 	Region string `json:"region,omitempty"`
 
 	// The API endpoint URL.  Set this for non-S3 object storages.
+	// For "gcs" backend, this is the URL of the JSON API endpoint,
+	// e.g. "https://storage.googleapis.com/storage/v1/".
+	// For "azure" backend, this is the URL of the blob service,
+	// e.g. "https://ACCOUNT.blob.core.windows.net/".
 	// +kubebuilder:validation:Pattern="^https?://.*"
 	// +optional
 	EndpointURL string `json:"endpointURL,omitempty"`
@@ -134,4 +274,345 @@ type BucketConfig struct {
 	// is used (https?://BUCKET.ENDPOINT/KEY).
 	// +optional
 	UsePathStyle bool `json:"usePathStyle,omitempty"`
+
+	// BackendType is the type of the storage backend.
+	// "s3" uses an S3-compatible object storage.  This is the default.
+	// "gcs" uses Google Cloud Storage.
+	// "azure" uses Azure Blob Storage.  `bucketName` is the name of the container.
+	// "file" stores objects as files in the volume given by `volume`,
+	// under the directory named `bucketName`.
+	// +kubebuilder:validation:Enum=s3;gcs;azure;file
+	// +optional
+	BackendType string `json:"backendType,omitempty"`
+
+	// AccountName is the name of the storage account for "azure" backend.
+	// +optional
+	AccountName string `json:"accountName,omitempty"`
+
+	// Volume is the volume source to store objects.
+	// This is required if `backendType` is "file".
+	// Typical volume sources are PersistentVolumeClaim and NFS.
+	// +optional
+	Volume *VolumeSourceApplyConfiguration `json:"volume,omitempty"`
+
+	// UploadConcurrency is the number of parts uploaded in parallel
+	// for "s3" backend.  Each part is buffered in memory, so the memory
+	// usage of the backup job grows by `uploadConcurrency` * `partSize`.
+	// The default is 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	UploadConcurrency int `json:"uploadConcurrency,omitempty"`
+
+	// PartSize is the size of each part of multi-part uploads for "s3" backend.
+	// It must be between 5Mi and 5Gi.  The size is increased if an object
+	// does not fit in 10,000 parts.
+	// If not specified, the size is decided from the object size.
+	// +optional
+	PartSize *resource.Quantity `json:"partSize,omitempty"`
+
+	// RateLimit limits the transfer rate in bytes per second of uploads
+	// during backups and downloads during restores.
+	// If not specified, the rate is not limited.
+	// +optional
+	RateLimit *resource.Quantity `json:"rateLimit,omitempty"`
+
+	// ServerSideEncryption is the server-side encryption algorithm of
+	// uploaded objects for "s3" backend.
+	// If not specified, the default encryption of the bucket applies.
+	// +kubebuilder:validation:Enum=AES256;"aws:kms"
+	// +optional
+	ServerSideEncryption string `json:"serverSideEncryption,omitempty"`
+
+	// KMSKeyID is the ID of the AWS KMS key to encrypt uploaded objects.
+	// This can be set only if `serverSideEncryption` is "aws:kms".
+	// If not specified, the AWS managed key is used.
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+
+	// StorageClass is the storage class of uploaded objects for "s3" backend,
+	// e.g. "STANDARD_IA" or "GLACIER_IR".
+	// If not specified, the default storage class of the bucket applies.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Tags are the tags set to uploaded objects for "s3" backend.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// ObjectLock is the S3 Object Lock retention of uploaded objects.
+	// The bucket must be created with Object Lock enabled.
+	// +optional
+	ObjectLock *ObjectLockConfig `json:"objectLock,omitempty"`
+
+	// TLS specifies the CA bundle and the client certificate to access
+	// the API endpoint of "s3" backend over HTTPS.
+	// +optional
+	TLS *BucketTLSConfig `json:"tls,omitempty"`
+}
+
+// BucketTLSConfig is a set of parameters for TLS connections to the bucket.
+type BucketTLSConfig struct {
+	// CABundle refers to PEM-encoded CA certificates to verify the server certificate.
+	// They are trusted in addition to the system CA certificates.
+	// +optional
+	CABundle *CABundleSource `json:"caBundle,omitempty"`
+
+	// ClientCertSecretName is the name of a Secret of type "kubernetes.io/tls"
+	// in the same namespace.  The certificate is presented to the server.
+	// +optional
+	ClientCertSecretName string `json:"clientCertSecretName,omitempty"`
+}
+
+// CABundleSource refers to a key of a ConfigMap or a Secret that holds CA certificates.
+// Exactly one of `configMapName` and `secretName` should be specified.
+type CABundleSource struct {
+	// ConfigMapName is the name of a ConfigMap in the same namespace.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// SecretName is the name of a Secret in the same namespace.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Key is the key of the CA certificates in the ConfigMap or the Secret.
+	// +kubebuilder:default="ca.crt"
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ObjectLockConfig is the S3 Object Lock retention of uploaded objects.
+type ObjectLockConfig struct {
+	// Mode is the retention mode.
+	// Objects in "COMPLIANCE" mode cannot be deleted by any user until the retention expires.
+	// +kubebuilder:validation:Enum=GOVERNANCE;COMPLIANCE
+	Mode string `json:"mode"`
+
+	// RetainFor is the period to retain uploaded objects.
+	// The retention of each object expires this period after it is uploaded.
+	RetainFor metav1.Duration `json:"retainFor"`
+}
+
+func (c JobConfig) validate(p *field.Path) field.ErrorList {
+	allErrs := c.BucketConfig.validate(p.Child("bucketConfig"))
+	if c.Filter != nil {
+		allErrs = append(allErrs, c.Filter.validate(p.Child("filter"))...)
+	}
+	if c.PodTemplate != nil {
+		allErrs = append(allErrs, c.PodTemplate.validate(p.Child("podTemplate"))...)
+	}
+	return allErrs
+}
+
+// reservedJobVolumeNames are the names of volumes that MOCO adds to backup and restore Pods.
+var reservedJobVolumeNames = map[string]bool{
+	"work":               true,
+	"bucket":             true,
+	"bucket-ca":          true,
+	"bucket-client-cert": true,
+	"encryption-keys":    true,
+}
+
+func (t JobPodTemplate) validate(p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, v := range t.Volumes {
+		pp := p.Child("volumes").Index(i)
+		switch {
+		case v.Name == nil || *v.Name == "":
+			allErrs = append(allErrs, field.Required(pp.Child("name"), "name is required"))
+		case reservedJobVolumeNames[*v.Name]:
+			allErrs = append(allErrs, field.Invalid(pp.Child("name"), *v.Name, "the name is reserved by MOCO"))
+		}
+	}
+	for i, m := range t.VolumeMounts {
+		pp := p.Child("volumeMounts").Index(i)
+		if m.MountPath != nil && *m.MountPath == "/work" {
+			allErrs = append(allErrs, field.Invalid(pp.Child("mountPath"), *m.MountPath, "the path is reserved by MOCO"))
+		}
+	}
+
+	return allErrs
+}
+
+func (c FilterConfig) validate(p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	validateTables := func(pp *field.Path, tables []string) {
+		for i, t := range tables {
+			fields := strings.Split(t, ".")
+			if len(fields) != 2 || fields[0] == "" || fields[1] == "" {
+				allErrs = append(allErrs, field.Invalid(pp.Index(i), t, "table must be in the form of schema.table"))
+			}
+		}
+	}
+	validateTables(p.Child("includeTables"), c.IncludeTables)
+	validateTables(p.Child("excludeTables"), c.ExcludeTables)
+
+	return allErrs
+}
+
+var (
+	minPartSize = resource.MustParse("5Mi")
+	maxPartSize = resource.MustParse("5Gi")
+)
+
+func (c BucketConfig) validate(p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.BackendType == constants.BackendTypeFile && c.Volume == nil {
+		allErrs = append(allErrs, field.Required(p.Child("volume"), "volume is required for file backend"))
+	}
+	if c.BackendType == constants.BackendTypeAzure && c.AccountName == "" {
+		allErrs = append(allErrs, field.Required(p.Child("accountName"), "accountName is required for azure backend"))
+	}
+	if c.PartSize != nil && (c.PartSize.Cmp(minPartSize) < 0 || c.PartSize.Cmp(maxPartSize) > 0) {
+		allErrs = append(allErrs, field.Invalid(p.Child("partSize"), c.PartSize.String(), "partSize must be between 5Mi and 5Gi"))
+	}
+	if c.RateLimit != nil && c.RateLimit.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(p.Child("rateLimit"), c.RateLimit.String(), "rateLimit must be positive"))
+	}
+
+	backend := c.BackendType
+	if backend == "" {
+		backend = constants.BackendTypeS3
+	}
+	forbidNonS3 := func(name string, set bool) {
+		if set && backend != constants.BackendTypeS3 {
+			allErrs = append(allErrs, field.Forbidden(p.Child(name), name+" is available only for s3 backend"))
+		}
+	}
+	forbidNonS3("serverSideEncryption", c.ServerSideEncryption != "")
+	forbidNonS3("kmsKeyID", c.KMSKeyID != "")
+	forbidNonS3("storageClass", c.StorageClass != "")
+	forbidNonS3("tags", len(c.Tags) > 0)
+	forbidNonS3("objectLock", c.ObjectLock != nil)
+	forbidNonS3("tls", c.TLS != nil)
+	if c.KMSKeyID != "" && c.ServerSideEncryption != "aws:kms" {
+		allErrs = append(allErrs, field.Invalid(p.Child("kmsKeyID"), c.KMSKeyID, "kmsKeyID requires serverSideEncryption to be aws:kms"))
+	}
+	for k := range c.Tags {
+		if k == "" {
+			allErrs = append(allErrs, field.Invalid(p.Child("tags"), k, "tag key must not be empty"))
+		}
+	}
+	if c.ObjectLock != nil && c.ObjectLock.RetainFor.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(p.Child("objectLock", "retainFor"), c.ObjectLock.RetainFor.Duration.String(), "retainFor must be positive"))
+	}
+	if c.TLS != nil && c.TLS.CABundle != nil {
+		ca := c.TLS.CABundle
+		pp := p.Child("tls", "caBundle")
+		switch {
+		case ca.ConfigMapName == "" && ca.SecretName == "":
+			allErrs = append(allErrs, field.Required(pp, "either configMapName or secretName must be specified"))
+		case ca.ConfigMapName != "" && ca.SecretName != "":
+			allErrs = append(allErrs, field.Forbidden(pp.Child("secretName"), "secretName cannot be specified with configMapName"))
+		}
+		if ca.Key == "" {
+			allErrs = append(allErrs, field.Required(pp.Child("key"), "key is required"))
+		}
+	}
+
+	return allErrs
+}
+
+// AffinityApplyConfiguration is the type defined to implement the DeepCopy method.
+type AffinityApplyConfiguration corev1ac.AffinityApplyConfiguration
+
+// DeepCopy is copying the receiver, creating a new AffinityApplyConfiguration.
+func (in *AffinityApplyConfiguration) DeepCopy() *AffinityApplyConfiguration {
+	out := new(AffinityApplyConfiguration)
+	bytes, err := json.Marshal(in)
+	if err != nil {
+		panic("Failed to marshal")
+	}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		panic("Failed to unmarshal")
+	}
+	return out
+}
+
+// TolerationApplyConfiguration is the type defined to implement the DeepCopy method.
+type TolerationApplyConfiguration corev1ac.TolerationApplyConfiguration
+
+// DeepCopy is copying the receiver, creating a new TolerationApplyConfiguration.
+func (in *TolerationApplyConfiguration) DeepCopy() *TolerationApplyConfiguration {
+	out := new(TolerationApplyConfiguration)
+	bytes, err := json.Marshal(in)
+	if err != nil {
+		panic("Failed to marshal")
+	}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		panic("Failed to unmarshal")
+	}
+	return out
+}
+
+// PodSecurityContextApplyConfiguration is the type defined to implement the DeepCopy method.
+type PodSecurityContextApplyConfiguration corev1ac.PodSecurityContextApplyConfiguration
+
+// DeepCopy is copying the receiver, creating a new PodSecurityContextApplyConfiguration.
+func (in *PodSecurityContextApplyConfiguration) DeepCopy() *PodSecurityContextApplyConfiguration {
+	out := new(PodSecurityContextApplyConfiguration)
+	bytes, err := json.Marshal(in)
+	if err != nil {
+		panic("Failed to marshal")
+	}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		panic("Failed to unmarshal")
+	}
+	return out
+}
+
+// SecurityContextApplyConfiguration is the type defined to implement the DeepCopy method.
+type SecurityContextApplyConfiguration corev1ac.SecurityContextApplyConfiguration
+
+// DeepCopy is copying the receiver, creating a new SecurityContextApplyConfiguration.
+func (in *SecurityContextApplyConfiguration) DeepCopy() *SecurityContextApplyConfiguration {
+	out := new(SecurityContextApplyConfiguration)
+	bytes, err := json.Marshal(in)
+	if err != nil {
+		panic("Failed to marshal")
+	}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		panic("Failed to unmarshal")
+	}
+	return out
+}
+
+// VolumeApplyConfiguration is the type defined to implement the DeepCopy method.
+type VolumeApplyConfiguration corev1ac.VolumeApplyConfiguration
+
+// DeepCopy is copying the receiver, creating a new VolumeApplyConfiguration.
+func (in *VolumeApplyConfiguration) DeepCopy() *VolumeApplyConfiguration {
+	out := new(VolumeApplyConfiguration)
+	bytes, err := json.Marshal(in)
+	if err != nil {
+		panic("Failed to marshal")
+	}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		panic("Failed to unmarshal")
+	}
+	return out
+}
+
+// VolumeMountApplyConfiguration is the type defined to implement the DeepCopy method.
+type VolumeMountApplyConfiguration corev1ac.VolumeMountApplyConfiguration
+
+// DeepCopy is copying the receiver, creating a new VolumeMountApplyConfiguration.
+func (in *VolumeMountApplyConfiguration) DeepCopy() *VolumeMountApplyConfiguration {
+	out := new(VolumeMountApplyConfiguration)
+	bytes, err := json.Marshal(in)
+	if err != nil {
+		panic("Failed to marshal")
+	}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		panic("Failed to unmarshal")
+	}
+	return out
 }
@@ -0,0 +1,171 @@
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// MySQLBackupSpec defines the desired state of MySQLBackup
+type MySQLBackupSpec struct {
+	// ClusterName is the name of the MySQLCluster to take a backup of.
+	// The MySQLCluster must be in the same namespace and reference a BackupPolicy
+	// because the backup job is configured with the policy's `jobConfig`.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+
+	// BucketConfig overrides the bucket of the BackupPolicy.
+	// A backup taken into another bucket is standalone; it does not
+	// update `status.backup` of the MySQLCluster, and binary logs
+	// since the last scheduled backup are not saved with it.
+	// +optional
+	BucketConfig *BucketConfig `json:"bucketConfig,omitempty"`
+
+	// Labels are recorded in the manifest of the backup.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// MySQLBackupPhase is the phase of a MySQLBackup.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type MySQLBackupPhase string
+
+const (
+	MySQLBackupPending   = MySQLBackupPhase("Pending")
+	MySQLBackupRunning   = MySQLBackupPhase("Running")
+	MySQLBackupSucceeded = MySQLBackupPhase("Succeeded")
+	MySQLBackupFailed    = MySQLBackupPhase("Failed")
+)
+
+// MySQLBackupStatus defines the observed state of MySQLBackup
+type MySQLBackupStatus struct {
+	// Phase is the current phase of the backup.
+	// +optional
+	Phase MySQLBackupPhase `json:"phase,omitempty"`
+
+	// Message is a human readable message about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time when the backup job was created.
+	// +nullable
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the backup finished.
+	// +nullable
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// BackupTime is the time of the backup.  This is used to generate object keys of backup files in a bucket,
+	// and can be used as `restorePoint` of a restore.
+	// +nullable
+	// +optional
+	BackupTime *metav1.Time `json:"backupTime,omitempty"`
+
+	// SourceIndex is the ordinal of the backup source instance.
+	// +optional
+	SourceIndex int `json:"sourceIndex,omitempty"`
+
+	// SourceUUID is the `server_uuid` of the backup source instance.
+	// +optional
+	SourceUUID string `json:"sourceUUID,omitempty"`
+
+	// BinlogFilename is the binlog filename that the backup source instance was writing to
+	// at the backup.
+	// +optional
+	BinlogFilename string `json:"binlogFilename,omitempty"`
+
+	// GTIDSet is the GTID set of the full dump of database.
+	// +optional
+	GTIDSet string `json:"gtidSet,omitempty"`
+
+	// VolumeSnapshotName is the name of the VolumeSnapshot taken by a snapshot backup.
+	// +optional
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+
+	// DumpSize is the size in bytes of a full dump of database stored in an object storage bucket.
+	// +optional
+	DumpSize int64 `json:"dumpSize,omitempty"`
+
+	// BinlogSize is the size in bytes of a tarball of binlog files stored in an object storage bucket.
+	// The binlog files are those executed since the previous backup.
+	// +optional
+	BinlogSize int64 `json:"binlogSize,omitempty"`
+
+	// WorkDirUsage is the max usage in bytes of the woking directory.
+	// +optional
+	WorkDirUsage int64 `json:"workDirUsage,omitempty"`
+
+	// UploadThroughput is the effective throughput in bytes per second
+	// of uploading the backup files to the bucket.
+	// +optional
+	UploadThroughput int64 `json:"uploadThroughput,omitempty"`
+
+	// DumpKey is the object key of the full dump.
+	// +optional
+	DumpKey string `json:"dumpKey,omitempty"`
+
+	// ManifestKey is the object key of the manifest of the backup.
+	// +optional
+	ManifestKey string `json:"manifestKey,omitempty"`
+
+	// BinlogKey is the object key of the binlog files executed since the previous backup.
+	// +optional
+	BinlogKey string `json:"binlogKey,omitempty"`
+
+	// Warnings are list of warnings from the backup, if any.
+	// +optional
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// IsFinished returns true if the backup has succeeded or failed.
+func (s MySQLBackupStatus) IsFinished() bool {
+	return s.Phase == MySQLBackupSucceeded || s.Phase == MySQLBackupFailed
+}
+
+func (s *MySQLBackupSpec) validate() field.ErrorList {
+	var allErrs field.ErrorList
+	p := field.NewPath("spec")
+
+	if s.BucketConfig != nil {
+		allErrs = append(allErrs, s.BucketConfig.validate(p.Child("bucketConfig"))...)
+	}
+
+	return allErrs
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Backup time",type="string",JSONPath=".status.backupTime"
+//+kubebuilder:printcolumn:name="Dump size",type="integer",JSONPath=".status.dumpSize"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MySQLBackup represents an on-demand backup of a MySQLCluster.
+// The backup is taken once when the resource is created.
+type MySQLBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MySQLBackupSpec   `json:"spec"`
+	Status MySQLBackupStatus `json:"status,omitempty"`
+}
+
+// JobName returns the name of the Job to take the backup.
+func (r *MySQLBackup) JobName() string {
+	return "moco-mysqlbackup-" + r.Name
+}
+
+//+kubebuilder:object:root=true
+
+// MySQLBackupList contains a list of MySQLBackup
+type MySQLBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MySQLBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MySQLBackup{}, &MySQLBackupList{})
+}
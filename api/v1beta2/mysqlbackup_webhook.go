@@ -0,0 +1,47 @@
+package v1beta2
+
+import (
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (r *MySQLBackup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-moco-cybozu-com-v1beta2-mysqlbackup,mutating=false,failurePolicy=fail,sideEffects=None,matchPolicy=Equivalent,groups=moco.cybozu.com,resources=mysqlbackups,verbs=create;update,versions=v1beta2,name=vmysqlbackup.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &MySQLBackup{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *MySQLBackup) ValidateCreate() error {
+	errs := r.Spec.validate()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "MySQLBackup"}, r.Name, errs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *MySQLBackup) ValidateUpdate(old runtime.Object) error {
+	oldBackup := old.(*MySQLBackup)
+	if apiequality.Semantic.DeepEqual(r.Spec, oldBackup.Spec) {
+		return nil
+	}
+
+	errs := field.ErrorList{field.Forbidden(field.NewPath("spec"), "spec is immutable")}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "MySQLBackup"}, r.Name, errs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *MySQLBackup) ValidateDelete() error {
+	return nil
+}
@@ -0,0 +1,82 @@
+package v1beta2_test
+
+import (
+	"context"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func makeMySQLBackup() *mocov1beta2.MySQLBackup {
+	r := &mocov1beta2.MySQLBackup{}
+	r.Namespace = "default"
+	r.Name = "test"
+	r.Spec.ClusterName = "test"
+	return r
+}
+
+var _ = Describe("MySQLBackup Webhook", func() {
+	ctx := context.TODO()
+
+	BeforeEach(func() {
+		r := &mocov1beta2.MySQLBackup{}
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test"}, r)
+		if apierrors.IsNotFound(err) {
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		err = k8sClient.Delete(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should create MySQLBackup", func() {
+		r := makeMySQLBackup()
+		r.Spec.Labels = map[string]string{"reason": "migration"}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should create MySQLBackup with another bucket", func() {
+		r := makeMySQLBackup()
+		r.Spec.BucketConfig = &mocov1beta2.BucketConfig{BucketName: "another"}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny MySQLBackup without clusterName", func() {
+		r := makeMySQLBackup()
+		r.Spec.ClusterName = ""
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny MySQLBackup with invalid bucketConfig", func() {
+		r := makeMySQLBackup()
+		r.Spec.BucketConfig = &mocov1beta2.BucketConfig{BucketName: "another", BackendType: "file"}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny updating spec", func() {
+		r := makeMySQLBackup()
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+
+		r.Spec.ClusterName = "foo"
+		err = k8sClient.Update(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should allow updating status", func() {
+		r := makeMySQLBackup()
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+
+		r.Status.Phase = mocov1beta2.MySQLBackupRunning
+		err = k8sClient.Status().Update(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
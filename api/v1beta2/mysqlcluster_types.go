@@ -3,6 +3,7 @@ package v1beta2
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 
 	"github.com/cybozu-go/moco/pkg/constants"
 	"github.com/robfig/cron/v3"
@@ -153,6 +154,10 @@ func (s MySQLClusterSpec) validateCreate() field.ErrorList {
 		allErrs = append(allErrs, field.Invalid(pp, s.Replicas, "replicas must be a positive integer"))
 	}
 
+	if s.Restore != nil {
+		allErrs = append(allErrs, s.Restore.validate(p.Child("restore"))...)
+	}
+
 	p = p.Child("podTemplate", "spec")
 
 	pp = p.Child("containers")
@@ -466,10 +471,53 @@ type RestoreSpec struct {
 	// The format is RFC3339.  e.g. "2006-01-02T15:04:05Z"
 	RestorePoint metav1.Time `json:"restorePoint"`
 
+	// StopGTID is a GTID set to stop the restoration.
+	// Transactions are applied up to, but not including, the first transaction in the set,
+	// or up to `restorePoint`, whichever comes first.
+	// e.g. "3E11FA47-71CA-11E1-9E33-C80AA9429562:23"
+	// +optional
+	StopGTID string `json:"stopGTID,omitempty"`
+
+	// VolumeSnapshotName is the name of a VolumeSnapshot taken by a snapshot backup
+	// of the source MySQLCluster.  If specified, the data volume of the first instance
+	// is provisioned from the snapshot instead of loading a dump, and binary logs
+	// in the bucket are applied up to `restorePoint`.
+	// The VolumeSnapshot must be in the namespace of this MySQLCluster.
+	// +optional
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+
+	// SecondaryBucketName is the name of an entry of `secondaryBuckets` in the
+	// BackupPolicy of the source MySQLCluster.  If specified, backups are read
+	// from that bucket instead of `jobConfig.bucketConfig`.
+	// If the source MySQLCluster or its BackupPolicy no longer exists, e.g. when the
+	// source region is lost, backups are read from `jobConfig.bucketConfig`, which
+	// should then point to the secondary bucket.
+	// +optional
+	SecondaryBucketName string `json:"secondaryBucketName,omitempty"`
+
 	// Specifies parameters for restore Pod.
 	JobConfig `json:"jobConfig"`
 }
 
+// gtidSetPattern matches a GTID set such as "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5:7,...".
+var gtidSetPattern = func() *regexp.Regexp {
+	elem := `[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}(:([a-zA-Z_][a-zA-Z0-9_]{0,31}|[0-9]+(-[0-9]+)?))+`
+	return regexp.MustCompile(`^\s*` + elem + `\s*(,\s*` + elem + `\s*)*$`)
+}()
+
+func (s RestoreSpec) validate(p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if s.StopGTID != "" && !gtidSetPattern.MatchString(s.StopGTID) {
+		allErrs = append(allErrs, field.Invalid(p.Child("stopGTID"), s.StopGTID, "invalid GTID set"))
+	}
+	if s.VolumeSnapshotName != "" && s.JobConfig.Filter != nil {
+		allErrs = append(allErrs, field.Forbidden(p.Child("jobConfig", "filter"), "filter cannot be used with volumeSnapshotName"))
+	}
+
+	return append(allErrs, s.JobConfig.validate(p.Child("jobConfig"))...)
+}
+
 // MySQLClusterStatus defines the observed state of MySQLCluster
 type MySQLClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -503,6 +551,19 @@ type MySQLClusterStatus struct {
 	// +optional
 	RestoredTime *metav1.Time `json:"restoredTime,omitempty"`
 
+	// Restore is the progress of the restoration from a backup.
+	// +optional
+	Restore *RestoreStatus `json:"restore,omitempty"`
+
+	// RestoreDrill is the result of the last restore drill.
+	// +optional
+	RestoreDrill *RestoreDrillStatus `json:"restoreDrill,omitempty"`
+
+	// RestoreDrillRequest is set by the restore drill Job to have moco-controller
+	// create the scratch MySQLCluster, and cleared when the drill finishes.
+	// +optional
+	RestoreDrillRequest *RestoreDrillRequest `json:"restoreDrillRequest,omitempty"`
+
 	// Cloned indicates if the initial cloning from an external source has been completed.
 	// +optional
 	Cloned bool `json:"cloned,omitempty"`
@@ -533,7 +594,7 @@ type MySQLClusterCondition struct {
 }
 
 // MySQLClusterConditionType is the type of MySQLCluster condition.
-// +kubebuilder:validation:Enum=Initialized;Available;Healthy
+// +kubebuilder:validation:Enum=Initialized;Available;Healthy;Restored
 type MySQLClusterConditionType string
 
 // Valid values for MySQLClusterConditionType
@@ -541,6 +602,7 @@ const (
 	ConditionInitialized MySQLClusterConditionType = "Initialized"
 	ConditionAvailable   MySQLClusterConditionType = "Available"
 	ConditionHealthy     MySQLClusterConditionType = "Healthy"
+	ConditionRestored    MySQLClusterConditionType = "Restored"
 )
 
 // BackupStatus represents the status of the last successful backup.
@@ -559,12 +621,21 @@ type BackupStatus struct {
 	SourceUUID string `json:"sourceUUID"`
 
 	// BinlogFilename is the binlog filename that the backup source instance was writing to
-	// at the backup.
+	// at the backup or the last binlog-only backup after it.
 	BinlogFilename string `json:"binlogFilename"`
 
-	// GTIDSet is the GTID set of the full dump of database.
+	// GTIDSet is the GTID set of the full dump of database, or the GTID set
+	// uploaded by the last binlog-only backup after it.
 	GTIDSet string `json:"gtidSet"`
 
+	// BinlogBackupTime is the time of the last binlog-only backup after the backup.
+	// +optional
+	BinlogBackupTime *metav1.Time `json:"binlogBackupTime,omitempty"`
+
+	// VolumeSnapshotName is the name of the VolumeSnapshot taken by a snapshot backup.
+	// +optional
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+
 	// DumpSize is the size in bytes of a full dump of database stored in an object storage bucket.
 	DumpSize int64 `json:"dumpSize"`
 
@@ -574,11 +645,131 @@ type BackupStatus struct {
 	// WorkDirUsage is the max usage in bytes of the woking directory.
 	WorkDirUsage int64 `json:"workDirUsage"`
 
+	// UploadThroughput is the effective throughput in bytes per second
+	// of uploading the backup files to the bucket.
+	// +optional
+	UploadThroughput int64 `json:"uploadThroughput,omitempty"`
+
+	// EarliestRestorableTime is the earliest point-in-time to which data
+	// can be restored from the backups in the bucket.
+	// +optional
+	EarliestRestorableTime *metav1.Time `json:"earliestRestorableTime,omitempty"`
+
+	// LatestRestorableTime is the latest point-in-time to which data
+	// can be restored from the backups in the bucket.
+	// +optional
+	LatestRestorableTime *metav1.Time `json:"latestRestorableTime,omitempty"`
+
+	// RestorableGaps are the periods between EarliestRestorableTime and
+	// LatestRestorableTime to which data cannot be restored because binlogs are missing.
+	// +optional
+	RestorableGaps []RestorableGap `json:"restorableGaps,omitempty"`
+
 	// Warnings are list of warnings from the last backup, if any.
 	// +nullable
 	Warnings []string `json:"warnings"`
 }
 
+// RestorePhase is the phase of the restoration.
+// +kubebuilder:validation:Enum=WaitingForPod;Downloading;LoadingDump;ApplyingBinlog;Finalizing;Completed;Failed
+type RestorePhase string
+
+// Valid values for RestorePhase
+const (
+	RestoreWaitingForPod  RestorePhase = "WaitingForPod"
+	RestoreDownloading    RestorePhase = "Downloading"
+	RestoreLoadingDump    RestorePhase = "LoadingDump"
+	RestoreApplyingBinlog RestorePhase = "ApplyingBinlog"
+	RestoreFinalizing     RestorePhase = "Finalizing"
+	RestoreCompleted      RestorePhase = "Completed"
+	RestoreFailed         RestorePhase = "Failed"
+)
+
+// RestoreStatus represents the progress of the restoration from a backup.
+type RestoreStatus struct {
+	// Phase is the current phase of the restoration.
+	// +optional
+	Phase RestorePhase `json:"phase,omitempty"`
+
+	// StartTime is the time when the restoration started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// DumpKey is the object key of the dump chosen for the restoration.
+	// +optional
+	DumpKey string `json:"dumpKey,omitempty"`
+
+	// BinlogKey is the object key of the binlog archive chosen for the restoration.
+	// +optional
+	BinlogKey string `json:"binlogKey,omitempty"`
+
+	// Segments is the number of binlog segments to be applied.
+	// +optional
+	Segments int `json:"segments,omitempty"`
+
+	// DownloadedBytes is the number of bytes downloaded from the bucket.
+	// +optional
+	DownloadedBytes int64 `json:"downloadedBytes,omitempty"`
+
+	// LoadedBytes is the number of bytes of the extracted files loaded into mysqld.
+	// +optional
+	LoadedBytes int64 `json:"loadedBytes,omitempty"`
+
+	// GTIDSet is the GTID set executed on the instance after the restoration.
+	// +optional
+	GTIDSet string `json:"gtidSet,omitempty"`
+
+	// Message describes the error if the restoration failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RestoreDrillRequest is a request for the scratch MySQLCluster of a restore drill.
+type RestoreDrillRequest struct {
+	// RestorePoint is the point-in-time to which the scratch MySQLCluster restores data.
+	RestorePoint metav1.Time `json:"restorePoint"`
+}
+
+// RestoreDrillStatus is the result of a restore drill.
+type RestoreDrillStatus struct {
+	// Time is the time when the drill started.
+	Time metav1.Time `json:"time"`
+
+	// Elapsed is the time taken for the drill.
+	Elapsed metav1.Duration `json:"elapsed"`
+
+	// Succeeded is true if the data was restored and all assertions passed.
+	Succeeded bool `json:"succeeded"`
+
+	// RestorePoint is the point-in-time to which the drill restored data.
+	// +nullable
+	// +optional
+	RestorePoint *metav1.Time `json:"restorePoint,omitempty"`
+
+	// FailedAssertions are the names of the assertions that did not pass.
+	// +optional
+	FailedAssertions []string `json:"failedAssertions,omitempty"`
+
+	// Message describes why the drill failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastSuccessTime is the time when the last successful drill started.
+	// +nullable
+	// +optional
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+}
+
+// RestorableGap is a period to which data cannot be restored.
+// Data can be restored to Start and End, but not to any point between them.
+type RestorableGap struct {
+	// Start is the end of the restorable period before the gap.
+	Start metav1.Time `json:"start"`
+
+	// End is the start of the restorable period after the gap.
+	End metav1.Time `json:"end"`
+}
+
 // ReconcileInfo is the type to record the last reconciliation information.
 type ReconcileInfo struct {
 	// Generation is the `metadata.generation` value of the last reconciliation.
@@ -681,11 +872,40 @@ func (r *MySQLCluster) BackupCronJobName() string {
 	return fmt.Sprintf("moco-backup-%s", r.Name)
 }
 
+// BinlogBackupCronJobName returns the name of CronJob for binlog-only backup.
+func (r *MySQLCluster) BinlogBackupCronJobName() string {
+	return fmt.Sprintf("moco-binlog-backup-%s", r.Name)
+}
+
+// RestoreDrillCronJobName returns the name of CronJob for restore drills.
+func (r *MySQLCluster) RestoreDrillCronJobName() string {
+	return fmt.Sprintf("moco-drill-%s", r.Name)
+}
+
+// RestoreDrillClusterName returns the name of the scratch MySQLCluster for restore drills.
+func (r *MySQLCluster) RestoreDrillClusterName() string {
+	return fmt.Sprintf("moco-scratch-%s", r.Name)
+}
+
+// RestoreDrillVolumeClaimNames returns the names of PVCs of the scratch MySQLCluster for restore drills.
+func (r *MySQLCluster) RestoreDrillVolumeClaimNames() []string {
+	names := make([]string, 0, len(r.Spec.VolumeClaimTemplates))
+	for _, vct := range r.Spec.VolumeClaimTemplates {
+		names = append(names, fmt.Sprintf("%s-moco-%s-0", vct.Name, r.RestoreDrillClusterName()))
+	}
+	return names
+}
+
 // BackupRoleName returns the name of Role/RoleBinding for backup.
 func (r *MySQLCluster) BackupRoleName() string {
 	return fmt.Sprintf("moco-backup-%s", r.Name)
 }
 
+// BinlogArchiverName returns the name of Deployment for binlog archiving.
+func (r *MySQLCluster) BinlogArchiverName() string {
+	return fmt.Sprintf("moco-binlog-%s", r.Name)
+}
+
 // RestoreJobName returns the name of Job for restoration.
 func (r *MySQLCluster) RestoreJobName() string {
 	return fmt.Sprintf("moco-restore-%s", r.Name)
@@ -1,22 +1,29 @@
 package v1beta2
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/binary"
+	"fmt"
 	"math"
+	"time"
 
 	"github.com/cybozu-go/moco/pkg/constants"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func (r *MySQLCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&mysqlClusterValidator{reader: mgr.GetAPIReader()}).
 		Complete()
 }
 
@@ -43,11 +50,25 @@ func (r *MySQLCluster) Default() {
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 //+kubebuilder:webhook:path=/validate-moco-cybozu-com-v1beta2-mysqlcluster,mutating=false,failurePolicy=fail,sideEffects=None,matchPolicy=Equivalent,groups=moco.cybozu.com,resources=mysqlclusters,verbs=create;update,versions=v1beta2,name=vmysqlcluster.kb.io,admissionReviewVersions=v1
 
-var _ webhook.Validator = &MySQLCluster{}
+// mysqlClusterValidator validates MySQLCluster.
+// It reads the source MySQLCluster of restoration with `reader`.
+type mysqlClusterValidator struct {
+	reader client.Reader
+}
+
+var _ admission.CustomValidator = &mysqlClusterValidator{}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *MySQLCluster) ValidateCreate() error {
+// ValidateCreate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *mysqlClusterValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	r := obj.(*MySQLCluster)
 	errs := r.Spec.validateCreate()
+	if r.Spec.Restore != nil {
+		errs = append(errs, v.validateRestore(ctx, r.Spec.Restore)...)
+		// a PVC can be provisioned only from a VolumeSnapshot in the same namespace.
+		if r.Spec.Restore.VolumeSnapshotName != "" && r.Spec.Restore.SourceNamespace != r.Namespace {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("restore").Child("sourceNamespace"), r.Spec.Restore.SourceNamespace, "must be the namespace of the cluster to restore from a snapshot"))
+		}
+	}
 	if len(errs) == 0 {
 		return nil
 	}
@@ -55,9 +76,10 @@ func (r *MySQLCluster) ValidateCreate() error {
 	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "MySQLCluster"}, r.Name, errs)
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *MySQLCluster) ValidateUpdate(old runtime.Object) error {
-	errs := r.Spec.validateUpdate(old.(*MySQLCluster).Spec)
+// ValidateUpdate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *mysqlClusterValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	r := newObj.(*MySQLCluster)
+	errs := r.Spec.validateUpdate(oldObj.(*MySQLCluster).Spec)
 	if len(errs) == 0 {
 		return nil
 	}
@@ -65,7 +87,77 @@ func (r *MySQLCluster) ValidateUpdate(old runtime.Object) error {
 	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "MySQLCluster"}, r.Name, errs)
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *MySQLCluster) ValidateDelete() error {
+// ValidateDelete implements admission.CustomValidator so a webhook will be registered for the type
+func (v *mysqlClusterValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+// validateRestore checks `spec.restore` against the source MySQLCluster.
+// If the source does not exist, e.g. when the source region is lost,
+// the restore Job reads `jobConfig.bucketConfig`, so there is nothing to check.
+func (v *mysqlClusterValidator) validateRestore(ctx context.Context, s *RestoreSpec) field.ErrorList {
+	p := field.NewPath("spec").Child("restore")
+
+	source := &MySQLCluster{}
+	err := v.reader.Get(ctx, client.ObjectKey{Namespace: s.SourceNamespace, Name: s.SourceName}, source)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return field.ErrorList{field.InternalError(p, err)}
+	}
+
+	// secondary buckets have the same backups as the primary bucket, so the restorable
+	// period of the source applies to them as well.
+	errs := s.validateRestorePoint(p.Child("restorePoint"), &source.Status.Backup)
+	if s.SecondaryBucketName != "" {
+		errs = append(errs, v.validateSecondaryBucket(ctx, s, source, p.Child("secondaryBucketName"))...)
+	}
+	return errs
+}
+
+// validateRestorePoint checks that the restore point is within the restorable
+// period recorded in the status of the source MySQLCluster.
+// If the period is unknown, the restore point is not checked.
+func (s RestoreSpec) validateRestorePoint(p *field.Path, bs *BackupStatus) field.ErrorList {
+	if bs.EarliestRestorableTime == nil || bs.LatestRestorableTime == nil {
+		return nil
+	}
+
+	t := s.RestorePoint.Time
+	if t.Before(bs.EarliestRestorableTime.Time) || t.After(bs.LatestRestorableTime.Time) {
+		return field.ErrorList{field.Invalid(p, s.RestorePoint.UTC().Format(time.RFC3339),
+			fmt.Sprintf("data can be restored only between %s and %s",
+				bs.EarliestRestorableTime.UTC().Format(time.RFC3339), bs.LatestRestorableTime.UTC().Format(time.RFC3339)))}
+	}
+	for _, gap := range bs.RestorableGaps {
+		if t.After(gap.Start.Time) && t.Before(gap.End.Time) {
+			return field.ErrorList{field.Invalid(p, s.RestorePoint.UTC().Format(time.RFC3339),
+				fmt.Sprintf("binlogs between %s and %s are missing",
+					gap.Start.UTC().Format(time.RFC3339), gap.End.UTC().Format(time.RFC3339)))}
+		}
+	}
+	return nil
+}
+
+// validateSecondaryBucket checks that the BackupPolicy of the source MySQLCluster
+// has the secondary bucket to restore from.
+// If the BackupPolicy does not exist, the restore Job reads `jobConfig.bucketConfig`
+// instead, so there is nothing to check.
+func (v *mysqlClusterValidator) validateSecondaryBucket(ctx context.Context, s *RestoreSpec, source *MySQLCluster, p *field.Path) field.ErrorList {
+	if source.Spec.BackupPolicyName == nil {
+		return nil
+	}
+
+	bp := &BackupPolicy{}
+	if err := v.reader.Get(ctx, client.ObjectKey{Namespace: s.SourceNamespace, Name: *source.Spec.BackupPolicyName}, bp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return field.ErrorList{field.InternalError(p, err)}
+	}
+	if bp.Spec.FindSecondaryBucket(s.SecondaryBucketName) == nil {
+		return field.ErrorList{field.NotFound(p, s.SecondaryBucketName)}
+	}
 	return nil
 }
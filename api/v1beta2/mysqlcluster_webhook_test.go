@@ -2,6 +2,7 @@ package v1beta2_test
 
 import (
 	"context"
+	"time"
 
 	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
 	"github.com/cybozu-go/moco/pkg/constants"
@@ -376,6 +377,38 @@ var _ = Describe("MySQLCluster Webhook", func() {
 		}
 		err = k8sClient.Create(ctx, r)
 		Expect(err).To(HaveOccurred())
+
+		r = makeMySQLCluster()
+		r.Spec.Restore = &mocov1beta2.RestoreSpec{
+			SourceName:      "test",
+			SourceNamespace: "test",
+			RestorePoint:    metav1.Now(),
+			JobConfig: mocov1beta2.JobConfig{
+				ServiceAccountName: "foo",
+				BucketConfig: mocov1beta2.BucketConfig{
+					BucketName:  "mybucket",
+					BackendType: "file",
+				},
+			},
+		}
+		err = k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+
+		r = makeMySQLCluster()
+		r.Spec.Restore = &mocov1beta2.RestoreSpec{
+			SourceName:      "test",
+			SourceNamespace: "test",
+			RestorePoint:    metav1.Now(),
+			StopGTID:        "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+			JobConfig: mocov1beta2.JobConfig{
+				ServiceAccountName: "foo",
+				BucketConfig: mocov1beta2.BucketConfig{
+					BucketName: "mybucket",
+				},
+			},
+		}
+		err = k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
 	})
 
 	It("should allow valid restore spec", func() {
@@ -384,6 +417,7 @@ var _ = Describe("MySQLCluster Webhook", func() {
 			SourceName:      "test",
 			SourceNamespace: "test",
 			RestorePoint:    metav1.Now(),
+			StopGTID:        "3E11FA47-71CA-11E1-9E33-C80AA9429562:23, 4d1e7e8f-71ca-11e1-9e33-c80aa9429562:1-5:7",
 			JobConfig: mocov1beta2.JobConfig{
 				ServiceAccountName: "foo",
 				BucketConfig: mocov1beta2.BucketConfig{
@@ -396,6 +430,171 @@ var _ = Describe("MySQLCluster Webhook", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should allow restore from a snapshot in the same namespace", func() {
+		r := makeMySQLCluster()
+		r.Spec.Restore = &mocov1beta2.RestoreSpec{
+			SourceName:         "test",
+			SourceNamespace:    "default",
+			RestorePoint:       metav1.Now(),
+			VolumeSnapshotName: "moco-test-20220501-000000",
+			JobConfig: mocov1beta2.JobConfig{
+				ServiceAccountName: "foo",
+				BucketConfig: mocov1beta2.BucketConfig{
+					BucketName: "mybucket",
+				},
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny restore from a snapshot in another namespace", func() {
+		r := makeMySQLCluster()
+		r.Spec.Restore = &mocov1beta2.RestoreSpec{
+			SourceName:         "test",
+			SourceNamespace:    "test",
+			RestorePoint:       metav1.Now(),
+			VolumeSnapshotName: "moco-test-20220501-000000",
+			JobConfig: mocov1beta2.JobConfig{
+				ServiceAccountName: "foo",
+				BucketConfig: mocov1beta2.BucketConfig{
+					BucketName: "mybucket",
+				},
+			},
+		}
+		err := k8sClient.Create(ctx, r)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny restore points outside the restorable period", func() {
+		source := makeMySQLCluster()
+		source.Name = "source"
+		err := k8sClient.Create(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			source.Finalizers = nil
+			err := k8sClient.Update(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Delete(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		base := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+		source.Status.Backup.Time = metav1.NewTime(base)
+		source.Status.Backup.EarliestRestorableTime = &metav1.Time{Time: base}
+		source.Status.Backup.LatestRestorableTime = &metav1.Time{Time: base.Add(4 * time.Hour)}
+		source.Status.Backup.RestorableGaps = []mocov1beta2.RestorableGap{
+			{Start: metav1.NewTime(base.Add(time.Hour)), End: metav1.NewTime(base.Add(2 * time.Hour))},
+		}
+		err = k8sClient.Status().Update(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, d := range []time.Duration{-time.Minute, 90 * time.Minute, 5 * time.Hour} {
+			r := makeMySQLCluster()
+			r.Spec.Restore = &mocov1beta2.RestoreSpec{
+				SourceName:      "source",
+				SourceNamespace: "default",
+				RestorePoint:    metav1.NewTime(base.Add(d)),
+				JobConfig: mocov1beta2.JobConfig{
+					ServiceAccountName: "foo",
+					BucketConfig: mocov1beta2.BucketConfig{
+						BucketName: "mybucket",
+					},
+				},
+			}
+			err = k8sClient.Create(ctx, r)
+			Expect(err).To(HaveOccurred(), "restore point: %v", d)
+		}
+
+		r := makeMySQLCluster()
+		r.Spec.Restore = &mocov1beta2.RestoreSpec{
+			SourceName:      "source",
+			SourceNamespace: "default",
+			RestorePoint:    metav1.NewTime(base.Add(3 * time.Hour)),
+			JobConfig: mocov1beta2.JobConfig{
+				ServiceAccountName: "foo",
+				BucketConfig: mocov1beta2.BucketConfig{
+					BucketName: "mybucket",
+				},
+			},
+		}
+		err = k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should validate restore from a secondary bucket", func() {
+		makeRestore := func(bucketName string) *mocov1beta2.MySQLCluster {
+			r := makeMySQLCluster()
+			r.Spec.Restore = &mocov1beta2.RestoreSpec{
+				SourceName:          "source",
+				SourceNamespace:     "default",
+				RestorePoint:        metav1.Now(),
+				SecondaryBucketName: bucketName,
+				JobConfig: mocov1beta2.JobConfig{
+					ServiceAccountName: "foo",
+					BucketConfig: mocov1beta2.BucketConfig{
+						BucketName: "mybucket",
+					},
+				},
+			}
+			return r
+		}
+
+		// without the source, jobConfig.bucketConfig is used.
+		err := k8sClient.Create(ctx, makeRestore("dr"))
+		Expect(err).NotTo(HaveOccurred())
+		err = deleteMySQLCluster()
+		Expect(err).NotTo(HaveOccurred())
+
+		bp := &mocov1beta2.BackupPolicy{}
+		bp.Namespace = "default"
+		bp.Name = "source-policy"
+		bp.Spec.Schedule = "*/5 * * * *"
+		bp.Spec.JobConfig.ServiceAccountName = "foo"
+		bp.Spec.JobConfig.BucketConfig.BucketName = "mybucket"
+		bp.Spec.SecondaryBuckets = []mocov1beta2.SecondaryBucket{
+			{Name: "dr", BucketConfig: mocov1beta2.BucketConfig{BucketName: "dr-bucket"}},
+		}
+		err = k8sClient.Create(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			err := k8sClient.Delete(ctx, bp)
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		source := makeMySQLCluster()
+		source.Name = "source"
+		source.Spec.BackupPolicyName = pointer.String("source-policy")
+		err = k8sClient.Create(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			source.Finalizers = nil
+			err := k8sClient.Update(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Delete(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		err = k8sClient.Create(ctx, makeRestore("unknown"))
+		Expect(err).To(HaveOccurred())
+
+		// the restorable period of the source applies to the secondary bucket too.
+		base := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+		source.Status.Backup.Time = metav1.NewTime(base)
+		source.Status.Backup.EarliestRestorableTime = &metav1.Time{Time: base}
+		source.Status.Backup.LatestRestorableTime = &metav1.Time{Time: base.Add(time.Hour)}
+		err = k8sClient.Status().Update(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = k8sClient.Create(ctx, makeRestore("dr"))
+		Expect(err).To(HaveOccurred())
+
+		r := makeRestore("dr")
+		r.Spec.Restore.RestorePoint = metav1.NewTime(base.Add(30 * time.Minute))
+		err = k8sClient.Create(ctx, r)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
 	It("should deny editing restore spec", func() {
 		r := makeMySQLCluster()
 		r.Spec.Restore = &mocov1beta2.RestoreSpec{
@@ -0,0 +1,67 @@
+package v1beta2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMySQLClusterValidatorRestore(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	source := &MySQLCluster{}
+	source.Namespace = "test"
+	source.Name = "source"
+	source.Spec.BackupPolicyName = pointer.String("policy")
+	source.Status.Backup.EarliestRestorableTime = &metav1.Time{Time: base}
+	source.Status.Backup.LatestRestorableTime = &metav1.Time{Time: base.Add(time.Hour)}
+
+	bp := &BackupPolicy{}
+	bp.Namespace = "test"
+	bp.Name = "policy"
+	bp.Spec.SecondaryBuckets = []SecondaryBucket{{Name: "dr"}}
+
+	v := &mysqlClusterValidator{reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(source, bp).Build()}
+
+	testCases := []struct {
+		name         string
+		sourceName   string
+		restorePoint time.Time
+		bucketName   string
+		valid        bool
+	}{
+		{"within the period", "source", base.Add(30 * time.Minute), "", true},
+		{"outside the period", "source", base.Add(2 * time.Hour), "", false},
+		{"secondary bucket within the period", "source", base.Add(30 * time.Minute), "dr", true},
+		{"secondary bucket outside the period", "source", base.Add(2 * time.Hour), "dr", false},
+		{"unknown secondary bucket", "source", base.Add(30 * time.Minute), "unknown", false},
+		{"no source", "lost", base.Add(2 * time.Hour), "dr", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &RestoreSpec{
+				SourceName:          tc.sourceName,
+				SourceNamespace:     "test",
+				RestorePoint:        metav1.NewTime(tc.restorePoint),
+				SecondaryBucketName: tc.bucketName,
+			}
+			errs := v.validateRestore(context.Background(), s)
+			if tc.valid && len(errs) != 0 {
+				t.Errorf("unexpected errors: %v", errs)
+			}
+			if !tc.valid && len(errs) == 0 {
+				t.Error("errors are expected")
+			}
+		})
+	}
+}
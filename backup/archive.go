@@ -0,0 +1,376 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/bucket"
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// segment is a binlog archive uploaded by BinlogArchiver.
+type segment struct {
+	Time time.Time
+	Key  string
+
+	// ManifestKey is the object key of the manifest of the segment.
+	// Empty if the segment has no manifest.
+	ManifestKey string
+}
+
+// listSegments lists binlog segments of a MySQLCluster in ascending order of time.
+func listSegments(ctx context.Context, b bucket.Bucket, namespace, name string) ([]segment, error) {
+	keys, err := b.List(ctx, calcSegmentPrefix(namespace, name)+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list object keys: %w", err)
+	}
+	return parseSegments(keys, calcSegmentPrefix(namespace, name)), nil
+}
+
+// parseSegments finds binlog segments stored under `segmentPrefix` from `keys`.
+// The result is sorted in ascending order of time.
+func parseSegments(keys []string, segmentPrefix string) []segment {
+	manifests := make(map[string]bool)
+	for _, key := range keys {
+		if path.Base(key) == constants.ManifestFilename {
+			manifests[path.Dir(key)] = true
+		}
+	}
+
+	var segments []segment
+	for _, key := range keys {
+		if path.Base(key) != constants.SegmentFilename {
+			continue
+		}
+		dir := path.Dir(key)
+		if path.Dir(dir) != segmentPrefix {
+			continue
+		}
+		t, err := time.Parse(constants.BackupTimeFormat, path.Base(dir))
+		if err != nil {
+			continue
+		}
+
+		s := segment{Time: t, Key: key}
+		if manifests[dir] {
+			s.ManifestKey = path.Join(dir, constants.ManifestFilename)
+		}
+		segments = append(segments, s)
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Time.Before(segments[j].Time)
+	})
+	return segments
+}
+
+// BinlogArchiver uploads binary logs of a MySQLCluster to a bucket periodically.
+//
+// Each upload is called a segment and contains the transactions executed
+// since the previous segment.  Segments are stored under the "binlog" directory
+// of the cluster's prefix, and used together with a full dump to restore data
+// to a point after the last full backup.
+type BinlogArchiver struct {
+	log           logr.Logger
+	client        client.Client
+	namespace     string
+	name          string
+	mysqlPassword string
+	workDir       string
+	bucket        bucket.Bucket
+	threads       int
+	interval      time.Duration
+	compression   Compression
+	secondaries   []SecondaryBucket
+
+	// state of the archive
+	initialized  bool
+	sourceIndex  int
+	sourceUUID   string
+	lastBinlog   string
+	archivedTime time.Time
+	archivedGTID string
+}
+
+// BinlogArchiverOption is an option for NewBinlogArchiver.
+type BinlogArchiverOption func(*BinlogArchiver)
+
+// WithSegmentCompression specifies the compression level of binlog segments.
+// The default is CompressionDefault.
+func WithSegmentCompression(c Compression) BinlogArchiverOption {
+	return func(ba *BinlogArchiver) {
+		ba.compression = c
+	}
+}
+
+// NewBinlogArchiver creates a BinlogArchiver.
+func NewBinlogArchiver(cfg *rest.Config, bc bucket.Bucket, dir, ns, name, password string, threads int, interval time.Duration, opts ...BinlogArchiverOption) (*BinlogArchiver, error) {
+	log := zap.New(zap.WriteTo(os.Stderr), zap.StacktraceLevel(zapcore.DPanicLevel))
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := mocov1beta2.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create controller-runtime client: %w", err)
+	}
+
+	ba := &BinlogArchiver{
+		log:           log,
+		client:        k8sClient,
+		namespace:     ns,
+		name:          name,
+		mysqlPassword: password,
+		workDir:       dir,
+		bucket:        bc,
+		threads:       threads,
+		interval:      interval,
+		compression:   CompressionDefault,
+		sourceIndex:   -1,
+	}
+	for _, o := range opts {
+		o(ba)
+	}
+	return ba, nil
+}
+
+// Run uploads binlog segments every interval until `ctx` is canceled.
+// Errors are logged and retried at the next interval.
+func (ba *BinlogArchiver) Run(ctx context.Context) error {
+	for {
+		if err := ba.archive(ctx); err != nil {
+			ba.log.Error(err, "failed to archive binlogs")
+		}
+
+		select {
+		case <-time.After(ba.interval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (ba *BinlogArchiver) archive(ctx context.Context) error {
+	cluster := &mocov1beta2.MySQLCluster{}
+	if err := ba.client.Get(ctx, client.ObjectKey{Namespace: ba.namespace, Name: ba.name}, cluster); err != nil {
+		return fmt.Errorf("failed to get MySQLCluster %s/%s: %w", ba.namespace, ba.name, err)
+	}
+
+	// segments are useless without a full dump to apply them on.
+	if cluster.Status.Backup.Time.IsZero() {
+		ba.log.Info("waiting for the first full backup")
+		return nil
+	}
+
+	if !ba.initialized {
+		if err := ba.initialize(ctx, cluster); err != nil {
+			return err
+		}
+	}
+
+	pods, err := listOrderedPods(ctx, ba.client, cluster)
+	if err != nil {
+		return err
+	}
+	index := ba.choosePod(cluster, pods)
+
+	op, err := newOperator(pods[index].Status.PodIP,
+		constants.MySQLPort, constants.BackupUser, ba.mysqlPassword, ba.threads)
+	if err != nil {
+		return fmt.Errorf("failed to create operator: %w", err)
+	}
+	defer op.Close()
+
+	now := time.Now().UTC()
+	st := &bkop.ServerStatus{}
+	if err := op.GetServerStatus(ctx, st); err != nil {
+		return fmt.Errorf("failed to get server status: %w", err)
+	}
+	if st.ExecutedGTIDSet == ba.archivedGTID {
+		return nil
+	}
+
+	// the binlog files of another instance may have different names.
+	binlogName := ba.lastBinlog
+	if st.UUID != ba.sourceUUID || binlogName == "" {
+		binlogs, err := op.GetBinlogs(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list binlog files: %w", err)
+		}
+		if len(binlogs) == 0 {
+			return fmt.Errorf("no binlog files found")
+		}
+		bkop.SortBinlogs(binlogs)
+		binlogName = binlogs[0]
+	}
+
+	binlogDir := filepath.Join(ba.workDir, "binlog")
+	if err := os.MkdirAll(binlogDir, 0755); err != nil {
+		return fmt.Errorf("failed to make binlog dump directory: %w", err)
+	}
+	defer os.RemoveAll(binlogDir)
+
+	// Transactions executed after GetServerStatus may be included in this segment
+	// and the next one.  They are skipped when applied twice thanks to GTID.
+	if err := op.DumpBinlog(ctx, binlogDir, binlogName, ba.archivedGTID); err != nil {
+		return fmt.Errorf("failed to dump binlogs: %w", err)
+	}
+
+	usage, err := dirUsage(binlogDir)
+	if err != nil {
+		return fmt.Errorf("failed to calculate dir usage: %w", err)
+	}
+
+	key := calcSegmentKey(ba.namespace, ba.name, constants.SegmentFilename, now)
+	obj, err := putArchive(ctx, ba.bucket, key, ba.workDir, "binlog", ba.compression, ba.threads, usage, nil)
+	if err != nil {
+		return err
+	}
+
+	m := &Manifest{
+		Version:         ManifestVersion,
+		Time:            now,
+		Namespace:       ba.namespace,
+		Name:            ba.name,
+		SourceIndex:     index,
+		SourceUUID:      st.UUID,
+		MySQLVersion:    st.Version,
+		BinlogFilename:  st.CurrentBinlog,
+		GTIDSet:         st.ExecutedGTIDSet,
+		PreviousGTIDSet: ba.archivedGTID,
+		Objects: map[string]ManifestObject{
+			constants.SegmentFilename: obj,
+		},
+	}
+	manifestKey := calcSegmentKey(ba.namespace, ba.name, constants.ManifestFilename, now)
+	if err := putManifest(ctx, ba.bucket, manifestKey, m); err != nil {
+		return fmt.Errorf("failed to upload the manifest: %w", err)
+	}
+
+	// segments failed to be copied here are copied by the next backup Job.
+	for _, sb := range ba.secondaries {
+		if _, err := copyBackupDir(ctx, ba.bucket, sb.Bucket, path.Dir(manifestKey), nil); err != nil {
+			ba.log.Error(err, "failed to copy the binlog segment", "bucket", sb.Name)
+		}
+	}
+
+	ba.sourceIndex = index
+	ba.sourceUUID = st.UUID
+	ba.lastBinlog = st.CurrentBinlog
+	ba.archivedTime = now
+	ba.archivedGTID = st.ExecutedGTIDSet
+	ba.log.Info("uploaded binlog segment", "key", key, "bytes", obj.Size, "source", index, "gtid", st.ExecutedGTIDSet)
+
+	if err := ba.extendLatestRestorableTime(ctx, now); err != nil {
+		ba.log.Error(err, "failed to update the latest restorable time")
+	}
+	return nil
+}
+
+// extendLatestRestorableTime updates the latest restorable time in the status of MySQLCluster.
+// A segment continues from the previous one or the last backup, so it extends
+// the last restorable range found by the backup Job.
+func (ba *BinlogArchiver) extendLatestRestorableTime(ctx context.Context, t time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &mocov1beta2.MySQLCluster{}
+		if err := ba.client.Get(ctx, client.ObjectKey{Namespace: ba.namespace, Name: ba.name}, cluster); err != nil {
+			return err
+		}
+
+		sb := &cluster.Status.Backup
+		if sb.LatestRestorableTime == nil || !t.After(sb.LatestRestorableTime.Time) {
+			return nil
+		}
+		latest := metav1.NewTime(t)
+		sb.LatestRestorableTime = &latest
+		return ba.client.Status().Update(ctx, cluster)
+	})
+}
+
+// initialize resumes archiving from the latest segment or the last full backup,
+// whichever is newer.
+func (ba *BinlogArchiver) initialize(ctx context.Context, cluster *mocov1beta2.MySQLCluster) error {
+	lastBackup := &cluster.Status.Backup
+	ba.sourceIndex = lastBackup.SourceIndex
+	ba.sourceUUID = lastBackup.SourceUUID
+	ba.lastBinlog = lastBackup.BinlogFilename
+	ba.archivedTime = lastBackup.Time.Time
+	ba.archivedGTID = lastBackup.GTIDSet
+
+	segments, err := listSegments(ctx, ba.bucket, ba.namespace, ba.name)
+	if err != nil {
+		return err
+	}
+	for i := len(segments) - 1; i >= 0; i-- {
+		s := segments[i]
+		if !s.Time.After(ba.archivedTime) {
+			break
+		}
+		if s.ManifestKey == "" {
+			continue
+		}
+
+		m, err := getManifest(ctx, ba.bucket, s.ManifestKey)
+		if err != nil {
+			return err
+		}
+		ba.sourceIndex = m.SourceIndex
+		ba.sourceUUID = m.SourceUUID
+		ba.lastBinlog = m.BinlogFilename
+		ba.archivedTime = m.Time
+		ba.archivedGTID = m.GTIDSet
+		break
+	}
+
+	ba.initialized = true
+	ba.log.Info("resuming binlog archive",
+		"time", ba.archivedTime.Format(constants.BackupTimeFormat),
+		"uuid", ba.sourceUUID,
+		"gtid", ba.archivedGTID)
+	return nil
+}
+
+// choosePod chooses the instance to read binlogs from.
+// It prefers the current source as long as it is a ready replica.
+func (ba *BinlogArchiver) choosePod(cluster *mocov1beta2.MySQLCluster, pods []*corev1.Pod) int {
+	primary := cluster.Status.CurrentPrimaryIndex
+	if len(pods) == 1 {
+		return 0
+	}
+
+	if ba.sourceIndex >= 0 && ba.sourceIndex < len(pods) &&
+		ba.sourceIndex != primary && podIsReady(pods[ba.sourceIndex]) {
+		return ba.sourceIndex
+	}
+
+	for i := range pods {
+		if i == primary {
+			continue
+		}
+		if podIsReady(pods[i]) {
+			return i
+		}
+	}
+	return primary
+}
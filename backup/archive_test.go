@@ -0,0 +1,276 @@
+package backup
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseSegments(t *testing.T) {
+	keys := []string{
+		"moco/test/test/20210525-112233/dump.tar",
+		"moco/test/test/binlog/20210525-120000/manifest.json",
+		"moco/test/test/binlog/20210525-120000/segment.tar.zst",
+		"moco/test/test/binlog/20210525-113000/segment.tar.zst",
+		"moco/test/test/binlog/20210525-130000/manifest.json", // no segment
+		"moco/test/test/binlog/garbage/segment.tar.zst",
+		"moco/test/test2/binlog/20210525-120000/segment.tar.zst",
+	}
+
+	segments := parseSegments(keys, "moco/test/test/binlog")
+	expect := []segment{
+		{
+			Time: time.Date(2021, time.May, 25, 11, 30, 0, 0, time.UTC),
+			Key:  "moco/test/test/binlog/20210525-113000/segment.tar.zst",
+		},
+		{
+			Time:        time.Date(2021, time.May, 25, 12, 0, 0, 0, time.UTC),
+			Key:         "moco/test/test/binlog/20210525-120000/segment.tar.zst",
+			ManifestKey: "moco/test/test/binlog/20210525-120000/manifest.json",
+		},
+	}
+	if !cmp.Equal(segments, expect) {
+		t.Errorf("unexpected segments: %s", cmp.Diff(expect, segments))
+	}
+}
+
+func TestSelectSegments(t *testing.T) {
+	date := func(hour, min int) time.Time {
+		return time.Date(2021, time.May, 25, hour, min, 0, 0, time.UTC)
+	}
+	segments := []segment{
+		{Time: date(10, 0)},
+		{Time: date(11, 0)},
+		{Time: date(12, 0)},
+		{Time: date(13, 0)},
+	}
+
+	testCases := []struct {
+		name         string
+		backupTime   time.Time
+		restorePoint time.Time
+		expect       []time.Time
+	}{
+		{"middle", date(10, 30), date(11, 30), []time.Time{date(11, 0), date(12, 0)}},
+		{"exact", date(10, 0), date(12, 0), []time.Time{date(11, 0), date(12, 0)}},
+		{"before-first", date(9, 0), date(9, 30), []time.Time{date(10, 0)}},
+		{"after-last", date(12, 30), date(14, 0), []time.Time{date(13, 0)}},
+		{"no-segments", date(13, 30), date(14, 0), nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rm := &RestoreManager{log: logr.Discard(), restorePoint: tc.restorePoint}
+			var actual []time.Time
+			for _, s := range rm.selectSegments(segments, tc.backupTime) {
+				actual = append(actual, s.Time)
+			}
+			if !cmp.Equal(actual, tc.expect) {
+				t.Errorf("unexpected segments: %s", cmp.Diff(tc.expect, actual))
+			}
+		})
+	}
+}
+
+func TestVerifySegment(t *testing.T) {
+	ctx := context.Background()
+	rm := &RestoreManager{log: logr.Discard()}
+	op := &mockOperator{missingGTID: "uuid1:101-200"}
+
+	// a segment whose previous GTID set has been restored.
+	m := &Manifest{GTIDSet: "uuid1:1-300", PreviousGTIDSet: "uuid1:1-100"}
+	if err := rm.verifySegment(ctx, op, "segment1", m); err != nil {
+		t.Error(err)
+	}
+
+	// a segment that follows a missing one.
+	m = &Manifest{GTIDSet: "uuid1:1-300", PreviousGTIDSet: "uuid1:101-200"}
+	if err := rm.verifySegment(ctx, op, "segment2", m); err == nil {
+		t.Error("a gap between segments should be detected")
+	}
+
+	// segments without the record cannot be verified.
+	if err := rm.verifySegment(ctx, op, "segment3", &Manifest{GTIDSet: "uuid1:1-300"}); err != nil {
+		t.Error(err)
+	}
+	if err := rm.verifySegment(ctx, op, "segment4", nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBinlogArchiverChoosePod(t *testing.T) {
+	makePods := func(ready ...bool) []*corev1.Pod {
+		pods := make([]*corev1.Pod, len(ready))
+		for i, r := range ready {
+			status := corev1.ConditionFalse
+			if r {
+				status = corev1.ConditionTrue
+			}
+			pods[i] = &corev1.Pod{}
+			pods[i].Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}
+		}
+		return pods
+	}
+
+	testCases := []struct {
+		name        string
+		primary     int
+		sourceIndex int
+		pods        []*corev1.Pod
+		expect      int
+	}{
+		{"single", 0, -1, makePods(true), 0},
+		{"first-replica", 0, -1, makePods(true, true, true), 1},
+		{"keep-source", 0, 2, makePods(true, true, true), 2},
+		{"source-not-ready", 0, 2, makePods(true, true, false), 1},
+		{"source-promoted", 2, 2, makePods(true, true, true), 0},
+		{"no-ready-replica", 1, 0, makePods(false, true, false), 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &mocov1beta2.MySQLCluster{}
+			cluster.Status.CurrentPrimaryIndex = tc.primary
+			ba := &BinlogArchiver{log: logr.Discard(), sourceIndex: tc.sourceIndex}
+			if actual := ba.choosePod(cluster, tc.pods); actual != tc.expect {
+				t.Errorf("unexpected index: expected %d, actual %d", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestPruneSegments(t *testing.T) {
+	bc := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20220501-000000/dump.tar":               nil,
+		"moco/test/test/20220502-000000/dump.tar":               nil,
+		"moco/test/test/20220503-000000/dump.tar":               nil,
+		"moco/test/test/binlog/20220501-120000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20220501-120000/manifest.json":   nil,
+		"moco/test/test/binlog/20220502-000000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20220502-120000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20220502-120000/manifest.json":   nil,
+	}}
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	bm := &BackupManager{
+		log:       logr.Discard(),
+		cluster:   cluster,
+		bucket:    bc,
+		retention: Retention{KeepLast: 2},
+		startTime: time.Date(2022, time.May, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	if err := bm.prune(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for k := range bc.contents {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	expect := []string{
+		"moco/test/test/20220502-000000/dump.tar",
+		"moco/test/test/20220503-000000/dump.tar",
+		"moco/test/test/binlog/20220502-120000/manifest.json",
+		"moco/test/test/binlog/20220502-120000/segment.tar.zst",
+	}
+	if !cmp.Equal(keys, expect) {
+		t.Errorf("unexpected keys: %s", cmp.Diff(expect, keys))
+	}
+}
+
+func TestListBackupsWithSegments(t *testing.T) {
+	ctx := context.Background()
+	b := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20210525-000000/dump.tar":               nil,
+		"moco/test/test/20210526-000000/dump.tar":               nil,
+		"moco/test/test/binlog/20210525-120000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20210526-120000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20210526-130000/segment.tar.zst": nil,
+	}}
+
+	backups, err := ListBackups(ctx, b, "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("unexpected number of backups: %d", len(backups))
+	}
+	if until := backups[0].Until; !until.Equal(time.Date(2021, time.May, 25, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected until: %s", until)
+	}
+	if until := backups[1].Until; !until.Equal(time.Date(2021, time.May, 26, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected until: %s", until)
+	}
+	if len(backups[0].Segments) != 1 || len(backups[1].Segments) != 2 {
+		t.Errorf("unexpected segments: %v, %v", backups[0].Segments, backups[1].Segments)
+	}
+}
+
+func TestListBackupsSegmentGap(t *testing.T) {
+	ctx := context.Background()
+	b := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20210525-000000/dump.tar":      nil,
+		"moco/test/test/20210525-000000/manifest.json": []byte(`{"version":1,"gtidSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10"}`),
+		"moco/test/test/20210526-000000/dump.tar":      nil,
+		"moco/test/test/20210526-000000/manifest.json": []byte(`{"version":1,"gtidSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-100"}`),
+
+		"moco/test/test/binlog/20210525-010000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20210525-010000/manifest.json": []byte(`{"version":1,` +
+			`"gtidSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-20","previousGTIDSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10"}`),
+		// no manifest; not checked
+		"moco/test/test/binlog/20210525-013000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20210525-020000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20210525-020000/manifest.json": []byte(`{"version":1,` +
+			`"gtidSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-30","previousGTIDSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-20"}`),
+		// transactions 31-40 are missing
+		"moco/test/test/binlog/20210525-030000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20210525-030000/manifest.json": []byte(`{"version":1,` +
+			`"gtidSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-50","previousGTIDSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-40"}`),
+		"moco/test/test/binlog/20210525-040000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20210525-040000/manifest.json": []byte(`{"version":1,` +
+			`"gtidSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-60","previousGTIDSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-50"}`),
+
+		// transactions 51-100 are lost on the new source
+		"moco/test/test/binlog/20210526-010000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20210526-010000/manifest.json": []byte(`{"version":1,` +
+			`"gtidSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-50,4d1e7e8f-71ca-11e1-9e33-c80aa9429562:1-5","previousGTIDSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-50"}`),
+	}}
+
+	backups, err := ListBackups(ctx, b, "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("unexpected number of backups: %d", len(backups))
+	}
+	if until := backups[0].Until; !until.Equal(time.Date(2021, time.May, 25, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected until: %s", until)
+	}
+	if len(backups[0].Segments) != 3 {
+		t.Errorf("unexpected segments: %v", backups[0].Segments)
+	}
+	if until := backups[1].Until; !until.Equal(backups[1].Time) {
+		t.Errorf("unexpected until: %s", until)
+	}
+	if len(backups[1].Segments) != 0 {
+		t.Errorf("unexpected segments: %v", backups[1].Segments)
+	}
+
+	ranges := RestorableRanges(backups)
+	if len(ranges) != 2 {
+		t.Fatalf("the gap is not reflected: %v", ranges)
+	}
+	if !ranges[0].End.Equal(backups[0].Until) || !ranges[1].Start.Equal(backups[1].Time) {
+		t.Errorf("unexpected ranges: %v", ranges)
+	}
+}
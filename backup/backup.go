@@ -3,10 +3,8 @@ package backup
 import (
 	"context"
 	"fmt"
-	"io"
 	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -40,6 +38,22 @@ type BackupManager struct {
 	workDir       string
 	bucket        bucket.Bucket
 	threads       int
+	retention     Retention
+	backupName    string
+	filter        *bkop.Filter
+	sourcePolicy  *SourcePolicy
+	hooks         *mocov1beta2.BackupHooks
+	streaming     bool
+	snapshot      bool
+	snapshotClass string
+	secondaries   []SecondaryBucket
+	binlogOnly    bool
+
+	dumpCompression   Compression
+	binlogCompression Compression
+
+	// backup is the MySQLBackup that requested this backup, if any.
+	backup *mocov1beta2.MySQLBackup
 
 	// status fields
 	startTime    time.Time
@@ -48,11 +62,59 @@ type BackupManager struct {
 	gtidSet      string
 	dumpSize     int64
 	binlogSize   int64
+	dumpKey      string
+	dumpObject   ManifestObject
+	dumpObjects  map[string]ManifestObject
+	binlogObject ManifestObject
+	binlogKey    string
+	snapshotName string
 	workDirUsage int64
 	warnings     []string
+
+	// uploadBytes and uploadTime are the total size and elapsed time of
+	// uploading the dump and binlog files.
+	uploadBytes int64
+	uploadTime  time.Duration
+
+	// restorable is the ranges of time to which data can be restored from the bucket.
+	restorable []TimeRange
+}
+
+// BackupOption is an option for NewBackupManager.
+type BackupOption func(*BackupManager)
+
+// WithRetention specifies the rules to prune old backups after a successful backup.
+func WithRetention(r Retention) BackupOption {
+	return func(bm *BackupManager) {
+		bm.retention = r
+	}
 }
 
-func NewBackupManager(cfg *rest.Config, bc bucket.Bucket, dir, ns, name, password string, threads int) (*BackupManager, error) {
+// WithBackupFilter specifies schemas and tables to be backed up.
+func WithBackupFilter(f *bkop.Filter) BackupOption {
+	return func(bm *BackupManager) {
+		bm.filter = f
+	}
+}
+
+// WithCompression specifies the compression levels of the dump and binlog archives.
+// By default, the dump is compressed at CompressionFastest and binlogs at CompressionDefault.
+func WithCompression(dump, binlog Compression) BackupOption {
+	return func(bm *BackupManager) {
+		bm.dumpCompression = dump
+		bm.binlogCompression = binlog
+	}
+}
+
+// WithBackupName specifies the name of the MySQLBackup that requested the backup.
+// The result of the backup is recorded in its status.
+func WithBackupName(name string) BackupOption {
+	return func(bm *BackupManager) {
+		bm.backupName = name
+	}
+}
+
+func NewBackupManager(cfg *rest.Config, bc bucket.Bucket, dir, ns, name, password string, threads int, opts ...BackupOption) (*BackupManager, error) {
 	log := zap.New(zap.WriteTo(os.Stderr), zap.StacktraceLevel(zapcore.DPanicLevel))
 	scheme := runtime.NewScheme()
 	if err := clientgoscheme.AddToScheme(scheme); err != nil {
@@ -77,7 +139,7 @@ func NewBackupManager(cfg *rest.Config, bc bucket.Bucket, dir, ns, name, passwor
 		return nil, fmt.Errorf("failed to get reference for MySQLCluster: %w", err)
 	}
 
-	return &BackupManager{
+	bm := &BackupManager{
 		log:           log,
 		client:        k8sClient,
 		cluster:       cluster,
@@ -86,35 +148,51 @@ func NewBackupManager(cfg *rest.Config, bc bucket.Bucket, dir, ns, name, passwor
 		workDir:       dir,
 		bucket:        bc,
 		threads:       threads,
-	}, nil
-}
 
-func (bm *BackupManager) Backup(ctx context.Context) error {
-	pods := &corev1.PodList{}
-	if err := bm.client.List(ctx, pods, client.InNamespace(bm.cluster.Namespace), client.MatchingLabels{
-		constants.LabelAppName:      constants.AppNameMySQL,
-		constants.LabelAppInstance:  bm.cluster.Name,
-		constants.LabelAppCreatedBy: constants.AppCreator,
-	}); err != nil {
-		return fmt.Errorf("failed to get pod list: %w", err)
+		dumpCompression:   CompressionFastest,
+		binlogCompression: CompressionDefault,
+	}
+	for _, o := range opts {
+		o(bm)
 	}
 
-	if len(pods.Items) != int(bm.cluster.Spec.Replicas) {
-		return fmt.Errorf("too few Pods for %s/%s", bm.cluster.Namespace, bm.cluster.Name)
+	if bm.backupName != "" {
+		backup := &mocov1beta2.MySQLBackup{}
+		if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: bm.backupName}, backup); err != nil {
+			return nil, fmt.Errorf("failed to get MySQLBackup %s/%s: %w", ns, bm.backupName, err)
+		}
+		bm.backup = backup
 	}
+	return bm, nil
+}
 
-	orderedPods := make([]*corev1.Pod, bm.cluster.Spec.Replicas)
-	for i, pod := range pods.Items {
-		fields := strings.Split(pod.Name, "-")
-		index, err := strconv.Atoi(fields[len(fields)-1])
+func (bm *BackupManager) Backup(ctx context.Context) error {
+	switch {
+	case bm.binlogOnly:
+		// the full backup saves the binlogs by itself.
+		running, err := bm.runningBackupJob(ctx, bm.isFullBackupJob)
 		if err != nil {
-			return fmt.Errorf("bad pod name: %s", pod.Name)
+			return err
 		}
-
-		if index < 0 || index >= len(pods.Items) {
-			return fmt.Errorf("index out of range: %d", index)
+		if running != "" {
+			bm.log.Info("skipping the binlog-only backup while a full backup is running", "jobName", running)
+			return nil
 		}
-		orderedPods[index] = &pods.Items[i]
+	case bm.backupName == "":
+		if err := bm.waitForBackupJobs(ctx, bm.blocksScheduledBackup); err != nil {
+			return err
+		}
+	}
+
+	if bm.binlogOnly && bm.cluster.Status.Backup.Time.IsZero() {
+		// binlogs are useless without a full backup to apply them on.
+		bm.log.Info("skipping the binlog-only backup until the first full backup is taken")
+		return nil
+	}
+
+	orderedPods, err := listOrderedPods(ctx, bm.client, bm.cluster)
+	if err != nil {
+		return err
 	}
 
 	sourceIndex, err := bm.ChoosePod(ctx, orderedPods)
@@ -134,21 +212,51 @@ func (bm *BackupManager) Backup(ctx context.Context) error {
 		return fmt.Errorf("failed to get server status: %w", err)
 	}
 
-	bm.startTime = time.Now().UTC()
+	// object keys and the status record the time in seconds.
+	bm.startTime = time.Now().UTC().Truncate(time.Second)
 	bm.log.Info("chosen source",
 		"index", sourceIndex,
 		"time", bm.startTime.Format(constants.BackupTimeFormat),
 		"uuid", bm.status.UUID,
 		"binlog", bm.status.CurrentBinlog)
 
-	if err := bm.backupFull(ctx, op); err != nil {
-		return fmt.Errorf("failed to take a full dump: %w", err)
+	if bm.binlogOnly {
+		return bm.backupBinlogOnly(ctx, op)
+	}
+
+	if bm.hooks != nil {
+		if err := bm.runHooks(ctx, op, HookPhasePreBackup, bm.hooks.PreBackup); err != nil {
+			return err
+		}
 	}
 
+	switch {
+	case bm.snapshot:
+		if err := bm.backupSnapshot(ctx, op); err != nil {
+			return fmt.Errorf("failed to take a snapshot: %w", err)
+		}
+	case bm.streaming:
+		if err := bm.backupFullStreaming(ctx, op); err != nil {
+			return fmt.Errorf("failed to take a full dump: %w", err)
+		}
+	default:
+		if err := bm.backupFull(ctx, op); err != nil {
+			return fmt.Errorf("failed to take a full dump: %w", err)
+		}
+	}
+
+	if err := bm.putManifest(ctx); err != nil {
+		return fmt.Errorf("failed to upload the manifest: %w", err)
+	}
+
+	// A backup into another bucket than the policy's is standalone.
+	// It must not be chained with the scheduled backups.
+	standalone := bm.backup != nil && bm.backup.Spec.BucketConfig != nil
+
 	// dump and upload binlog for the second or later backups
 	lastBackup := &bm.cluster.Status.Backup
-	if !lastBackup.Time.IsZero() {
-		if err := bm.backupBinlog(ctx, op); err != nil {
+	if !lastBackup.Time.IsZero() && !standalone {
+		if err := bm.backupBinlog(ctx, op, constants.BinlogFilename); err != nil {
 			// since the full backup has succeeded, we should continue
 			ev := event.BackupNoBinlog.ToEvent(bm.clusterRef)
 			if err := bm.client.Create(ctx, ev); err != nil {
@@ -156,12 +264,70 @@ func (bm *BackupManager) Backup(ctx context.Context) error {
 			}
 			bm.log.Error(err, "failed to backup binary logs")
 			bm.warnings = append(bm.warnings, fmt.Sprintf("failed to backup binary logs: %v", err))
+		} else if err := bm.addBinlogToManifest(ctx, lastBackup.Time.Time, constants.BinlogFilename); err != nil {
+			bm.log.Error(err, "failed to update the manifest of the last backup")
+			bm.warnings = append(bm.warnings, fmt.Sprintf("failed to update the manifest of the last backup: %v", err))
+		}
+	}
+
+	// copy backups before pruning so that every backup is copied at least once.
+	if !standalone {
+		bm.replicate(ctx)
+	}
+
+	if !bm.retention.IsZero() {
+		if err := bm.prune(ctx); err != nil {
+			bm.log.Error(err, "failed to prune old backups")
+			bm.warnings = append(bm.warnings, fmt.Sprintf("failed to prune old backups: %v", err))
+		}
+		if !standalone {
+			bm.pruneSecondaries(ctx)
+		}
+	}
+
+	if !standalone {
+		bm.findRestorableRanges(ctx)
+	}
+
+	// The backup is complete at this point, so it is recorded even if
+	// a postBackup hook aborts.  The Job fails after recording it.
+	var hookErr error
+	if bm.hooks != nil {
+		hookErr = bm.runHooks(ctx, op, HookPhasePostBackup, bm.hooks.PostBackup)
+		if hookErr != nil {
+			bm.log.Error(hookErr, "postBackup hook failed")
+			bm.warnings = append(bm.warnings, hookErr.Error())
 		}
 	}
 
 	elapsed := time.Since(bm.startTime)
 
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	if !standalone {
+		if err := bm.updateClusterStatus(ctx, elapsed); err != nil {
+			return fmt.Errorf("failed to update MySQLCluster status: %w", err)
+		}
+	}
+
+	if bm.backup != nil {
+		if err := bm.updateBackupStatus(ctx); err != nil {
+			return fmt.Errorf("failed to update MySQLBackup status: %w", err)
+		}
+	}
+
+	ev := event.BackupCreated.ToEvent(bm.clusterRef)
+	if err := bm.client.Create(ctx, ev); err != nil {
+		bm.log.Error(err, "failed to create an event for backup creation")
+	}
+	if hookErr != nil {
+		return hookErr
+	}
+	bm.log.Info("backup finished successfully")
+
+	return nil
+}
+
+func (bm *BackupManager) updateClusterStatus(ctx context.Context, elapsed time.Duration) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		cluster := &mocov1beta2.MySQLCluster{}
 		if err := bm.client.Get(ctx, client.ObjectKeyFromObject(bm.cluster), cluster); err != nil {
 			return err
@@ -170,31 +336,111 @@ func (bm *BackupManager) Backup(ctx context.Context) error {
 		sb := &cluster.Status.Backup
 		sb.Time = metav1.NewTime(bm.startTime)
 		sb.Elapsed = metav1.Duration{Duration: elapsed}
-		sb.SourceIndex = sourceIndex
+		sb.SourceIndex = bm.sourceIndex
 		sb.SourceUUID = bm.status.UUID
 		sb.BinlogFilename = bm.status.CurrentBinlog
 		sb.GTIDSet = bm.gtidSet
+		sb.BinlogBackupTime = nil
+		sb.VolumeSnapshotName = bm.snapshotName
 		sb.DumpSize = bm.dumpSize
 		sb.BinlogSize = bm.binlogSize
 		sb.WorkDirUsage = bm.workDirUsage
+		sb.UploadThroughput = bm.uploadThroughput()
+		if len(bm.restorable) > 0 {
+			setRestorableRanges(sb, bm.restorable)
+		}
 		sb.Warnings = bm.warnings
 
 		return bm.client.Status().Update(ctx, cluster)
 	})
+}
+
+// findRestorableRanges lists the backups in the bucket to find the ranges of time
+// to which data can be restored.  Ranges are separated where binlogs are missing.
+func (bm *BackupManager) findRestorableRanges(ctx context.Context) {
+	backups, err := ListBackups(ctx, bm.bucket, bm.cluster.Namespace, bm.cluster.Name)
 	if err != nil {
-		return fmt.Errorf("failed to update MySQLCluster status: %w", err)
+		bm.log.Error(err, "failed to list backups")
+		bm.warnings = append(bm.warnings, fmt.Sprintf("failed to find restorable ranges: %v", err))
+		return
 	}
 
-	ev := event.BackupCreated.ToEvent(bm.clusterRef)
-	if err := bm.client.Create(ctx, ev); err != nil {
-		bm.log.Error(err, "failed to create an event for backup creation")
+	bm.restorable = RestorableRanges(backups)
+	for i := 1; i < len(bm.restorable); i++ {
+		bm.log.Info("binlogs are missing",
+			"from", bm.restorable[i-1].End.Format(constants.BackupTimeFormat),
+			"to", bm.restorable[i].Start.Format(constants.BackupTimeFormat))
 	}
-	bm.log.Info("backup finished successfully")
+}
 
-	return nil
+// setRestorableRanges records the restorable ranges of time in the backup status.
+// `ranges` must not be empty.
+func setRestorableRanges(sb *mocov1beta2.BackupStatus, ranges []TimeRange) {
+	earliest := metav1.NewTime(ranges[0].Start)
+	latest := metav1.NewTime(ranges[len(ranges)-1].End)
+	sb.EarliestRestorableTime = &earliest
+	sb.LatestRestorableTime = &latest
+
+	sb.RestorableGaps = nil
+	for i := 1; i < len(ranges); i++ {
+		sb.RestorableGaps = append(sb.RestorableGaps, mocov1beta2.RestorableGap{
+			Start: metav1.NewTime(ranges[i-1].End),
+			End:   metav1.NewTime(ranges[i].Start),
+		})
+	}
+}
+
+func (bm *BackupManager) addUpload(bytes int64, elapsed time.Duration) {
+	bm.uploadBytes += bytes
+	bm.uploadTime += elapsed
+}
+
+// uploadThroughput returns the effective upload throughput in bytes per second.
+func (bm *BackupManager) uploadThroughput() int64 {
+	if bm.uploadTime <= 0 {
+		return 0
+	}
+	return int64(float64(bm.uploadBytes) / bm.uploadTime.Seconds())
+}
+
+// updateBackupStatus records the result of the backup in the status of the MySQLBackup.
+func (bm *BackupManager) updateBackupStatus(ctx context.Context) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		backup := &mocov1beta2.MySQLBackup{}
+		if err := bm.client.Get(ctx, client.ObjectKeyFromObject(bm.backup), backup); err != nil {
+			return err
+		}
+
+		now := metav1.Now()
+		backupTime := metav1.NewTime(bm.startTime)
+		st := &backup.Status
+		st.Phase = mocov1beta2.MySQLBackupSucceeded
+		st.Message = ""
+		st.CompletionTime = &now
+		st.BackupTime = &backupTime
+		st.SourceIndex = bm.sourceIndex
+		st.SourceUUID = bm.status.UUID
+		st.BinlogFilename = bm.status.CurrentBinlog
+		st.GTIDSet = bm.gtidSet
+		st.VolumeSnapshotName = bm.snapshotName
+		st.DumpSize = bm.dumpSize
+		st.BinlogSize = bm.binlogSize
+		st.WorkDirUsage = bm.workDirUsage
+		st.UploadThroughput = bm.uploadThroughput()
+		st.DumpKey = bm.dumpKey
+		st.ManifestKey = calcKey(bm.cluster.Namespace, bm.cluster.Name, constants.ManifestFilename, bm.startTime)
+		st.BinlogKey = bm.binlogKey
+		st.Warnings = bm.warnings
+
+		return bm.client.Status().Update(ctx, backup)
+	})
 }
 
 func (bm *BackupManager) ChoosePod(ctx context.Context, pods []*corev1.Pod) (int, error) {
+	if bm.sourcePolicy != nil {
+		return bm.choosePodByPolicy(ctx, pods)
+	}
+
 	cluster := bm.cluster
 	// if this is the first time
 	if cluster.Status.Backup.Time.IsZero() {
@@ -286,7 +532,7 @@ func (bm *BackupManager) backupFull(ctx context.Context, op bkop.Operator) error
 	}
 	defer os.RemoveAll(dumpDir)
 
-	if err := op.DumpFull(ctx, dumpDir); err != nil {
+	if err := op.DumpFull(ctx, dumpDir, bm.filter); err != nil {
 		return fmt.Errorf("failed to take a full dump: %w", err)
 	}
 
@@ -303,43 +549,24 @@ func (bm *BackupManager) backupFull(ctx context.Context, op bkop.Operator) error
 	bm.workDirUsage = usage
 	bm.log.Info("work dir usage (full dump)", "bytes", usage)
 
-	tarCmd := exec.Command("tar", "-c", "-f", "-", "-C", bm.workDir, "dump")
-	pr, pw, err := os.Pipe()
-	if err != nil {
-		return fmt.Errorf("failed to create pipe: %w", err)
-	}
-	defer func() {
-		if pr != nil {
-			pr.Close()
-		}
-		if pw != nil {
-			pw.Close()
-		}
-	}()
-	tarCmd.Stdout = pw
-	tarCmd.Stderr = os.Stderr
-
-	if err := tarCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start tar process: %w", err)
-	}
-	pw.Close()
-	pw = nil
-
-	bw := &ByteCountWriter{}
+	bw := &ByteCountWriter{Progress: progressLogger(bm.log, "archiving dump files", usage)}
 	key := calcKey(bm.cluster.Namespace, bm.cluster.Name, constants.DumpFilename, bm.startTime)
-	if err := bm.bucket.Put(ctx, key, io.TeeReader(pr, bw), usage); err != nil {
-		return fmt.Errorf("failed to put dump.tar: %w", err)
-	}
-	if err := tarCmd.Wait(); err != nil {
-		return fmt.Errorf("tar command failed: %w", err)
+	uploadStart := time.Now()
+	obj, err := putArchive(ctx, bm.bucket, key, bm.workDir, "dump", bm.dumpCompression, bm.threads, usage, bw)
+	if err != nil {
+		return err
 	}
 
-	bm.dumpSize = bw.Written()
+	bm.addUpload(obj.Size, time.Since(uploadStart))
+	bm.dumpSize = obj.Size
+	bm.dumpObject = obj
+	bm.dumpKey = key
 	bm.log.Info("uploaded dump file", "key", key, "bytes", bm.dumpSize)
 	return nil
 }
 
-func (bm *BackupManager) backupBinlog(ctx context.Context, op bkop.Operator) error {
+// backupBinlog uploads the binlogs since the last backup as `filename` in the directory of the last backup.
+func (bm *BackupManager) backupBinlog(ctx context.Context, op bkop.Operator, filename string) error {
 	binlogDir := filepath.Join(bm.workDir, "binlog")
 	if err := os.MkdirAll(binlogDir, 0755); err != nil {
 		return fmt.Errorf("failed to make binlog dump directory: %w", err)
@@ -348,7 +575,7 @@ func (bm *BackupManager) backupBinlog(ctx context.Context, op bkop.Operator) err
 
 	lastBackup := &bm.cluster.Status.Backup
 	binlogName := lastBackup.BinlogFilename
-	if bm.sourceIndex != lastBackup.SourceIndex {
+	if bm.sourceIndex != lastBackup.SourceIndex || bm.status.UUID != lastBackup.SourceUUID {
 		binlogs, err := op.GetBinlogs(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to list binlog files: %w", err)
@@ -373,66 +600,114 @@ func (bm *BackupManager) backupBinlog(ctx context.Context, op bkop.Operator) err
 		bm.workDirUsage = usage
 	}
 
-	tarCmd := exec.Command("tar", "-c", "-f", "-", "-C", bm.workDir, "binlog")
-	pr, pw, err := os.Pipe()
+	key := calcKey(bm.cluster.Namespace, bm.cluster.Name, filename, lastBackup.Time.Time)
+	uploadStart := time.Now()
+	bw := &ByteCountWriter{Progress: progressLogger(bm.log, "archiving binlog files", usage)}
+	obj, err := putArchive(ctx, bm.bucket, key, bm.workDir, "binlog", bm.binlogCompression, bm.threads, usage, bw)
 	if err != nil {
-		return fmt.Errorf("failed to create pipe: %w", err)
+		return err
 	}
-	defer func() {
-		if pr != nil {
-			pr.Close()
-		}
-		if pw != nil {
-			pw.Close()
+
+	bm.addUpload(obj.Size, time.Since(uploadStart))
+	bm.binlogSize = obj.Size
+	bm.binlogObject = obj
+	bm.binlogKey = key
+	bm.log.Info("uploaded binlog files", "key", key, "bytes", bm.binlogSize)
+	return nil
+}
+
+func (bm *BackupManager) putManifest(ctx context.Context) error {
+	m := &Manifest{
+		Version:        ManifestVersion,
+		Time:           bm.startTime,
+		Namespace:      bm.cluster.Namespace,
+		Name:           bm.cluster.Name,
+		SourceIndex:    bm.sourceIndex,
+		SourceUUID:     bm.status.UUID,
+		MySQLVersion:   bm.status.Version,
+		BinlogFilename: bm.status.CurrentBinlog,
+		GTIDSet:        bm.gtidSet,
+		Objects:        make(map[string]ManifestObject),
+		ClusterSpec:    bm.cluster.Spec.DeepCopy(),
+	}
+	switch {
+	case bm.snapshot:
+		m.VolumeSnapshotName = bm.snapshotName
+	case bm.streaming:
+		for k, obj := range bm.dumpObjects {
+			m.Objects[k] = obj
 		}
-	}()
-	tarCmd.Stdout = pw
-	tarCmd.Stderr = os.Stderr
+	default:
+		m.Objects[constants.DumpFilename] = bm.dumpObject
+	}
+	if bm.backup != nil {
+		m.Labels = bm.backup.Spec.Labels
+	}
+	if !bm.filter.IsEmpty() {
+		m.Filter = bm.filter
+	}
 
-	if err := tarCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start tar process: %w", err)
+	key := calcKey(bm.cluster.Namespace, bm.cluster.Name, constants.ManifestFilename, bm.startTime)
+	if err := putManifest(ctx, bm.bucket, key, m); err != nil {
+		return err
 	}
-	pw.Close()
-	pw = nil
+	bm.log.Info("uploaded manifest", "key", key)
+	return nil
+}
 
-	zstdCmd := exec.Command("zstd", "--no-progress", "-T"+fmt.Sprint(bm.threads))
-	zstdCmd.Stdin = pr
-	pr2, pw2, err := os.Pipe()
+// addBinlogToManifest records the binlog archive named `filename` in the manifest of the last backup.
+func (bm *BackupManager) addBinlogToManifest(ctx context.Context, lastBackupTime time.Time, filename string) error {
+	key := calcKey(bm.cluster.Namespace, bm.cluster.Name, constants.ManifestFilename, lastBackupTime)
+	keys, err := bm.bucket.List(ctx, key)
 	if err != nil {
-		return fmt.Errorf("failed to create pipe: %w", err)
+		return fmt.Errorf("failed to list %s: %w", key, err)
 	}
-	defer func() {
-		if pr2 != nil {
-			pr2.Close()
-		}
-		if pw2 != nil {
-			pw2.Close()
-		}
-	}()
-	zstdCmd.Stdout = pw2
-	zstdCmd.Stderr = os.Stderr
-
-	if err := zstdCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start zstd process: %w", err)
+	if len(keys) == 0 {
+		// the last backup was taken by an older version of moco-backup.
+		bm.log.Info("no manifest for the last backup", "key", key)
+		return nil
 	}
-	pw2.Close()
-	pw2 = nil
 
-	bw := &ByteCountWriter{}
-	key := calcKey(bm.cluster.Namespace, bm.cluster.Name, constants.BinlogFilename, lastBackup.Time.Time)
-	if err := bm.bucket.Put(ctx, key, io.TeeReader(pr2, bw), usage); err != nil {
-		return fmt.Errorf("failed to put binlog.tar.zst: %w", err)
+	m, err := getManifest(ctx, bm.bucket, key)
+	if err != nil {
+		return err
 	}
-	if err := tarCmd.Wait(); err != nil {
-		return fmt.Errorf("tar command failed: %w", err)
+	if m.Objects == nil {
+		m.Objects = make(map[string]ManifestObject)
 	}
-	if err := zstdCmd.Wait(); err != nil {
-		return fmt.Errorf("zstd command failed: %w", err)
+	m.Objects[filename] = bm.binlogObject
+	return putManifest(ctx, bm.bucket, key, m)
+}
+
+// listOrderedPods returns the Pods of the cluster ordered by their indices.
+func listOrderedPods(ctx context.Context, c client.Client, cluster *mocov1beta2.MySQLCluster) ([]*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		constants.LabelAppName:      constants.AppNameMySQL,
+		constants.LabelAppInstance:  cluster.Name,
+		constants.LabelAppCreatedBy: constants.AppCreator,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get pod list: %w", err)
 	}
 
-	bm.binlogSize = bw.Written()
-	bm.log.Info("uploaded binlog files", "key", key, "bytes", bm.binlogSize)
-	return nil
+	if len(pods.Items) != int(cluster.Spec.Replicas) {
+		return nil, fmt.Errorf("too few Pods for %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	orderedPods := make([]*corev1.Pod, cluster.Spec.Replicas)
+	for i, pod := range pods.Items {
+		fields := strings.Split(pod.Name, "-")
+		index, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			return nil, fmt.Errorf("bad pod name: %s", pod.Name)
+		}
+
+		if index < 0 || index >= len(pods.Items) {
+			return nil, fmt.Errorf("index out of range: %d", index)
+		}
+		orderedPods[index] = &pods.Items[i]
+	}
+	return orderedPods, nil
 }
 
 func podIsReady(pod *corev1.Pod) bool {
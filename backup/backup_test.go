@@ -2,11 +2,13 @@ package backup
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
 	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/constants"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,6 +17,7 @@ import (
 type choosePodMockOp struct {
 	closed bool
 	uuid   string
+	lag    *time.Duration
 }
 
 func (o *choosePodMockOp) Ping() error {
@@ -27,10 +30,28 @@ func (o *choosePodMockOp) Close() {
 
 func (o *choosePodMockOp) GetServerStatus(_ context.Context, st *bkop.ServerStatus) error {
 	st.UUID = o.uuid
+	st.IsReplica = o.lag != nil
+	st.ReplicationLag = o.lag
 	return nil
 }
 
-func (o *choosePodMockOp) DumpFull(ctx context.Context, dir string) error {
+func (o *choosePodMockOp) ContainsGTIDSet(_ context.Context, set string) (bool, error) {
+	panic("not implemented")
+}
+
+func (o *choosePodMockOp) RunSQL(_ context.Context, stmts []string) error {
+	panic("not implemented")
+}
+
+func (o *choosePodMockOp) QueryValue(_ context.Context, query string) (string, error) {
+	panic("not implemented")
+}
+
+func (o *choosePodMockOp) DumpFull(ctx context.Context, dir string, filter *bkop.Filter) error {
+	panic("not implemented")
+}
+
+func (o *choosePodMockOp) LockForSnapshot(_ context.Context) (func() error, error) {
 	panic("not implemented")
 }
 
@@ -46,11 +67,15 @@ func (o *choosePodMockOp) PrepareRestore(_ context.Context) error {
 	panic("not implemented")
 }
 
-func (o *choosePodMockOp) LoadDump(ctx context.Context, dir string) error {
+func (o *choosePodMockOp) LoadDump(ctx context.Context, dir string, filter *bkop.Filter) error {
+	panic("not implemented")
+}
+
+func (o *choosePodMockOp) LoadDumpStream(ctx context.Context, dir, progressFile string, filter *bkop.Filter) error {
 	panic("not implemented")
 }
 
-func (o *choosePodMockOp) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, restorePoint time.Time) error {
+func (o *choosePodMockOp) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, restorePoint time.Time, stopGTIDSet bkop.GTIDSet, filter *bkop.Filter) (bool, error) {
 	panic("not implemented")
 }
 
@@ -145,3 +170,132 @@ func TestChoosePod(t *testing.T) {
 		})
 	}
 }
+
+func TestChoosePodWithPolicy(t *testing.T) {
+	makePod := func(ready bool, zone string) *corev1.Pod {
+		pod := &corev1.Pod{}
+		pod.Annotations = map[string]string{constants.AnnZone: zone}
+		if !ready {
+			return pod
+		}
+		pod.Status.Conditions = []corev1.PodCondition{{
+			Type:   corev1.PodReady,
+			Status: corev1.ConditionTrue,
+		}}
+		return pod
+	}
+	makePods := func(ready ...bool) []*corev1.Pod {
+		pods := make([]*corev1.Pod, len(ready))
+		zones := []string{"zone-a", "zone-b", "zone-b", "zone-a"}
+		for i, r := range ready {
+			pods[i] = makePod(r, zones[i])
+			pods[i].Status.PodIP = fmt.Sprintf("10.0.0.%d", i)
+		}
+		return pods
+	}
+
+	lag := func(d time.Duration) *time.Duration { return &d }
+	lags := map[string]*time.Duration{
+		"10.0.0.0": lag(0),
+		"10.0.0.1": lag(10 * time.Second),
+		"10.0.0.2": lag(3 * time.Second),
+		"10.0.0.3": nil,
+	}
+	var ops []*choosePodMockOp
+	newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+		op := &choosePodMockOp{uuid: "123", lag: lags[host]}
+		ops = append(ops, op)
+		return op, nil
+	}
+
+	index := func(i int) *int { return &i }
+	lastBackup := mocov1beta2.BackupStatus{Time: metav1.Now(), SourceIndex: 2, SourceUUID: "123"}
+
+	testCases := []struct {
+		name    string
+		current int
+		bkup    mocov1beta2.BackupStatus
+		policy  SourcePolicy
+		pods    []*corev1.Pod
+
+		expectIdx int
+		expectErr bool
+	}{
+		{"first", 0, mocov1beta2.BackupStatus{}, SourcePolicy{}, makePods(true, true, true), 1, false},
+		{"last", 0, lastBackup, SourcePolicy{}, makePods(true, true, true), 2, false},
+		{"last-not-ready", 0, lastBackup, SourcePolicy{}, makePods(true, true, false), 1, false},
+		{"fallback-primary", 0, lastBackup, SourcePolicy{}, makePods(true, false, false), 0, false},
+		{"exclude-primary", 0, lastBackup, SourcePolicy{ExcludePrimary: true}, makePods(true, false, false), -1, true},
+		{"index", 0, lastBackup, SourcePolicy{Index: index(1)}, makePods(true, true, true), 1, false},
+		{"index-not-ready", 0, lastBackup, SourcePolicy{Index: index(1)}, makePods(true, false, true), -1, true},
+		{"index-primary", 0, lastBackup, SourcePolicy{Index: index(0)}, makePods(true, true, true), 0, false},
+		{"index-primary-excluded", 0, lastBackup, SourcePolicy{Index: index(0), ExcludePrimary: true}, makePods(true, true, true), -1, true},
+		{"zone", 0, lastBackup, SourcePolicy{Zone: "zone-a"}, makePods(true, true, true, true), 3, false},
+		{"zone-primary", 0, lastBackup, SourcePolicy{Zone: "zone-a"}, makePods(true, true, true, false), 0, false},
+		{"zone-none", 0, lastBackup, SourcePolicy{Zone: "zone-c"}, makePods(true, true, true, true), -1, true},
+		{"least-lag", 0, mocov1beta2.BackupStatus{}, SourcePolicy{PreferLeastLag: true}, makePods(true, true, true, true), 2, false},
+		{"least-lag-primary", 2, lastBackup, SourcePolicy{PreferLeastLag: true}, makePods(true, true, true, true), 0, false},
+		{"least-lag-unknown", 0, lastBackup, SourcePolicy{PreferLeastLag: true}, makePods(true, false, false, true), 3, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ops = nil
+			cluster := &mocov1beta2.MySQLCluster{}
+			cluster.Spec.Replicas = int32(len(tc.pods))
+			cluster.Status.CurrentPrimaryIndex = tc.current
+			cluster.Status.Backup = tc.bkup
+			policy := tc.policy
+			bm := &BackupManager{
+				log:          logr.Discard(),
+				cluster:      cluster,
+				sourcePolicy: &policy,
+			}
+
+			idx, err := bm.ChoosePod(context.Background(), tc.pods)
+			for _, op := range ops {
+				if !op.closed {
+					t.Error("op was not closed")
+				}
+			}
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("error is expected, but chose %d", idx)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if idx != tc.expectIdx {
+				t.Errorf("unexpected index %d, expected %d", idx, tc.expectIdx)
+			}
+		})
+	}
+}
+
+func TestSetRestorableRanges(t *testing.T) {
+	t1 := time.Date(2021, time.May, 25, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+	t4 := t3.Add(24 * time.Hour)
+
+	sb := &mocov1beta2.BackupStatus{
+		RestorableGaps: []mocov1beta2.RestorableGap{{}},
+	}
+	setRestorableRanges(sb, []TimeRange{{Start: t1, End: t4}})
+	if !sb.EarliestRestorableTime.Time.Equal(t1) || !sb.LatestRestorableTime.Time.Equal(t4) {
+		t.Errorf("unexpected restorable time: %v, %v", sb.EarliestRestorableTime, sb.LatestRestorableTime)
+	}
+	if len(sb.RestorableGaps) != 0 {
+		t.Errorf("unexpected gaps: %v", sb.RestorableGaps)
+	}
+
+	setRestorableRanges(sb, []TimeRange{{Start: t1, End: t2}, {Start: t3, End: t4}})
+	if !sb.EarliestRestorableTime.Time.Equal(t1) || !sb.LatestRestorableTime.Time.Equal(t4) {
+		t.Errorf("unexpected restorable time: %v, %v", sb.EarliestRestorableTime, sb.LatestRestorableTime)
+	}
+	if len(sb.RestorableGaps) != 1 || !sb.RestorableGaps[0].Start.Time.Equal(t2) || !sb.RestorableGaps[0].End.Time.Equal(t3) {
+		t.Errorf("unexpected gaps: %v", sb.RestorableGaps)
+	}
+}
@@ -8,13 +8,19 @@ import (
 // ByteCountWriter counts the written data in bytes
 type ByteCountWriter struct {
 	written int64
+
+	// Progress, if not nil, is called with the total number of written bytes after each write.
+	Progress func(written int64)
 }
 
 var _ io.Writer = &ByteCountWriter{}
 
 // Write implements io.Writer interface.
 func (w *ByteCountWriter) Write(data []byte) (int, error) {
-	atomic.AddInt64(&w.written, int64(len(data)))
+	written := atomic.AddInt64(&w.written, int64(len(data)))
+	if w.Progress != nil {
+		w.Progress(written)
+	}
 	return len(data), nil
 }
 
@@ -22,3 +22,18 @@ func TestByteCountWriter(t *testing.T) {
 		t.Errorf("unexpected written bytes: %d (n=%d)", written, n)
 	}
 }
+
+func TestByteCountWriterProgress(t *testing.T) {
+	var progress []int64
+	bcw := &ByteCountWriter{
+		Progress: func(written int64) {
+			progress = append(progress, written)
+		},
+	}
+
+	bcw.Write([]byte("abc"))
+	bcw.Write([]byte("defg"))
+	if len(progress) != 2 || progress[0] != 3 || progress[1] != 7 {
+		t.Errorf("unexpected progress: %v", progress)
+	}
+}
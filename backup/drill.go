@@ -0,0 +1,375 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/cybozu-go/moco/pkg/event"
+	"github.com/cybozu-go/moco/pkg/password"
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/reference"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// DrillManager restores the latest backup of a MySQLCluster into a scratch
+// MySQLCluster and verifies the restored data.
+type DrillManager struct {
+	log        logr.Logger
+	client     client.Client
+	scheme     *runtime.Scheme
+	namespace  string
+	name       string
+	assertions []mocov1beta2.RestoreDrillAssertion
+	timeout    time.Duration
+
+	// interval is the interval to poll the scratch MySQLCluster.
+	interval time.Duration
+}
+
+func NewDrillManager(cfg *rest.Config, ns, name string, assertions []mocov1beta2.RestoreDrillAssertion, timeout time.Duration) (*DrillManager, error) {
+	log := zap.New(zap.WriteTo(os.Stderr), zap.StacktraceLevel(zapcore.DPanicLevel))
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := mocov1beta2.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create controller-runtime client: %w", err)
+	}
+
+	return &DrillManager{
+		log:        log,
+		client:     k8sClient,
+		scheme:     scheme,
+		namespace:  ns,
+		name:       name,
+		assertions: assertions,
+		timeout:    timeout,
+		interval:   10 * time.Second,
+	}, nil
+}
+
+// Drill restores the latest backup of the MySQLCluster into a scratch MySQLCluster,
+// runs the assertions on it, and has the scratch MySQLCluster deleted.
+//
+// The scratch MySQLCluster is created and deleted by moco-controller
+// while `status.restoreDrillRequest` of the MySQLCluster is set.
+//
+// The result is recorded in the status of the MySQLCluster and as an event.
+// A failed drill is not an error because retrying the Job would not fix the backup;
+// an error is returned only when the result cannot be recorded.
+func (dm *DrillManager) Drill(ctx context.Context) error {
+	cluster := &mocov1beta2.MySQLCluster{}
+	if err := dm.client.Get(ctx, client.ObjectKey{Namespace: dm.namespace, Name: dm.name}, cluster); err != nil {
+		return fmt.Errorf("failed to get MySQLCluster: %w", err)
+	}
+	if cluster.Status.Backup.Time.IsZero() {
+		dm.log.Info("skipping the restore drill as no backup has been taken")
+		return nil
+	}
+
+	startTime := time.Now()
+	result := &mocov1beta2.RestoreDrillStatus{
+		Time:         metav1.NewTime(startTime),
+		RestorePoint: drillRestorePoint(cluster),
+	}
+
+	drillCtx, cancel := context.WithTimeout(ctx, dm.timeout)
+	failed, drillErr := dm.drill(drillCtx, cluster, result.RestorePoint.Time)
+	cancel()
+
+	result.Elapsed = metav1.Duration{Duration: time.Since(startTime)}
+	result.FailedAssertions = failed
+	if drillErr != nil {
+		dm.log.Error(drillErr, "restore drill failed")
+		result.Message = drillErr.Error()
+	} else {
+		dm.log.Info("restore drill succeeded", "elapsed", result.Elapsed.Duration)
+		result.Succeeded = true
+		result.LastSuccessTime = &result.Time
+	}
+
+	// the request is withdrawn together with recording the result even if
+	// the drill has timed out, so that the scratch MySQLCluster is deleted.
+	return dm.recordResult(ctx, result, drillErr)
+}
+
+// drillRestorePoint returns the latest point-in-time to which data can be restored.
+func drillRestorePoint(cluster *mocov1beta2.MySQLCluster) *metav1.Time {
+	if t := cluster.Status.Backup.LatestRestorableTime; t != nil {
+		return t.DeepCopy()
+	}
+	t := cluster.Status.Backup.Time
+	return &t
+}
+
+// drill returns the names of failed assertions and the reason of the failure.
+func (dm *DrillManager) drill(ctx context.Context, cluster *mocov1beta2.MySQLCluster, restorePoint time.Time) ([]string, error) {
+	if cluster.Spec.BackupPolicyName == nil {
+		return nil, errors.New("the MySQLCluster has no backup policy")
+	}
+
+	// withdraw the request of an interrupted drill and wait for its leftovers to be removed.
+	if err := dm.setRequest(ctx, nil); err != nil {
+		return nil, err
+	}
+	if err := dm.waitForCleanup(ctx, cluster); err != nil {
+		return nil, err
+	}
+
+	dm.log.Info("requesting the scratch MySQLCluster", "name", cluster.RestoreDrillClusterName(), "restorePoint", restorePoint)
+	if err := dm.setRequest(ctx, &mocov1beta2.RestoreDrillRequest{RestorePoint: metav1.NewTime(restorePoint)}); err != nil {
+		return nil, err
+	}
+
+	scratch, err := dm.waitForRestore(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := dm.connect(ctx, scratch)
+	if err != nil {
+		return nil, err
+	}
+	defer op.Close()
+
+	var failed, msgs []string
+	for _, a := range dm.assertions {
+		value, err := op.QueryValue(ctx, a.Query)
+		if err == nil {
+			err = checkAssertion(a, value)
+		}
+		if err != nil {
+			dm.log.Info("assertion failed", "name", a.Name, "reason", err.Error())
+			failed = append(failed, a.Name)
+			msgs = append(msgs, fmt.Sprintf("%s: %v", a.Name, err))
+			continue
+		}
+		dm.log.Info("assertion passed", "name", a.Name, "value", value)
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("assertions failed: %s", strings.Join(msgs, "; "))
+	}
+	return nil, nil
+}
+
+// setRequest sets or clears the request for the scratch MySQLCluster in the status of the MySQLCluster.
+func (dm *DrillManager) setRequest(ctx context.Context, req *mocov1beta2.RestoreDrillRequest) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &mocov1beta2.MySQLCluster{}
+		if err := dm.client.Get(ctx, client.ObjectKey{Namespace: dm.namespace, Name: dm.name}, cluster); err != nil {
+			return err
+		}
+		if req == nil && cluster.Status.RestoreDrillRequest == nil {
+			return nil
+		}
+		cluster.Status.RestoreDrillRequest = req
+		return dm.client.Status().Update(ctx, cluster)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the request for the scratch MySQLCluster: %w", err)
+	}
+	return nil
+}
+
+// waitForRestore waits for the scratch MySQLCluster to be created and restored.
+func (dm *DrillManager) waitForRestore(ctx context.Context, cluster *mocov1beta2.MySQLCluster) (*mocov1beta2.MySQLCluster, error) {
+	name := cluster.RestoreDrillClusterName()
+	dm.log.Info("waiting for the restoration to finish", "name", name)
+	for {
+		select {
+		case <-time.After(dm.interval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to wait for the restoration: %w", ctx.Err())
+		}
+
+		scratch := &mocov1beta2.MySQLCluster{}
+		err := dm.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, scratch)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			dm.log.Error(err, "failed to get the scratch MySQLCluster")
+			continue
+		}
+		if !metav1.IsControlledBy(scratch, cluster) {
+			return nil, fmt.Errorf("MySQLCluster %s is not a scratch MySQLCluster of %s", name, cluster.Name)
+		}
+		if scratch.Status.RestoredTime != nil {
+			return scratch, nil
+		}
+		for _, cond := range scratch.Status.Conditions {
+			if cond.Type == mocov1beta2.ConditionRestored && cond.Status == corev1.ConditionFalse {
+				return nil, fmt.Errorf("restoration failed: %s", cond.Message)
+			}
+		}
+	}
+}
+
+// connect connects to the restored instance as the backup user.
+func (dm *DrillManager) connect(ctx context.Context, scratch *mocov1beta2.MySQLCluster) (bkop.Operator, error) {
+	secret := &corev1.Secret{}
+	if err := dm.client.Get(ctx, client.ObjectKey{Namespace: scratch.Namespace, Name: scratch.UserSecretName()}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get the user secret of the scratch MySQLCluster: %w", err)
+	}
+	pwd := string(secret.Data[password.BackupPasswordKey])
+
+	pod := &corev1.Pod{}
+	if err := dm.client.Get(ctx, client.ObjectKey{Namespace: scratch.Namespace, Name: scratch.PodName(0)}, pod); err != nil {
+		return nil, fmt.Errorf("failed to get the pod of the scratch MySQLCluster: %w", err)
+	}
+	if pod.Status.PodIP == "" {
+		return nil, fmt.Errorf("pod %s has no IP address", pod.Name)
+	}
+
+	op, err := newOperator(pod.Status.PodIP, constants.MySQLPort, constants.BackupUser, pwd, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create an operator: %w", err)
+	}
+	for {
+		err := op.Ping()
+		if err == nil {
+			return op, nil
+		}
+		dm.log.Error(err, "failed to connect to the scratch MySQLCluster")
+
+		select {
+		case <-time.After(dm.interval):
+		case <-ctx.Done():
+			op.Close()
+			return nil, fmt.Errorf("failed to connect to the scratch MySQLCluster: %w", err)
+		}
+	}
+}
+
+// checkAssertion checks the result of the query of an assertion.
+func checkAssertion(a mocov1beta2.RestoreDrillAssertion, value string) error {
+	if a.Expect != nil && value != *a.Expect {
+		return fmt.Errorf("got %q, expected %q", value, *a.Expect)
+	}
+	if a.Min == nil && a.Max == nil {
+		return nil
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("got %q, expected an integer", value)
+	}
+	if a.Min != nil && n < *a.Min {
+		return fmt.Errorf("got %d, expected at least %d", n, *a.Min)
+	}
+	if a.Max != nil && n > *a.Max {
+		return fmt.Errorf("got %d, expected at most %d", n, *a.Max)
+	}
+	return nil
+}
+
+// waitForCleanup waits for the scratch MySQLCluster and its PVCs to be removed.
+// It fails if they exist but do not belong to a scratch MySQLCluster of `cluster`,
+// because they may be of a MySQLCluster created by users.
+func (dm *DrillManager) waitForCleanup(ctx context.Context, cluster *mocov1beta2.MySQLCluster) error {
+	name := cluster.RestoreDrillClusterName()
+	objs := []client.Object{&mocov1beta2.MySQLCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: name},
+	}}
+	for _, pvcName := range cluster.RestoreDrillVolumeClaimNames() {
+		objs = append(objs, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: pvcName},
+		})
+	}
+
+	for _, obj := range objs {
+		for {
+			err := dm.client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+			if apierrors.IsNotFound(err) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get %s: %w", obj.GetName(), err)
+			}
+			if !isScratchObject(obj, cluster) {
+				return fmt.Errorf("%s is not a leftover of a restore drill of %s", obj.GetName(), cluster.Name)
+			}
+
+			dm.log.Info("waiting for the deletion of the leftover", "name", obj.GetName())
+			select {
+			case <-time.After(dm.interval):
+			case <-ctx.Done():
+				return fmt.Errorf("failed to wait for the deletion of %s: %w", obj.GetName(), ctx.Err())
+			}
+		}
+	}
+	return nil
+}
+
+// isScratchObject returns true if `obj` is the scratch MySQLCluster of `cluster`
+// or a PVC owned by it.
+func isScratchObject(obj client.Object, cluster *mocov1beta2.MySQLCluster) bool {
+	if _, ok := obj.(*mocov1beta2.MySQLCluster); ok {
+		return metav1.IsControlledBy(obj, cluster)
+	}
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "MySQLCluster" && ref.Name == cluster.RestoreDrillClusterName() {
+			return true
+		}
+	}
+	return false
+}
+
+// recordResult records the result of the drill in the status of the MySQLCluster and as an event.
+// The request for the scratch MySQLCluster is withdrawn at the same time.
+func (dm *DrillManager) recordResult(ctx context.Context, result *mocov1beta2.RestoreDrillStatus, drillErr error) error {
+	cluster := &mocov1beta2.MySQLCluster{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster = &mocov1beta2.MySQLCluster{}
+		if err := dm.client.Get(ctx, client.ObjectKey{Namespace: dm.namespace, Name: dm.name}, cluster); err != nil {
+			return err
+		}
+
+		status := result.DeepCopy()
+		if status.LastSuccessTime == nil && cluster.Status.RestoreDrill != nil {
+			status.LastSuccessTime = cluster.Status.RestoreDrill.LastSuccessTime
+		}
+		cluster.Status.RestoreDrill = status
+		cluster.Status.RestoreDrillRequest = nil
+		return dm.client.Status().Update(ctx, cluster)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record the result of the restore drill: %w", err)
+	}
+
+	ref, err := reference.GetReference(dm.scheme, cluster)
+	if err != nil {
+		dm.log.Error(err, "failed to get reference for MySQLCluster")
+		return nil
+	}
+	ev := event.RestoreDrillSucceeded.ToEvent(ref, result.Elapsed.Duration.Round(time.Second))
+	if drillErr != nil {
+		ev = event.RestoreDrillFailed.ToEvent(ref, drillErr)
+	}
+	if err := dm.client.Create(ctx, ev); err != nil {
+		dm.log.Error(err, "failed to create an event for the restore drill")
+	}
+	return nil
+}
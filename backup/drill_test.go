@@ -0,0 +1,262 @@
+package backup
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/password"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestCheckAssertion(t *testing.T) {
+	testCases := []struct {
+		name      string
+		assertion mocov1beta2.RestoreDrillAssertion
+		value     string
+		expectErr bool
+	}{
+		{"no-check", mocov1beta2.RestoreDrillAssertion{}, "NULL", false},
+		{"expect", mocov1beta2.RestoreDrillAssertion{Expect: pointer.String("abc")}, "abc", false},
+		{"expect-mismatch", mocov1beta2.RestoreDrillAssertion{Expect: pointer.String("abc")}, "abd", true},
+		{"min", mocov1beta2.RestoreDrillAssertion{Min: pointer.Int64(10)}, "10", false},
+		{"below-min", mocov1beta2.RestoreDrillAssertion{Min: pointer.Int64(10)}, "9", true},
+		{"max", mocov1beta2.RestoreDrillAssertion{Max: pointer.Int64(10)}, "10", false},
+		{"above-max", mocov1beta2.RestoreDrillAssertion{Max: pointer.Int64(10)}, "11", true},
+		{"range", mocov1beta2.RestoreDrillAssertion{Min: pointer.Int64(1), Max: pointer.Int64(3)}, "2", false},
+		{"not-integer", mocov1beta2.RestoreDrillAssertion{Min: pointer.Int64(1)}, "NULL", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkAssertion(tc.assertion, tc.value)
+			if tc.expectErr && err == nil {
+				t.Error("the assertion should fail")
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("the assertion should pass: %v", err)
+			}
+		})
+	}
+}
+
+func TestDrill(t *testing.T) {
+	ctx := context.Background()
+	backupTime := time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC)
+	lastSuccess := metav1.NewTime(time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC))
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	cluster.UID = "uid"
+	cluster.Spec.Replicas = 3
+	cluster.Spec.BackupPolicyName = pointer.String("bp")
+	cluster.Spec.VolumeClaimTemplates = []mocov1beta2.PersistentVolumeClaim{
+		{ObjectMeta: mocov1beta2.ObjectMeta{Name: "mysql-data"}},
+	}
+	cluster.Status.Backup.Time = metav1.NewTime(backupTime)
+	cluster.Status.RestoreDrill = &mocov1beta2.RestoreDrillStatus{LastSuccessTime: &lastSuccess}
+	// the request of an interrupted drill.
+	cluster.Status.RestoreDrillRequest = &mocov1beta2.RestoreDrillRequest{RestorePoint: lastSuccess}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := mocov1beta2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	// leftovers of an interrupted drill.
+	leftover := &mocov1beta2.MySQLCluster{}
+	leftover.Namespace = "test"
+	leftover.Name = "moco-scratch-test"
+	if err := controllerutil.SetControllerReference(cluster, leftover, scheme); err != nil {
+		t.Fatal(err)
+	}
+	leftoverPVC := &corev1.PersistentVolumeClaim{}
+	leftoverPVC.Namespace = "test"
+	leftoverPVC.Name = "mysql-data-moco-moco-scratch-test-0"
+	leftoverPVC.OwnerReferences = []metav1.OwnerReference{{APIVersion: "moco.cybozu.com/v1beta2", Kind: "MySQLCluster", Name: "moco-scratch-test", UID: "scratch"}}
+
+	secret := &corev1.Secret{}
+	secret.Namespace = "test"
+	secret.Name = "moco-moco-scratch-test"
+	secret.Data = map[string][]byte{password.BackupPasswordKey: []byte("pwd")}
+	pod := &corev1.Pod{}
+	pod.Namespace = "test"
+	pod.Name = "moco-moco-scratch-test-0"
+	pod.Status.PodIP = "10.0.0.1"
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, leftover, leftoverPVC, secret, pod).Build()
+
+	values := map[string]string{
+		"SELECT COUNT(*) FROM db.t": "100",
+		"CHECKSUM TABLE db.t":       "12345",
+	}
+	var lastOp *mockOperator
+	origNewOperator := newOperator
+	defer func() { newOperator = origNewOperator }()
+	newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+		if host != "10.0.0.1" || user != "moco-backup" || password != "pwd" {
+			t.Errorf("unexpected connection: %s, %s, %s", host, user, password)
+		}
+		lastOp = &mockOperator{values: values}
+		return lastOp, nil
+	}
+
+	// simulate moco-controller, which creates the scratch MySQLCluster while requested,
+	// and the garbage collector.
+	simulateController := func(done <-chan struct{}) {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+			}
+
+			source := &mocov1beta2.MySQLCluster{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(cluster), source); err != nil {
+				continue
+			}
+			scratch := &mocov1beta2.MySQLCluster{}
+			err := c.Get(ctx, client.ObjectKey{Namespace: "test", Name: "moco-scratch-test"}, scratch)
+			switch {
+			case apierrors.IsNotFound(err):
+				c.Delete(ctx, leftoverPVC)
+				if req := source.Status.RestoreDrillRequest; req != nil {
+					scratch = &mocov1beta2.MySQLCluster{}
+					scratch.Namespace = "test"
+					scratch.Name = "moco-scratch-test"
+					controllerutil.SetControllerReference(source, scratch, scheme)
+					now := metav1.Now()
+					scratch.Status.RestoredTime = &now
+					c.Create(ctx, scratch)
+				}
+			case err == nil && source.Status.RestoreDrillRequest == nil && metav1.IsControlledBy(scratch, source):
+				c.Delete(ctx, scratch)
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go simulateController(done)
+
+	runDrill := func(assertions []mocov1beta2.RestoreDrillAssertion) *mocov1beta2.RestoreDrillStatus {
+		t.Helper()
+		dm := &DrillManager{
+			log:        logr.Discard(),
+			client:     c,
+			scheme:     scheme,
+			namespace:  "test",
+			name:       "test",
+			assertions: assertions,
+			timeout:    time.Second,
+			interval:   time.Millisecond,
+		}
+		if err := dm.Drill(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		updated := &mocov1beta2.MySQLCluster{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(cluster), updated); err != nil {
+			t.Fatal(err)
+		}
+		if updated.Status.RestoreDrillRequest != nil {
+			t.Error("the request is not withdrawn")
+		}
+		if updated.Status.RestoreDrill == nil {
+			t.Fatal("the result is not recorded")
+		}
+		return updated.Status.RestoreDrill
+	}
+
+	status := runDrill([]mocov1beta2.RestoreDrillAssertion{
+		{Name: "count", Query: "SELECT COUNT(*) FROM db.t", Min: pointer.Int64(1)},
+		{Name: "checksum", Query: "CHECKSUM TABLE db.t", Expect: pointer.String("54321")},
+		{Name: "unknown", Query: "SELECT 1 FROM db.unknown"},
+	})
+	if status.Succeeded {
+		t.Error("the drill should fail")
+	}
+	if !lastOp.closed {
+		t.Error("the connection is not closed")
+	}
+	if !cmp.Equal(status.FailedAssertions, []string{"checksum", "unknown"}) {
+		t.Errorf("unexpected failed assertions: %v", status.FailedAssertions)
+	}
+	if status.Message == "" {
+		t.Error("no message is recorded")
+	}
+	if status.RestorePoint == nil || !status.RestorePoint.Time.Equal(backupTime) {
+		t.Errorf("unexpected restore point: %v", status.RestorePoint)
+	}
+	if status.LastSuccessTime == nil || !status.LastSuccessTime.Equal(&lastSuccess) {
+		t.Errorf("the last success time is not kept: %v", status.LastSuccessTime)
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(leftoverPVC), pvc); !apierrors.IsNotFound(err) {
+		t.Errorf("the leftover PVC is not deleted: %v", err)
+	}
+
+	status = runDrill([]mocov1beta2.RestoreDrillAssertion{
+		{Name: "count", Query: "SELECT COUNT(*) FROM db.t", Min: pointer.Int64(1), Max: pointer.Int64(100)},
+		{Name: "checksum", Query: "CHECKSUM TABLE db.t", Expect: pointer.String("12345")},
+	})
+	if !status.Succeeded || len(status.FailedAssertions) != 0 || status.Message != "" {
+		t.Errorf("the drill should succeed: %+v", status)
+	}
+	if status.LastSuccessTime == nil || !status.LastSuccessTime.Equal(&status.Time) {
+		t.Errorf("the last success time is not updated: %v", status.LastSuccessTime)
+	}
+
+	// wait for the scratch MySQLCluster of the last drill to be deleted.
+	for {
+		err := c.Get(ctx, client.ObjectKey{Namespace: "test", Name: "moco-scratch-test"}, &mocov1beta2.MySQLCluster{})
+		if apierrors.IsNotFound(err) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// a MySQLCluster of users that happens to have the name must not be used nor deleted.
+	foreign := &mocov1beta2.MySQLCluster{}
+	foreign.Namespace = "test"
+	foreign.Name = "moco-scratch-test"
+	if err := c.Create(ctx, foreign); err != nil {
+		t.Fatal(err)
+	}
+	status = runDrill(nil)
+	if status.Succeeded || !strings.Contains(status.Message, "moco-scratch-test") {
+		t.Errorf("the drill should fail: %+v", status)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(foreign), &mocov1beta2.MySQLCluster{}); err != nil {
+		t.Errorf("the MySQLCluster of users is deleted: %v", err)
+	}
+
+	events := &corev1.EventList{}
+	if err := c.List(ctx, events, client.InNamespace("test")); err != nil {
+		t.Fatal(err)
+	}
+	reasons := make(map[string]bool)
+	for _, ev := range events.Items {
+		reasons[ev.Reason] = true
+	}
+	if !reasons["RestoreDrillFailed"] || !reasons["RestoreDrillSucceeded"] {
+		t.Errorf("unexpected events: %v", reasons)
+	}
+}
@@ -0,0 +1,182 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+)
+
+// Hook phases.
+const (
+	HookPhasePreBackup  = "preBackup"
+	HookPhasePostBackup = "postBackup"
+)
+
+// defaultHookTimeout is used when the timeout of a hook is not specified.
+const defaultHookTimeout = time.Minute
+
+// HookPayload is the metadata of a backup sent to HTTP hooks.
+// Fields about the backup files are set only for post-backup hooks.
+type HookPayload struct {
+	// Phase is either "preBackup" or "postBackup".
+	Phase string `json:"phase"`
+
+	// Namespace and Name are of the MySQLCluster.
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// BackupName is the name of the MySQLBackup that requested the backup, if any.
+	BackupName string `json:"backupName,omitempty"`
+
+	// Time is the time of the backup.
+	Time time.Time `json:"time"`
+
+	// SourceIndex and SourceUUID identify the backup source instance.
+	SourceIndex int    `json:"sourceIndex"`
+	SourceUUID  string `json:"sourceUUID"`
+
+	// GTIDSet is the GTID set of the full dump.
+	GTIDSet string `json:"gtidSet,omitempty"`
+
+	// VolumeSnapshotName is the name of the VolumeSnapshot of a snapshot backup.
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+
+	// DumpKey and BinlogKey are the keys of the uploaded archives.
+	DumpKey    string `json:"dumpKey,omitempty"`
+	DumpSize   int64  `json:"dumpSize,omitempty"`
+	BinlogKey  string `json:"binlogKey,omitempty"`
+	BinlogSize int64  `json:"binlogSize,omitempty"`
+
+	// Warnings are the warnings of the backup so far.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// WithHooks specifies the hooks to be run before and after the backup.
+func WithHooks(h *mocov1beta2.BackupHooks) BackupOption {
+	return func(bm *BackupManager) {
+		bm.hooks = h
+	}
+}
+
+func (bm *BackupManager) hookPayload(phase string) *HookPayload {
+	return &HookPayload{
+		Phase:              phase,
+		Namespace:          bm.cluster.Namespace,
+		Name:               bm.cluster.Name,
+		BackupName:         bm.backupName,
+		Time:               bm.startTime,
+		SourceIndex:        bm.sourceIndex,
+		SourceUUID:         bm.status.UUID,
+		GTIDSet:            bm.gtidSet,
+		VolumeSnapshotName: bm.snapshotName,
+		DumpKey:            bm.dumpKey,
+		DumpSize:           bm.dumpSize,
+		BinlogKey:          bm.binlogKey,
+		BinlogSize:         bm.binlogSize,
+		Warnings:           bm.warnings,
+	}
+}
+
+// runHooks runs hooks in order.
+// If a hook whose failure policy is Warn fails, the failure is added to the warnings.
+// If a hook whose failure policy is Abort fails, runHooks returns an error immediately.
+func (bm *BackupManager) runHooks(ctx context.Context, op bkop.Operator, phase string, hooks []mocov1beta2.BackupHook) error {
+	for i := range hooks {
+		h := &hooks[i]
+		bm.log.Info("running a hook", "phase", phase, "hook", h.Name)
+
+		err := runHook(ctx, op, phase, i, h, bm.hookPayload(phase))
+		if err == nil {
+			continue
+		}
+
+		if h.FailurePolicy == mocov1beta2.HookWarn {
+			bm.log.Error(err, "hook failed", "phase", phase, "hook", h.Name)
+			bm.warnings = append(bm.warnings, fmt.Sprintf("%s hook %s failed: %v", phase, h.Name, err))
+			continue
+		}
+		return fmt.Errorf("%s hook %s failed: %w", phase, h.Name, err)
+	}
+	return nil
+}
+
+func runHook(ctx context.Context, op bkop.Operator, phase string, index int, h *mocov1beta2.BackupHook, payload *HookPayload) error {
+	timeout := h.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if len(h.SQL) > 0 {
+		if err := op.RunSQL(ctx, h.SQL); err != nil {
+			return err
+		}
+	}
+
+	if h.HTTP != nil {
+		header, err := hookHeader(phase, index, h.HTTP)
+		if err != nil {
+			return err
+		}
+		if err := callHTTPHook(ctx, timeout, h.HTTP, header, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hookHeader returns the headers of an HTTP hook.
+// moco-controller gives their values to the Pod as environment variables
+// read from Secrets, so that they do not appear in the command line.
+func hookHeader(phase string, index int, h *mocov1beta2.HTTPHook) (http.Header, error) {
+	header := make(http.Header)
+	for i, hdr := range h.Headers {
+		v, ok := os.LookupEnv(mocov1beta2.HookHeaderEnvName(phase, index, i))
+		if !ok {
+			if opt := hdr.ValueFrom.SecretKeyRef.Optional; opt != nil && *opt {
+				continue
+			}
+			return nil, fmt.Errorf("the value of header %s is not given", hdr.Name)
+		}
+		header.Set(hdr.Name, v)
+	}
+	return header, nil
+}
+
+func callHTTPHook(ctx context.Context, timeout time.Duration, h *mocov1beta2.HTTPHook, header http.Header, payload *HookPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create a request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", h.URL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", h.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunHooks(t *testing.T) {
+	var payloads []HookPayload
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			http.Error(w, "error", http.StatusInternalServerError)
+			return
+		}
+		var p HookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		payloads = append(payloads, p)
+		tokens = append(tokens, r.Header.Get("X-Token"))
+	}))
+	defer srv.Close()
+
+	startTime := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	makeBM := func() *BackupManager {
+		cluster := &mocov1beta2.MySQLCluster{}
+		cluster.Namespace = "test"
+		cluster.Name = "mysql"
+		return &BackupManager{
+			log:         logr.Discard(),
+			cluster:     cluster,
+			startTime:   startTime,
+			sourceIndex: 1,
+			dumpKey:     "moco/test/mysql/20220401-000000/dump.tar",
+			dumpSize:    100,
+		}
+	}
+
+	tokenHeader := mocov1beta2.HTTPHeader{Name: "X-Token"}
+	tokenHeader.ValueFrom.SecretKeyRef.Name = "catalog"
+	tokenHeader.ValueFrom.SecretKeyRef.Key = "token"
+	t.Setenv(mocov1beta2.HookHeaderEnvName(HookPhasePostBackup, 1, 0), "secret")
+
+	op := &mockOperator{}
+	bm := makeBM()
+	hooks := []mocov1beta2.BackupHook{
+		{Name: "flush", SQL: []string{"FLUSH TABLES", "SELECT 1"}},
+		{Name: "notify", HTTP: &mocov1beta2.HTTPHook{URL: srv.URL, Headers: []mocov1beta2.HTTPHeader{tokenHeader}}},
+		{Name: "warn-sql", SQL: []string{"FAIL"}, FailurePolicy: mocov1beta2.HookWarn},
+		{Name: "warn-http", HTTP: &mocov1beta2.HTTPHook{URL: srv.URL + "/error"}, FailurePolicy: mocov1beta2.HookWarn},
+		{Name: "notify2", HTTP: &mocov1beta2.HTTPHook{URL: srv.URL}},
+	}
+	if err := bm.runHooks(context.Background(), op, HookPhasePostBackup, hooks); err != nil {
+		t.Fatal(err)
+	}
+
+	if !cmp.Equal(op.statements, []string{"FLUSH TABLES", "SELECT 1"}) {
+		t.Error("unexpected statements", op.statements)
+	}
+	if len(bm.warnings) != 2 || !strings.Contains(bm.warnings[0], "warn-sql") || !strings.Contains(bm.warnings[1], "warn-http") {
+		t.Error("unexpected warnings", bm.warnings)
+	}
+	expected := HookPayload{
+		Phase:       HookPhasePostBackup,
+		Namespace:   "test",
+		Name:        "mysql",
+		Time:        startTime,
+		SourceIndex: 1,
+		DumpKey:     "moco/test/mysql/20220401-000000/dump.tar",
+		DumpSize:    100,
+	}
+	if len(payloads) != 2 {
+		t.Fatal("unexpected number of HTTP calls", len(payloads))
+	}
+	if !cmp.Equal(payloads[0], expected) {
+		t.Error("unexpected payload", cmp.Diff(payloads[0], expected))
+	}
+	if !cmp.Equal(payloads[1].Warnings, bm.warnings) {
+		t.Error("warnings are not sent", payloads[1].Warnings)
+	}
+	if !cmp.Equal(tokens, []string{"secret", ""}) {
+		t.Error("unexpected headers", tokens)
+	}
+
+	// a failed hook with Abort policy stops the rest.
+	payloads = nil
+	op = &mockOperator{}
+	bm = makeBM()
+	hooks = []mocov1beta2.BackupHook{
+		{Name: "abort", SQL: []string{"FAIL"}, FailurePolicy: mocov1beta2.HookAbort},
+		{Name: "notify", HTTP: &mocov1beta2.HTTPHook{URL: srv.URL}},
+	}
+	if err := bm.runHooks(context.Background(), op, HookPhasePreBackup, hooks); err == nil {
+		t.Error("hook should fail")
+	}
+	if len(payloads) != 0 {
+		t.Error("hooks after the failure should not be run")
+	}
+
+	// hooks without failure policy abort on failure.
+	bm = makeBM()
+	hooks = []mocov1beta2.BackupHook{
+		{Name: "error", HTTP: &mocov1beta2.HTTPHook{URL: srv.URL + "/error"}},
+	}
+	if err := bm.runHooks(context.Background(), op, HookPhasePreBackup, hooks); err == nil {
+		t.Error("hook should fail")
+	}
+
+	// a hook fails if the value of a header is not given.
+	payloads = nil
+	bm = makeBM()
+	hooks = []mocov1beta2.BackupHook{
+		{Name: "notify", HTTP: &mocov1beta2.HTTPHook{URL: srv.URL, Headers: []mocov1beta2.HTTPHeader{tokenHeader}}},
+	}
+	if err := bm.runHooks(context.Background(), op, HookPhasePreBackup, hooks); err == nil {
+		t.Error("hook should fail without the header value")
+	}
+	if len(payloads) != 0 {
+		t.Error("the request should not be sent without the header value")
+	}
+
+	// the timeout applies to the hook.
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+	bm = makeBM()
+	hooks = []mocov1beta2.BackupHook{
+		{Name: "slow", HTTP: &mocov1beta2.HTTPHook{URL: slow.URL}, Timeout: metav1.Duration{Duration: 100 * time.Millisecond}},
+	}
+	if err := bm.runHooks(context.Background(), op, HookPhasePreBackup, hooks); err == nil {
+		t.Error("hook should time out")
+	}
+}
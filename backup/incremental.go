@@ -0,0 +1,93 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/event"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithBinlogOnly makes the backup upload only the binlogs since the last backup
+// without taking a full dump.  Hooks, retention, and options for the dump are ignored.
+func WithBinlogOnly() BackupOption {
+	return func(bm *BackupManager) {
+		bm.binlogOnly = true
+	}
+}
+
+// backupBinlogOnly uploads the binlogs since the last backup or binlog-only backup
+// as an archive in the directory of the last backup.
+//
+// The GTID set and the binlog filename in the status of MySQLCluster are advanced
+// so that the next binlog-only backup, or the binlog uploaded by the next backup,
+// continues from this archive.  The restore Job applies the archives in order.
+func (bm *BackupManager) backupBinlogOnly(ctx context.Context, op bkop.Operator) error {
+	lastBackup := &bm.cluster.Status.Backup
+	if bm.status.ExecutedGTIDSet == lastBackup.GTIDSet {
+		bm.log.Info("no transactions since the last backup")
+		return nil
+	}
+
+	// Transactions executed after GetServerStatus may be included in this archive
+	// and the next one.  They are skipped when applied twice thanks to GTID.
+	name := incrementalBinlogName(bm.startTime)
+	if err := bm.backupBinlog(ctx, op, name); err != nil {
+		return fmt.Errorf("failed to backup binary logs: %w", err)
+	}
+	bm.gtidSet = bm.status.ExecutedGTIDSet
+
+	if err := bm.addBinlogToManifest(ctx, lastBackup.Time.Time, name); err != nil {
+		return fmt.Errorf("failed to update the manifest of the last backup: %w", err)
+	}
+
+	bm.replicate(ctx)
+	bm.findRestorableRanges(ctx)
+
+	if err := bm.updateBinlogStatus(ctx, lastBackup.Time.Time); err != nil {
+		return fmt.Errorf("failed to update MySQLCluster status: %w", err)
+	}
+
+	ev := event.BinlogBackupCreated.ToEvent(bm.clusterRef)
+	if err := bm.client.Create(ctx, ev); err != nil {
+		bm.log.Error(err, "failed to create an event for binlog backup creation")
+	}
+	bm.log.Info("binlog-only backup finished successfully")
+	return nil
+}
+
+// updateBinlogStatus advances the binlog position of the last backup in the status of MySQLCluster.
+//
+// If another backup has been taken since `lastBackupTime`, the status is kept as is
+// because the binlogs of the new backup must continue from its own dump.
+func (bm *BackupManager) updateBinlogStatus(ctx context.Context, lastBackupTime time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &mocov1beta2.MySQLCluster{}
+		if err := bm.client.Get(ctx, client.ObjectKeyFromObject(bm.cluster), cluster); err != nil {
+			return err
+		}
+
+		sb := &cluster.Status.Backup
+		if !sb.Time.Time.Equal(lastBackupTime) {
+			bm.log.Info("another backup has been taken; the status is not updated", "time", sb.Time.Time)
+			return nil
+		}
+
+		backupTime := metav1.NewTime(bm.startTime)
+		sb.BinlogBackupTime = &backupTime
+		sb.SourceIndex = bm.sourceIndex
+		sb.SourceUUID = bm.status.UUID
+		sb.BinlogFilename = bm.status.CurrentBinlog
+		sb.GTIDSet = bm.gtidSet
+		if len(bm.restorable) > 0 {
+			setRestorableRanges(sb, bm.restorable)
+		}
+
+		return bm.client.Status().Update(ctx, cluster)
+	})
+}
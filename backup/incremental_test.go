@@ -0,0 +1,130 @@
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBackupBinlogOnly(t *testing.T) {
+	ctx := context.Background()
+	lastBackupTime := time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	cluster.Status.Backup = mocov1beta2.BackupStatus{
+		Time:           metav1.NewTime(lastBackupTime),
+		SourceIndex:    1,
+		SourceUUID:     "uuid1",
+		BinlogFilename: "binlog.000002",
+		GTIDSet:        "gtid1",
+	}
+
+	scheme := runtime.NewScheme()
+	if err := mocov1beta2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster.DeepCopy()).Build()
+
+	b := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20220501-000000/dump.tar": []byte("dump"),
+	}}
+	putTestManifest(t, b, "moco/test/test/20220501-000000/manifest.json", map[string]string{
+		"dump.tar": "dump",
+	})
+
+	newManager := func(startTime time.Time, gtid string) *BackupManager {
+		return &BackupManager{
+			log:               logr.Discard(),
+			client:            c,
+			cluster:           cluster,
+			clusterRef:        &corev1.ObjectReference{Namespace: "test", Name: "test"},
+			bucket:            b,
+			workDir:           t.TempDir(),
+			threads:           1,
+			binlogCompression: CompressionDefault,
+			startTime:         startTime,
+			sourceIndex:       1,
+			status: bkop.ServerStatus{
+				UUID:            "uuid1",
+				CurrentBinlog:   "binlog.000003",
+				ExecutedGTIDSet: gtid,
+			},
+		}
+	}
+	op := &mockOperator{binlogs: []string{"binlog.000001", "binlog.000002", "binlog.000003"}}
+
+	// nothing is uploaded without new transactions.
+	bm := newManager(time.Date(2022, time.May, 1, 6, 0, 0, 0, time.UTC), "gtid1")
+	if err := bm.backupBinlogOnly(ctx, op); err != nil {
+		t.Fatal(err)
+	}
+	if bm.binlogKey != "" {
+		t.Errorf("binlogs are uploaded without new transactions: %s", bm.binlogKey)
+	}
+
+	bm = newManager(time.Date(2022, time.May, 1, 6, 0, 0, 0, time.UTC), "gtid2")
+	if err := bm.backupBinlogOnly(ctx, op); err != nil {
+		t.Fatal(err)
+	}
+	key := "moco/test/test/20220501-000000/binlog-20220501-060000.tar.zst"
+	if bm.binlogKey != key {
+		t.Errorf("unexpected binlog key: %s", bm.binlogKey)
+	}
+	if _, ok := b.contents[key]; !ok {
+		t.Errorf("%s is not uploaded", key)
+	}
+
+	m, err := getManifest(ctx, b, "moco/test/test/20220501-000000/manifest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.Objects["binlog-20220501-060000.tar.zst"]; !ok {
+		t.Errorf("the archive is not recorded in the manifest: %v", m.Objects)
+	}
+
+	updated := &mocov1beta2.MySQLCluster{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cluster), updated); err != nil {
+		t.Fatal(err)
+	}
+	sb := &updated.Status.Backup
+	if !sb.Time.Time.Equal(lastBackupTime) {
+		t.Errorf("the time of the last backup is changed: %s", sb.Time)
+	}
+	if sb.BinlogBackupTime == nil || !sb.BinlogBackupTime.Time.Equal(bm.startTime) {
+		t.Errorf("unexpected binlog backup time: %v", sb.BinlogBackupTime)
+	}
+	if sb.BinlogFilename != "binlog.000003" || sb.GTIDSet != "gtid2" {
+		t.Errorf("unexpected binlog position: %s, %s", sb.BinlogFilename, sb.GTIDSet)
+	}
+
+	// the status is kept if another backup has been taken meanwhile.
+	updated.Status.Backup.Time = metav1.NewTime(time.Date(2022, time.May, 1, 12, 0, 0, 0, time.UTC))
+	updated.Status.Backup.GTIDSet = "gtid3"
+	if err := c.Status().Update(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	bm = newManager(time.Date(2022, time.May, 1, 13, 0, 0, 0, time.UTC), "gtid4")
+	if err := bm.updateBinlogStatus(ctx, lastBackupTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cluster), updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.Backup.GTIDSet != "gtid3" {
+		t.Errorf("the status of the new backup is overwritten: %s", updated.Status.Backup.GTIDSet)
+	}
+}
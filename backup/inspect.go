@@ -0,0 +1,555 @@
+package backup
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/bucket"
+	"github.com/cybozu-go/moco/pkg/constants"
+)
+
+// BackupInfo describes a backup stored in a bucket.
+type BackupInfo struct {
+	// Time is the time of the backup.
+	Time time.Time
+
+	// Until is the latest point-in-time that can be restored from this backup.
+	// If neither the binlog following the dump nor binlog segments are available,
+	// this is the same as Time.
+	Until time.Time
+
+	// DumpKey is the object key of the dump.
+	// For a streamed dump, this is the prefix of the files of the dump.
+	DumpKey string
+
+	// VolumeSnapshotName is the name of the VolumeSnapshot of a snapshot backup.
+	// A snapshot backup has no dump, so DumpKey is empty.
+	VolumeSnapshotName string
+
+	// BinlogKey is the object key of the binlog following the dump.
+	// Empty if there is no binlog.
+	BinlogKey string
+
+	// IncrementalBinlogKeys are the object keys of the binlog archives uploaded
+	// by binlog-only backups after the dump, in ascending order of time.
+	// They are applied before BinlogKey.
+	IncrementalBinlogKeys []string
+
+	// Manifest is the manifest of the backup.  nil if the backup has no manifest.
+	Manifest *Manifest
+
+	// Segments are the times of binlog segments taken after this backup
+	// and until the next backup, in ascending order.
+	// Segments after a gap in their GTID sets are not included.
+	Segments []time.Time
+}
+
+// ObjectSize returns the size of the object recorded in the manifest.
+// It returns -1 if the size is unknown.
+func (bi *BackupInfo) ObjectSize(filename string) int64 {
+	if bi.Manifest == nil {
+		return -1
+	}
+	obj, ok := bi.Manifest.Objects[filename]
+	if !ok {
+		return -1
+	}
+	return obj.Size
+}
+
+// Streamed returns true if the dump was uploaded file by file.
+func (bi *BackupInfo) Streamed() bool {
+	return path.Base(bi.DumpKey) == constants.DumpDirname
+}
+
+// DumpSize returns the size of the dump recorded in the manifest.
+// For a streamed dump, this is the total size of its files.
+// It returns -1 if the size is unknown.
+func (bi *BackupInfo) DumpSize() int64 {
+	if !bi.Streamed() {
+		return bi.ObjectSize(constants.DumpFilename)
+	}
+	if bi.Manifest == nil {
+		return -1
+	}
+	objs := dumpObjects(bi.Manifest)
+	if len(objs) == 0 {
+		return -1
+	}
+	var size int64
+	for _, obj := range objs {
+		size += obj.Size
+	}
+	return size
+}
+
+// ListBackups lists backups of a MySQLCluster stored in the bucket in ascending order of time.
+func ListBackups(ctx context.Context, b bucket.Bucket, namespace, name string) ([]*BackupInfo, error) {
+	prefix := calcPrefix(namespace, name)
+	keys, err := b.List(ctx, prefix+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list object keys: %w", err)
+	}
+
+	dirs := make(map[string]map[string]bool)
+	for _, key := range keys {
+		dir, file := path.Split(key)
+		dir = path.Clean(dir)
+		if dirs[dir] == nil {
+			dirs[dir] = make(map[string]bool)
+		}
+		dirs[dir][file] = true
+	}
+
+	var backups []*BackupInfo
+	for dir, files := range dirs {
+		var dumpKey string
+		switch {
+		case files[constants.DumpFilename]:
+			dumpKey = path.Join(dir, constants.DumpFilename)
+		case path.Base(dir) == constants.DumpDirname && files[constants.DumpDoneFilename]:
+			// a streamed dump is stored in a sub-directory of the backup.
+			dumpKey = dir
+			dir = path.Dir(dir)
+			files = dirs[dir]
+		case path.Dir(dir) == prefix && files[constants.ManifestFilename] && dirs[path.Join(dir, constants.DumpDirname)] == nil:
+			// this may be a snapshot backup, which has only the manifest and the binlog.
+		default:
+			continue
+		}
+		bkt, err := time.Parse(constants.BackupTimeFormat, path.Base(dir))
+		if err != nil {
+			continue
+		}
+
+		bi := &BackupInfo{
+			Time:    bkt,
+			Until:   bkt,
+			DumpKey: dumpKey,
+		}
+		if files[constants.BinlogFilename] {
+			bi.BinlogKey = path.Join(dir, constants.BinlogFilename)
+		}
+		bi.IncrementalBinlogKeys = incrementalBinlogKeys(dir, files)
+		if files[constants.ManifestFilename] {
+			m, err := getManifest(ctx, b, path.Join(dir, constants.ManifestFilename))
+			if err != nil {
+				return nil, err
+			}
+			bi.Manifest = m
+		}
+		if dumpKey == "" {
+			if bi.Manifest == nil || bi.Manifest.VolumeSnapshotName == "" {
+				continue
+			}
+			bi.VolumeSnapshotName = bi.Manifest.VolumeSnapshotName
+		}
+		backups = append(backups, bi)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.Before(backups[j].Time)
+	})
+
+	// binlog following a dump contains transactions until the next backup.
+	for i := 0; i < len(backups)-1; i++ {
+		if backups[i].BinlogKey != "" {
+			backups[i].Until = backups[i+1].Time
+		}
+	}
+
+	// binlog-only backups extend the restorable range until the last one.
+	for _, bi := range backups {
+		if len(bi.IncrementalBinlogKeys) == 0 {
+			continue
+		}
+		t, _ := parseIncrementalBinlogName(path.Base(bi.IncrementalBinlogKeys[len(bi.IncrementalBinlogKeys)-1]))
+		if t.After(bi.Until) {
+			bi.Until = t
+		}
+	}
+
+	// binlog segments extend the restorable range until the next backup
+	// as long as each of them continues from the previous one.
+	segments := parseSegments(keys, path.Join(prefix, constants.BinlogArchiveDir))
+	for i, bi := range backups {
+		var reached bkop.GTIDSet
+		if bi.Manifest != nil {
+			reached, _ = bkop.ParseGTIDSet(bi.Manifest.GTIDSet)
+		}
+		for _, s := range segments {
+			if !s.Time.After(bi.Time) {
+				continue
+			}
+			if i < len(backups)-1 && s.Time.After(backups[i+1].Time) {
+				break
+			}
+			if s.ManifestKey != "" {
+				m, err := getManifest(ctx, b, s.ManifestKey)
+				if err != nil {
+					return nil, err
+				}
+				next, ok := continueSegment(reached, m)
+				if !ok {
+					// the segments after a gap cannot be applied.
+					break
+				}
+				reached = next
+			}
+			bi.Segments = append(bi.Segments, s.Time)
+			if s.Time.After(bi.Until) {
+				bi.Until = s.Time
+			}
+		}
+	}
+	return backups, nil
+}
+
+// continueSegment checks that the binlog segment of manifest `m` continues
+// from `reached`, the GTID set reached by the backup or the previous segment.
+// It returns the GTID set reached by the segment and true if it does.
+//
+// The segment does not continue if some transactions in `reached` are lost
+// on the source, or if the transactions excluded from the segment are not
+// all in `reached`.  GTID sets that are unknown or cannot be parsed are not checked.
+func continueSegment(reached bkop.GTIDSet, m *Manifest) (bkop.GTIDSet, bool) {
+	set, err := bkop.ParseGTIDSet(m.GTIDSet)
+	if err != nil || len(set) == 0 {
+		return reached, true
+	}
+	if len(reached) == 0 {
+		return set, true
+	}
+	if !reached.IsSubsetOf(set) {
+		return nil, false
+	}
+	if prev, err := bkop.ParseGTIDSet(m.PreviousGTIDSet); err == nil && !prev.IsSubsetOf(reached) {
+		return nil, false
+	}
+	return set, true
+}
+
+// incrementalBinlogKeys returns the keys of the archives of binlog-only backups
+// among `files` in `dir` in ascending order of time.
+func incrementalBinlogKeys(dir string, files map[string]bool) []string {
+	var names []string
+	for name := range files {
+		if _, ok := parseIncrementalBinlogName(name); ok {
+			names = append(names, name)
+		}
+	}
+	// the names sort in order of time.
+	sort.Strings(names)
+
+	var keys []string
+	for _, name := range names {
+		keys = append(keys, path.Join(dir, name))
+	}
+	return keys
+}
+
+// FindBackup returns the backup that is used to restore data to `restorePoint`.
+// `backups` must be sorted in ascending order of time.
+// It returns nil if no backup is available.
+func FindBackup(backups []*BackupInfo, restorePoint time.Time) *BackupInfo {
+	var found *BackupInfo
+	for _, bi := range backups {
+		if bi.Time.After(restorePoint) {
+			break
+		}
+		found = bi
+	}
+	return found
+}
+
+// TimeRange is a range of time from Start to End, inclusive.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// RestorableRanges returns the ranges of time to which data can be restored from `backups`.
+// `backups` must be sorted in ascending order of time.
+//
+// Each backup can restore data to any point from its Time to its Until.
+// The ranges of consecutive backups are merged if they overlap or adjoin,
+// so there is a gap between two ranges in the result.
+func RestorableRanges(backups []*BackupInfo) []TimeRange {
+	var ranges []TimeRange
+	for _, bi := range backups {
+		if len(ranges) > 0 {
+			last := &ranges[len(ranges)-1]
+			if !bi.Time.After(last.End) {
+				if bi.Until.After(last.End) {
+					last.End = bi.Until
+				}
+				continue
+			}
+		}
+		ranges = append(ranges, TimeRange{Start: bi.Time, End: bi.Until})
+	}
+	return ranges
+}
+
+// VerifyBackup downloads the objects of a backup and checks their integrity.
+//
+// It checks that the dump is a valid tar archive whose metadata can be read,
+// or that a streamed dump is complete, and that the binlog archives are valid tar archives.  Archives may be compressed with zstd.
+// For a snapshot backup, only the binlog archives are checked.
+// If the backup has a manifest, the sizes and checksums of the objects and
+// the GTID set of the dump are also compared with those in the manifest.
+//
+// `workDir` is used to store the metadata of the dump temporarily.
+func VerifyBackup(ctx context.Context, b bucket.Bucket, bi *BackupInfo, workDir string) error {
+	if bi.VolumeSnapshotName != "" {
+		// the VolumeSnapshot itself is not in the bucket and is not checked.
+		return verifyBinlogs(ctx, b, bi)
+	}
+
+	var dumpObject *ManifestObject
+	var dumpFiles map[string]ManifestObject
+	if bi.Manifest != nil {
+		if bi.Streamed() {
+			dumpFiles = dumpObjects(bi.Manifest)
+			if len(dumpFiles) == 0 {
+				return fmt.Errorf("the manifest has no record of %s", constants.DumpDirname)
+			}
+		} else {
+			obj, ok := bi.Manifest.Objects[constants.DumpFilename]
+			if !ok {
+				return fmt.Errorf("the manifest has no record of %s", constants.DumpFilename)
+			}
+			dumpObject = &obj
+		}
+	}
+
+	var gtid string
+	var err error
+	if bi.Streamed() {
+		gtid, err = verifyDumpFiles(ctx, b, bi.DumpKey, dumpFiles, workDir)
+	} else {
+		gtid, err = verifyDump(ctx, b, bi.DumpKey, dumpObject, workDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %w", bi.DumpKey, err)
+	}
+	if bi.Manifest != nil && gtid != bi.Manifest.GTIDSet {
+		return fmt.Errorf("GTID set of %s does not match the manifest: %s", bi.DumpKey, gtid)
+	}
+
+	return verifyBinlogs(ctx, b, bi)
+}
+
+// verifyBinlogs checks the archives of binlog-only backups and the binlog following the backup.
+func verifyBinlogs(ctx context.Context, b bucket.Bucket, bi *BackupInfo) error {
+	keys := append([]string(nil), bi.IncrementalBinlogKeys...)
+	if bi.BinlogKey != "" {
+		keys = append(keys, bi.BinlogKey)
+	}
+	for _, key := range keys {
+		var expected *ManifestObject
+		if bi.Manifest != nil {
+			if obj, ok := bi.Manifest.Objects[path.Base(key)]; ok {
+				expected = &obj
+			}
+		}
+		if err := verifyBinlog(ctx, b, key, expected); err != nil {
+			return fmt.Errorf("failed to verify %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func verifyDump(ctx context.Context, b bucket.Bucket, key string, expected *ManifestObject, workDir string) (string, error) {
+	rc, err := b.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to get object: %w", err)
+	}
+	defer rc.Close()
+	r := newChecksumReader(rc)
+
+	dumpDir, err := os.MkdirTemp(workDir, "verify-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dumpDir)
+
+	ar, err := newArchiveReader(r)
+	if err != nil {
+		return "", err
+	}
+	defer ar.Close()
+
+	found := false
+	for {
+		hdr, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("broken tar archive: %w", err)
+		}
+		if path.Clean(hdr.Name) != "dump/@.json" {
+			if _, err := io.Copy(io.Discard, ar); err != nil {
+				return "", fmt.Errorf("broken tar archive: %w", err)
+			}
+			continue
+		}
+
+		f, err := os.Create(filepath.Join(dumpDir, "@.json"))
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(f, ar)
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("broken tar archive: %w", err)
+		}
+		found = true
+	}
+	if !found {
+		return "", errors.New("no dump metadata")
+	}
+	if err := ar.drain(); err != nil {
+		return "", fmt.Errorf("broken archive: %w", err)
+	}
+
+	if expected != nil {
+		if err := r.Verify(*expected); err != nil {
+			return "", err
+		}
+	}
+
+	return bkop.GetGTIDExecuted(dumpDir)
+}
+
+// verifyDumpFiles checks the files of a streamed dump stored under `prefix`.
+// If `expected` is not nil, the files must match it exactly.
+func verifyDumpFiles(ctx context.Context, b bucket.Bucket, prefix string, expected map[string]ManifestObject, workDir string) (string, error) {
+	keys, err := b.List(ctx, prefix+"/")
+	if err != nil {
+		return "", fmt.Errorf("failed to list object keys: %w", err)
+	}
+
+	dumpDir, err := os.MkdirTemp(workDir, "verify-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dumpDir)
+
+	found := make(map[string]bool)
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, prefix+"/")
+		found[name] = true
+		var obj *ManifestObject
+		if expected != nil {
+			o, ok := expected[name]
+			if !ok {
+				return "", fmt.Errorf("the manifest has no record of %s", name)
+			}
+			obj = &o
+		}
+		if err := verifyDumpFile(ctx, b, key, obj, dumpDir); err != nil {
+			return "", fmt.Errorf("failed to verify %s: %w", name, err)
+		}
+	}
+	for name := range expected {
+		if !found[name] {
+			return "", fmt.Errorf("%s is missing", name)
+		}
+	}
+	if !found[constants.DumpDoneFilename] {
+		return "", errors.New("the dump is not complete")
+	}
+	if !found["@.json"] {
+		return "", errors.New("no dump metadata")
+	}
+
+	return bkop.GetGTIDExecuted(dumpDir)
+}
+
+// verifyDumpFile reads an object of a streamed dump and compares it with `expected`.
+// The dump metadata is saved in `dumpDir` to read the GTID set.
+func verifyDumpFile(ctx context.Context, b bucket.Bucket, key string, expected *ManifestObject, dumpDir string) error {
+	rc, err := b.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get object: %w", err)
+	}
+	defer rc.Close()
+	r := newChecksumReader(rc)
+
+	if path.Base(key) == "@.json" {
+		f, err := os.Create(filepath.Join(dumpDir, "@.json"))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, r)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if expected == nil {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	}
+	return r.Verify(*expected)
+}
+
+func verifyBinlog(ctx context.Context, b bucket.Bucket, key string, expected *ManifestObject) error {
+	rc, err := b.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get object: %w", err)
+	}
+	defer rc.Close()
+	r := newChecksumReader(rc)
+
+	// the zstd decoder verifies the checksum of each frame.
+	ar, err := newArchiveReader(r)
+	if err != nil {
+		return err
+	}
+	defer ar.Close()
+
+	count := 0
+	for {
+		hdr, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("broken tar archive: %w", err)
+		}
+		if _, err := io.Copy(io.Discard, ar); err != nil {
+			return fmt.Errorf("broken tar archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && strings.HasPrefix(path.Clean(hdr.Name), "binlog/") {
+			count++
+		}
+	}
+	// read the rest of the data so that the whole stream is checked.
+	if err := ar.drain(); err != nil {
+		return fmt.Errorf("broken archive: %w", err)
+	}
+	if count == 0 {
+		return errors.New("no binlog files")
+	}
+
+	if expected != nil {
+		if err := r.Verify(*expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
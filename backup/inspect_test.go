@@ -0,0 +1,390 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+)
+
+func makeTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func makeObject(data []byte) ManifestObject {
+	sum := sha256.Sum256(data)
+	return ManifestObject{Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])}
+}
+
+func TestListBackups(t *testing.T) {
+	ctx := context.Background()
+	b := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20210525-112233/dump.tar":       nil,
+		"moco/test/test/20210525-112233/binlog.tar.zst": nil,
+		"moco/test/test/20210525-112233/manifest.json":  []byte(`{"version":1,"gtidSet":"gtid1"}`),
+		"moco/test/test/20210525-120001/dump.tar":       nil,
+		"moco/test/test/garbage":                        nil,
+		"moco/test/test/20210526000000/dump.tar":        nil, // invalid
+		"moco/test/test/20210526-000000/dump.tar":       nil,
+		"moco/test/test/20210526-000000/binlog.tar.zst": nil,
+		"moco/test/test/20210527-000000/binlog.tar.zst": nil, // no dump
+		"moco/test/test2/20210525-000000/dump.tar":      nil,
+	}}
+
+	backups, err := ListBackups(ctx, b, "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 3 {
+		t.Fatalf("unexpected number of backups: %d", len(backups))
+	}
+
+	bi := backups[0]
+	if bi.DumpKey != "moco/test/test/20210525-112233/dump.tar" || bi.BinlogKey != "moco/test/test/20210525-112233/binlog.tar.zst" {
+		t.Errorf("unexpected keys: %s, %s", bi.DumpKey, bi.BinlogKey)
+	}
+	if !bi.Time.Equal(time.Date(2021, time.May, 25, 11, 22, 33, 0, time.UTC)) {
+		t.Errorf("unexpected time: %s", bi.Time)
+	}
+	if !bi.Until.Equal(backups[1].Time) {
+		t.Errorf("unexpected until: %s", bi.Until)
+	}
+	if bi.Manifest == nil || bi.Manifest.GTIDSet != "gtid1" {
+		t.Errorf("unexpected manifest: %+v", bi.Manifest)
+	}
+	if bi.ObjectSize(constants.DumpFilename) != -1 {
+		t.Errorf("unexpected dump size: %d", bi.ObjectSize(constants.DumpFilename))
+	}
+
+	bi = backups[1]
+	if bi.BinlogKey != "" || bi.Manifest != nil || !bi.Until.Equal(bi.Time) {
+		t.Errorf("unexpected backup: %+v", bi)
+	}
+
+	// the binlog of the latest backup covers nothing yet
+	bi = backups[2]
+	if bi.BinlogKey == "" || !bi.Until.Equal(bi.Time) {
+		t.Errorf("unexpected backup: %+v", bi)
+	}
+
+	// FindBackup should choose the same backup as FindNearestDump
+	keys, err := b.List(ctx, calcPrefix("test", "test")+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := []time.Time{
+		time.Date(2021, time.May, 24, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, time.May, 25, 11, 22, 33, 0, time.UTC),
+		time.Date(2021, time.May, 25, 11, 30, 0, 0, time.UTC),
+		time.Date(2021, time.May, 25, 13, 0, 0, 0, time.UTC),
+		time.Date(2021, time.May, 26, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, time.May, 28, 0, 0, 0, 0, time.UTC),
+	}
+	for _, p := range points {
+		rm := &RestoreManager{log: logr.Discard(), restorePoint: p}
+		dump, binlogs, _ := rm.FindNearestDump(keys)
+		bi := FindBackup(backups, p)
+		if bi == nil {
+			if dump != "" {
+				t.Errorf("%s: FindBackup found nothing, but FindNearestDump found %s", p, dump)
+			}
+			continue
+		}
+		if bi.DumpKey != dump {
+			t.Errorf("%s: unexpected dump %s, expected %s", p, bi.DumpKey, dump)
+		}
+		if len(binlogs) > 0 && bi.BinlogKey != binlogs[len(binlogs)-1] {
+			t.Errorf("%s: unexpected binlog %s, expected %v", p, bi.BinlogKey, binlogs)
+		}
+	}
+}
+
+func TestListBackupsIncremental(t *testing.T) {
+	ctx := context.Background()
+	b := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20210525-000000/dump.tar":                        nil,
+		"moco/test/test/20210525-000000/binlog-20210525-120000.tar.zst":  nil,
+		"moco/test/test/20210525-000000/binlog-20210525-060000.tar.zst":  nil,
+		"moco/test/test/20210525-000000/binlog-garbage.tar.zst":          nil,
+		"moco/test/test/20210525-000000/binlog.tar.zst":                  nil,
+		"moco/test/test/20210526-000000/dump.tar":                        nil,
+		"moco/test/test/20210526-000000/binlog-20210526-060000.tar.zst":  nil,
+		"moco/test/test/20210526-000000/binlog-20210526-060000.tar.zst2": nil,
+	}}
+
+	backups, err := ListBackups(ctx, b, "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("unexpected number of backups: %d", len(backups))
+	}
+
+	expect := []string{
+		"moco/test/test/20210525-000000/binlog-20210525-060000.tar.zst",
+		"moco/test/test/20210525-000000/binlog-20210525-120000.tar.zst",
+	}
+	if !cmp.Equal(backups[0].IncrementalBinlogKeys, expect) {
+		t.Errorf("unexpected incremental binlogs: %s", cmp.Diff(expect, backups[0].IncrementalBinlogKeys))
+	}
+	if !backups[0].Until.Equal(backups[1].Time) {
+		t.Errorf("unexpected until: %s", backups[0].Until)
+	}
+
+	// the latest backup can be restored up to the last binlog-only backup
+	if len(backups[1].IncrementalBinlogKeys) != 1 {
+		t.Errorf("unexpected incremental binlogs: %v", backups[1].IncrementalBinlogKeys)
+	}
+	if !backups[1].Until.Equal(time.Date(2021, time.May, 26, 6, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected until: %s", backups[1].Until)
+	}
+}
+
+func TestListBackupsStreamed(t *testing.T) {
+	ctx := context.Background()
+	b := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20210525-112233/dump/@.done.json":     nil,
+		"moco/test/test/20210525-112233/dump/@.json":          nil,
+		"moco/test/test/20210525-112233/binlog.tar.zst":       nil,
+		"moco/test/test/20210525-112233/manifest.json":        []byte(`{"version":1,"objects":{"dump/@.json":{"size":10},"dump/@.done.json":{"size":2}}}`),
+		"moco/test/test/20210526-000000/dump/@.done.json":     nil,
+		"moco/test/test/20210527-000000/dump/@.json":          nil, // incomplete
+		"moco/test/test/20210527-000000/dump/db@t@@0.tsv.zst": nil,
+	}}
+
+	backups, err := ListBackups(ctx, b, "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("unexpected number of backups: %d", len(backups))
+	}
+
+	bi := backups[0]
+	if !bi.Streamed() || bi.DumpKey != "moco/test/test/20210525-112233/dump" || bi.BinlogKey != "moco/test/test/20210525-112233/binlog.tar.zst" {
+		t.Errorf("unexpected keys: %s, %s", bi.DumpKey, bi.BinlogKey)
+	}
+	if !bi.Until.Equal(backups[1].Time) {
+		t.Errorf("unexpected until: %s", bi.Until)
+	}
+	if bi.DumpSize() != 12 {
+		t.Errorf("unexpected dump size: %d", bi.DumpSize())
+	}
+
+	bi = backups[1]
+	if bi.Manifest != nil || bi.DumpSize() != -1 {
+		t.Errorf("unexpected backup: %+v", bi)
+	}
+}
+
+func TestRestorableRanges(t *testing.T) {
+	t1 := time.Date(2021, time.May, 25, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+	t4 := t3.Add(24 * time.Hour)
+	seg := t3.Add(time.Hour)
+
+	if ranges := RestorableRanges(nil); len(ranges) != 0 {
+		t.Errorf("unexpected ranges: %v", ranges)
+	}
+
+	// t1's binlog covers until t2, t2 has no binlog, t3 has segments after it.
+	backups := []*BackupInfo{
+		{Time: t1, Until: t2},
+		{Time: t2, Until: t2},
+		{Time: t3, Until: seg},
+		{Time: t4, Until: t4},
+	}
+	expected := []TimeRange{
+		{Start: t1, End: t2},
+		{Start: t3, End: seg},
+		{Start: t4, End: t4},
+	}
+	if ranges := RestorableRanges(backups); !cmp.Equal(ranges, expected) {
+		t.Error("unexpected ranges", cmp.Diff(ranges, expected))
+	}
+
+	// backups are continuous.
+	backups[1].Until = t3
+	backups[2].Until = t4
+	expected = []TimeRange{{Start: t1, End: t4}}
+	if ranges := RestorableRanges(backups); !cmp.Equal(ranges, expected) {
+		t.Error("unexpected ranges", cmp.Diff(ranges, expected))
+	}
+}
+
+func TestVerifyBackup(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd is not available")
+	}
+
+	ctx := context.Background()
+	workDir := t.TempDir()
+
+	dump := makeTar(t, map[string]string{
+		"dump/@.json":   `{"gtidExecuted":"gtid1"}`,
+		"dump/dumpdata": "1234567890",
+	})
+	binlogTar := makeTar(t, map[string]string{"binlog/binlog.000001": "binlog"})
+	zstdCmd := exec.Command("zstd", "-q", "-c")
+	zstdCmd.Stdin = bytes.NewReader(binlogTar)
+	zstdCmd.Stderr = os.Stderr
+	binlog, err := zstdCmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dumpKey := "moco/test/test/20210525-112233/dump.tar"
+	binlogKey := "moco/test/test/20210525-112233/binlog.tar.zst"
+	newBackup := func() (*mockBucket, *BackupInfo) {
+		b := &mockBucket{contents: map[string][]byte{
+			dumpKey:   append([]byte(nil), dump...),
+			binlogKey: append([]byte(nil), binlog...),
+		}}
+		return b, &BackupInfo{
+			DumpKey:   dumpKey,
+			BinlogKey: binlogKey,
+			Manifest: &Manifest{
+				GTIDSet: "gtid1",
+				Objects: map[string]ManifestObject{
+					constants.DumpFilename:   makeObject(dump),
+					constants.BinlogFilename: makeObject(binlog),
+				},
+			},
+		}
+	}
+
+	b, bi := newBackup()
+	if err := VerifyBackup(ctx, b, bi, workDir); err != nil {
+		t.Error(err)
+	}
+
+	b, bi = newBackup()
+	bi.Manifest = nil
+	if err := VerifyBackup(ctx, b, bi, workDir); err != nil {
+		t.Error(err)
+	}
+
+	b, bi = newBackup()
+	bi.Manifest.GTIDSet = "gtid2"
+	if err := VerifyBackup(ctx, b, bi, workDir); err == nil {
+		t.Error("GTID mismatch should be detected")
+	}
+
+	b, bi = newBackup()
+	b.contents[dumpKey][len(dump)-1] ^= 1
+	if err := VerifyBackup(ctx, b, bi, workDir); err == nil {
+		t.Error("corrupted dump should be detected")
+	}
+
+	b, bi = newBackup()
+	b.contents[dumpKey] = makeTar(t, map[string]string{"dump/dumpdata": "1234567890"})
+	bi.Manifest = nil
+	if err := VerifyBackup(ctx, b, bi, workDir); err == nil {
+		t.Error("dump without metadata should be detected")
+	}
+
+	b, bi = newBackup()
+	b.contents[binlogKey] = b.contents[binlogKey][:len(binlog)-4]
+	bi.Manifest = nil
+	if err := VerifyBackup(ctx, b, bi, workDir); err == nil {
+		t.Error("truncated binlog should be detected")
+	}
+
+	entries, err := os.ReadDir(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("temporary files are left: %v", entries)
+	}
+}
+
+func TestVerifyBackupStreamed(t *testing.T) {
+	ctx := context.Background()
+	workDir := t.TempDir()
+
+	prefix := "moco/test/test/20210525-112233/dump"
+	files := map[string]string{
+		"@.json":          `{"gtidExecuted":"gtid1"}`,
+		"db@t@@0.tsv.zst": "1234567890",
+		"@.done.json":     "{}",
+	}
+	newBackup := func() (*mockBucket, *BackupInfo) {
+		b := &mockBucket{contents: make(map[string][]byte)}
+		m := &Manifest{GTIDSet: "gtid1", Objects: make(map[string]ManifestObject)}
+		for name, content := range files {
+			b.contents[prefix+"/"+name] = []byte(content)
+			m.Objects["dump/"+name] = makeObject([]byte(content))
+		}
+		return b, &BackupInfo{DumpKey: prefix, Manifest: m}
+	}
+
+	b, bi := newBackup()
+	if err := VerifyBackup(ctx, b, bi, workDir); err != nil {
+		t.Error(err)
+	}
+
+	b, bi = newBackup()
+	bi.Manifest = nil
+	if err := VerifyBackup(ctx, b, bi, workDir); err != nil {
+		t.Error(err)
+	}
+
+	b, bi = newBackup()
+	bi.Manifest.GTIDSet = "gtid2"
+	if err := VerifyBackup(ctx, b, bi, workDir); err == nil {
+		t.Error("GTID mismatch should be detected")
+	}
+
+	b, bi = newBackup()
+	b.contents[prefix+"/db@t@@0.tsv.zst"][0] ^= 1
+	if err := VerifyBackup(ctx, b, bi, workDir); err == nil {
+		t.Error("corrupted file should be detected")
+	}
+
+	b, bi = newBackup()
+	delete(b.contents, prefix+"/db@t@@0.tsv.zst")
+	if err := VerifyBackup(ctx, b, bi, workDir); err == nil {
+		t.Error("missing file should be detected")
+	}
+
+	b, bi = newBackup()
+	delete(b.contents, prefix+"/@.done.json")
+	bi.Manifest = nil
+	if err := VerifyBackup(ctx, b, bi, workDir); err == nil {
+		t.Error("incomplete dump should be detected")
+	}
+
+	entries, err := os.ReadDir(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("temporary files are left: %v", entries)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
@@ -103,6 +104,7 @@ var _ = Describe("Backup/Restore", func() {
 		k8sClient.DeleteAllOf(ctx, &mocov1beta2.MySQLCluster{}, client.InNamespace("test"))
 		k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("test"))
 		k8sClient.DeleteAllOf(ctx, &corev1.Event{}, client.InNamespace("test"))
+		k8sClient.DeleteAllOf(ctx, &mocov1beta2.MySQLBackup{}, client.InNamespace("test"))
 		k8sClient.DeleteAllOf(ctx, &mocov1beta2.MySQLCluster{}, client.InNamespace("restore"))
 		k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("restore"))
 		k8sClient.DeleteAllOf(ctx, &corev1.Event{}, client.InNamespace("restore"))
@@ -132,7 +134,7 @@ var _ = Describe("Backup/Restore", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(events.Items).To(HaveLen(1))
 
-		Expect(bc.contents).To(HaveLen(1))
+		Expect(bc.contents).To(HaveLen(2))
 
 		cluster := &mocov1beta2.MySQLCluster{}
 		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "single"}, cluster)
@@ -178,6 +180,16 @@ var _ = Describe("Backup/Restore", func() {
 		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "restore", Name: "target"}, cluster)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(cluster.Status.RestoredTime).NotTo(BeNil())
+		Expect(cluster.Status.Restore).NotTo(BeNil())
+		Expect(cluster.Status.Restore.Phase).To(Equal(mocov1beta2.RestoreCompleted))
+		Expect(cluster.Status.Restore.DumpKey).To(HaveSuffix(constants.DumpFilename))
+		Expect(cluster.Status.Restore.DownloadedBytes).To(BeNumerically(">", 0))
+		Expect(cluster.Status.Restore.LoadedBytes).To(BeNumerically(">", 0))
+		Expect(cluster.Status.Restore.GTIDSet).To(Equal("gtid1"))
+		Expect(cluster.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", mocov1beta2.ConditionRestored),
+			HaveField("Status", corev1.ConditionTrue),
+		)))
 	})
 
 	It("should take an incremental backup and be able to do PiTR", func() {
@@ -196,7 +208,7 @@ var _ = Describe("Backup/Restore", func() {
 
 		err = bm.Backup(ctx)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(bc.contents).To(HaveLen(1))
+		Expect(bc.contents).To(HaveLen(2))
 
 		time.Sleep(1100 * time.Millisecond)
 		restorePoint := time.Now()
@@ -220,7 +232,7 @@ var _ = Describe("Backup/Restore", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = bm.Backup(ctx)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(bc.contents).To(HaveLen(3))
+		Expect(bc.contents).To(HaveLen(5))
 
 		cluster := &mocov1beta2.MySQLCluster{}
 		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "single"}, cluster)
@@ -236,6 +248,32 @@ var _ = Describe("Backup/Restore", func() {
 		Expect(bs.WorkDirUsage).To(BeNumerically(">", 0))
 		Expect(bs.Warnings).To(BeEmpty())
 
+		var manifests []*Manifest
+		for k := range bc.contents {
+			if !strings.HasSuffix(k, constants.ManifestFilename) {
+				continue
+			}
+			m, err := getManifest(ctx, bc, k)
+			Expect(err).NotTo(HaveOccurred())
+			manifests = append(manifests, m)
+		}
+		Expect(manifests).To(HaveLen(2))
+		if manifests[0].Time.After(manifests[1].Time) {
+			manifests[0], manifests[1] = manifests[1], manifests[0]
+		}
+		Expect(manifests[0].GTIDSet).To(Equal("gtid1"))
+		Expect(manifests[0].Objects).To(HaveKey(constants.DumpFilename))
+		Expect(manifests[0].Objects).To(HaveKey(constants.BinlogFilename))
+		Expect(manifests[0].Objects[constants.BinlogFilename].Size).To(Equal(bs.BinlogSize))
+		Expect(manifests[1].GTIDSet).To(Equal("gtid2"))
+		Expect(manifests[1].Time.Equal(bs.Time.Time)).To(BeTrue())
+		Expect(manifests[1].SourceUUID).To(Equal("123"))
+		Expect(manifests[1].BinlogFilename).To(Equal("binlog.000002"))
+		Expect(manifests[1].Objects).To(HaveKey(constants.DumpFilename))
+		Expect(manifests[1].Objects).NotTo(HaveKey(constants.BinlogFilename))
+		Expect(manifests[1].Objects[constants.DumpFilename].Size).To(Equal(bs.DumpSize))
+		Expect(manifests[1].ClusterSpec).NotTo(BeNil())
+
 		rm, err := NewRestoreManager(cfg, bc, workDir2, "test", "single", "restore", "target", "", 3, restorePoint)
 		Expect(err).NotTo(HaveOccurred())
 
@@ -243,6 +281,151 @@ var _ = Describe("Backup/Restore", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should NOT restore a corrupted backup", func() {
+		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+			op := &mockOperator{
+				binlogs: []string{"binlog.000001"},
+				uuid:    "123",
+				gtid:    "gtid1",
+			}
+			ops = append(ops, op)
+			return op, nil
+		}
+
+		bm, err := NewBackupManager(cfg, bc, workDir, "test", "single", "", 3)
+		Expect(err).NotTo(HaveOccurred())
+		err = bm.Backup(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		for k, v := range bc.contents {
+			if strings.HasSuffix(k, constants.DumpFilename) {
+				v[len(v)-1] ^= 1
+			}
+		}
+
+		cluster := &mocov1beta2.MySQLCluster{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "single"}, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		rm, err := NewRestoreManager(cfg, bc, workDir2, "test", "single", "restore", "target", "", 3, cluster.Status.Backup.Time.Time)
+		Expect(err).NotTo(HaveOccurred())
+		err = rm.Restore(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("checksum mismatch"))
+
+		// the target instance is left prepared as the restoration failed.
+		restoreOp := ops[len(ops)-1]
+		Expect(restoreOp.closed).To(BeTrue())
+		Expect(restoreOp.prepared).To(BeTrue())
+		Expect(restoreOp.finished).To(BeFalse())
+		ops = ops[:len(ops)-1]
+
+		target := &mocov1beta2.MySQLCluster{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "restore", Name: "target"}, target)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target.Status.Restore).NotTo(BeNil())
+		Expect(target.Status.Restore.Phase).To(Equal(mocov1beta2.RestoreFailed))
+		Expect(target.Status.Restore.Message).To(ContainSubstring("checksum mismatch"))
+		Expect(target.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", mocov1beta2.ConditionRestored),
+			HaveField("Status", corev1.ConditionFalse),
+			HaveField("Reason", "RestoreFailed"),
+		)))
+
+		events := &corev1.EventList{}
+		err = k8sClient.List(ctx, events, client.InNamespace("restore"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events.Items).To(ContainElement(HaveField("Reason", "RestoreFailed")))
+	})
+
+	It("should archive binlog segments and be able to do PiTR with them", func() {
+		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+			op := &mockOperator{
+				binlogs: []string{"binlog.000001"},
+				uuid:    "123",
+				gtid:    "gtid1",
+			}
+			ops = append(ops, op)
+			return op, nil
+		}
+
+		ba, err := NewBinlogArchiver(cfg, bc, workDir2, "test", "single", "", 3, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+
+		// nothing is archived before the first full backup
+		err = ba.archive(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bc.contents).To(BeEmpty())
+
+		bm, err := NewBackupManager(cfg, bc, workDir, "test", "single", "", 3)
+		Expect(err).NotTo(HaveOccurred())
+		err = bm.Backup(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bc.contents).To(HaveLen(2))
+
+		// nothing is archived if no transactions have been executed
+		err = ba.archive(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bc.contents).To(HaveLen(2))
+
+		time.Sleep(1100 * time.Millisecond)
+
+		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+			op := &mockOperator{
+				binlogs: []string{"binlog.000001", "binlog.000002"},
+				uuid:    "123",
+				gtid:    "gtid2",
+			}
+			ops = append(ops, op)
+			return op, nil
+		}
+
+		err = ba.archive(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bc.contents).To(HaveLen(4))
+		err = ba.archive(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bc.contents).To(HaveLen(4))
+
+		segments, err := listSegments(ctx, bc, "test", "single")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(segments).To(HaveLen(1))
+		Expect(segments[0].ManifestKey).NotTo(BeEmpty())
+		m, err := getManifest(ctx, bc, segments[0].ManifestKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.GTIDSet).To(Equal("gtid2"))
+		Expect(m.BinlogFilename).To(Equal("binlog.000002"))
+		Expect(m.SourceIndex).To(Equal(1))
+		Expect(m.Objects).To(HaveKey(constants.SegmentFilename))
+
+		// a new archiver resumes from the latest segment
+		ba, err = NewBinlogArchiver(cfg, bc, workDir2, "test", "single", "", 3, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		err = ba.archive(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bc.contents).To(HaveLen(4))
+
+		time.Sleep(1100 * time.Millisecond)
+		restorePoint := time.Now()
+
+		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+			op := &mockOperator{
+				binlogs:    []string{"binlog.000001", "binlog.000002"},
+				uuid:       "123",
+				gtid:       "gtid2",
+				expectPiTR: true,
+			}
+			ops = append(ops, op)
+			return op, nil
+		}
+
+		rm, err := NewRestoreManager(cfg, bc, workDir, "test", "single", "restore", "target", "", 3, restorePoint)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = rm.Restore(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
 	It("should NOT do a PiTR when the time matches the time of a full backup", func() {
 		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
 			op := &mockOperator{
@@ -259,7 +442,7 @@ var _ = Describe("Backup/Restore", func() {
 
 		err = bm.Backup(ctx)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(bc.contents).To(HaveLen(1))
+		Expect(bc.contents).To(HaveLen(2))
 
 		cluster := &mocov1beta2.MySQLCluster{}
 		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "single"}, cluster)
@@ -286,7 +469,7 @@ var _ = Describe("Backup/Restore", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = bm.Backup(ctx)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(bc.contents).To(HaveLen(3))
+		Expect(bc.contents).To(HaveLen(5))
 
 		rm, err := NewRestoreManager(cfg, bc, workDir2, "test", "single", "restore", "target", "", 3, bt)
 		Expect(err).NotTo(HaveOccurred())
@@ -295,6 +478,91 @@ var _ = Describe("Backup/Restore", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should record the backup even if a postBackup hook aborts", func() {
+		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+			op := &mockOperator{
+				binlogs: []string{"binlog.000001"},
+				uuid:    "123",
+				gtid:    "gtid1",
+			}
+			ops = append(ops, op)
+			return op, nil
+		}
+
+		hooks := &mocov1beta2.BackupHooks{
+			PostBackup: []mocov1beta2.BackupHook{
+				{Name: "abort", SQL: []string{"FAIL"}, FailurePolicy: mocov1beta2.HookAbort},
+			},
+		}
+		bm, err := NewBackupManager(cfg, bc, workDir, "test", "single", "", 3, WithHooks(hooks))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = bm.Backup(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(bc.contents).To(HaveLen(2))
+
+		cluster := &mocov1beta2.MySQLCluster{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "single"}, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Status.Backup.Time.Time).To(BeTemporally("==", bm.startTime))
+		Expect(cluster.Status.Backup.GTIDSet).To(Equal("gtid1"))
+		Expect(cluster.Status.Backup.Warnings).To(HaveLen(1))
+	})
+
+	It("should prune old backups", func() {
+		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+			op := &mockOperator{
+				binlogs: []string{"binlog.000001"},
+				uuid:    "123",
+				gtid:    "gtid1",
+			}
+			ops = append(ops, op)
+			return op, nil
+		}
+
+		bm, err := NewBackupManager(cfg, bc, workDir, "test", "single", "", 3, WithRetention(Retention{KeepLast: 1}))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = bm.Backup(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bc.contents).To(HaveLen(2))
+		var firstDump string
+		for k := range bc.contents {
+			if strings.HasSuffix(k, constants.DumpFilename) {
+				firstDump = k
+			}
+		}
+
+		time.Sleep(1100 * time.Millisecond)
+
+		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+			op := &mockOperator{
+				binlogs: []string{"binlog.000001", "binlog.000002"},
+				uuid:    "123",
+				gtid:    "gtid2",
+			}
+			ops = append(ops, op)
+			return op, nil
+		}
+
+		// second shot
+		err = os.RemoveAll(filepath.Join(workDir, "dump"))
+		Expect(err).NotTo(HaveOccurred())
+		bm, err = NewBackupManager(cfg, bc, workDir, "test", "single", "", 3, WithRetention(Retention{KeepLast: 1}))
+		Expect(err).NotTo(HaveOccurred())
+		err = bm.Backup(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		// the first dump, the binlog following it, and the manifest are deleted
+		Expect(bc.contents).To(HaveLen(2))
+		Expect(bc.contents).NotTo(HaveKey(firstDump))
+
+		cluster := &mocov1beta2.MySQLCluster{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "single"}, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Status.Backup.Warnings).To(BeEmpty())
+	})
+
 	It("should record binlog backup failure", func() {
 		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
 			op := &mockOperator{
@@ -311,7 +579,7 @@ var _ = Describe("Backup/Restore", func() {
 
 		err = bm.Backup(ctx)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(bc.contents).To(HaveLen(1))
+		Expect(bc.contents).To(HaveLen(2))
 
 		time.Sleep(1100 * time.Millisecond)
 
@@ -332,7 +600,7 @@ var _ = Describe("Backup/Restore", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = bm.Backup(ctx)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(bc.contents).To(HaveLen(2))
+		Expect(bc.contents).To(HaveLen(4))
 
 		events := &corev1.EventList{}
 		err = k8sClient.List(ctx, events, client.InNamespace("test"))
@@ -348,4 +616,98 @@ var _ = Describe("Backup/Restore", func() {
 		bs := &cluster.Status.Backup
 		Expect(bs.Warnings).NotTo(BeEmpty())
 	})
+
+	It("should record the result in MySQLBackup", func() {
+		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+			op := &mockOperator{
+				binlogs: []string{"binlog.000001"},
+				uuid:    "123",
+				gtid:    "gtid1",
+			}
+			ops = append(ops, op)
+			return op, nil
+		}
+
+		bm, err := NewBackupManager(cfg, bc, workDir, "test", "single", "", 3)
+		Expect(err).NotTo(HaveOccurred())
+		err = bm.Backup(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		time.Sleep(1100 * time.Millisecond)
+
+		newOperator = func(host string, port int, user, password string, threads int) (bkop.Operator, error) {
+			op := &mockOperator{
+				binlogs: []string{"binlog.000001", "binlog.000002"},
+				uuid:    "123",
+				gtid:    "gtid2",
+			}
+			ops = append(ops, op)
+			return op, nil
+		}
+
+		backup := &mocov1beta2.MySQLBackup{}
+		backup.Namespace = "test"
+		backup.Name = "on-demand"
+		backup.Spec.ClusterName = "single"
+		backup.Spec.Labels = map[string]string{"reason": "migration"}
+		err = k8sClient.Create(ctx, backup)
+		Expect(err).NotTo(HaveOccurred())
+
+		bm, err = NewBackupManager(cfg, bc, workDir, "test", "single", "", 3, WithBackupName("on-demand"))
+		Expect(err).NotTo(HaveOccurred())
+		err = bm.Backup(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		backup = &mocov1beta2.MySQLBackup{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "on-demand"}, backup)
+		Expect(err).NotTo(HaveOccurred())
+		st := &backup.Status
+		Expect(st.Phase).To(Equal(mocov1beta2.MySQLBackupSucceeded))
+		Expect(st.CompletionTime).NotTo(BeNil())
+		Expect(st.BackupTime).NotTo(BeNil())
+		Expect(st.GTIDSet).To(Equal("gtid2"))
+		Expect(st.DumpSize).To(BeNumerically(">", 0))
+		Expect(st.BinlogSize).To(BeNumerically(">", 0))
+		Expect(bc.contents).To(HaveKey(st.DumpKey))
+		Expect(bc.contents).To(HaveKey(st.ManifestKey))
+		Expect(bc.contents).To(HaveKey(st.BinlogKey))
+
+		m, err := getManifest(ctx, bc, st.ManifestKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.Labels).To(Equal(map[string]string{"reason": "migration"}))
+
+		cluster := &mocov1beta2.MySQLCluster{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "single"}, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Status.Backup.Time.Equal(st.BackupTime)).To(BeTrue())
+
+		By("taking a standalone backup into another bucket")
+		time.Sleep(1100 * time.Millisecond)
+		backup = &mocov1beta2.MySQLBackup{}
+		backup.Namespace = "test"
+		backup.Name = "standalone"
+		backup.Spec.ClusterName = "single"
+		backup.Spec.BucketConfig = &mocov1beta2.BucketConfig{BucketName: "another"}
+		err = k8sClient.Create(ctx, backup)
+		Expect(err).NotTo(HaveOccurred())
+
+		bc2 := &mockBucket{contents: map[string][]byte{}}
+		bm, err = NewBackupManager(cfg, bc2, workDir, "test", "single", "", 3, WithBackupName("standalone"))
+		Expect(err).NotTo(HaveOccurred())
+		err = bm.Backup(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bc2.contents).To(HaveLen(2))
+
+		backup = &mocov1beta2.MySQLBackup{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "standalone"}, backup)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backup.Status.Phase).To(Equal(mocov1beta2.MySQLBackupSucceeded))
+		Expect(backup.Status.BinlogKey).To(BeEmpty())
+
+		prevTime := cluster.Status.Backup.Time
+		cluster = &mocov1beta2.MySQLCluster{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "single"}, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Status.Backup.Time.Equal(&prevTime)).To(BeTrue())
+	})
 })
@@ -0,0 +1,117 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/constants"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// waitJobInterval is the interval to check backup Jobs of the cluster.
+var waitJobInterval = 10 * time.Second
+
+// runningBackupJob returns the name of an unfinished backup Job of the cluster
+// whose controller satisfies `match`, or an empty string if there is none.
+func (bm *BackupManager) runningBackupJob(ctx context.Context, match func(owner *metav1.OwnerReference) bool) (string, error) {
+	jobs := &batchv1.JobList{}
+	err := bm.client.List(ctx, jobs, client.InNamespace(bm.cluster.Namespace), client.MatchingLabels{
+		constants.LabelAppName:      constants.AppNameBackup,
+		constants.LabelAppInstance:  bm.cluster.Name,
+		constants.LabelAppCreatedBy: constants.AppCreator,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list Jobs: %w", err)
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if isJobFinished(job) {
+			continue
+		}
+		owner := metav1.GetControllerOf(job)
+		if owner == nil || !match(owner) {
+			continue
+		}
+		return job.Name, nil
+	}
+	return "", nil
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		if cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// isFullBackupJob returns true if the Job takes a full backup, that is,
+// it is created by a MySQLBackup or by the backup CronJob.
+func (bm *BackupManager) isFullBackupJob(owner *metav1.OwnerReference) bool {
+	switch owner.Kind {
+	case "MySQLBackup":
+		return true
+	case "CronJob":
+		return owner.Name == bm.cluster.BackupCronJobName()
+	}
+	return false
+}
+
+// blocksScheduledBackup returns true if a scheduled backup must wait for the Job,
+// that is, it is created by a MySQLBackup or by the binlog-only backup CronJob.
+func (bm *BackupManager) blocksScheduledBackup(owner *metav1.OwnerReference) bool {
+	switch owner.Kind {
+	case "MySQLBackup":
+		return true
+	case "CronJob":
+		return owner.Name == bm.cluster.BinlogBackupCronJobName()
+	}
+	return false
+}
+
+// waitForBackupJobs waits for the backup Jobs of the cluster that satisfy `match` to finish.
+//
+// moco-controller does not start a MySQLBackup while a scheduled backup is running,
+// but a scheduled backup is started by the CronJob regardless of other backups.
+// As the other backup may have updated the status of the cluster, it is read again.
+func (bm *BackupManager) waitForBackupJobs(ctx context.Context, match func(owner *metav1.OwnerReference) bool) error {
+	waited := false
+	for {
+		running, err := bm.runningBackupJob(ctx, match)
+		if err != nil {
+			return err
+		}
+		if running == "" {
+			break
+		}
+		if !waited {
+			bm.log.Info("waiting for another backup Job to finish", "jobName", running)
+			waited = true
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitJobInterval):
+		}
+	}
+	if !waited {
+		return nil
+	}
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	if err := bm.client.Get(ctx, client.ObjectKeyFromObject(bm.cluster), cluster); err != nil {
+		return fmt.Errorf("failed to get MySQLCluster %s/%s: %w", bm.cluster.Namespace, bm.cluster.Name, err)
+	}
+	bm.cluster = cluster
+	return nil
+}
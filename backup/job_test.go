@@ -0,0 +1,152 @@
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestBackupJob(name, ownerKind, ownerName string) *batchv1.Job {
+	job := &batchv1.Job{}
+	job.Namespace = "test"
+	job.Name = name
+	job.Labels = map[string]string{
+		constants.LabelAppName:      constants.AppNameBackup,
+		constants.LabelAppInstance:  "test",
+		constants.LabelAppCreatedBy: constants.AppCreator,
+	}
+	job.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       ownerKind,
+		Name:       ownerName,
+		UID:        "uid",
+		Controller: pointer.Bool(true),
+	}}
+	return job
+}
+
+func TestWaitForBackupJobs(t *testing.T) {
+	ctx := context.Background()
+	defer func(d time.Duration) { waitJobInterval = d }(waitJobInterval)
+	waitJobInterval = 10 * time.Millisecond
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+
+	scheme := runtime.NewScheme()
+	if err := mocov1beta2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := batchv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	finished := newTestBackupJob("finished", "MySQLBackup", "old")
+	finished.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	scheduled := newTestBackupJob("scheduled", "CronJob", "moco-backup-test")
+	running := newTestBackupJob("ondemand", "MySQLBackup", "ondemand")
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster.DeepCopy(), finished, scheduled, running).Build()
+
+	bm := &BackupManager{
+		log:     logr.Discard(),
+		client:  c,
+		cluster: cluster,
+	}
+
+	// the MySQLBackup finishes after updating the status of the cluster.
+	backupTime := metav1.NewTime(time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC))
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		updated := &mocov1beta2.MySQLCluster{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(cluster), updated); err != nil {
+			panic(err)
+		}
+		updated.Status.Backup.Time = backupTime
+		if err := c.Status().Update(ctx, updated); err != nil {
+			panic(err)
+		}
+		job := &batchv1.Job{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: "test", Name: "ondemand"}, job); err != nil {
+			panic(err)
+		}
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		if err := c.Status().Update(ctx, job); err != nil {
+			panic(err)
+		}
+	}()
+
+	start := time.Now()
+	if err := bm.waitForBackupJobs(ctx, bm.blocksScheduledBackup); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < 100*time.Millisecond {
+		t.Error("the running Job of MySQLBackup is not waited for")
+	}
+	if !bm.cluster.Status.Backup.Time.Equal(&backupTime) {
+		t.Error("the status of the cluster is not read again", bm.cluster.Status.Backup.Time)
+	}
+
+	// Jobs of the backup CronJob are not waited for.
+	name, err := bm.runningBackupJob(ctx, bm.blocksScheduledBackup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "" {
+		t.Error("unexpected running Job", name)
+	}
+}
+
+func TestBackupBinlogOnlySkip(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	cluster.Spec.Replicas = 1
+	cluster.Status.Backup.Time = metav1.NewTime(time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC))
+
+	scheme := runtime.NewScheme()
+	if err := mocov1beta2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := batchv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	full := newTestBackupJob("full", "CronJob", cluster.BackupCronJobName())
+	binlog := newTestBackupJob("binlog", "CronJob", cluster.BinlogBackupCronJobName())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster.DeepCopy(), full, binlog).Build()
+
+	bm := &BackupManager{
+		log:        logr.Discard(),
+		client:     c,
+		cluster:    cluster,
+		binlogOnly: true,
+	}
+	if err := bm.Backup(ctx); err != nil {
+		t.Fatal("the binlog-only backup should be skipped while a full backup is running:", err)
+	}
+
+	// once the full backup finishes, the binlog-only backup goes on to find the source.
+	full.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	if err := c.Status().Update(ctx, full); err != nil {
+		t.Fatal(err)
+	}
+	if err := bm.Backup(ctx); err == nil {
+		t.Fatal("the binlog-only backup should not be skipped")
+	}
+}
@@ -2,6 +2,7 @@ package backup
 
 import (
 	"path"
+	"strings"
 	"time"
 
 	"github.com/cybozu-go/moco/pkg/constants"
@@ -16,3 +17,30 @@ func calcKey(clusterNS, clusterName, filename string, dt time.Time) string {
 func calcPrefix(clusterNS, clusterName string) string {
 	return path.Join(prefix, clusterNS, clusterName)
 }
+
+func calcSegmentKey(clusterNS, clusterName, filename string, dt time.Time) string {
+	return path.Join(calcSegmentPrefix(clusterNS, clusterName), dt.UTC().Format(constants.BackupTimeFormat), filename)
+}
+
+func calcSegmentPrefix(clusterNS, clusterName string) string {
+	return path.Join(prefix, clusterNS, clusterName, constants.BinlogArchiveDir)
+}
+
+// incrementalBinlogName returns the name of the archive of a binlog-only backup taken at `dt`.
+func incrementalBinlogName(dt time.Time) string {
+	return constants.IncrementalBinlogPrefix + dt.UTC().Format(constants.BackupTimeFormat) + constants.IncrementalBinlogSuffix
+}
+
+// parseIncrementalBinlogName returns the time of a binlog-only backup from the name of its archive.
+// It returns false if `name` is not such a name.
+func parseIncrementalBinlogName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, constants.IncrementalBinlogPrefix) || !strings.HasSuffix(name, constants.IncrementalBinlogSuffix) {
+		return time.Time{}, false
+	}
+	s := strings.TrimSuffix(strings.TrimPrefix(name, constants.IncrementalBinlogPrefix), constants.IncrementalBinlogSuffix)
+	t, err := time.Parse(constants.BackupTimeFormat, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
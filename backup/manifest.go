@@ -0,0 +1,152 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/bucket"
+)
+
+// ManifestVersion is the format version of Manifest.
+const ManifestVersion = 1
+
+// Manifest describes a backup stored in a directory of a bucket.
+//
+// The manifest is uploaded as manifest.json after the dump has been uploaded,
+// and is updated by the next backup when it uploads the binlog archive
+// that follows the dump into the same directory.
+//
+// Binlog segments uploaded by BinlogArchiver also have manifests.
+// For them, Time is the time when the segment was taken.
+type Manifest struct {
+	// Version is the format version of the manifest.
+	Version int `json:"version"`
+
+	// Time is the time of the backup.
+	Time time.Time `json:"time"`
+
+	// Namespace and Name are of the source MySQLCluster.
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// SourceIndex is the ordinal of the backup source instance.
+	SourceIndex int `json:"sourceIndex"`
+
+	// SourceUUID is the `server_uuid` of the backup source instance.
+	SourceUUID string `json:"sourceUUID"`
+
+	// MySQLVersion is the version of the backup source instance.
+	MySQLVersion string `json:"mysqlVersion"`
+
+	// BinlogFilename is the binlog filename that the backup source instance was writing to
+	// when the backup was taken.
+	BinlogFilename string `json:"binlogFilename"`
+
+	// GTIDSet is the GTID set of the full dump.
+	// For a binlog segment, this is the GTID set executed on the source
+	// instance when the segment was taken.
+	GTIDSet string `json:"gtidSet"`
+
+	// PreviousGTIDSet is the GTID set excluded from a binlog segment,
+	// that is, the GTID set of the previous segment or the last backup.
+	PreviousGTIDSet string `json:"previousGTIDSet,omitempty"`
+
+	// VolumeSnapshotName is the name of the VolumeSnapshot of a snapshot backup.
+	// A snapshot backup has no dump in the bucket.
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+
+	// Objects maps the filenames of the objects in the directory to their information.
+	Objects map[string]ManifestObject `json:"objects"`
+
+	// ClusterSpec is the spec of the source MySQLCluster at the time of the backup.
+	ClusterSpec *mocov1beta2.MySQLClusterSpec `json:"clusterSpec,omitempty"`
+
+	// Labels are the labels given by the MySQLBackup that requested the backup, if any.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Filter specifies the schemas and tables in the dump, if the backup is filtered.
+	Filter *bkop.Filter `json:"filter,omitempty"`
+}
+
+// ManifestObject records the size and checksum of an object.
+// The size and checksum are calculated for the data before encryption.
+type ManifestObject struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+func putManifest(ctx context.Context, b bucket.Bucket, key string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	return b.Put(ctx, key, bytes.NewReader(data), int64(len(data)))
+}
+
+func getManifest(ctx context.Context, b bucket.Bucket, key string) (*Manifest, error) {
+	r, err := b.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	m := &Manifest{}
+	if err := json.NewDecoder(r).Decode(m); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	if m.Version > ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d in %s", m.Version, key)
+	}
+	return m, nil
+}
+
+// checksumReader calculates the size and SHA-256 checksum of the data read through it.
+type checksumReader struct {
+	r    io.Reader
+	h    hash.Hash
+	size int64
+}
+
+func newChecksumReader(r io.Reader) *checksumReader {
+	return &checksumReader{r: r, h: sha256.New()}
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.h.Write(p[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+// Object returns the size and checksum of the data read so far.
+func (c *checksumReader) Object() ManifestObject {
+	return ManifestObject{
+		Size:   c.size,
+		SHA256: hex.EncodeToString(c.h.Sum(nil)),
+	}
+}
+
+// Verify reads the rest of the data and compares its size and checksum with `expected`.
+func (c *checksumReader) Verify(expected ManifestObject) error {
+	if _, err := io.Copy(io.Discard, c); err != nil {
+		return err
+	}
+
+	actual := c.Object()
+	if actual.Size != expected.Size {
+		return fmt.Errorf("size mismatch: expected %d, actual %d", expected.Size, actual.Size)
+	}
+	if actual.SHA256 != expected.SHA256 {
+		return fmt.Errorf("checksum mismatch: expected %s, actual %s", expected.SHA256, actual.SHA256)
+	}
+	return nil
+}
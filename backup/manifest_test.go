@@ -0,0 +1,81 @@
+package backup
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cybozu-go/moco/pkg/constants"
+)
+
+func TestManifest(t *testing.T) {
+	ctx := context.Background()
+	b := &mockBucket{contents: make(map[string][]byte)}
+
+	now := time.Date(2021, time.May, 25, 11, 22, 33, 0, time.UTC)
+	m := &Manifest{
+		Version:        ManifestVersion,
+		Time:           now,
+		Namespace:      "test",
+		Name:           "single",
+		SourceUUID:     "123",
+		MySQLVersion:   "8.0.28",
+		BinlogFilename: "binlog.000001",
+		GTIDSet:        "gtid1",
+		Objects: map[string]ManifestObject{
+			constants.DumpFilename: {Size: 10, SHA256: "abc"},
+		},
+	}
+	key := calcKey("test", "single", constants.ManifestFilename, now)
+	if err := putManifest(ctx, b, key, m); err != nil {
+		t.Fatal(err)
+	}
+	if key != "moco/test/single/20210525-112233/manifest.json" {
+		t.Errorf("unexpected key: %s", key)
+	}
+
+	m2, err := getManifest(ctx, b, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m2.Time.Equal(now) {
+		t.Errorf("unexpected time: %s", m2.Time)
+	}
+	if m2.GTIDSet != "gtid1" || m2.SourceUUID != "123" || m2.MySQLVersion != "8.0.28" {
+		t.Errorf("unexpected manifest: %+v", m2)
+	}
+	if m2.Objects[constants.DumpFilename] != m.Objects[constants.DumpFilename] {
+		t.Errorf("unexpected objects: %+v", m2.Objects)
+	}
+
+	b.contents[key] = []byte(`{"version": 2}`)
+	if _, err := getManifest(ctx, b, key); err == nil {
+		t.Error("newer manifest version should not be accepted")
+	}
+}
+
+func TestChecksumReader(t *testing.T) {
+	// sha256sum of "hello"
+	hello := ManifestObject{Size: 5, SHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"}
+
+	cr := newChecksumReader(strings.NewReader("hello"))
+	buf := make([]byte, 2)
+	if _, err := cr.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+	// Verify reads the rest of the data
+	if err := cr.Verify(hello); err != nil {
+		t.Error(err)
+	}
+
+	cr = newChecksumReader(strings.NewReader("hellO"))
+	if err := cr.Verify(hello); err == nil {
+		t.Error("checksum mismatch should be detected")
+	}
+
+	cr = newChecksumReader(strings.NewReader("hello!"))
+	if err := cr.Verify(hello); err == nil {
+		t.Error("size mismatch should be detected")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/cybozu-go/moco/pkg/bkop"
@@ -21,6 +22,10 @@ type mockOperator struct {
 	uuid       string
 	gtid       string
 	expectPiTR bool
+	statements []string
+	values     map[string]string
+	// missingGTID is a GTID that ContainsGTIDSet treats as not executed.
+	missingGTID string
 
 	// status
 	alive    bool
@@ -29,6 +34,13 @@ type mockOperator struct {
 	prepared bool
 	pitr     bool
 	finished bool
+	streamed bool
+	locked   bool
+
+	// noProgress makes LoadDumpStream not write the progress file.
+	noProgress bool
+	// maxDataFiles is the maximum number of data files seen at once by LoadDumpStream.
+	maxDataFiles int
 }
 
 var _ bkop.Operator = &mockOperator{}
@@ -48,11 +60,35 @@ func (o *mockOperator) Close() {
 func (o *mockOperator) GetServerStatus(_ context.Context, st *bkop.ServerStatus) error {
 	st.CurrentBinlog = o.binlogs[len(o.binlogs)-1]
 	st.UUID = o.uuid
+	st.Version = "8.0.28"
+	st.ExecutedGTIDSet = o.gtid
 	st.SuperReadOnly = !o.writable
 	return nil
 }
 
-func (o *mockOperator) DumpFull(ctx context.Context, dir string) error {
+func (o *mockOperator) ContainsGTIDSet(_ context.Context, set string) (bool, error) {
+	return o.missingGTID == "" || !strings.Contains(set, o.missingGTID), nil
+}
+
+func (o *mockOperator) RunSQL(_ context.Context, stmts []string) error {
+	for _, stmt := range stmts {
+		if stmt == "FAIL" {
+			return errors.New("failed to execute FAIL")
+		}
+		o.statements = append(o.statements, stmt)
+	}
+	return nil
+}
+
+func (o *mockOperator) QueryValue(_ context.Context, query string) (string, error) {
+	v, ok := o.values[query]
+	if !ok {
+		return "", fmt.Errorf("failed to execute %q", query)
+	}
+	return v, nil
+}
+
+func (o *mockOperator) DumpFull(ctx context.Context, dir string, filter *bkop.Filter) error {
 	data, err := json.Marshal(map[string]string{
 		"gtidExecuted": o.gtid,
 	})
@@ -63,7 +99,21 @@ func (o *mockOperator) DumpFull(ctx context.Context, dir string) error {
 	if err := os.WriteFile(filepath.Join(dir, "@.json"), data, 0644); err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(dir, "dumpdata"), []byte("1234567890"), 0644)
+	if err := os.WriteFile(filepath.Join(dir, "dumpdata"), []byte("1234567890"), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "@.done.json"), []byte("{}"), 0644)
+}
+
+func (o *mockOperator) LockForSnapshot(_ context.Context) (func() error, error) {
+	if o.locked {
+		return nil, errors.New("already locked")
+	}
+	o.locked = true
+	return func() error {
+		o.locked = false
+		return nil
+	}, nil
 }
 
 func (o *mockOperator) GetBinlogs(_ context.Context) ([]string, error) {
@@ -97,7 +147,7 @@ func (o *mockOperator) PrepareRestore(_ context.Context) error {
 	return nil
 }
 
-func (o *mockOperator) LoadDump(ctx context.Context, dir string) error {
+func (o *mockOperator) LoadDump(ctx context.Context, dir string, filter *bkop.Filter) error {
 	if !o.prepared {
 		return errors.New("not prepared")
 	}
@@ -105,20 +155,78 @@ func (o *mockOperator) LoadDump(ctx context.Context, dir string) error {
 	return err
 }
 
-func (o *mockOperator) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, restorePoint time.Time) error {
+func (o *mockOperator) LoadDumpStream(ctx context.Context, dir, progressFile string, filter *bkop.Filter) error {
+	o.streamed = true
+	if err := o.LoadDump(ctx, dir, filter); err != nil {
+		return err
+	}
+
+	// load data files and wait for the rest of the dump like mysqlsh does.
+	// The test data files are named like "db@t@0.tsv.zst" or "db@t@@1.tsv.zst".
+	loaded := make(map[string]bool)
+	for {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		var dataFiles int
+		var complete bool
+		for _, e := range entries {
+			name := e.Name()
+			if name == "@.done.json" {
+				complete = true
+			}
+			if !strings.HasSuffix(name, ".tsv.zst") {
+				continue
+			}
+			dataFiles++
+			if loaded[name] || o.noProgress {
+				continue
+			}
+			loaded[name] = true
+
+			fields := strings.Split(strings.TrimSuffix(name, ".tsv.zst"), "@")
+			var chunk int
+			fmt.Sscanf(fields[len(fields)-1], "%d", &chunk)
+			entry, _ := json.Marshal(map[string]interface{}{
+				"op": "TABLE-DATA", "done": true, "schema": fields[0], "table": fields[1], "chunk": chunk,
+			})
+			f, err := os.OpenFile(progressFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(f, "%s\n", entry)
+			f.Close()
+		}
+		if dataFiles > o.maxDataFiles {
+			o.maxDataFiles = dataFiles
+		}
+		if complete {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func (o *mockOperator) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, restorePoint time.Time, stopGTIDSet bkop.GTIDSet, filter *bkop.Filter) (bool, error) {
 	if !o.prepared {
-		return errors.New("not prepared")
+		return false, errors.New("not prepared")
 	}
 	entries, err := os.ReadDir(binlogDir)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if len(entries) == 0 {
-		return errors.New("no binlog")
+		return false, errors.New("no binlog")
 	}
 
 	o.pitr = true
-	return nil
+	return false, nil
 }
 
 func (o *mockOperator) FinishRestore(_ context.Context) error {
@@ -156,8 +264,15 @@ func (b *mockBucket) Get(ctx context.Context, key string) (io.ReadCloser, error)
 func (b *mockBucket) List(ctx context.Context, prefix string) ([]string, error) {
 	keys := make([]string, 0, len(b.contents))
 	for k := range b.contents {
-		keys = append(keys, k)
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
 	}
 	sort.Strings(keys)
 	return keys, nil
 }
+
+func (b *mockBucket) Delete(ctx context.Context, key string) error {
+	delete(b.contents, key)
+	return nil
+}
@@ -0,0 +1,255 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cybozu-go/moco/pkg/bucket"
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/go-logr/logr"
+)
+
+// Retention is a set of rules to decide which backups to keep.
+// A backup is kept if any of the rules keeps it.
+type Retention struct {
+	// KeepLast keeps the last N backups.
+	KeepLast int
+
+	// KeepFor keeps backups needed to restore data to any point within the duration.
+	KeepFor time.Duration
+
+	// KeepDaily keeps the last backup of each day for the last N days.
+	KeepDaily int
+
+	// KeepWeekly keeps the last backup of each ISO week for the last N weeks.
+	KeepWeekly int
+
+	// KeepMonthly keeps the last backup of each month for the last N months.
+	KeepMonthly int
+}
+
+// IsZero returns true if no rules are specified.
+func (r Retention) IsZero() bool {
+	return r == Retention{}
+}
+
+// selectPrunable returns the times of backups that can be deleted.
+//
+// The most recent backup is always kept.  For KeepFor, the most recent backup
+// taken before `now - KeepFor` is also kept because it is needed to restore
+// data to the oldest point in the duration.
+func selectPrunable(times []time.Time, now time.Time, r Retention) []time.Time {
+	sorted := make([]time.Time, len(times))
+	copy(sorted, times)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].After(sorted[j]) })
+
+	keep := make([]bool, len(sorted))
+	if len(sorted) > 0 {
+		keep[0] = true
+	}
+
+	for i := 0; i < len(sorted) && i < r.KeepLast; i++ {
+		keep[i] = true
+	}
+
+	if r.KeepFor > 0 {
+		cutoff := now.Add(-r.KeepFor)
+		for i, t := range sorted {
+			keep[i] = true
+			if !t.After(cutoff) {
+				break
+			}
+		}
+	}
+
+	keepPeriodic := func(n int, period func(time.Time) string) {
+		var last string
+		for i, t := range sorted {
+			if n <= 0 {
+				return
+			}
+			p := period(t.UTC())
+			if p == last {
+				continue
+			}
+			keep[i] = true
+			last = p
+			n--
+		}
+	}
+	keepPeriodic(r.KeepDaily, func(t time.Time) string {
+		return t.Format("2006-01-02")
+	})
+	keepPeriodic(r.KeepWeekly, func(t time.Time) string {
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%d-%02d", y, w)
+	})
+	keepPeriodic(r.KeepMonthly, func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+
+	var prunable []time.Time
+	for i, t := range sorted {
+		if !keep[i] {
+			prunable = append(prunable, t)
+		}
+	}
+	return prunable
+}
+
+// isDumpMarker returns true if `key` is the object that makes the dump available,
+// that is, the archive of the dump or the last file of a streamed dump.
+func isDumpMarker(key string) bool {
+	if path.Base(key) == constants.DumpFilename {
+		return true
+	}
+	return path.Base(key) == constants.DumpDoneFilename && path.Base(path.Dir(key)) == constants.DumpDirname
+}
+
+// prune deletes backups that are not retained by the retention rules.
+//
+// Objects are deleted per backup directory so that a full dump and
+// the binlog archive needed to restore from it are deleted together.
+// The dump, or the marker of a streamed dump, is deleted first so that an interrupted pruning never leaves
+// a dump without the following binlogs.  Likewise, the VolumeSnapshot of a snapshot backup is deleted first.
+//
+// Binlog segments taken before the oldest remaining backup are deleted too
+// because no backup can use them anymore.
+func (bm *BackupManager) prune(ctx context.Context) error {
+	return bm.pruneBucket(ctx, bm.bucket, bm.log, true)
+}
+
+// pruneSecondaries applies the retention rules to the secondary buckets.
+// Failures are recorded as warnings because the backup itself has succeeded.
+//
+// Each bucket is pruned on its own set of backups, so a backup that has not been
+// copied to a secondary bucket does not cause the backups there to be deleted early.
+func (bm *BackupManager) pruneSecondaries(ctx context.Context) {
+	for _, sb := range bm.secondaries {
+		if err := bm.pruneBucket(ctx, sb.Bucket, bm.log.WithValues("bucket", sb.Name), false); err != nil {
+			bm.log.Error(err, "failed to prune old backups", "bucket", sb.Name)
+			bm.warnings = append(bm.warnings, fmt.Sprintf("failed to prune old backups in %s: %v", sb.Name, err))
+		}
+	}
+}
+
+// pruneBucket deletes the backups in `b` that are not retained by the retention rules.
+// VolumeSnapshots are deleted only if `snapshots` is true, i.e. for the primary bucket.
+func (bm *BackupManager) pruneBucket(ctx context.Context, b bucket.Bucket, log logr.Logger, snapshots bool) error {
+	prefix := calcPrefix(bm.cluster.Namespace, bm.cluster.Name)
+	keys, err := b.List(ctx, prefix+"/")
+	if err != nil {
+		return fmt.Errorf("failed to list object keys: %w", err)
+	}
+
+	backups := make(map[time.Time][]string)
+	for _, key := range keys {
+		// a streamed dump is stored in a sub-directory of the backup directory.
+		dir := strings.SplitN(strings.TrimPrefix(key, prefix+"/"), "/", 2)
+		if len(dir) != 2 {
+			continue
+		}
+		t, err := time.Parse(constants.BackupTimeFormat, dir[0])
+		if err != nil {
+			continue
+		}
+		backups[t] = append(backups[t], key)
+	}
+
+	times := make([]time.Time, 0, len(backups))
+	for t := range backups {
+		times = append(times, t)
+	}
+
+	prunable := selectPrunable(times, bm.startTime, bm.retention)
+	for _, t := range prunable {
+		keys := backups[t]
+		if snapshots {
+			if err := bm.pruneSnapshot(ctx, keys); err != nil {
+				return err
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if isDumpMarker(keys[i]) != isDumpMarker(keys[j]) {
+				return isDumpMarker(keys[i])
+			}
+			return keys[i] < keys[j]
+		})
+
+		for _, key := range keys {
+			if err := b.Delete(ctx, key); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", key, err)
+			}
+		}
+		log.Info("pruned an old backup", "time", t.Format(constants.BackupTimeFormat))
+	}
+
+	if len(times) == len(prunable) {
+		return nil
+	}
+	pruned := make(map[time.Time]bool)
+	for _, t := range prunable {
+		pruned[t] = true
+	}
+	var oldest time.Time
+	for _, t := range times {
+		if pruned[t] {
+			continue
+		}
+		if oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+
+	segments, err := listSegments(ctx, b, bm.cluster.Namespace, bm.cluster.Name)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, s := range segments {
+		if s.Time.After(oldest) {
+			break
+		}
+		if err := b.Delete(ctx, s.Key); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", s.Key, err)
+		}
+		if s.ManifestKey != "" {
+			if err := b.Delete(ctx, s.ManifestKey); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", s.ManifestKey, err)
+			}
+		}
+		count++
+	}
+	if count > 0 {
+		log.Info("pruned old binlog segments", "count", count)
+	}
+
+	return nil
+}
+
+// pruneSnapshot deletes the VolumeSnapshot recorded in the manifest among `keys`, if any.
+func (bm *BackupManager) pruneSnapshot(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if path.Base(key) != constants.ManifestFilename {
+			continue
+		}
+		// a broken manifest must not block pruning.
+		m, err := getManifest(ctx, bm.bucket, key)
+		if err != nil {
+			bm.log.Error(err, "failed to read the manifest of a pruned backup", "key", key)
+			continue
+		}
+		if m.VolumeSnapshotName == "" {
+			continue
+		}
+		if err := bm.deleteSnapshot(ctx, m.VolumeSnapshotName); err != nil {
+			return err
+		}
+		bm.log.Info("deleted VolumeSnapshot", "name", m.VolumeSnapshotName)
+	}
+	return nil
+}
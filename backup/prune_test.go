@@ -0,0 +1,237 @@
+package backup
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSelectPrunable(t *testing.T) {
+	date := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2022, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	// 2022-04-30 is Saturday, 2022-05-01 is Sunday, and 2022-05-02 is Monday.
+	times := []time.Time{
+		date(time.April, 29, 0),
+		date(time.April, 30, 0),
+		date(time.April, 30, 12),
+		date(time.May, 1, 0),
+		date(time.May, 1, 12),
+		date(time.May, 2, 0),
+		date(time.May, 2, 12),
+	}
+	now := date(time.May, 2, 13)
+
+	testCases := []struct {
+		name      string
+		retention Retention
+		expect    []time.Time
+	}{
+		{"keep-last", Retention{KeepLast: 3}, []time.Time{
+			date(time.May, 1, 0),
+			date(time.April, 30, 12),
+			date(time.April, 30, 0),
+			date(time.April, 29, 0),
+		}},
+		{"keep-last-too-many", Retention{KeepLast: 10}, nil},
+		{"only-latest", Retention{}, []time.Time{
+			date(time.May, 2, 0),
+			date(time.May, 1, 12),
+			date(time.May, 1, 0),
+			date(time.April, 30, 12),
+			date(time.April, 30, 0),
+			date(time.April, 29, 0),
+		}},
+		{"keep-for", Retention{KeepFor: 24 * time.Hour}, []time.Time{
+			date(time.May, 1, 0),
+			date(time.April, 30, 12),
+			date(time.April, 30, 0),
+			date(time.April, 29, 0),
+		}},
+		{"keep-for-exact", Retention{KeepFor: 13 * time.Hour}, []time.Time{
+			date(time.May, 1, 12),
+			date(time.May, 1, 0),
+			date(time.April, 30, 12),
+			date(time.April, 30, 0),
+			date(time.April, 29, 0),
+		}},
+		{"keep-daily", Retention{KeepDaily: 3}, []time.Time{
+			date(time.May, 2, 0),
+			date(time.May, 1, 0),
+			date(time.April, 30, 0),
+			date(time.April, 29, 0),
+		}},
+		{"keep-weekly", Retention{KeepWeekly: 2}, []time.Time{
+			date(time.May, 2, 0),
+			date(time.May, 1, 0),
+			date(time.April, 30, 12),
+			date(time.April, 30, 0),
+			date(time.April, 29, 0),
+		}},
+		{"keep-monthly", Retention{KeepMonthly: 12}, []time.Time{
+			date(time.May, 2, 0),
+			date(time.May, 1, 12),
+			date(time.May, 1, 0),
+			date(time.April, 30, 0),
+			date(time.April, 29, 0),
+		}},
+		{"combined", Retention{KeepLast: 1, KeepDaily: 2, KeepMonthly: 2}, []time.Time{
+			date(time.May, 2, 0),
+			date(time.May, 1, 0),
+			date(time.April, 30, 0),
+			date(time.April, 29, 0),
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := selectPrunable(times, now, tc.retention)
+			if !cmp.Equal(actual, tc.expect) {
+				t.Errorf("unexpected result: %s", cmp.Diff(tc.expect, actual))
+			}
+		})
+	}
+
+	if actual := selectPrunable(nil, now, Retention{KeepLast: 1}); len(actual) != 0 {
+		t.Errorf("unexpected result for empty input: %v", actual)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	bc := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20220501-000000/dump.tar":       nil,
+		"moco/test/test/20220501-000000/binlog.tar.zst": nil,
+		"moco/test/test/20220502-000000/dump.tar":       nil,
+		"moco/test/test/20220502-000000/binlog.tar.zst": nil,
+		"moco/test/test/20220503-000000/dump.tar":       nil,
+		"moco/test/test/garbage":                        nil,
+		"moco/test/test2/20220501-000000/dump.tar":      nil,
+	}}
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	bm := &BackupManager{
+		log:       logr.Discard(),
+		cluster:   cluster,
+		bucket:    bc,
+		retention: Retention{KeepLast: 2},
+		startTime: time.Date(2022, time.May, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	if err := bm.prune(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for k := range bc.contents {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	expect := []string{
+		"moco/test/test/20220502-000000/binlog.tar.zst",
+		"moco/test/test/20220502-000000/dump.tar",
+		"moco/test/test/20220503-000000/dump.tar",
+		"moco/test/test/garbage",
+		"moco/test/test2/20220501-000000/dump.tar",
+	}
+	if !cmp.Equal(keys, expect) {
+		t.Errorf("unexpected keys: %s", cmp.Diff(expect, keys))
+	}
+}
+
+func TestPruneSecondaries(t *testing.T) {
+	primary := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20220503-000000/dump.tar": nil,
+	}}
+	// the backup of 20220503 has not been copied to the secondary bucket yet.
+	secondary := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20220501-000000/dump.tar":               nil,
+		"moco/test/test/20220502-000000/dump.tar":               nil,
+		"moco/test/test/binlog/20220501-120000/segment.tar.zst": nil,
+		"moco/test/test/binlog/20220502-120000/segment.tar.zst": nil,
+	}}
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	bm := &BackupManager{
+		log:         logr.Discard(),
+		cluster:     cluster,
+		bucket:      primary,
+		secondaries: []SecondaryBucket{{Name: "dr", Bucket: secondary}},
+		retention:   Retention{KeepLast: 1},
+		startTime:   time.Date(2022, time.May, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	bm.pruneSecondaries(context.Background())
+	if len(bm.warnings) != 0 {
+		t.Fatal(bm.warnings)
+	}
+
+	expect := []string{
+		"moco/test/test/20220502-000000/dump.tar",
+		"moco/test/test/binlog/20220502-120000/segment.tar.zst",
+	}
+	if keys := sortedKeys(secondary); !cmp.Equal(keys, expect) {
+		t.Errorf("unexpected keys: %s", cmp.Diff(expect, keys))
+	}
+	if len(primary.contents) != 1 {
+		t.Errorf("the primary bucket is modified: %v", primary.contents)
+	}
+}
+
+func TestPruneStreamed(t *testing.T) {
+	bc := &orderedDeleteBucket{mockBucket: &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20220501-000000/dump/@.done.json": nil,
+		"moco/test/test/20220501-000000/dump/@.json":      nil,
+		"moco/test/test/20220501-000000/manifest.json":    nil,
+		"moco/test/test/20220502-000000/dump/@.done.json": nil,
+		"moco/test/test/20220502-000000/dump/@.json":      nil,
+		"moco/test/test/20220502-000000/manifest.json":    nil,
+	}}}
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	bm := &BackupManager{
+		log:       logr.Discard(),
+		cluster:   cluster,
+		bucket:    bc,
+		retention: Retention{KeepLast: 1},
+		startTime: time.Date(2022, time.May, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	if err := bm.prune(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{
+		"moco/test/test/20220501-000000/dump/@.done.json",
+		"moco/test/test/20220501-000000/dump/@.json",
+		"moco/test/test/20220501-000000/manifest.json",
+	}
+	if !cmp.Equal(bc.deleted, expect) {
+		t.Errorf("unexpected deletion: %s", cmp.Diff(expect, bc.deleted))
+	}
+	if len(bc.contents) != 3 {
+		t.Errorf("unexpected keys left: %v", bc.contents)
+	}
+}
+
+// orderedDeleteBucket records the order of deleted keys.
+type orderedDeleteBucket struct {
+	*mockBucket
+	deleted []string
+}
+
+func (b *orderedDeleteBucket) Delete(ctx context.Context, key string) error {
+	b.deleted = append(b.deleted, key)
+	return b.mockBucket.Delete(ctx, key)
+}
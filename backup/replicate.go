@@ -0,0 +1,186 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cybozu-go/moco/pkg/bucket"
+	"github.com/cybozu-go/moco/pkg/constants"
+)
+
+// SecondaryBucket is a bucket to which backups are copied.
+type SecondaryBucket struct {
+	// Name identifies the bucket in logs and warnings.
+	Name string
+
+	// Bucket must encrypt objects in the same way as the primary bucket.
+	Bucket bucket.Bucket
+}
+
+// WithSecondaryBuckets makes the backup copy the backups of the cluster
+// to `buckets` after a successful backup.
+func WithSecondaryBuckets(buckets []SecondaryBucket) BackupOption {
+	return func(bm *BackupManager) {
+		bm.secondaries = buckets
+	}
+}
+
+// WithArchiveSecondaryBuckets makes the archiver copy each binlog segment to `buckets`.
+func WithArchiveSecondaryBuckets(buckets []SecondaryBucket) BinlogArchiverOption {
+	return func(ba *BinlogArchiver) {
+		ba.secondaries = buckets
+	}
+}
+
+// replicate copies the backups not copied yet to the secondary buckets.
+// Failures are recorded as warnings because the backup itself has succeeded.
+func (bm *BackupManager) replicate(ctx context.Context) {
+	for _, sb := range bm.secondaries {
+		n, err := syncBackups(ctx, bm.bucket, sb.Bucket, calcPrefix(bm.cluster.Namespace, bm.cluster.Name))
+		if err != nil {
+			bm.log.Error(err, "failed to copy backups", "bucket", sb.Name)
+			bm.warnings = append(bm.warnings, fmt.Sprintf("failed to copy backups to %s: %v", sb.Name, err))
+			continue
+		}
+		bm.log.Info("copied backups", "bucket", sb.Name, "objects", n)
+	}
+}
+
+// syncBackups copies the backups and binlog segments under `prefix` from `src` to `dst`,
+// and returns the number of copied objects.
+//
+// Only directories with a manifest are copied, and only the objects recorded in
+// the manifest are copied.  Objects that already exist in `dst` are skipped,
+// so an interrupted copy is resumed by the next call.
+func syncBackups(ctx context.Context, src, dst bucket.Bucket, prefix string) (int, error) {
+	srcKeys, err := src.List(ctx, prefix+"/")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list objects in the source bucket: %w", err)
+	}
+	dstKeys, err := dst.List(ctx, prefix+"/")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list objects in the destination bucket: %w", err)
+	}
+	exists := make(map[string]bool, len(dstKeys))
+	for _, key := range dstKeys {
+		exists[key] = true
+	}
+
+	sort.Strings(srcKeys)
+	var total int
+	for _, key := range srcKeys {
+		if path.Base(key) != constants.ManifestFilename {
+			continue
+		}
+		dir := path.Dir(key)
+		if exists[key] && isCopied(srcKeys, exists, dir) {
+			continue
+		}
+
+		n, err := copyBackupDir(ctx, src, dst, dir, exists)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// isCopied returns true if all objects in `dir` of the sorted `srcKeys` exist.
+func isCopied(srcKeys []string, exists map[string]bool, dir string) bool {
+	for i := sort.SearchStrings(srcKeys, dir+"/"); i < len(srcKeys) && strings.HasPrefix(srcKeys[i], dir+"/"); i++ {
+		if !exists[srcKeys[i]] {
+			return false
+		}
+	}
+	return true
+}
+
+// copyBackupDir copies the objects recorded in the manifest of `dir` that do not
+// exist in `dst`, and then the manifest.  The manifest is copied last so that
+// the copy is not used for restoration until it is complete.
+// The dump marker is copied after other files of a streamed dump for the same reason.
+func copyBackupDir(ctx context.Context, src, dst bucket.Bucket, dir string, exists map[string]bool) (int, error) {
+	manifestKey := path.Join(dir, constants.ManifestFilename)
+	m, err := getManifest(ctx, src, manifestKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the manifest: %w", err)
+	}
+
+	keys := make([]string, 0, len(m.Objects))
+	for name := range m.Objects {
+		keys = append(keys, path.Join(dir, name))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if isDumpMarker(keys[i]) != isDumpMarker(keys[j]) {
+			return isDumpMarker(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	var copied int
+	for _, key := range keys {
+		if exists[key] {
+			continue
+		}
+		if err := copyObject(ctx, src, dst, key, m.Objects[strings.TrimPrefix(key, dir+"/")]); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+
+	if copied == 0 && exists[manifestKey] {
+		return 0, nil
+	}
+	if err := putManifest(ctx, dst, manifestKey, m); err != nil {
+		return copied, fmt.Errorf("failed to put %s: %w", manifestKey, err)
+	}
+	return copied, nil
+}
+
+// copyObject copies an object and verifies both the source and the copy
+// with the size and checksum recorded in the manifest.
+// A copy that does not match is deleted.
+func copyObject(ctx context.Context, src, dst bucket.Bucket, key string, expected ManifestObject) error {
+	r, err := src.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	defer r.Close()
+
+	cr := newChecksumReader(r)
+	if err := dst.Put(ctx, key, cr, expected.Size); err != nil {
+		return fmt.Errorf("failed to put %s: %w", key, err)
+	}
+	if err := cr.Verify(expected); err != nil {
+		return deleteBrokenCopy(ctx, dst, key, fmt.Errorf("%s in the source bucket is broken: %w", key, err))
+	}
+
+	if err := verifyObject(ctx, dst, key, expected); err != nil {
+		return deleteBrokenCopy(ctx, dst, key, fmt.Errorf("the copy of %s is broken: %w", key, err))
+	}
+	return nil
+}
+
+// deleteBrokenCopy deletes `key` from `dst` and returns `cause`.
+// If the deletion fails, the returned error tells so because the broken copy
+// is left in the bucket and a later copy skips the key as already copied.
+func deleteBrokenCopy(ctx context.Context, dst bucket.Bucket, key string, cause error) error {
+	if err := dst.Delete(ctx, key); err != nil {
+		return fmt.Errorf("%w, and failed to delete the copy: %v", cause, err)
+	}
+	return cause
+}
+
+// verifyObject reads an object and compares its size and checksum with `expected`.
+func verifyObject(ctx context.Context, b bucket.Bucket, key string, expected ManifestObject) error {
+	r, err := b.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return newChecksumReader(r).Verify(expected)
+}
@@ -0,0 +1,193 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// corruptBucket flips the first byte of objects on upload.
+type corruptBucket struct {
+	*mockBucket
+}
+
+func (b *corruptBucket) Put(ctx context.Context, key string, r io.Reader, objectSize int64) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		data[0] ^= 0xff
+	}
+	return b.mockBucket.Put(ctx, key, bytes.NewReader(data), objectSize)
+}
+
+func putTestManifest(t *testing.T, b *mockBucket, key string, objects map[string]string) {
+	t.Helper()
+
+	m := &Manifest{Version: ManifestVersion, Objects: make(map[string]ManifestObject)}
+	for name, data := range objects {
+		m.Objects[name] = makeObject([]byte(data))
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.contents[key] = data
+}
+
+func sortedKeys(b *mockBucket) []string {
+	keys := make([]string, 0, len(b.contents))
+	for k := range b.contents {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestSyncBackups(t *testing.T) {
+	ctx := context.Background()
+	prefix := "moco/test/test"
+	src := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20220501-000000/dump.tar":                  []byte("dump1"),
+		"moco/test/test/20220502-000000/dump/@.json":               []byte("{}"),
+		"moco/test/test/20220502-000000/dump/@.done.json":          []byte("{}"),
+		"moco/test/test/20220502-000000/dump/db@t@@0.tsv.zst":      []byte("data"),
+		"moco/test/test/20220503-000000/dump.tar":                  []byte("no manifest"),
+		"moco/test/test/binlog/20220502-010000/segment.tar.zst":    []byte("segment"),
+		"moco/test/test2/20220501-000000/dump.tar":                 []byte("other cluster"),
+		"moco/test/test/20220501-000000/binlog.tar.zst.incomplete": []byte("not recorded"),
+	}}
+	putTestManifest(t, src, "moco/test/test/20220501-000000/manifest.json", map[string]string{
+		"dump.tar": "dump1",
+	})
+	putTestManifest(t, src, "moco/test/test/20220502-000000/manifest.json", map[string]string{
+		"dump/@.json":          "{}",
+		"dump/@.done.json":     "{}",
+		"dump/db@t@@0.tsv.zst": "data",
+	})
+	putTestManifest(t, src, "moco/test/test/binlog/20220502-010000/manifest.json", map[string]string{
+		"segment.tar.zst": "segment",
+	})
+
+	dst := &orderedBucket{mockBucket: &mockBucket{contents: make(map[string][]byte)}}
+	n, err := syncBackups(ctx, src, dst, prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 {
+		t.Errorf("unexpected number of copied objects: %d", n)
+	}
+	expect := []string{
+		"moco/test/test/20220501-000000/dump.tar",
+		"moco/test/test/20220501-000000/manifest.json",
+		"moco/test/test/20220502-000000/dump/@.json",
+		"moco/test/test/20220502-000000/dump/db@t@@0.tsv.zst",
+		"moco/test/test/20220502-000000/dump/@.done.json",
+		"moco/test/test/20220502-000000/manifest.json",
+		"moco/test/test/binlog/20220502-010000/segment.tar.zst",
+		"moco/test/test/binlog/20220502-010000/manifest.json",
+	}
+	if !cmp.Equal(dst.keys, expect) {
+		t.Errorf("unexpected uploads: %s", cmp.Diff(expect, dst.keys))
+	}
+	for _, key := range expect {
+		if !strings.HasSuffix(key, "manifest.json") && !bytes.Equal(dst.contents[key], src.contents[key]) {
+			t.Errorf("unexpected content of %s: %s", key, dst.contents[key])
+		}
+	}
+
+	// nothing is copied again.
+	dst.keys = nil
+	n, err = syncBackups(ctx, src, dst, prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 || len(dst.keys) != 0 {
+		t.Errorf("objects are copied again: %v", dst.keys)
+	}
+
+	// the binlog added by the next backup is copied with the updated manifest.
+	src.contents["moco/test/test/20220501-000000/binlog.tar.zst"] = []byte("binlog")
+	putTestManifest(t, src, "moco/test/test/20220501-000000/manifest.json", map[string]string{
+		"dump.tar":       "dump1",
+		"binlog.tar.zst": "binlog",
+	})
+	n, err = syncBackups(ctx, src, dst, prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = []string{
+		"moco/test/test/20220501-000000/binlog.tar.zst",
+		"moco/test/test/20220501-000000/manifest.json",
+	}
+	if n != 1 || !cmp.Equal(dst.keys, expect) {
+		t.Errorf("unexpected uploads: %s", cmp.Diff(expect, dst.keys))
+	}
+	m, err := getManifest(ctx, dst, "moco/test/test/20220501-000000/manifest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.Objects["binlog.tar.zst"]; !ok {
+		t.Error("the manifest is not updated")
+	}
+}
+
+func TestSyncBackupsBroken(t *testing.T) {
+	ctx := context.Background()
+	prefix := "moco/test/test"
+	newSource := func() *mockBucket {
+		src := &mockBucket{contents: map[string][]byte{
+			"moco/test/test/20220501-000000/dump.tar": []byte("dump1"),
+		}}
+		putTestManifest(t, src, "moco/test/test/20220501-000000/manifest.json", map[string]string{
+			"dump.tar": "dump1",
+		})
+		return src
+	}
+
+	src := newSource()
+	src.contents["moco/test/test/20220501-000000/dump.tar"] = []byte("broken")
+	dst := &mockBucket{contents: make(map[string][]byte)}
+	if _, err := syncBackups(ctx, src, dst, prefix); err == nil {
+		t.Error("a broken source should be detected")
+	}
+	if len(dst.contents) != 0 {
+		t.Errorf("broken objects are left: %v", sortedKeys(dst))
+	}
+
+	src = newSource()
+	cdst := &corruptBucket{mockBucket: &mockBucket{contents: make(map[string][]byte)}}
+	if _, err := syncBackups(ctx, src, cdst, prefix); err == nil {
+		t.Error("a broken copy should be detected")
+	}
+	if len(cdst.contents) != 0 {
+		t.Errorf("broken objects are left: %v", sortedKeys(cdst.mockBucket))
+	}
+
+	src = newSource()
+	udst := &undeletableBucket{corruptBucket: &corruptBucket{mockBucket: &mockBucket{contents: make(map[string][]byte)}}}
+	_, err := syncBackups(ctx, src, udst, prefix)
+	if err == nil {
+		t.Fatal("a broken copy should be detected")
+	}
+	if !strings.Contains(err.Error(), "failed to delete the copy") {
+		t.Errorf("the failure to delete the broken copy is not reported: %v", err)
+	}
+}
+
+// undeletableBucket corrupts uploaded objects and fails to delete them.
+type undeletableBucket struct {
+	*corruptBucket
+}
+
+func (b *undeletableBucket) Delete(ctx context.Context, key string) error {
+	return errors.New("access denied")
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"sort"
@@ -42,11 +42,48 @@ type RestoreManager struct {
 	keyPrefix    string
 	restorePoint time.Time
 	workDir      string
+
+	stopGTIDSet  bkop.GTIDSet
+	filter       *bkop.Filter
+	snapshotName string
+
+	// manifests are the manifests of backups keyed by their directories.
+	manifests map[string]*Manifest
+
+	// progress is recorded in the status of the target MySQLCluster.
+	progress mocov1beta2.RestoreStatus
 }
 
 var ErrBadConnection = errors.New("the connection hasn't reflected the latest user's privileges")
 
-func NewRestoreManager(cfg *rest.Config, bc bucket.Bucket, dir, srcNS, srcName, ns, name, password string, threads int, restorePoint time.Time) (*RestoreManager, error) {
+// RestoreOption is an option for NewRestoreManager.
+type RestoreOption func(*RestoreManager)
+
+// WithStopGTIDSet specifies the GTID set to stop the restoration.
+// Transactions are applied up to, but not including, the first transaction in the set.
+func WithStopGTIDSet(set bkop.GTIDSet) RestoreOption {
+	return func(rm *RestoreManager) {
+		rm.stopGTIDSet = set
+	}
+}
+
+// WithRestoreFilter specifies schemas and tables to be restored.
+// Changes to other schemas and tables in binary logs are not applied.
+func WithRestoreFilter(f *bkop.Filter) RestoreOption {
+	return func(rm *RestoreManager) {
+		rm.filter = f
+	}
+}
+
+// WithRestoreSnapshot specifies the VolumeSnapshot from which the data volume of
+// the target instance has been provisioned.  Only binlogs are applied on the instance.
+func WithRestoreSnapshot(name string) RestoreOption {
+	return func(rm *RestoreManager) {
+		rm.snapshotName = name
+	}
+}
+
+func NewRestoreManager(cfg *rest.Config, bc bucket.Bucket, dir, srcNS, srcName, ns, name, password string, threads int, restorePoint time.Time, opts ...RestoreOption) (*RestoreManager, error) {
 	log := zap.New(zap.WriteTo(os.Stderr), zap.StacktraceLevel(zapcore.DPanicLevel))
 	scheme := runtime.NewScheme()
 	if err := clientgoscheme.AddToScheme(scheme); err != nil {
@@ -62,7 +99,7 @@ func NewRestoreManager(cfg *rest.Config, bc bucket.Bucket, dir, srcNS, srcName,
 	}
 
 	prefix := calcPrefix(srcNS, srcName)
-	return &RestoreManager{
+	rm := &RestoreManager{
 		log:          log,
 		client:       k8sClient,
 		scheme:       scheme,
@@ -74,15 +111,34 @@ func NewRestoreManager(cfg *rest.Config, bc bucket.Bucket, dir, srcNS, srcName,
 		keyPrefix:    prefix,
 		restorePoint: restorePoint,
 		workDir:      dir,
-	}, nil
+	}
+	for _, o := range opts {
+		o(rm)
+	}
+	return rm, nil
 }
 
+// Restore restores data from a backup into the target MySQLCluster.
+// The progress is recorded in the status of the MySQLCluster.
+// If it fails, the failure is also recorded as the Restored condition and an event.
 func (rm *RestoreManager) Restore(ctx context.Context) error {
+	err := rm.restore(ctx)
+	if err != nil {
+		rm.recordFailure(ctx, err)
+	}
+	return err
+}
+
+func (rm *RestoreManager) restore(ctx context.Context) error {
 	cluster := &mocov1beta2.MySQLCluster{}
 	cluster.Namespace = rm.namespace
 	cluster.Name = rm.name
 	podName := cluster.PodName(0)
 
+	now := metav1.Now()
+	rm.progress = mocov1beta2.RestoreStatus{StartTime: &now}
+	rm.setPhase(ctx, mocov1beta2.RestoreWaitingForPod)
+
 	rm.log.Info("waiting for a pod to become ready", "name", podName)
 	var pod *corev1.Pod
 	for i := 0; i < 600; i++ {
@@ -140,43 +196,182 @@ func (rm *RestoreManager) Restore(ctx context.Context) error {
 	}
 	sort.Strings(keys)
 
-	dumpKey, binlogKey, backupTime := rm.FindNearestDump(keys)
-	if dumpKey == "" {
-		return fmt.Errorf("no available backup")
+	if err := rm.loadManifests(ctx, keys); err != nil {
+		return err
+	}
+
+	var dumpKey, backupDir string
+	var binlogKeys []string
+	var backupTime time.Time
+	if rm.snapshotName != "" {
+		backupTime, backupDir, binlogKeys, err = rm.findSnapshotBackup(ctx, keys)
+		if err != nil {
+			return err
+		}
+	} else {
+		dumpKey, binlogKeys, backupTime = rm.FindNearestDump(keys)
+		if dumpKey == "" {
+			return fmt.Errorf("no available backup")
+		}
+		backupDir = path.Dir(dumpKey)
+	}
+
+	streamed := path.Base(dumpKey) == constants.DumpDirname
+	var dumpObject *ManifestObject
+	var dumpFiles map[string]ManifestObject
+	var binlogObjects map[string]*ManifestObject
+	manifestKey := path.Join(backupDir, constants.ManifestFilename)
+	if m := rm.manifests[backupDir]; m != nil {
+		if !m.Time.Equal(backupTime) {
+			return fmt.Errorf("the manifest %s has unexpected time %s", manifestKey, m.Time.Format(time.RFC3339))
+		}
+
+		switch {
+		case rm.snapshotName != "":
+			if m.VolumeSnapshotName != rm.snapshotName {
+				return fmt.Errorf("the manifest %s has no record of VolumeSnapshot %s", manifestKey, rm.snapshotName)
+			}
+		case streamed:
+			dumpFiles = dumpObjects(m)
+			if len(dumpFiles) == 0 {
+				return fmt.Errorf("the manifest %s has no record of %s", manifestKey, constants.DumpDirname)
+			}
+		default:
+			obj, ok := m.Objects[constants.DumpFilename]
+			if !ok {
+				return fmt.Errorf("the manifest %s has no record of %s", manifestKey, constants.DumpFilename)
+			}
+			dumpObject = &obj
+		}
+		binlogObjects = make(map[string]*ManifestObject)
+		for _, key := range binlogKeys {
+			obj, ok := m.Objects[path.Base(key)]
+			if !ok {
+				rm.log.Info("the manifest has no record of binlog; its checksum will not be verified", "binlog", key)
+				continue
+			}
+			binlogObjects[key] = &obj
+		}
+
+		rm.log.Info("read the manifest", "key", manifestKey,
+			"uuid", m.SourceUUID,
+			"version", m.MySQLVersion,
+			"binlog", m.BinlogFilename,
+			"gtid", m.GTIDSet)
+	} else {
+		rm.log.Info("no manifest found; checksums will not be verified", "key", manifestKey)
 	}
 
-	rm.log.Info("restoring from a backup", "dump", dumpKey, "binlog", binlogKey)
+	var segments []segment
+	if !backupTime.Equal(rm.restorePoint) {
+		segments = rm.selectSegments(parseSegments(keys, path.Join(rm.keyPrefix, constants.BinlogArchiveDir)), backupTime)
+	}
+
+	rm.log.Info("restoring from a backup", "dump", dumpKey, "snapshot", rm.snapshotName, "binlogs", binlogKeys, "segments", len(segments))
+	rm.progress.DumpKey = dumpKey
+	if len(binlogKeys) > 0 {
+		rm.progress.BinlogKey = binlogKeys[len(binlogKeys)-1]
+	}
+	rm.progress.Segments = len(segments)
 
 	if err := op.PrepareRestore(ctx); err != nil {
 		return fmt.Errorf("failed to prepare instance for restoration: %w", err)
 	}
 
-	if err := rm.loadDump(ctx, op, dumpKey); err != nil {
-		return fmt.Errorf("failed to load dump: %w", err)
+	switch {
+	case rm.snapshotName != "":
+		// the data has been provisioned from the snapshot.
+	case streamed:
+		var files []string
+		for _, key := range keys {
+			if strings.HasPrefix(key, dumpKey+"/") {
+				files = append(files, strings.TrimPrefix(key, dumpKey+"/"))
+			}
+		}
+		if err := rm.loadDumpStream(ctx, op, dumpKey, files, dumpFiles); err != nil {
+			return fmt.Errorf("failed to load dump: %w", err)
+		}
+		rm.log.Info("loaded dump successfully")
+	default:
+		if err := rm.loadDump(ctx, op, dumpKey, dumpObject); err != nil {
+			return fmt.Errorf("failed to load dump: %w", err)
+		}
+		rm.log.Info("loaded dump successfully")
+	}
+
+	var reached bool
+	if !backupTime.Equal(rm.restorePoint) && (len(binlogKeys) > 0 || len(segments) > 0) {
+		rm.setPhase(ctx, mocov1beta2.RestoreApplyingBinlog)
+	}
+	if !backupTime.Equal(rm.restorePoint) {
+		for _, key := range binlogKeys {
+			if reached {
+				break
+			}
+			reached, err = rm.applyBinlog(ctx, op, key, binlogObjects[key])
+			if err != nil {
+				return fmt.Errorf("failed to apply transactions: %w", err)
+			}
+			rm.log.Info("applied binlog successfully", "binlog", key)
+		}
 	}
 
-	rm.log.Info("loaded dump successfully")
+	// Segments may overlap with the binlogs above and with each other.
+	// Transactions already applied are skipped because their GTIDs have been executed.
+	for _, s := range segments {
+		if reached {
+			break
+		}
+
+		var m *Manifest
+		var expected *ManifestObject
+		if s.ManifestKey != "" {
+			m, err = getManifest(ctx, rm.bucket, s.ManifestKey)
+			if err != nil {
+				return fmt.Errorf("failed to read the manifest: %w", err)
+			}
+			if obj, ok := m.Objects[constants.SegmentFilename]; ok {
+				expected = &obj
+			}
+		}
+		if expected == nil {
+			rm.log.Info("the segment has no manifest; its checksum will not be verified", "segment", s.Key)
+		}
+		if err := rm.verifySegment(ctx, op, s.Key, m); err != nil {
+			return err
+		}
 
-	if !backupTime.Equal(rm.restorePoint) && binlogKey != "" {
-		if err := rm.applyBinlog(ctx, op, binlogKey); err != nil {
+		reached, err = rm.applyBinlog(ctx, op, s.Key, expected)
+		if err != nil {
 			return fmt.Errorf("failed to apply transactions: %w", err)
 		}
-		rm.log.Info("applied binlog successfully")
+		rm.log.Info("applied binlog segment successfully", "segment", s.Key)
 	}
 
+	switch {
+	case reached:
+		rm.log.Info("stopped before a transaction in the stop GTID set")
+	case rm.stopGTIDSet != nil:
+		rm.log.Info("no transaction in the stop GTID set was found; restored up to the restore point")
+	}
+
+	rm.setPhase(ctx, mocov1beta2.RestoreFinalizing)
 	if err := op.FinishRestore(ctx); err != nil {
 		return fmt.Errorf("failed to finalize the restoration: %w", err)
 	}
 
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		cluster = &mocov1beta2.MySQLCluster{}
-		if err := rm.client.Get(ctx, client.ObjectKey{Namespace: rm.namespace, Name: rm.name}, cluster); err != nil {
-			return err
-		}
+	st := &bkop.ServerStatus{}
+	if err := op.GetServerStatus(ctx, st); err != nil {
+		return fmt.Errorf("failed to get server status: %w", err)
+	}
+	rm.progress.GTIDSet = st.ExecutedGTIDSet
+	rm.progress.Phase = mocov1beta2.RestoreCompleted
+	rm.log.Info("restored", "gtid", st.ExecutedGTIDSet)
 
+	cluster, err = rm.updateStatus(ctx, func(status *mocov1beta2.MySQLClusterStatus) {
 		t := metav1.Now()
-		cluster.Status.RestoredTime = &t
-		return rm.client.Status().Update(ctx, cluster)
+		status.RestoredTime = &t
+		setRestoredCondition(status, corev1.ConditionTrue, "Restored", "")
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update MySQLCluster status: %w", err)
@@ -195,120 +390,436 @@ func (rm *RestoreManager) Restore(ctx context.Context) error {
 	return nil
 }
 
-func (rm *RestoreManager) FindNearestDump(keys []string) (string, string, time.Time) {
-	var nearest time.Time
-	var nearestDump, nearestBinlog string
+// updateStatus records the progress in the status of the target MySQLCluster.
+// `fn` can modify other status fields, if not nil.
+func (rm *RestoreManager) updateStatus(ctx context.Context, fn func(*mocov1beta2.MySQLClusterStatus)) (*mocov1beta2.MySQLCluster, error) {
+	cluster := &mocov1beta2.MySQLCluster{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster = &mocov1beta2.MySQLCluster{}
+		if err := rm.client.Get(ctx, client.ObjectKey{Namespace: rm.namespace, Name: rm.name}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.Restore = rm.progress.DeepCopy()
+		if fn != nil {
+			fn(&cluster.Status)
+		}
+		return rm.client.Status().Update(ctx, cluster)
+	})
+	return cluster, err
+}
+
+// setPhase records the phase of the restoration.
+// As the progress is informational, failures to record it are only logged.
+func (rm *RestoreManager) setPhase(ctx context.Context, phase mocov1beta2.RestorePhase) {
+	rm.progress.Phase = phase
+	rm.log.Info("restore phase changed", "phase", phase)
+	rm.reportProgress(ctx)
+}
+
+func (rm *RestoreManager) reportProgress(ctx context.Context) {
+	if _, err := rm.updateStatus(ctx, nil); err != nil {
+		rm.log.Error(err, "failed to record the restore progress")
+	}
+}
+
+// downloadProgress returns a function for ByteCountWriter.Progress that records
+// the number of downloaded bytes at most once per progressInterval.
+func (rm *RestoreManager) downloadProgress(ctx context.Context) func(int64) {
+	base := rm.progress.DownloadedBytes
+	last := time.Now()
+	return func(n int64) {
+		if time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+		rm.progress.DownloadedBytes = base + n
+		rm.reportProgress(ctx)
+	}
+}
+
+func (rm *RestoreManager) recordFailure(ctx context.Context, restoreErr error) {
+	rm.progress.Phase = mocov1beta2.RestoreFailed
+	rm.progress.Message = restoreErr.Error()
+	cluster, err := rm.updateStatus(ctx, func(status *mocov1beta2.MySQLClusterStatus) {
+		setRestoredCondition(status, corev1.ConditionFalse, "RestoreFailed", restoreErr.Error())
+	})
+	if err != nil {
+		rm.log.Error(err, "failed to record the restore failure")
+		return
+	}
+
+	ref, err := reference.GetReference(rm.scheme, cluster)
+	if err != nil {
+		rm.log.Error(err, "failed to get reference for MySQLCluster")
+		return
+	}
+	ev := event.RestoreFailed.ToEvent(ref, restoreErr)
+	if err := rm.client.Create(ctx, ev); err != nil {
+		rm.log.Error(err, "failed to create an event for restoration failure")
+	}
+}
+
+// setRestoredCondition sets the Restored condition.
+// The transition time is kept unless the condition status changes.
+func setRestoredCondition(status *mocov1beta2.MySQLClusterStatus, val corev1.ConditionStatus, reason, msg string) {
+	cond := mocov1beta2.MySQLClusterCondition{
+		Type:               mocov1beta2.ConditionRestored,
+		Status:             val,
+		Reason:             reason,
+		Message:            msg,
+		LastTransitionTime: metav1.Now(),
+	}
+	for i, c := range status.Conditions {
+		if c.Type != mocov1beta2.ConditionRestored {
+			continue
+		}
+		if c.Status == val {
+			cond.LastTransitionTime = c.LastTransitionTime
+		}
+		status.Conditions[i] = cond
+		return
+	}
+	status.Conditions = append(status.Conditions, cond)
+}
+
+// loadManifests reads the manifests of backups.
+// The manifests of binlog segments are not read here.
+func (rm *RestoreManager) loadManifests(ctx context.Context, keys []string) error {
+	rm.manifests = make(map[string]*Manifest)
+	for _, key := range keys {
+		if path.Base(key) != constants.ManifestFilename {
+			continue
+		}
+		if strings.HasPrefix(key, path.Join(rm.keyPrefix, constants.BinlogArchiveDir)+"/") {
+			continue
+		}
+
+		m, err := getManifest(ctx, rm.bucket, key)
+		if err != nil {
+			return fmt.Errorf("failed to read the manifest: %w", err)
+		}
+		rm.manifests[path.Dir(key)] = m
+	}
+	return nil
+}
+
+// dumpCandidate is a dump that can be restored.
+type dumpCandidate struct {
+	key  string
+	time time.Time
+
+	// gtidSet is nil if the GTID set of the dump is unknown.
+	gtidSet bkop.GTIDSet
+}
+
+// dumpCandidates returns the dumps in the bucket in ascending order of time.
+//
+// The dumps of backups that have a manifest are taken from the manifest along
+// with their time and GTID sets.  A dump recorded in a manifest but missing in
+// the bucket is not used.  The dumps of backups without a manifest, which were
+// taken by older versions of MOCO, are found by their object keys.
+func (rm *RestoreManager) dumpCandidates(keys []string) []dumpCandidate {
+	exists := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		exists[key] = true
+	}
+
+	var candidates []dumpCandidate
+	for dir, m := range rm.manifests {
+		var dumpKey string
+		switch {
+		case m.VolumeSnapshotName != "":
+			continue
+		case len(dumpObjects(m)) > 0:
+			dumpKey = path.Join(dir, constants.DumpDirname)
+			if !exists[path.Join(dumpKey, constants.DumpDoneFilename)] {
+				rm.log.Info("skipping an incomplete dump", "key", dumpKey)
+				continue
+			}
+		default:
+			if _, ok := m.Objects[constants.DumpFilename]; !ok {
+				continue
+			}
+			dumpKey = path.Join(dir, constants.DumpFilename)
+			if !exists[dumpKey] {
+				rm.log.Info("skipping a dump missing in the bucket", "key", dumpKey)
+				continue
+			}
+		}
+
+		c := dumpCandidate{key: dumpKey, time: m.Time}
+		set, err := bkop.ParseGTIDSet(m.GTIDSet)
+		if err != nil {
+			rm.log.Error(err, "invalid GTID set in the manifest", "dir", dir)
+		} else {
+			c.gtidSet = set
+		}
+		candidates = append(candidates, c)
+	}
 
 	for _, key := range keys {
-		if strings.HasSuffix(key, constants.BinlogFilename) {
-			nearestBinlog = key
+		dumpKey := key
+		switch {
+		case strings.HasSuffix(key, constants.DumpFilename):
+		case strings.HasSuffix(key, "/"+path.Join(constants.DumpDirname, constants.DumpDoneFilename)):
+			// a streamed dump is complete when its last file exists.
+			dumpKey = path.Dir(key)
+		default:
 			continue
 		}
-		if !strings.HasSuffix(key, constants.DumpFilename) {
+		dir := path.Dir(dumpKey)
+		if rm.manifests[dir] != nil {
 			continue
 		}
 
-		bkt, err := time.Parse(constants.BackupTimeFormat, path.Base(path.Dir(key)))
+		bkt, err := time.Parse(constants.BackupTimeFormat, path.Base(dir))
 		if err != nil {
 			rm.log.Error(err, "invalid object key", "key", key)
 			continue
 		}
-		if bkt.After(rm.restorePoint) {
+		candidates = append(candidates, dumpCandidate{key: dumpKey, time: bkt})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].time.Before(candidates[j].time)
+	})
+	return candidates
+}
+
+// FindNearestDump returns the key of the dump, the keys of the binlog archives to be
+// applied after it in order, and the time of the backup to restore data to the restore point.
+// For a streamed dump, the key of the dump is the prefix of its files.
+//
+// The manifests must be loaded in advance; see dumpCandidates.
+// If the stop GTID set is given, dumps that contain a transaction in the set are not
+// selected, nor those whose GTID set is unknown.
+func (rm *RestoreManager) FindNearestDump(keys []string) (string, []string, time.Time) {
+	var nearest time.Time
+	var nearestDump string
+
+	for _, c := range rm.dumpCandidates(keys) {
+		if c.time.After(rm.restorePoint) {
 			break
 		}
+		if rm.stopGTIDSet != nil {
+			if c.gtidSet == nil {
+				rm.log.Info("skipping a dump whose GTID set is unknown", "key", c.key)
+				continue
+			}
+			if c.gtidSet.Intersects(rm.stopGTIDSet) {
+				break
+			}
+		}
+
+		nearestDump = c.key
+		nearest = c.time
+	}
 
-		nearestDump = key
-		nearest = bkt
-		if path.Dir(nearestDump) != path.Dir(nearestBinlog) {
-			nearestBinlog = ""
+	if nearestDump == "" {
+		return "", nil, nearest
+	}
+	// the binlogs must follow the dump in the same directory.
+	// Binlogs whose record in the manifest failed to be added are still applied
+	// without verification, so that the transactions in them are not lost.
+	return nearestDump, rm.binlogChain(keys, path.Dir(nearestDump)), nearest
+}
+
+// binlogChain returns the keys of the binlog archives in the backup directory `dir`
+// in the order to be applied: the archives of binlog-only backups in ascending
+// order of time, followed by the binlog uploaded by the next backup.
+//
+// An archive contains transactions executed before its time, so the archives
+// after the first one taken at or after the restore point are not needed.
+func (rm *RestoreManager) binlogChain(keys []string, dir string) []string {
+	type archive struct {
+		time time.Time
+		key  string
+	}
+	var archives []archive
+	var binlogKey string
+	for _, key := range keys {
+		if path.Dir(key) != dir {
+			continue
+		}
+		name := path.Base(key)
+		if name == constants.BinlogFilename {
+			binlogKey = key
+			continue
+		}
+		if t, ok := parseIncrementalBinlogName(name); ok {
+			archives = append(archives, archive{time: t, key: key})
 		}
 	}
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].time.Before(archives[j].time)
+	})
 
-	return nearestDump, nearestBinlog, nearest
+	var chain []string
+	for _, a := range archives {
+		chain = append(chain, a.key)
+		if !a.time.Before(rm.restorePoint) {
+			return chain
+		}
+	}
+	if binlogKey != "" {
+		chain = append(chain, binlogKey)
+	}
+	return chain
+}
+
+// findSnapshotBackup returns the time and the directory of the snapshot backup,
+// and the keys of the binlog archives following it.
+//
+// The snapshot must be taken at or before the restore point.  If the stop GTID set is
+// given, the snapshot must not contain a transaction in the set.
+func (rm *RestoreManager) findSnapshotBackup(ctx context.Context, keys []string) (time.Time, string, []string, error) {
+	backupTime, gtid, err := rm.getSnapshot(ctx)
+	if err != nil {
+		return time.Time{}, "", nil, err
+	}
+	if backupTime.After(rm.restorePoint) {
+		return time.Time{}, "", nil, fmt.Errorf("VolumeSnapshot %s was taken after the restore point", rm.snapshotName)
+	}
+	if rm.stopGTIDSet != nil {
+		set, err := bkop.ParseGTIDSet(gtid)
+		if err != nil {
+			return time.Time{}, "", nil, fmt.Errorf("invalid GTID set of VolumeSnapshot %s: %w", rm.snapshotName, err)
+		}
+		if set.Intersects(rm.stopGTIDSet) {
+			return time.Time{}, "", nil, fmt.Errorf("VolumeSnapshot %s contains a transaction in the stop GTID set", rm.snapshotName)
+		}
+	}
+
+	dir := path.Join(rm.keyPrefix, backupTime.UTC().Format(constants.BackupTimeFormat))
+	return backupTime, dir, rm.binlogChain(keys, dir), nil
+}
+
+// selectSegments returns binlog segments needed to restore data from a backup taken at `backupTime`.
+// `segments` must be sorted in ascending order of time.
+//
+// A segment contains transactions executed before its time, so segments are
+// selected up to the first one taken at or after the restore point.
+func (rm *RestoreManager) selectSegments(segments []segment, backupTime time.Time) []segment {
+	var selected []segment
+	for _, s := range segments {
+		if !s.Time.After(backupTime) {
+			continue
+		}
+		selected = append(selected, s)
+		if !s.Time.Before(rm.restorePoint) {
+			return selected
+		}
+	}
+
+	if len(selected) > 0 {
+		rm.log.Info("the restore point is after the latest binlog segment",
+			"segment", selected[len(selected)-1].Time.Format(constants.BackupTimeFormat))
+	}
+	return selected
 }
 
-func (rm *RestoreManager) loadDump(ctx context.Context, op bkop.Operator, key string) error {
-	r, err := rm.bucket.Get(ctx, key)
+// verifySegment returns an error if some transactions between the data restored
+// so far and the binlog segment of `key` are missing, for example because a segment
+// failed to be uploaded.  The segment excludes the transactions in the previous GTID
+// set recorded in its manifest, so all of them must have been executed already.
+func (rm *RestoreManager) verifySegment(ctx context.Context, op bkop.Operator, key string, m *Manifest) error {
+	if m == nil || m.PreviousGTIDSet == "" {
+		rm.log.Info("the segment has no record of the previous GTID set; its continuity will not be verified", "segment", key)
+		return nil
+	}
+
+	ok, err := op.ContainsGTIDSet(ctx, m.PreviousGTIDSet)
+	if err != nil {
+		return fmt.Errorf("failed to verify the continuity of binlog segment %s: %w", key, err)
+	}
+	if !ok {
+		return fmt.Errorf("transactions before binlog segment %s are missing; the restored data does not contain %s", key, m.PreviousGTIDSet)
+	}
+	return nil
+}
+
+func (rm *RestoreManager) loadDump(ctx context.Context, op bkop.Operator, key string, expected *ManifestObject) error {
+	rm.setPhase(ctx, mocov1beta2.RestoreDownloading)
+	rc, err := rm.bucket.Get(ctx, key)
 	if err != nil {
 		return fmt.Errorf("failed to get object %s: %w", key, err)
 	}
-	defer r.Close()
+	defer rc.Close()
+	downloaded := rm.progress.DownloadedBytes
+	r := newChecksumReader(io.TeeReader(rc, &ByteCountWriter{Progress: rm.downloadProgress(ctx)}))
 
 	dumpDir := filepath.Join(rm.workDir, "dump")
 	defer func() {
 		os.RemoveAll(dumpDir)
 	}()
 
-	tarCmd := exec.CommandContext(ctx, "tar", "-C", rm.workDir, "-x", "-f", "-")
-	tarCmd.Stdin = r
-	tarCmd.Stdout = os.Stdout
-	tarCmd.Stderr = os.Stderr
-	if err := tarCmd.Run(); err != nil {
-		return fmt.Errorf("failed to untar dump file: %w", err)
+	if err := extractArchive(r, rm.workDir); err != nil {
+		return fmt.Errorf("failed to extract dump file: %w", err)
+	}
+	if expected != nil {
+		if err := r.Verify(*expected); err != nil {
+			return fmt.Errorf("failed to verify %s: %w", key, err)
+		}
+	}
+	rm.progress.DownloadedBytes = downloaded + r.Object().Size
+
+	usage, err := dirUsage(dumpDir)
+	if err != nil {
+		return fmt.Errorf("failed to calculate dir usage: %w", err)
 	}
 
-	return op.LoadDump(ctx, dumpDir)
+	rm.setPhase(ctx, mocov1beta2.RestoreLoadingDump)
+	if err := op.LoadDump(ctx, dumpDir, rm.filter); err != nil {
+		return err
+	}
+	rm.progress.LoadedBytes += usage
+	return nil
 }
 
-func (rm *RestoreManager) applyBinlog(ctx context.Context, op bkop.Operator, key string) error {
-	r, err := rm.bucket.Get(ctx, key)
+func (rm *RestoreManager) applyBinlog(ctx context.Context, op bkop.Operator, key string, expected *ManifestObject) (bool, error) {
+	rc, err := rm.bucket.Get(ctx, key)
 	if err != nil {
-		return fmt.Errorf("failed to get object %s: %w", key, err)
+		return false, fmt.Errorf("failed to get object %s: %w", key, err)
 	}
-	defer r.Close()
+	defer rc.Close()
+	downloaded := rm.progress.DownloadedBytes
+	r := newChecksumReader(io.TeeReader(rc, &ByteCountWriter{Progress: rm.downloadProgress(ctx)}))
 
 	binlogDir := filepath.Join(rm.workDir, "binlog")
 	defer func() {
 		os.RemoveAll(binlogDir)
 	}()
 
-	pr, pw, err := os.Pipe()
-	if err != nil {
-		return fmt.Errorf("failed to create pipe: %w", err)
+	if err := extractArchive(r, rm.workDir); err != nil {
+		return false, fmt.Errorf("failed to extract binlog files: %w", err)
 	}
-	defer func() {
-		if pr != nil {
-			pr.Close()
-		}
-		if pw != nil {
-			pw.Close()
+	if expected != nil {
+		if err := r.Verify(*expected); err != nil {
+			return false, fmt.Errorf("failed to verify %s: %w", key, err)
 		}
-	}()
-
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	zstdCmd := exec.CommandContext(ctx, "zstd", "-d", "--no-progress")
-	zstdCmd.Stdin = r
-	zstdCmd.Stdout = pw
-	zstdCmd.Stderr = os.Stderr
-
-	if err := zstdCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start zstd: %w", err)
 	}
-	pw.Close()
-	pw = nil
+	rm.progress.DownloadedBytes = downloaded + r.Object().Size
 
-	tarCmd := exec.CommandContext(ctx, "tar", "-C", rm.workDir, "-x", "-f", "-")
-	tarCmd.Stdin = pr
-	tarCmd.Stdout = os.Stdout
-	tarCmd.Stderr = os.Stderr
-
-	if err := tarCmd.Run(); err != nil {
-		return fmt.Errorf("failed to run tar: %w", err)
-	}
-	if err := zstdCmd.Wait(); err != nil {
-		return fmt.Errorf("zstd exited abnormally: %w", err)
+	usage, err := dirUsage(binlogDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to calculate dir usage: %w", err)
 	}
 
 	// for mysqlbinlog
 	tmpDir := filepath.Join(rm.workDir, "tmp")
 	if err := os.MkdirAll(tmpDir, 0755); err != nil {
-		return fmt.Errorf("failed to create %s: %w", tmpDir, err)
+		return false, fmt.Errorf("failed to create %s: %w", tmpDir, err)
 	}
 	defer func() {
 		os.RemoveAll(tmpDir)
 	}()
 
-	return op.LoadBinlog(ctx, binlogDir, tmpDir, rm.restorePoint)
+	reached, err := op.LoadBinlog(ctx, binlogDir, tmpDir, rm.restorePoint, rm.stopGTIDSet, rm.filter)
+	if err != nil {
+		return false, err
+	}
+	rm.progress.LoadedBytes += usage
+	rm.reportProgress(ctx)
+	return reached, nil
 }
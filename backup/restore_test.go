@@ -4,7 +4,13 @@ import (
 	"testing"
 	"time"
 
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/constants"
 	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestFindNearestDump(t *testing.T) {
@@ -21,20 +27,20 @@ func TestFindNearestDump(t *testing.T) {
 		name         string
 		restorePoint time.Time
 
-		expectDump   string
-		expectBinlog string
-		expectTime   time.Time
+		expectDump    string
+		expectBinlogs []string
+		expectTime    time.Time
 	}{
 		{"latest", time.Date(2021, time.May, 26, 0, 0, 0, 0, time.UTC),
-			"moco/test/test/20210526-000000/dump.tar", "", time.Date(2021, time.May, 26, 0, 0, 0, 0, time.UTC)},
+			"moco/test/test/20210526-000000/dump.tar", nil, time.Date(2021, time.May, 26, 0, 0, 0, 0, time.UTC)},
 		{"up-to-date", time.Date(2021, time.May, 26, 1, 0, 0, 0, time.UTC),
-			"moco/test/test/20210526-000000/dump.tar", "", time.Date(2021, time.May, 26, 0, 0, 0, 0, time.UTC)},
+			"moco/test/test/20210526-000000/dump.tar", nil, time.Date(2021, time.May, 26, 0, 0, 0, 0, time.UTC)},
 		{"no-binlog", time.Date(2021, time.May, 25, 13, 0, 0, 0, time.UTC),
-			"moco/test/test/20210525-120001/dump.tar", "", time.Date(2021, time.May, 25, 12, 0, 1, 0, time.UTC)},
+			"moco/test/test/20210525-120001/dump.tar", nil, time.Date(2021, time.May, 25, 12, 0, 1, 0, time.UTC)},
 		{"with-binlog", time.Date(2021, time.May, 25, 11, 22, 33, 0, time.UTC),
-			"moco/test/test/20210525-112233/dump.tar", "moco/test/test/20210525-112233/binlog.tar.zst",
+			"moco/test/test/20210525-112233/dump.tar", []string{"moco/test/test/20210525-112233/binlog.tar.zst"},
 			time.Date(2021, time.May, 25, 11, 22, 33, 0, time.UTC)},
-		{"not-found", time.Date(2021, time.May, 24, 0, 0, 0, 0, time.UTC), "", "", time.Time{}},
+		{"not-found", time.Date(2021, time.May, 24, 0, 0, 0, 0, time.UTC), "", nil, time.Time{}},
 	}
 
 	for _, tc := range testCases {
@@ -43,12 +49,12 @@ func TestFindNearestDump(t *testing.T) {
 				log:          logr.Discard(),
 				restorePoint: tc.restorePoint,
 			}
-			dump, binlog, bkt := rm.FindNearestDump(keys)
+			dump, binlogs, bkt := rm.FindNearestDump(keys)
 			if dump != tc.expectDump {
 				t.Errorf("unexpected dump: %s, expected %s", dump, tc.expectDump)
 			}
-			if binlog != tc.expectBinlog {
-				t.Errorf("unexpected binlog %s, expected %s", binlog, tc.expectBinlog)
+			if !cmp.Equal(binlogs, tc.expectBinlogs) {
+				t.Errorf("unexpected binlogs %v, expected %v", binlogs, tc.expectBinlogs)
 			}
 			if !bkt.Equal(tc.expectTime) {
 				t.Errorf("unexpected backup time %s, expected %s", bkt.String(), tc.expectTime.String())
@@ -56,3 +62,277 @@ func TestFindNearestDump(t *testing.T) {
 		})
 	}
 }
+
+func TestFindNearestDumpStreamed(t *testing.T) {
+	keys := []string{
+		"moco/test/test/20210525-112233/binlog.tar.zst",
+		"moco/test/test/20210525-112233/dump/@.done.json",
+		"moco/test/test/20210525-112233/dump/@.json",
+		"moco/test/test/20210525-112233/dump/db@t@@0.tsv.zst",
+		"moco/test/test/20210525-112233/manifest.json",
+		"moco/test/test/20210525-120001/dump.tar",
+		"moco/test/test/20210526-000000/dump/@.json", // incomplete
+		"moco/test/test/20210526-000000/dump/db@t@@0.tsv.zst",
+	}
+
+	rm := &RestoreManager{log: logr.Discard(), restorePoint: time.Date(2021, time.May, 25, 12, 0, 0, 0, time.UTC)}
+	dump, binlogs, bkt := rm.FindNearestDump(keys)
+	if dump != "moco/test/test/20210525-112233/dump" || !cmp.Equal(binlogs, []string{"moco/test/test/20210525-112233/binlog.tar.zst"}) {
+		t.Errorf("unexpected keys: %s, %v", dump, binlogs)
+	}
+	if !bkt.Equal(time.Date(2021, time.May, 25, 11, 22, 33, 0, time.UTC)) {
+		t.Errorf("unexpected backup time: %s", bkt)
+	}
+
+	rm.restorePoint = time.Date(2021, time.May, 27, 0, 0, 0, 0, time.UTC)
+	dump, _, _ = rm.FindNearestDump(keys)
+	if dump != "moco/test/test/20210525-120001/dump.tar" {
+		t.Errorf("incomplete dump should not be chosen: %s", dump)
+	}
+}
+
+func TestFindNearestDumpBinlogDir(t *testing.T) {
+	// the dump of 20210525-120001 has no binlog, and the binlog of the next backup must not be used.
+	keys := []string{
+		"moco/test/test/20210525-120001/dump.tar",
+		"moco/test/test/20210526-000000/binlog.tar.zst",
+		"moco/test/test/20210526-000000/dump.tar",
+	}
+
+	rm := &RestoreManager{
+		log:          logr.Discard(),
+		restorePoint: time.Date(2021, time.May, 25, 13, 0, 0, 0, time.UTC),
+	}
+	dump, binlogs, _ := rm.FindNearestDump(keys)
+	if dump != "moco/test/test/20210525-120001/dump.tar" {
+		t.Errorf("unexpected dump: %s", dump)
+	}
+	if len(binlogs) != 0 {
+		t.Errorf("unexpected binlogs: %v", binlogs)
+	}
+}
+
+func TestFindNearestDumpChain(t *testing.T) {
+	keys := []string{
+		"moco/test/test/20210525-000000/dump.tar",
+		"moco/test/test/20210525-000000/binlog-20210525-120000.tar.zst",
+		"moco/test/test/20210525-000000/binlog-20210525-060000.tar.zst",
+		"moco/test/test/20210525-000000/binlog.tar.zst",
+		"moco/test/test/20210526-000000/dump.tar",
+		"moco/test/test/20210526-000000/binlog-20210526-060000.tar.zst",
+	}
+
+	testCases := []struct {
+		name         string
+		restorePoint time.Time
+
+		expectBinlogs []string
+	}{
+		{"first", time.Date(2021, time.May, 25, 3, 0, 0, 0, time.UTC), []string{
+			"moco/test/test/20210525-000000/binlog-20210525-060000.tar.zst",
+		}},
+		{"second", time.Date(2021, time.May, 25, 12, 0, 0, 0, time.UTC), []string{
+			"moco/test/test/20210525-000000/binlog-20210525-060000.tar.zst",
+			"moco/test/test/20210525-000000/binlog-20210525-120000.tar.zst",
+		}},
+		{"last", time.Date(2021, time.May, 25, 18, 0, 0, 0, time.UTC), []string{
+			"moco/test/test/20210525-000000/binlog-20210525-060000.tar.zst",
+			"moco/test/test/20210525-000000/binlog-20210525-120000.tar.zst",
+			"moco/test/test/20210525-000000/binlog.tar.zst",
+		}},
+		{"latest", time.Date(2021, time.May, 26, 12, 0, 0, 0, time.UTC), []string{
+			"moco/test/test/20210526-000000/binlog-20210526-060000.tar.zst",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rm := &RestoreManager{
+				log:          logr.Discard(),
+				restorePoint: tc.restorePoint,
+			}
+			_, binlogs, _ := rm.FindNearestDump(keys)
+			if !cmp.Equal(binlogs, tc.expectBinlogs) {
+				t.Errorf("unexpected binlogs: %s", cmp.Diff(tc.expectBinlogs, binlogs))
+			}
+		})
+	}
+}
+
+func TestFindNearestDumpByGTID(t *testing.T) {
+	keys := []string{
+		"moco/test/test/20210525-112233/dump.tar",
+		"moco/test/test/20210525-112233/binlog.tar.zst",
+		"moco/test/test/20210525-120001/dump.tar", // no manifest
+		"moco/test/test/20210525-120001/binlog.tar.zst",
+		"moco/test/test/20210526-000000/dump.tar",
+	}
+	mustParse := func(s string) bkop.GTIDSet {
+		set, err := bkop.ParseGTIDSet(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return set
+	}
+	manifests := map[string]*Manifest{
+		"moco/test/test/20210525-112233": {
+			Time:    time.Date(2021, time.May, 25, 11, 22, 33, 0, time.UTC),
+			GTIDSet: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10",
+			Objects: map[string]ManifestObject{constants.DumpFilename: {}},
+		},
+		"moco/test/test/20210526-000000": {
+			Time:    time.Date(2021, time.May, 26, 0, 0, 0, 0, time.UTC),
+			GTIDSet: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-30",
+			Objects: map[string]ManifestObject{constants.DumpFilename: {}},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		stopSet string
+
+		expectDump    string
+		expectBinlogs []string
+	}{
+		{"after-latest", "3e11fa47-71ca-11e1-9e33-c80aa9429562:31",
+			"moco/test/test/20210526-000000/dump.tar", nil},
+		{"skip-unknown", "3e11fa47-71ca-11e1-9e33-c80aa9429562:25",
+			"moco/test/test/20210525-112233/dump.tar", []string{"moco/test/test/20210525-112233/binlog.tar.zst"}},
+		{"not-found", "3e11fa47-71ca-11e1-9e33-c80aa9429562:10", "", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rm := &RestoreManager{
+				log:          logr.Discard(),
+				restorePoint: time.Date(2021, time.May, 26, 1, 0, 0, 0, time.UTC),
+				stopGTIDSet:  mustParse(tc.stopSet),
+				manifests:    manifests,
+			}
+			dump, binlogs, _ := rm.FindNearestDump(keys)
+			if dump != tc.expectDump {
+				t.Errorf("unexpected dump: %s, expected %s", dump, tc.expectDump)
+			}
+			if !cmp.Equal(binlogs, tc.expectBinlogs) {
+				t.Errorf("unexpected binlogs %v, expected %v", binlogs, tc.expectBinlogs)
+			}
+		})
+	}
+}
+
+func TestFindNearestDumpManifest(t *testing.T) {
+	keys := []string{
+		"moco/test/test/20210525-000000/dump.tar", // no manifest
+		"moco/test/test/20210525-000000/binlog.tar.zst",
+		"moco/test/test/20210525-120000/binlog.tar.zst",
+		"moco/test/test/20210525-120000/dump.tar",
+		"moco/test/test/20210525-120000/manifest.json",
+		"moco/test/test/20210525-130000/manifest.json", // snapshot
+		"moco/test/test/20210525-140000/dump/@.done.json",
+		"moco/test/test/20210525-140000/dump/@.json",
+		"moco/test/test/20210525-140000/manifest.json",
+		"moco/test/test/20210525-150000/manifest.json", // the dump is missing
+	}
+	manifests := map[string]*Manifest{
+		// the time of the manifest is used instead of the directory name.
+		"moco/test/test/20210525-120000": {
+			Time:    time.Date(2021, time.May, 25, 12, 0, 0, 0, time.UTC),
+			Objects: map[string]ManifestObject{constants.DumpFilename: {}, constants.BinlogFilename: {}},
+		},
+		"moco/test/test/20210525-130000": {
+			Time:               time.Date(2021, time.May, 25, 13, 0, 0, 0, time.UTC),
+			VolumeSnapshotName: "snap",
+			Objects:            map[string]ManifestObject{},
+		},
+		"moco/test/test/20210525-140000": {
+			Time: time.Date(2021, time.May, 25, 14, 0, 0, 0, time.UTC),
+			Objects: map[string]ManifestObject{
+				"dump/@.json":      {},
+				"dump/@.done.json": {},
+			},
+		},
+		"moco/test/test/20210525-150000": {
+			Time:    time.Date(2021, time.May, 25, 15, 0, 0, 0, time.UTC),
+			Objects: map[string]ManifestObject{constants.DumpFilename: {}},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		restorePoint time.Time
+
+		expectDump    string
+		expectBinlogs []string
+	}{
+		{"no-manifest", time.Date(2021, time.May, 25, 6, 0, 0, 0, time.UTC),
+			"moco/test/test/20210525-000000/dump.tar", []string{"moco/test/test/20210525-000000/binlog.tar.zst"}},
+		{"manifest", time.Date(2021, time.May, 25, 13, 30, 0, 0, time.UTC),
+			"moco/test/test/20210525-120000/dump.tar", []string{"moco/test/test/20210525-120000/binlog.tar.zst"}},
+		{"streamed", time.Date(2021, time.May, 25, 15, 30, 0, 0, time.UTC),
+			"moco/test/test/20210525-140000/dump", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rm := &RestoreManager{
+				log:          logr.Discard(),
+				restorePoint: tc.restorePoint,
+				manifests:    manifests,
+			}
+			dump, binlogs, _ := rm.FindNearestDump(keys)
+			if dump != tc.expectDump {
+				t.Errorf("unexpected dump: %s, expected %s", dump, tc.expectDump)
+			}
+			if !cmp.Equal(binlogs, tc.expectBinlogs) {
+				t.Errorf("unexpected binlogs %v, expected %v", binlogs, tc.expectBinlogs)
+			}
+		})
+	}
+
+	// the time of the manifest is returned as the backup time.
+	manifests["moco/test/test/20210525-120000"].Time = time.Date(2021, time.May, 25, 11, 59, 59, 0, time.UTC)
+	rm := &RestoreManager{
+		log:          logr.Discard(),
+		restorePoint: time.Date(2021, time.May, 25, 12, 0, 0, 0, time.UTC),
+		manifests:    manifests,
+	}
+	dump, _, bkt := rm.FindNearestDump(keys)
+	if dump != "moco/test/test/20210525-120000/dump.tar" || !bkt.Equal(manifests["moco/test/test/20210525-120000"].Time) {
+		t.Errorf("unexpected backup: %s, %s", dump, bkt)
+	}
+}
+
+func TestSetRestoredCondition(t *testing.T) {
+	status := &mocov1beta2.MySQLClusterStatus{
+		Conditions: []mocov1beta2.MySQLClusterCondition{
+			{Type: mocov1beta2.ConditionInitialized, Status: corev1.ConditionTrue},
+		},
+	}
+
+	setRestoredCondition(status, corev1.ConditionFalse, "RestoreFailed", "error")
+	if len(status.Conditions) != 2 {
+		t.Fatal("the condition was not added", status.Conditions)
+	}
+	cond := status.Conditions[1]
+	if cond.Type != mocov1beta2.ConditionRestored || cond.Status != corev1.ConditionFalse || cond.Reason != "RestoreFailed" || cond.Message != "error" {
+		t.Error("unexpected condition", cond)
+	}
+
+	transition := metav1.NewTime(time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC))
+	status.Conditions[1].LastTransitionTime = transition
+	setRestoredCondition(status, corev1.ConditionFalse, "RestoreFailed", "another error")
+	if cond := status.Conditions[1]; !cond.LastTransitionTime.Equal(&transition) || cond.Message != "another error" {
+		t.Error("unexpected condition", cond)
+	}
+
+	setRestoredCondition(status, corev1.ConditionTrue, "Restored", "")
+	if len(status.Conditions) != 2 {
+		t.Fatal("the condition was duplicated", status.Conditions)
+	}
+	if cond := status.Conditions[1]; cond.Status != corev1.ConditionTrue || cond.LastTransitionTime.Equal(&transition) {
+		t.Error("unexpected condition", cond)
+	}
+	if status.Conditions[0].Type != mocov1beta2.ConditionInitialized {
+		t.Error("other conditions should be kept", status.Conditions)
+	}
+}
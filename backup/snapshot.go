@@ -0,0 +1,189 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// volumeSnapshotGVK is the GroupVersionKind of CSI VolumeSnapshot.
+var volumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
+const (
+	// snapshotCutTimeout is the maximum time to hold the lock until the snapshot is cut.
+	snapshotCutTimeout = 5 * time.Minute
+
+	// snapshotReadyTimeout is the maximum time to wait for the snapshot to become ready to use.
+	snapshotReadyTimeout = time.Hour
+
+	snapshotPollInterval = 2 * time.Second
+)
+
+// WithSnapshot makes the backup take a VolumeSnapshot of the data volume of
+// the source instance instead of a full dump.  The source must be a replica.
+// If `className` is empty, the default VolumeSnapshotClass is used.
+func WithSnapshot(className string) BackupOption {
+	return func(bm *BackupManager) {
+		bm.snapshot = true
+		bm.snapshotClass = className
+	}
+}
+
+// snapshotName returns the name of the VolumeSnapshot of a backup taken at `t`.
+func snapshotName(clusterName string, t time.Time) string {
+	return fmt.Sprintf("moco-%s-%s", clusterName, t.UTC().Format(constants.BackupTimeFormat))
+}
+
+func newVolumeSnapshot() *unstructured.Unstructured {
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(volumeSnapshotGVK)
+	return vs
+}
+
+// backupSnapshot takes a VolumeSnapshot of the data volume of the source instance.
+//
+// Tables are flushed and a global read lock is held until the snapshot is cut,
+// so the data in the snapshot is consistent with the executed GTID set read
+// under the lock.  The lock also pauses the replication applier.
+func (bm *BackupManager) backupSnapshot(ctx context.Context, op bkop.Operator) error {
+	if !bm.status.IsReplica {
+		return errors.New("a snapshot cannot be taken from the primary instance")
+	}
+
+	unlock, err := op.LockForSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	locked := true
+	defer func() {
+		if !locked {
+			return
+		}
+		if err := unlock(); err != nil {
+			bm.log.Error(err, "failed to release the lock")
+		}
+	}()
+
+	if err := op.GetServerStatus(ctx, &bm.status); err != nil {
+		return fmt.Errorf("failed to get server status: %w", err)
+	}
+	bm.gtidSet = bm.status.ExecutedGTIDSet
+
+	name := snapshotName(bm.cluster.Name, bm.startTime)
+	pvcName := constants.MySQLDataVolumeName + "-" + bm.cluster.PodName(bm.sourceIndex)
+	vs := newVolumeSnapshot()
+	vs.SetNamespace(bm.cluster.Namespace)
+	vs.SetName(name)
+	vs.SetLabels(map[string]string{
+		constants.LabelAppName:      constants.AppNameSnapshot,
+		constants.LabelAppInstance:  bm.cluster.Name,
+		constants.LabelAppCreatedBy: constants.AppCreator,
+	})
+	vs.SetAnnotations(map[string]string{
+		constants.AnnBackupTime: bm.startTime.Format(time.RFC3339),
+		constants.AnnGTIDSet:    bm.gtidSet,
+	})
+	if err := unstructured.SetNestedField(vs.Object, pvcName, "spec", "source", "persistentVolumeClaimName"); err != nil {
+		return err
+	}
+	if bm.snapshotClass != "" {
+		if err := unstructured.SetNestedField(vs.Object, bm.snapshotClass, "spec", "volumeSnapshotClassName"); err != nil {
+			return err
+		}
+	}
+	if err := bm.client.Create(ctx, vs); err != nil {
+		return fmt.Errorf("failed to create VolumeSnapshot %s: %w", name, err)
+	}
+	bm.snapshotName = name
+	bm.log.Info("created VolumeSnapshot", "name", name, "pvc", pvcName, "gtid", bm.gtidSet)
+
+	// the point-in-time of the snapshot is fixed when its creation time is set.
+	err = bm.waitSnapshot(ctx, name, snapshotCutTimeout, func(vs *unstructured.Unstructured) bool {
+		_, found, _ := unstructured.NestedString(vs.Object, "status", "creationTime")
+		return found
+	})
+	if err != nil {
+		return err
+	}
+	locked = false
+	if err := unlock(); err != nil {
+		return err
+	}
+	bm.log.Info("VolumeSnapshot has been cut", "name", name)
+
+	err = bm.waitSnapshot(ctx, name, snapshotReadyTimeout, func(vs *unstructured.Unstructured) bool {
+		ready, _, _ := unstructured.NestedBool(vs.Object, "status", "readyToUse")
+		return ready
+	})
+	if err != nil {
+		return err
+	}
+	bm.log.Info("VolumeSnapshot is ready to use", "name", name)
+	return nil
+}
+
+// waitSnapshot waits until `done` returns true for the VolumeSnapshot.
+// Errors reported in the status of the VolumeSnapshot are only logged
+// because the snapshot controller retries them.
+func (bm *BackupManager) waitSnapshot(ctx context.Context, name string, timeout time.Duration, done func(*unstructured.Unstructured) bool) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		vs := newVolumeSnapshot()
+		if err := bm.client.Get(ctx, client.ObjectKey{Namespace: bm.cluster.Namespace, Name: name}, vs); err != nil {
+			return fmt.Errorf("failed to get VolumeSnapshot %s: %w", name, err)
+		}
+		if done(vs) {
+			return nil
+		}
+		if msg, found, _ := unstructured.NestedString(vs.Object, "status", "error", "message"); found {
+			bm.log.Info("VolumeSnapshot has an error", "name", name, "message", msg)
+		}
+
+		select {
+		case <-time.After(snapshotPollInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("failed to wait for VolumeSnapshot %s: %w", name, ctx.Err())
+		}
+	}
+}
+
+// deleteSnapshot deletes the VolumeSnapshot of a pruned backup.
+func (bm *BackupManager) deleteSnapshot(ctx context.Context, name string) error {
+	vs := newVolumeSnapshot()
+	vs.SetNamespace(bm.cluster.Namespace)
+	vs.SetName(name)
+	if err := bm.client.Delete(ctx, vs); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete VolumeSnapshot %s: %w", name, err)
+	}
+	return nil
+}
+
+// getSnapshot returns the time and the GTID set of the snapshot backup
+// recorded in the VolumeSnapshot.
+func (rm *RestoreManager) getSnapshot(ctx context.Context) (time.Time, string, error) {
+	vs := newVolumeSnapshot()
+	if err := rm.client.Get(ctx, client.ObjectKey{Namespace: rm.namespace, Name: rm.snapshotName}, vs); err != nil {
+		return time.Time{}, "", fmt.Errorf("failed to get VolumeSnapshot %s: %w", rm.snapshotName, err)
+	}
+
+	ann := vs.GetAnnotations()
+	t, err := time.Parse(time.RFC3339, ann[constants.AnnBackupTime])
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("VolumeSnapshot %s has no valid backup time: %w", rm.snapshotName, err)
+	}
+	return t, ann[constants.AnnGTIDSet], nil
+}
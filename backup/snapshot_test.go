@@ -0,0 +1,215 @@
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// snapshotClient emulates the snapshot controller by making VolumeSnapshots ready on creation.
+type snapshotClient struct {
+	client.Client
+	op *mockOperator
+
+	// lockedOnCreate records whether the lock was held when the snapshot was created.
+	lockedOnCreate bool
+}
+
+func (c *snapshotClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if vs, ok := obj.(*unstructured.Unstructured); ok && vs.GroupVersionKind() == volumeSnapshotGVK {
+		c.lockedOnCreate = c.op.locked
+		unstructured.SetNestedField(vs.Object, "2022-05-01T00:00:01Z", "status", "creationTime")
+		unstructured.SetNestedField(vs.Object, true, "status", "readyToUse")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func testSnapshotCluster() *mocov1beta2.MySQLCluster {
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	return cluster
+}
+
+func TestBackupSnapshot(t *testing.T) {
+	ctx := context.Background()
+	op := &mockOperator{binlogs: []string{"binlog.000001"}, gtid: "gtid1"}
+	c := &snapshotClient{
+		Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build(),
+		op:     op,
+	}
+	bm := &BackupManager{
+		log:           logr.Discard(),
+		client:        c,
+		cluster:       testSnapshotCluster(),
+		snapshot:      true,
+		snapshotClass: "csi-snapclass",
+		startTime:     time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC),
+		sourceIndex:   1,
+	}
+
+	if err := bm.backupSnapshot(ctx, op); err == nil {
+		t.Error("a snapshot should not be taken from the primary")
+	}
+
+	bm.status.IsReplica = true
+	if err := bm.backupSnapshot(ctx, op); err != nil {
+		t.Fatal(err)
+	}
+	if !c.lockedOnCreate {
+		t.Error("the snapshot was created without the lock")
+	}
+	if op.locked {
+		t.Error("the lock was not released")
+	}
+	if bm.snapshotName != "moco-test-20220501-000000" {
+		t.Errorf("unexpected snapshot name: %s", bm.snapshotName)
+	}
+	if bm.gtidSet != "gtid1" {
+		t.Errorf("unexpected GTID set: %s", bm.gtidSet)
+	}
+
+	vs := newVolumeSnapshot()
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "test", Name: bm.snapshotName}, vs); err != nil {
+		t.Fatal(err)
+	}
+	if pvc, _, _ := unstructured.NestedString(vs.Object, "spec", "source", "persistentVolumeClaimName"); pvc != "mysql-data-moco-test-1" {
+		t.Errorf("unexpected source PVC: %s", pvc)
+	}
+	if class, _, _ := unstructured.NestedString(vs.Object, "spec", "volumeSnapshotClassName"); class != "csi-snapclass" {
+		t.Errorf("unexpected class: %s", class)
+	}
+	if name := vs.GetLabels()[constants.LabelAppName]; name != constants.AppNameSnapshot {
+		t.Errorf("unexpected app name: %s", name)
+	}
+	ann := vs.GetAnnotations()
+	if ann[constants.AnnBackupTime] != "2022-05-01T00:00:00Z" || ann[constants.AnnGTIDSet] != "gtid1" {
+		t.Errorf("unexpected annotations: %v", ann)
+	}
+
+	rm := &RestoreManager{
+		log:          logr.Discard(),
+		client:       c,
+		namespace:    "test",
+		keyPrefix:    "moco/test/test",
+		restorePoint: time.Date(2022, time.May, 2, 0, 0, 0, 0, time.UTC),
+		snapshotName: bm.snapshotName,
+	}
+	keys := []string{
+		"moco/test/test/20220501-000000/binlog.tar.zst",
+		"moco/test/test/20220501-000000/manifest.json",
+	}
+	backupTime, dir, binlogKeys, err := rm.findSnapshotBackup(ctx, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !backupTime.Equal(bm.startTime) {
+		t.Errorf("unexpected backup time: %s", backupTime)
+	}
+	if dir != "moco/test/test/20220501-000000" {
+		t.Errorf("unexpected dir: %s", dir)
+	}
+	if len(binlogKeys) != 1 || binlogKeys[0] != keys[0] {
+		t.Errorf("unexpected binlog keys: %v", binlogKeys)
+	}
+
+	rm.restorePoint = time.Date(2022, time.April, 30, 0, 0, 0, 0, time.UTC)
+	if _, _, _, err := rm.findSnapshotBackup(ctx, keys); err == nil {
+		t.Error("a snapshot taken after the restore point should not be used")
+	}
+
+	if err := bm.deleteSnapshot(ctx, bm.snapshotName); err != nil {
+		t.Fatal(err)
+	}
+	err = c.Get(ctx, client.ObjectKey{Namespace: "test", Name: bm.snapshotName}, newVolumeSnapshot())
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("the snapshot was not deleted: %v", err)
+	}
+	if err := bm.deleteSnapshot(ctx, bm.snapshotName); err != nil {
+		t.Errorf("deleting a missing snapshot should succeed: %v", err)
+	}
+}
+
+func TestBackupSnapshotLockFailure(t *testing.T) {
+	op := &mockOperator{binlogs: []string{"binlog.000001"}, locked: true}
+	bm := &BackupManager{
+		log:     logr.Discard(),
+		client:  fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build(),
+		cluster: testSnapshotCluster(),
+		status:  bkop.ServerStatus{IsReplica: true},
+	}
+	err := bm.backupSnapshot(context.Background(), op)
+	if err == nil {
+		t.Error("the backup should fail without the lock")
+	}
+	if bm.snapshotName != "" {
+		t.Errorf("a snapshot was created without the lock: %s", bm.snapshotName)
+	}
+}
+
+func TestPruneSnapshot(t *testing.T) {
+	ctx := context.Background()
+	bc := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20220501-000000/binlog.tar.zst": nil,
+		"moco/test/test/20220501-000000/manifest.json":  []byte(`{"version":1,"volumeSnapshotName":"moco-test-20220501-000000"}`),
+		"moco/test/test/20220502-000000/manifest.json":  []byte(`{"version":1,"volumeSnapshotName":"moco-test-20220502-000000"}`),
+	}}
+
+	vs := newVolumeSnapshot()
+	vs.SetNamespace("test")
+	vs.SetName("moco-test-20220501-000000")
+	k8sClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(vs).Build()
+
+	bm := &BackupManager{
+		log:       logr.Discard(),
+		client:    k8sClient,
+		cluster:   testSnapshotCluster(),
+		bucket:    bc,
+		retention: Retention{KeepLast: 1},
+		startTime: time.Date(2022, time.May, 2, 0, 0, 0, 0, time.UTC),
+	}
+	if err := bm.prune(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bc.contents) != 1 {
+		t.Errorf("unexpected keys left: %v", bc.contents)
+	}
+	err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "moco-test-20220501-000000"}, newVolumeSnapshot())
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("the snapshot of the pruned backup was not deleted: %v", err)
+	}
+}
+
+func TestListBackupsSnapshot(t *testing.T) {
+	b := &mockBucket{contents: map[string][]byte{
+		"moco/test/test/20220501-000000/binlog.tar.zst": nil,
+		"moco/test/test/20220501-000000/manifest.json":  []byte(`{"version":1,"gtidSet":"gtid1","volumeSnapshotName":"moco-test-20220501-000000"}`),
+		"moco/test/test/20220502-000000/manifest.json":  []byte(`{"version":1,"gtidSet":"gtid2"}`), // neither dump nor snapshot
+	}}
+
+	backups, err := ListBackups(context.Background(), b, "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("unexpected backups: %+v", backups)
+	}
+	bi := backups[0]
+	if bi.VolumeSnapshotName != "moco-test-20220501-000000" || bi.DumpKey != "" {
+		t.Errorf("unexpected backup: %+v", bi)
+	}
+	if bi.BinlogKey != "moco/test/test/20220501-000000/binlog.tar.zst" {
+		t.Errorf("unexpected binlog key: %s", bi.BinlogKey)
+	}
+}
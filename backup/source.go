@@ -0,0 +1,143 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SourcePolicy is the policy to choose the backup source instance.
+type SourcePolicy struct {
+	// Index pins the source to the instance of this index.
+	Index *int
+
+	// Zone restricts the source to instances running on Nodes in this zone.
+	Zone string
+
+	// ExcludePrimary forbids falling back to the primary instance.
+	ExcludePrimary bool
+
+	// PreferLeastLag chooses the replica with the lowest replication lag.
+	PreferLeastLag bool
+}
+
+// WithSourcePolicy specifies the policy to choose the backup source instance.
+func WithSourcePolicy(p *SourcePolicy) BackupOption {
+	return func(bm *BackupManager) {
+		bm.sourcePolicy = p
+	}
+}
+
+// choosePodByPolicy chooses the source instance according to the source policy.
+// Only ready instances that satisfy the policy are candidates, and replicas are
+// preferred over the primary.
+func (bm *BackupManager) choosePodByPolicy(ctx context.Context, pods []*corev1.Pod) (int, error) {
+	p := bm.sourcePolicy
+	primary := bm.cluster.Status.CurrentPrimaryIndex
+
+	var replicas []int
+	primaryOK := false
+	for i, pod := range pods {
+		if p.Index != nil && i != *p.Index {
+			continue
+		}
+		if !podIsReady(pod) {
+			continue
+		}
+		if p.Zone != "" && podZone(pod) != p.Zone {
+			continue
+		}
+		if i == primary {
+			primaryOK = !p.ExcludePrimary
+			continue
+		}
+		replicas = append(replicas, i)
+	}
+
+	switch {
+	case len(replicas) == 0 && primaryOK:
+		bm.log.Info("no replica instance can be the source; falling back to the primary", "index", primary)
+		return primary, nil
+	case len(replicas) == 0:
+		return -1, errors.New("no ready instance satisfies the backup source policy")
+	case p.PreferLeastLag:
+		return bm.chooseLeastLag(ctx, pods, replicas), nil
+	}
+
+	if lastIndex, ok := bm.lastSourceIndex(); ok {
+		for _, i := range replicas {
+			if i == lastIndex {
+				return i, nil
+			}
+		}
+	}
+	return replicas[0], nil
+}
+
+// lastSourceIndex returns the index of the last backup source, if any.
+func (bm *BackupManager) lastSourceIndex() (int, bool) {
+	if bm.cluster.Status.Backup.Time.IsZero() {
+		return -1, false
+	}
+	return bm.cluster.Status.Backup.SourceIndex, true
+}
+
+// chooseLeastLag returns the replica with the lowest replication lag among `candidates`.
+// The last source instance wins a tie.  Replicas whose lag is unknown are skipped,
+// and the first candidate is returned if the lag of no replica is known.
+func (bm *BackupManager) chooseLeastLag(ctx context.Context, pods []*corev1.Pod, candidates []int) int {
+	lastIndex, _ := bm.lastSourceIndex()
+
+	chosen := -1
+	var minLag time.Duration
+	for _, i := range candidates {
+		lag, err := bm.replicationLag(ctx, pods[i])
+		if err != nil {
+			bm.log.Error(err, "failed to get the replication lag", "index", i)
+			continue
+		}
+		if lag == nil {
+			bm.log.Info("replication is not running", "index", i)
+			continue
+		}
+		if chosen == -1 || *lag < minLag || (*lag == minLag && i == lastIndex) {
+			chosen = i
+			minLag = *lag
+		}
+	}
+
+	if chosen == -1 {
+		bm.log.Info("replication lag of no replica is known", "index", candidates[0])
+		return candidates[0]
+	}
+	bm.log.Info("chose the replica with the least lag", "index", chosen, "lag", minLag.String())
+	return chosen
+}
+
+func (bm *BackupManager) replicationLag(ctx context.Context, pod *corev1.Pod) (*time.Duration, error) {
+	op, err := newOperator(pod.Status.PodIP,
+		constants.MySQLPort,
+		constants.BackupUser,
+		bm.mysqlPassword,
+		bm.threads)
+	if err != nil {
+		return nil, err
+	}
+	defer op.Close()
+
+	st := &bkop.ServerStatus{}
+	if err := op.GetServerStatus(ctx, st); err != nil {
+		return nil, err
+	}
+	return st.ReplicationLag, nil
+}
+
+// podZone returns the zone of the Node where the Pod is running.
+// moco-controller records it in the annotation of the Pod.
+func podZone(pod *corev1.Pod) string {
+	return pod.Annotations[constants.AnnZone]
+}
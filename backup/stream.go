@@ -0,0 +1,543 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/bkop"
+	"github.com/cybozu-go/moco/pkg/constants"
+)
+
+// streamInterval is the interval to look for dump files ready to be uploaded.
+const streamInterval = 5 * time.Second
+
+// WithStreaming makes the backup upload the files of the full dump one by one
+// while mysqlsh is writing the dump, instead of archiving the dump after it finishes.
+// The work directory then needs room only for the files not uploaded yet.
+//
+// The files are stored under the "dump" directory of the backup.
+func WithStreaming() BackupOption {
+	return func(bm *BackupManager) {
+		bm.streaming = true
+	}
+}
+
+// isDataFile returns true if `name` is a data file of a dump that mysqlsh has finished writing.
+// mysqlsh writes data files with the ".dumping" suffix and renames them when done.
+func isDataFile(name string) bool {
+	return strings.HasSuffix(name, ".tsv.zst") || strings.HasSuffix(name, ".tsv")
+}
+
+// isChunkFile returns true if `name` is a data file or its index.
+// Other files in a dump are metadata.
+func isChunkFile(name string) bool {
+	return isDataFile(strings.TrimSuffix(name, ".idx"))
+}
+
+// dumpObjects returns the objects of a streamed dump recorded in the manifest.
+// The keys are the filenames relative to the dump directory.
+func dumpObjects(m *Manifest) map[string]ManifestObject {
+	objs := make(map[string]ManifestObject)
+	for k, obj := range m.Objects {
+		if !strings.HasPrefix(k, constants.DumpDirname+"/") {
+			continue
+		}
+		objs[strings.TrimPrefix(k, constants.DumpDirname+"/")] = obj
+	}
+	return objs
+}
+
+// backupFullStreaming takes a full dump while uploading finished data files.
+// Metadata files and indices of data files are uploaded after the dump finishes,
+// and `@.done.json` is uploaded last to mark the dump complete.
+func (bm *BackupManager) backupFullStreaming(ctx context.Context, op bkop.Operator) error {
+	dumpDir := filepath.Join(bm.workDir, constants.DumpDirname)
+	if err := os.MkdirAll(dumpDir, 0755); err != nil {
+		return fmt.Errorf("failed to make dump directory: %w", err)
+	}
+	defer os.RemoveAll(dumpDir)
+
+	bm.dumpKey = calcKey(bm.cluster.Namespace, bm.cluster.Name, constants.DumpDirname, bm.startTime)
+	bm.dumpObjects = make(map[string]ManifestObject)
+
+	dumpCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- op.DumpFull(dumpCtx, dumpDir, bm.filter)
+	}()
+
+	ticker := time.NewTicker(streamInterval)
+	defer ticker.Stop()
+	for dumping := true; dumping; {
+		select {
+		case err := <-done:
+			if err != nil {
+				return fmt.Errorf("failed to take a full dump: %w", err)
+			}
+			dumping = false
+		case <-ticker.C:
+			if err := bm.uploadDumpFiles(ctx, dumpDir, isDataFile); err != nil {
+				cancel()
+				<-done
+				return err
+			}
+		}
+	}
+
+	gtid, err := bkop.GetGTIDExecuted(dumpDir)
+	if err != nil {
+		return fmt.Errorf("failed to get GTID set from the dump: %w", err)
+	}
+	bm.gtidSet = gtid
+
+	if _, err := os.Stat(filepath.Join(dumpDir, constants.DumpDoneFilename)); err != nil {
+		return fmt.Errorf("the dump is not complete: %w", err)
+	}
+	notDone := func(name string) bool {
+		return name != constants.DumpDoneFilename
+	}
+	if err := bm.uploadDumpFiles(ctx, dumpDir, notDone); err != nil {
+		return err
+	}
+	if err := bm.uploadDumpFile(ctx, dumpDir, constants.DumpDoneFilename); err != nil {
+		return err
+	}
+
+	bm.log.Info("work dir usage (full dump)", "bytes", bm.workDirUsage)
+	bm.log.Info("uploaded dump files", "key", bm.dumpKey, "files", len(bm.dumpObjects), "bytes", bm.dumpSize)
+	return nil
+}
+
+// uploadDumpFiles uploads the files in `dir` whose names satisfy `match`, and removes them.
+// The peak usage of `dir` is recorded as the work dir usage.
+func (bm *BackupManager) uploadDumpFiles(ctx context.Context, dir string, match func(string) bool) error {
+	usage, err := dirUsage(dir)
+	if err != nil {
+		return fmt.Errorf("failed to calculate dir usage: %w", err)
+	}
+	if usage > bm.workDirUsage {
+		bm.workDirUsage = usage
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && match(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := bm.uploadDumpFile(ctx, dir, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bm *BackupManager) uploadDumpFile(ctx context.Context, dir, name string) error {
+	p := filepath.Join(dir, name)
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	key := path.Join(bm.dumpKey, name)
+	r := newChecksumReader(f)
+	uploadStart := time.Now()
+	if err := bm.bucket.Put(ctx, key, r, fi.Size()); err != nil {
+		return fmt.Errorf("failed to put %s: %w", key, err)
+	}
+	bm.addUpload(r.Object().Size, time.Since(uploadStart))
+
+	bm.dumpObjects[path.Join(constants.DumpDirname, name)] = r.Object()
+	bm.dumpSize += r.Object().Size
+	if err := os.Remove(p); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", p, err)
+	}
+	return nil
+}
+
+// loadDumpStream downloads the files of a streamed dump and loads them.
+//
+// Loading starts as soon as the metadata files are downloaded, and data files are
+// loaded as they are downloaded.  The dump is marked complete by downloading
+// `@.done.json` last.  `files` are the names of the files relative to `dumpKey`.
+// If `expected` is not nil, each file must have a record in it.
+//
+// Data files are removed once mysqlsh has loaded them, and only a few of them
+// per thread are downloaded ahead of mysqlsh, so the work directory needs room
+// only for the metadata and these data files.
+func (rm *RestoreManager) loadDumpStream(ctx context.Context, op bkop.Operator, dumpKey string, files []string, expected map[string]ManifestObject) error {
+	var metadata, chunks []string
+	hasDone := false
+	for _, name := range files {
+		switch {
+		case name == constants.DumpDoneFilename:
+			hasDone = true
+		case isChunkFile(name):
+			chunks = append(chunks, name)
+		default:
+			metadata = append(metadata, name)
+		}
+	}
+	if !hasDone {
+		return fmt.Errorf("%s has no %s", dumpKey, constants.DumpDoneFilename)
+	}
+
+	dumpDir := filepath.Join(rm.workDir, constants.DumpDirname)
+	if err := os.MkdirAll(dumpDir, 0755); err != nil {
+		return fmt.Errorf("failed to make dump directory: %w", err)
+	}
+	progressFile := filepath.Join(rm.workDir, loadProgressFilename)
+	defer func() {
+		os.RemoveAll(dumpDir)
+		os.Remove(progressFile)
+	}()
+
+	var loaded int64
+	download := func(name string) error {
+		var obj *ManifestObject
+		if expected != nil {
+			o, ok := expected[name]
+			if !ok {
+				return fmt.Errorf("the manifest has no record of %s", path.Join(constants.DumpDirname, name))
+			}
+			obj = &o
+		}
+		size, err := rm.downloadDumpFile(ctx, path.Join(dumpKey, name), dumpDir, obj)
+		if err != nil {
+			return err
+		}
+		loaded += size
+		return nil
+	}
+
+	rm.setPhase(ctx, mocov1beta2.RestoreDownloading)
+	for _, name := range metadata {
+		if err := download(name); err != nil {
+			return err
+		}
+	}
+
+	rm.setPhase(ctx, mocov1beta2.RestoreLoadingDump)
+	loadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- op.LoadDumpStream(loadCtx, dumpDir, progressFile, rm.filter)
+	}()
+
+	threads := rm.threads
+	if threads < 1 {
+		threads = 1
+	}
+	tracker := newChunkTracker(dumpDir, progressFile, rm.filter)
+	for _, name := range append(chunks, constants.DumpDoneFilename) {
+		if isDataFile(name) {
+			for tracker.pending() >= threads*streamLoadAhead {
+				if err := tracker.update(); err != nil {
+					rm.log.Error(err, "failed to read the progress of loading")
+				}
+				if tracker.pending() < threads*streamLoadAhead {
+					break
+				}
+				if time.Since(tracker.lastProgress) > streamLoadStallTimeout {
+					rm.log.Info("no progress of loading is recorded; downloading the rest of the dump without waiting")
+					tracker.stalled = true
+					break
+				}
+
+				select {
+				case err := <-done:
+					if err == nil {
+						err = errors.New("mysqlsh exited before the dump is fully downloaded")
+					}
+					return err
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(streamLoadInterval):
+				}
+			}
+		}
+
+		select {
+		case err := <-done:
+			if err == nil {
+				err = errors.New("mysqlsh exited before the dump is fully downloaded")
+			}
+			return err
+		default:
+		}
+
+		if err := download(name); err != nil {
+			cancel()
+			<-done
+			return err
+		}
+		tracker.add(name)
+	}
+
+	if err := <-done; err != nil {
+		return err
+	}
+	rm.progress.LoadedBytes += loaded
+	return nil
+}
+
+const (
+	// loadProgressFilename is the name of the progress file of mysqlsh in the work directory.
+	loadProgressFilename = "load-progress.json"
+
+	// streamLoadAhead is the number of data files per thread downloaded ahead of mysqlsh.
+	streamLoadAhead = 2
+)
+
+var (
+	// streamLoadInterval is the interval to read the progress file while waiting for mysqlsh.
+	streamLoadInterval = time.Second
+
+	// streamLoadStallTimeout is the time for which loadDumpStream waits for mysqlsh
+	// to record progress.  After that, data files are downloaded without waiting
+	// until progress is recorded again, in case the progress file cannot be read.
+	streamLoadStallTimeout = 10 * time.Minute
+)
+
+// chunkKey identifies a chunk of table data in the progress file of mysqlsh.
+// chunk is -1 if the table is not chunked.
+type chunkKey struct {
+	schema    string
+	table     string
+	partition string
+	chunk     int64
+}
+
+// chunkTracker follows the progress file of mysqlsh and removes the data files
+// of a streamed dump that have been loaded.
+//
+// mysqlsh appends a JSON line like the following to the progress file
+// when it has loaded a chunk:
+//
+//	{"op":"TABLE-DATA","done":true,"schema":"db","table":"t","chunk":3,...}
+type chunkTracker struct {
+	dir          string
+	progressFile string
+	filter       *bkop.Filter
+	offset       int64
+
+	// files maps chunks to the names of their data and index files.
+	files map[chunkKey][]string
+
+	// downloaded is the number of data files to be loaded, and
+	// loaded is the number of chunks loaded by mysqlsh.
+	downloaded   int
+	loaded       int
+	lastProgress time.Time
+
+	// stalled is true while no progress is recorded for streamLoadStallTimeout.
+	stalled bool
+}
+
+func newChunkTracker(dir, progressFile string, filter *bkop.Filter) *chunkTracker {
+	return &chunkTracker{
+		dir:          dir,
+		progressFile: progressFile,
+		filter:       filter,
+		files:        make(map[chunkKey][]string),
+		lastProgress: time.Now(),
+	}
+}
+
+// add records a downloaded data file or index file.
+func (t *chunkTracker) add(name string) {
+	if !isChunkFile(name) {
+		return
+	}
+	keys := parseChunkFilename(name)
+	for _, k := range keys {
+		t.files[k] = append(t.files[k], name)
+	}
+	if !isDataFile(name) {
+		return
+	}
+	// data files of tables excluded by the filter are never loaded.
+	// Files whose names cannot be parsed are counted in case they are loaded.
+	for _, k := range keys {
+		if !t.filter.MatchTable(k.schema, k.table) {
+			return
+		}
+	}
+	t.downloaded++
+}
+
+// pending returns the number of data files that are not loaded yet.
+func (t *chunkTracker) pending() int {
+	if t.stalled || t.loaded >= t.downloaded {
+		return 0
+	}
+	return t.downloaded - t.loaded
+}
+
+// update reads the lines appended to the progress file since the last call,
+// and removes the files of the chunks loaded.
+func (t *chunkTracker) update() error {
+	f, err := os.Open(t.progressFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	// the last line may be being written.
+	n := bytes.LastIndexByte(data, '\n')
+	if n < 0 {
+		return nil
+	}
+	t.offset += int64(n + 1)
+
+	for _, line := range bytes.Split(data[:n], []byte("\n")) {
+		var entry struct {
+			Op        string `json:"op"`
+			Done      bool   `json:"done"`
+			Schema    string `json:"schema"`
+			Table     string `json:"table"`
+			Partition string `json:"partition"`
+			Chunk     *int64 `json:"chunk"`
+			Subchunk  *int64 `json:"subchunk"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		if entry.Op != "TABLE-DATA" || !entry.Done || entry.Subchunk != nil {
+			continue
+		}
+
+		t.loaded++
+		t.lastProgress = time.Now()
+		t.stalled = false
+		key := chunkKey{schema: entry.Schema, table: entry.Table, partition: entry.Partition, chunk: -1}
+		if entry.Chunk != nil {
+			key.chunk = *entry.Chunk
+		}
+		for _, name := range t.files[key] {
+			if err := os.Remove(filepath.Join(t.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		delete(t.files, key)
+	}
+	return nil
+}
+
+// parseChunkFilename returns the chunks that a data file or an index file may belong to.
+//
+// The names are like "schema@table@3.tsv.zst", "schema@table@@4.tsv.zst" for the last
+// chunk, "schema@table.tsv.zst" for a table not chunked, and "schema@table@partition@3.tsv.zst"
+// for a partition.  Special characters in the names are percent-encoded.
+// "schema@table@3.tsv.zst" may also be partition "3" not chunked, so both are returned.
+// Nothing is returned for names that cannot be parsed, such as shortened long names.
+func parseChunkFilename(name string) []chunkKey {
+	name = strings.TrimSuffix(name, ".idx")
+	name = strings.TrimSuffix(name, ".zst")
+	name = strings.TrimSuffix(name, ".tsv")
+
+	var fields []string
+	for _, f := range strings.Split(name, "@") {
+		decoded, err := url.PathUnescape(f)
+		if err != nil {
+			return nil
+		}
+		fields = append(fields, decoded)
+	}
+
+	var keys []chunkKey
+	addKey := func(fields []string, chunk int64) {
+		switch len(fields) {
+		case 2:
+			keys = append(keys, chunkKey{schema: fields[0], table: fields[1], chunk: chunk})
+		case 3:
+			keys = append(keys, chunkKey{schema: fields[0], table: fields[1], partition: fields[2], chunk: chunk})
+		}
+	}
+
+	addKey(fields, -1)
+	last := len(fields) - 1
+	if n, err := strconv.ParseInt(fields[last], 10, 64); err == nil && n >= 0 {
+		rest := fields[:last]
+		if len(rest) > 0 && rest[len(rest)-1] == "" {
+			rest = rest[:len(rest)-1]
+		}
+		addKey(rest, n)
+	}
+	return keys
+}
+
+// downloadDumpFile downloads `key` into `dir`.  The file is written with
+// the ".dumping" suffix and renamed when done, so that mysqlsh loading
+// the directory never reads a partial file.
+func (rm *RestoreManager) downloadDumpFile(ctx context.Context, key, dir string, expected *ManifestObject) (int64, error) {
+	rc, err := rm.bucket.Get(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+	defer rc.Close()
+	downloaded := rm.progress.DownloadedBytes
+	r := newChecksumReader(io.TeeReader(rc, &ByteCountWriter{Progress: rm.downloadProgress(ctx)}))
+
+	dst := filepath.Join(dir, path.Base(key))
+	f, err := os.Create(dst + ".dumping")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return 0, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	if expected != nil {
+		if err := r.Verify(*expected); err != nil {
+			return 0, fmt.Errorf("failed to verify %s: %w", key, err)
+		}
+	}
+	if err := os.Rename(dst+".dumping", dst); err != nil {
+		return 0, err
+	}
+	rm.progress.DownloadedBytes = downloaded + r.Object().Size
+	return r.Object().Size, nil
+}
@@ -0,0 +1,246 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// orderedBucket records the order of uploaded keys.
+type orderedBucket struct {
+	*mockBucket
+	keys []string
+}
+
+func (b *orderedBucket) Put(ctx context.Context, key string, r io.Reader, objectSize int64) error {
+	b.keys = append(b.keys, key)
+	return b.mockBucket.Put(ctx, key, r, objectSize)
+}
+
+func TestIsChunkFile(t *testing.T) {
+	testCases := []struct {
+		name  string
+		data  bool
+		chunk bool
+	}{
+		{"db@t@@0.tsv.zst", true, true},
+		{"db@t@@1.tsv", true, true},
+		{"db@t@@0.tsv.zst.idx", false, true},
+		{"db@t@@0.tsv.zst.dumping", false, false},
+		{"db@t.json", false, false},
+		{"db.sql", false, false},
+		{"@.json", false, false},
+		{"@.done.json", false, false},
+	}
+
+	for _, tc := range testCases {
+		if isDataFile(tc.name) != tc.data {
+			t.Errorf("isDataFile(%s) should be %v", tc.name, tc.data)
+		}
+		if isChunkFile(tc.name) != tc.chunk {
+			t.Errorf("isChunkFile(%s) should be %v", tc.name, tc.chunk)
+		}
+	}
+}
+
+func TestBackupFullStreaming(t *testing.T) {
+	b := &orderedBucket{mockBucket: &mockBucket{contents: make(map[string][]byte)}}
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	workDir := t.TempDir()
+	bm := &BackupManager{
+		log:       logr.Discard(),
+		cluster:   cluster,
+		bucket:    b,
+		workDir:   workDir,
+		streaming: true,
+		startTime: time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	op := &mockOperator{gtid: "gtid1"}
+	if err := bm.backupFullStreaming(context.Background(), op); err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := "moco/test/test/20220501-000000/dump"
+	if bm.dumpKey != prefix {
+		t.Errorf("unexpected dump key: %s", bm.dumpKey)
+	}
+	if bm.gtidSet != "gtid1" {
+		t.Errorf("unexpected GTID set: %s", bm.gtidSet)
+	}
+	expect := []string{prefix + "/@.json", prefix + "/dumpdata", prefix + "/@.done.json"}
+	if !cmp.Equal(b.keys, expect) {
+		t.Errorf("unexpected uploads: %s", cmp.Diff(expect, b.keys))
+	}
+
+	var names []string
+	var size int64
+	for k, obj := range bm.dumpObjects {
+		names = append(names, k)
+		size += obj.Size
+		if obj != makeObject(b.contents[prefix+strings.TrimPrefix(k, "dump")]) {
+			t.Errorf("unexpected object for %s: %+v", k, obj)
+		}
+	}
+	sort.Strings(names)
+	if !cmp.Equal(names, []string{"dump/@.done.json", "dump/@.json", "dump/dumpdata"}) {
+		t.Errorf("unexpected objects: %v", names)
+	}
+	if bm.dumpSize != size {
+		t.Errorf("unexpected dump size: %d", bm.dumpSize)
+	}
+
+	entries, err := os.ReadDir(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("files are left in the work dir: %v", entries)
+	}
+}
+
+func TestLoadDumpStream(t *testing.T) {
+	ctx := context.Background()
+	prefix := "moco/test/test/20220501-000000/dump"
+	files := map[string]string{
+		"@.json":              `{"gtidExecuted":"gtid1"}`,
+		"db@t.json":           "{}",
+		"db@t@0.tsv.zst":      "data0",
+		"db@t@0.tsv.zst.idx":  "index0",
+		"db@t@1.tsv.zst":      "data1",
+		"db@t@1.tsv.zst.idx":  "index1",
+		"db@t@2.tsv.zst":      "data2",
+		"db@t@2.tsv.zst.idx":  "index2",
+		"db@t@@3.tsv.zst":     "data3",
+		"db@t@@3.tsv.zst.idx": "index3",
+		"@.done.json":         "{}",
+	}
+
+	origInterval := streamLoadInterval
+	origTimeout := streamLoadStallTimeout
+	defer func() {
+		streamLoadInterval = origInterval
+		streamLoadStallTimeout = origTimeout
+	}()
+	streamLoadInterval = time.Millisecond
+
+	newRM := func() (*RestoreManager, []string, map[string]ManifestObject) {
+		b := &mockBucket{contents: make(map[string][]byte)}
+		var names []string
+		expected := make(map[string]ManifestObject)
+		for name, content := range files {
+			b.contents[prefix+"/"+name] = []byte(content)
+			names = append(names, name)
+			expected[name] = makeObject([]byte(content))
+		}
+		sort.Strings(names)
+		return &RestoreManager{
+			log:     logr.Discard(),
+			client:  fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build(),
+			bucket:  b,
+			workDir: t.TempDir(),
+			threads: 1,
+		}, names, expected
+	}
+
+	rm, names, expected := newRM()
+	op := &mockOperator{prepared: true}
+	if err := rm.loadDumpStream(ctx, op, prefix, names, expected); err != nil {
+		t.Fatal(err)
+	}
+	if !op.streamed {
+		t.Error("the dump was not loaded")
+	}
+	var total int64
+	for _, obj := range expected {
+		total += obj.Size
+	}
+	if rm.progress.DownloadedBytes != total || rm.progress.LoadedBytes != total {
+		t.Errorf("unexpected progress: %+v", rm.progress)
+	}
+	entries, err := os.ReadDir(rm.workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("files are left in the work dir: %v", entries)
+	}
+	// loaded data files are removed before downloading more.
+	if op.maxDataFiles > streamLoadAhead {
+		t.Errorf("too many data files are downloaded ahead: %d", op.maxDataFiles)
+	}
+
+	// mysqlsh that records no progress
+	streamLoadStallTimeout = 10 * time.Millisecond
+	rm, names, _ = newRM()
+	op = &mockOperator{prepared: true, noProgress: true}
+	if err := rm.loadDumpStream(ctx, op, prefix, names, nil); err != nil {
+		t.Error(err)
+	}
+	if op.maxDataFiles != 4 {
+		t.Errorf("all data files should be downloaded: %d", op.maxDataFiles)
+	}
+	streamLoadStallTimeout = origTimeout
+
+	// without manifest
+	rm, names, _ = newRM()
+	if err := rm.loadDumpStream(ctx, &mockOperator{prepared: true}, prefix, names, nil); err != nil {
+		t.Error(err)
+	}
+
+	rm, names, expected = newRM()
+	rm.bucket.(*mockBucket).contents[prefix+"/db@t@@3.tsv.zst"] = []byte("broken")
+	if err := rm.loadDumpStream(ctx, &mockOperator{prepared: true}, prefix, names, expected); err == nil {
+		t.Error("corrupted file should be detected")
+	}
+
+	rm, names, expected = newRM()
+	delete(expected, "db@t.json")
+	if err := rm.loadDumpStream(ctx, &mockOperator{prepared: true}, prefix, names, expected); err == nil {
+		t.Error("file without record should be detected")
+	}
+
+	rm, names, expected = newRM()
+	if err := rm.loadDumpStream(ctx, &mockOperator{prepared: true}, prefix, names[1:], expected); err == nil {
+		t.Error("incomplete dump should be detected")
+	}
+}
+
+func TestParseChunkFilename(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected []chunkKey
+	}{
+		{"db@t@3.tsv.zst", []chunkKey{
+			{schema: "db", table: "t", partition: "3", chunk: -1},
+			{schema: "db", table: "t", chunk: 3},
+		}},
+		{"db@t@@4.tsv.zst.idx", []chunkKey{{schema: "db", table: "t", chunk: 4}}},
+		{"db@t.tsv", []chunkKey{{schema: "db", table: "t", chunk: -1}}},
+		{"db@t@p0@2.tsv.zst", []chunkKey{{schema: "db", table: "t", partition: "p0", chunk: 2}}},
+		{"my%40db@a%20table@0.tsv.zst", []chunkKey{
+			{schema: "my@db", table: "a table", partition: "0", chunk: -1},
+			{schema: "my@db", table: "a table", chunk: 0},
+		}},
+		{"db@t%zz@0.tsv.zst", nil},
+	}
+
+	for _, tc := range testCases {
+		keys := parseChunkFilename(tc.name)
+		if !cmp.Equal(keys, tc.expected, cmp.AllowUnexported(chunkKey{})) {
+			t.Errorf("unexpected keys for %s: %s", tc.name, cmp.Diff(tc.expected, keys, cmp.AllowUnexported(chunkKey{})))
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cybozu-go/moco/pkg/bucket"
+	"github.com/go-logr/logr"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the compression level of archives.
+type Compression string
+
+// Compression levels.  Levels other than CompressionNone are those of zstd.
+const (
+	CompressionNone    = Compression("none")
+	CompressionFastest = Compression("fastest")
+	CompressionDefault = Compression("default")
+	CompressionBetter  = Compression("better")
+	CompressionBest    = Compression("best")
+)
+
+// ParseCompression parses the name of a compression level.
+func ParseCompression(s string) (Compression, error) {
+	switch c := Compression(s); c {
+	case CompressionNone, CompressionFastest, CompressionDefault, CompressionBetter, CompressionBest:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown compression level: %s", s)
+}
+
+func (c Compression) encoderLevel() zstd.EncoderLevel {
+	_, level := zstd.EncoderLevelFromString(string(c))
+	return level
+}
+
+// zstdMagic is the magic number at the beginning of zstd frames.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// writeArchive writes a tar archive of the directory `name` under `baseDir` to `w`.
+// The archive is compressed with zstd unless `c` is CompressionNone.
+// The uncompressed archive is also written to `progress` if not nil.
+func writeArchive(w io.Writer, baseDir, name string, c Compression, threads int, progress io.Writer) error {
+	if threads < 1 {
+		threads = 1
+	}
+
+	var zw *zstd.Encoder
+	if c != CompressionNone {
+		var err error
+		zw, err = zstd.NewWriter(w, zstd.WithEncoderLevel(c.encoderLevel()), zstd.WithEncoderConcurrency(threads))
+		if err != nil {
+			return fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+		defer zw.Close()
+		w = zw
+	}
+	if progress != nil {
+		w = io.MultiWriter(w, progress)
+	}
+
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(filepath.Join(baseDir, name), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(baseDir, p)
+		if err != nil {
+			return err
+		}
+		return addToArchive(tw, p, filepath.ToSlash(rel), d)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", name, err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish the tar archive: %w", err)
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("failed to finish the zstd stream: %w", err)
+		}
+	}
+	return nil
+}
+
+func addToArchive(tw *tar.Writer, p, name string, d fs.DirEntry) error {
+	fi, err := d.Info()
+	if err != nil {
+		return err
+	}
+	if !fi.Mode().IsRegular() && !fi.IsDir() {
+		return fmt.Errorf("unsupported file type: %s", p)
+	}
+
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if fi.IsDir() {
+		hdr.Name += "/"
+	}
+	hdr.Uname = ""
+	hdr.Gname = ""
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return nil
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// putArchive uploads a tar archive of the directory `name` under `workDir` to the bucket as `key`.
+// `usage` is the approximate size of the archive.
+func putArchive(ctx context.Context, b bucket.Bucket, key, workDir, name string, c Compression, threads int, usage int64, progress io.Writer) (ManifestObject, error) {
+	pr, pw := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		err := writeArchive(pw, workDir, name, c, threads, progress)
+		pw.CloseWithError(err)
+		errCh <- err
+	}()
+
+	cr := newChecksumReader(pr)
+	err := b.Put(ctx, key, cr, usage)
+	pr.Close()
+	werr := <-errCh
+	if err != nil {
+		return ManifestObject{}, fmt.Errorf("failed to put %s: %w", path.Base(key), err)
+	}
+	if werr != nil {
+		return ManifestObject{}, werr
+	}
+	return cr.Object(), nil
+}
+
+// progressInterval is the minimum interval between progress logs.
+const progressInterval = 30 * time.Second
+
+// progressLogger returns a function for ByteCountWriter.Progress that logs the
+// number of archived bytes at most once per progressInterval.
+// `total` is the approximate size of the files to be archived.
+func progressLogger(log logr.Logger, msg string, total int64) func(int64) {
+	last := time.Now()
+	return func(written int64) {
+		if time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+		log.Info(msg, "bytes", written, "total", total)
+	}
+}
+
+// archiveReader reads a tar archive that may be compressed with zstd.
+// Archives created by old versions are read as they are.
+type archiveReader struct {
+	*tar.Reader
+	r  io.Reader
+	zr *zstd.Decoder
+}
+
+func newArchiveReader(r io.Reader) (*archiveReader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	ar := &archiveReader{r: br}
+	if bytes.Equal(magic, zstdMagic) {
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+		}
+		ar.zr = zr
+		ar.r = zr
+	}
+	ar.Reader = tar.NewReader(ar.r)
+	return ar, nil
+}
+
+// drain reads the rest of the stream after the end of the tar archive
+// so that the whole object is checked.
+func (ar *archiveReader) drain() error {
+	_, err := io.Copy(io.Discard, ar.r)
+	return err
+}
+
+func (ar *archiveReader) Close() {
+	if ar.zr != nil {
+		ar.zr.Close()
+	}
+}
+
+// extractArchive extracts a tar archive read from `r` into `dir`.
+func extractArchive(r io.Reader, dir string) error {
+	ar, err := newArchiveReader(r)
+	if err != nil {
+		return err
+	}
+	defer ar.Close()
+
+	for {
+		hdr, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("broken tar archive: %w", err)
+		}
+
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("invalid file name in the archive: %s", hdr.Name)
+		}
+		p := filepath.Join(dir, filepath.FromSlash(name))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(p, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractFile(ar.Reader, p, hdr.FileInfo().Mode().Perm()); err != nil {
+				return fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+			}
+		default:
+			return fmt.Errorf("unsupported file type in the archive: %s", hdr.Name)
+		}
+	}
+	return ar.drain()
+}
+
+func extractFile(r io.Reader, p string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
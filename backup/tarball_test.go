@@ -0,0 +1,127 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func readFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestArchive(t *testing.T) {
+	srcDir := t.TempDir()
+	files := map[string]string{
+		"dump/@.json":        `{"gtidExecuted":"gtid1"}`,
+		"dump/foo/data.tsv":  "1\t2\t3\n",
+		"dump/foo/empty.tsv": "",
+	}
+	for name, content := range files {
+		p := filepath.Join(srcDir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// files outside of the directory must not be archived.
+	if err := os.WriteFile(filepath.Join(srcDir, "other"), []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []Compression{CompressionNone, CompressionFastest, CompressionDefault, CompressionBetter, CompressionBest} {
+		t.Run(string(c), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			bw := &ByteCountWriter{}
+			if err := writeArchive(buf, srcDir, "dump", c, 2, bw); err != nil {
+				t.Fatal(err)
+			}
+			compressed := bytes.HasPrefix(buf.Bytes(), zstdMagic)
+			if compressed != (c != CompressionNone) {
+				t.Errorf("unexpected compression: %v", compressed)
+			}
+			if c == CompressionNone && bw.Written() != int64(buf.Len()) {
+				t.Errorf("unexpected progress: %d", bw.Written())
+			}
+
+			dstDir := t.TempDir()
+			if err := extractArchive(buf, dstDir); err != nil {
+				t.Fatal(err)
+			}
+			if got := readFiles(t, dstDir); !cmp.Equal(got, files) {
+				t.Error("unexpected files", cmp.Diff(got, files))
+			}
+		})
+	}
+}
+
+func TestExtractArchive(t *testing.T) {
+	// archives created by tar command are read as well.
+	dstDir := t.TempDir()
+	data := makeTar(t, map[string]string{"binlog/binlog.000001": "binlog"})
+	if err := extractArchive(bytes.NewReader(data), dstDir); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"binlog/binlog.000001": "binlog"}
+	if got := readFiles(t, dstDir); !cmp.Equal(got, expected) {
+		t.Error("unexpected files", cmp.Diff(got, expected))
+	}
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "../escape", Mode: 0644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Write([]byte("a"))
+	tw.Close()
+	if err := extractArchive(buf, filepath.Join(dstDir, "sub")); err == nil {
+		t.Error("files outside of the directory should not be extracted")
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "escape")); err == nil {
+		t.Error("escape should not exist")
+	}
+}
+
+func TestParseCompression(t *testing.T) {
+	for _, s := range []string{"none", "fastest", "default", "better", "best"} {
+		c, err := ParseCompression(s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if string(c) != s {
+			t.Errorf("unexpected compression: %s", c)
+		}
+	}
+
+	if _, err := ParseCompression("gzip"); err == nil {
+		t.Error("gzip should not be accepted")
+	}
+}
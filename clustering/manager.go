@@ -43,6 +43,7 @@ func NewClusterManager(interval time.Duration, m manager.Manager, opf dbop.Opera
 }
 
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get
 
 type clusterManager struct {
 	client   client.Client
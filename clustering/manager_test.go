@@ -113,6 +113,9 @@ var _ = Describe("manager", func() {
 		ms.backupBinlogSize = metrics.BackupBinlogSize.WithLabelValues("test", "test")
 		ms.backupWorkDirUsage = metrics.BackupWorkDirUsage.WithLabelValues("test", "test")
 		ms.backupWarnings = metrics.BackupWarnings.WithLabelValues("test", "test")
+		ms.backupEarliestRestorable = metrics.BackupEarliestRestorable.WithLabelValues("test", "test")
+		ms.backupLatestRestorable = metrics.BackupLatestRestorable.WithLabelValues("test", "test")
+		ms.backupRestorableGaps = metrics.BackupRestorableGaps.WithLabelValues("test", "test")
 
 		var err error
 		mgr, err = ctrl.NewManager(cfg, ctrl.Options{
@@ -838,6 +841,11 @@ var _ = Describe("manager", func() {
 			cluster.Status.Backup.BinlogSize = 20
 			cluster.Status.Backup.WorkDirUsage = 30
 			cluster.Status.Backup.Warnings = []string{"aaa", "bbb"}
+			cluster.Status.Backup.EarliestRestorableTime = &metav1.Time{Time: time.Unix(1000, 0)}
+			cluster.Status.Backup.LatestRestorableTime = &metav1.Time{Time: time.Unix(5000, 0)}
+			cluster.Status.Backup.RestorableGaps = []mocov1beta2.RestorableGap{
+				{Start: metav1.Unix(2000, 0), End: metav1.Unix(3000, 0)},
+			}
 			return k8sClient.Status().Update(ctx, cluster)
 		}).Should(Succeed())
 
@@ -852,5 +860,8 @@ var _ = Describe("manager", func() {
 		Expect(ms.backupBinlogSize).To(MetricsIs("==", 20))
 		Expect(ms.backupWorkDirUsage).To(MetricsIs("==", 30))
 		Expect(ms.backupWarnings).To(MetricsIs("==", 2))
+		Expect(ms.backupEarliestRestorable).To(MetricsIs("==", 1000))
+		Expect(ms.backupLatestRestorable).To(MetricsIs("==", 5000))
+		Expect(ms.backupRestorableGaps).To(MetricsIs("==", 1))
 	})
 })
@@ -37,6 +37,34 @@ func init() {
 	waitForRestartDuration = interval
 }
 
+// annotateZones records the zone of the Node of each Pod in the Pod's annotation.
+// Backup Jobs read the annotation to choose the source instance by zone.
+func (p *managerProcess) annotateZones(ctx context.Context, ss *StatusSet) error {
+	for _, pod := range ss.Pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		// a Pod never moves to another Node.
+		if _, ok := pod.Annotations[constants.AnnZone]; ok {
+			continue
+		}
+
+		node := &corev1.Node{}
+		if err := p.reader.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+			return fmt.Errorf("failed to get node %s: %w", pod.Spec.NodeName, err)
+		}
+		modified := pod.DeepCopy()
+		if modified.Annotations == nil {
+			modified.Annotations = make(map[string]string)
+		}
+		modified.Annotations[constants.AnnZone] = node.Labels[corev1.LabelTopologyZone]
+		if err := p.client.Patch(ctx, modified, client.MergeFrom(pod)); err != nil {
+			return fmt.Errorf("failed to set zone for pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	return nil
+}
+
 func (p *managerProcess) isCloning(ctx context.Context, ss *StatusSet) bool {
 	pst := ss.MySQLStatus[ss.Primary]
 	if pst == nil {
@@ -37,6 +37,15 @@ type metricsSet struct {
 	backupBinlogSize   prometheus.Gauge
 	backupWorkDirUsage prometheus.Gauge
 	backupWarnings     prometheus.Gauge
+
+	backupEarliestRestorable prometheus.Gauge
+	backupLatestRestorable   prometheus.Gauge
+	backupRestorableGaps     prometheus.Gauge
+
+	drillTimestamp   prometheus.Gauge
+	drillSuccess     prometheus.Gauge
+	drillElapsed     prometheus.Gauge
+	drillLastSuccess prometheus.Gauge
 }
 
 type managerProcess struct {
@@ -81,6 +90,15 @@ func newManagerProcess(c client.Client, r client.Reader, recorder record.EventRe
 			backupBinlogSize:   metrics.BackupBinlogSize.WithLabelValues(name.Name, name.Namespace),
 			backupWorkDirUsage: metrics.BackupWorkDirUsage.WithLabelValues(name.Name, name.Namespace),
 			backupWarnings:     metrics.BackupWarnings.WithLabelValues(name.Name, name.Namespace),
+
+			backupEarliestRestorable: metrics.BackupEarliestRestorable.WithLabelValues(name.Name, name.Namespace),
+			backupLatestRestorable:   metrics.BackupLatestRestorable.WithLabelValues(name.Name, name.Namespace),
+			backupRestorableGaps:     metrics.BackupRestorableGaps.WithLabelValues(name.Name, name.Namespace),
+
+			drillTimestamp:   metrics.RestoreDrillTimestamp.WithLabelValues(name.Name, name.Namespace),
+			drillSuccess:     metrics.RestoreDrillSuccess.WithLabelValues(name.Name, name.Namespace),
+			drillElapsed:     metrics.RestoreDrillElapsed.WithLabelValues(name.Name, name.Namespace),
+			drillLastSuccess: metrics.RestoreDrillLastSuccess.WithLabelValues(name.Name, name.Namespace),
 		},
 		deleteMetrics: func() {
 			metrics.CheckCountVec.DeleteLabelValues(name.Name, name.Namespace)
@@ -98,6 +116,13 @@ func newManagerProcess(c client.Client, r client.Reader, recorder record.EventRe
 			metrics.BackupBinlogSize.DeleteLabelValues(name.Name, name.Namespace)
 			metrics.BackupWorkDirUsage.DeleteLabelValues(name.Name, name.Namespace)
 			metrics.BackupWarnings.DeleteLabelValues(name.Name, name.Namespace)
+			metrics.BackupEarliestRestorable.DeleteLabelValues(name.Name, name.Namespace)
+			metrics.BackupLatestRestorable.DeleteLabelValues(name.Name, name.Namespace)
+			metrics.BackupRestorableGaps.DeleteLabelValues(name.Name, name.Namespace)
+			metrics.RestoreDrillTimestamp.DeleteLabelValues(name.Name, name.Namespace)
+			metrics.RestoreDrillSuccess.DeleteLabelValues(name.Name, name.Namespace)
+			metrics.RestoreDrillElapsed.DeleteLabelValues(name.Name, name.Namespace)
+			metrics.RestoreDrillLastSuccess.DeleteLabelValues(name.Name, name.Namespace)
 		},
 	}
 }
@@ -155,6 +180,11 @@ func (p *managerProcess) do(ctx context.Context) (bool, error) {
 		return false, fmt.Errorf("failed to update status fields in MySQLCluster: %w", err)
 	}
 
+	// the zones are used only by backup Jobs, so failures do not stop clustering.
+	if err := p.annotateZones(ctx, ss); err != nil {
+		p.log.Error(err, "failed to annotate pods with their zones")
+	}
+
 	p.log.Info("cluster state is " + ss.State.String())
 	switch ss.State {
 	case StateCloning:
@@ -218,6 +248,23 @@ func (p *managerProcess) updateStatus(ctx context.Context, ss *StatusSet) error
 		p.metrics.backupWorkDirUsage.Set(float64(bs.WorkDirUsage))
 		p.metrics.backupWarnings.Set(float64(len(bs.Warnings)))
 	}
+	if bs.EarliestRestorableTime != nil && bs.LatestRestorableTime != nil {
+		p.metrics.backupEarliestRestorable.Set(float64(bs.EarliestRestorableTime.Unix()))
+		p.metrics.backupLatestRestorable.Set(float64(bs.LatestRestorableTime.Unix()))
+		p.metrics.backupRestorableGaps.Set(float64(len(bs.RestorableGaps)))
+	}
+	if ds := ss.Cluster.Status.RestoreDrill; ds != nil {
+		p.metrics.drillTimestamp.Set(float64(ds.Time.Unix()))
+		p.metrics.drillElapsed.Set(ds.Elapsed.Seconds())
+		if ds.Succeeded {
+			p.metrics.drillSuccess.Set(1)
+		} else {
+			p.metrics.drillSuccess.Set(0)
+		}
+		if ds.LastSuccessTime != nil {
+			p.metrics.drillLastSuccess.Set(float64(ds.LastSuccessTime.Unix()))
+		}
+	}
 
 	now := metav1.Now()
 	ststr := ss.State.String()
@@ -269,6 +316,14 @@ func (p *managerProcess) updateStatus(ctx context.Context, ss *StatusSet) error
 			updateCond(mocov1beta2.ConditionAvailable, available, cluster.Status.Conditions),
 			updateCond(mocov1beta2.ConditionHealthy, healthy, cluster.Status.Conditions),
 		}
+		// conditions set by others, such as the restore Job, are kept as they are.
+		for _, cond := range cluster.Status.Conditions {
+			switch cond.Type {
+			case mocov1beta2.ConditionInitialized, mocov1beta2.ConditionAvailable, mocov1beta2.ConditionHealthy:
+				continue
+			}
+			conditions = append(conditions, cond)
+		}
 		cluster.Status.Conditions = conditions
 		if available == corev1.ConditionTrue {
 			p.metrics.available.Set(1)
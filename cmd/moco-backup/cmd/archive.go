@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cybozu-go/moco/backup"
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var archiveArgs struct {
+	interval    time.Duration
+	compression string
+}
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive-binlog BUCKET NAMESPACE NAME",
+	Short: "archive binary logs of a MySQLCluster to an object storage bucket continuously",
+	Long: `Archive binary logs of a MySQLCluster continuously.
+
+This uploads binary logs executed since the last upload every interval
+until the process is terminated.  Archiving starts after the first
+full backup of the MySQLCluster.
+
+BUCKET:    The bucket name.
+NAMESPACE: The namespace of the MySQLCluster.
+NAME:      The name of the MySQLCluster.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkMySQLPassword(); err != nil {
+			return err
+		}
+		if archiveArgs.interval <= 0 {
+			return errors.New("interval must be positive")
+		}
+		compression, err := parseBinlogCompression(archiveArgs.compression)
+		if err != nil {
+			return err
+		}
+
+		bucketName := args[0]
+		namespace := args[1]
+		name := args[2]
+
+		b, err := makeBucket(bucketName)
+		if err != nil {
+			return fmt.Errorf("failed to create a bucket interface: %w", err)
+		}
+		secondaries, err := makeSecondaryBuckets()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := ctrl.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config for Kubernetes: %w", err)
+		}
+
+		ba, err := backup.NewBinlogArchiver(cfg, b, commonArgs.workDir, namespace, name, mysqlPassword, commonArgs.threads, archiveArgs.interval,
+			backup.WithSegmentCompression(compression), backup.WithArchiveSecondaryBuckets(secondaries))
+		if err != nil {
+			return fmt.Errorf("failed to create a binlog archiver: %w", err)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return ba.Run(ctx)
+	},
+}
+
+func init() {
+	fs := archiveCmd.Flags()
+	fs.DurationVar(&archiveArgs.interval, "interval", 5*time.Minute, "The interval to upload binary logs")
+	fs.StringVar(&archiveArgs.compression, "binlog-compression", string(backup.CompressionDefault), "The compression level of binlogs: fastest, default, better, or best")
+	addSecondaryBucketsFlag(fs)
+
+	rootCmd.AddCommand(archiveCmd)
+}
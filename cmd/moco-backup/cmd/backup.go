@@ -1,13 +1,42 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
 	"github.com/cybozu-go/moco/backup"
 	"github.com/spf13/cobra"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+var backupArgs struct {
+	keepLast    int
+	keepFor     time.Duration
+	keepDaily   int
+	keepWeekly  int
+	keepMonthly int
+	backupName  string
+
+	dumpCompression   string
+	binlogCompression string
+
+	sourceIndex    int
+	sourceZone     string
+	excludePrimary bool
+	preferLeastLag bool
+
+	hooks string
+
+	stream bool
+
+	snapshot      bool
+	snapshotClass string
+
+	binlogOnly bool
+}
+
 var backupCmd = &cobra.Command{
 	Use:   "backup BUCKET NAMESPACE NAME",
 	Short: "backup a MySQLCluster's data to an object storage bucket",
@@ -18,21 +47,87 @@ NAMESPACE: The namespace of the MySQLCluster.
 NAME:      The name of the MySQLCluster.`,
 	Args: cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkMySQLPassword(); err != nil {
+			return err
+		}
+
 		bucketName := args[0]
 		namespace := args[1]
 		name := args[2]
 
+		filter, err := makeFilter()
+		if err != nil {
+			return err
+		}
+		if backupArgs.snapshot && (filter != nil || backupArgs.stream) {
+			return fmt.Errorf("--snapshot cannot be used with filters or --stream")
+		}
+		if backupArgs.binlogOnly && (filter != nil || backupArgs.stream || backupArgs.snapshot || backupArgs.backupName != "") {
+			return fmt.Errorf("--binlog-only cannot be used with filters, --stream, --snapshot, or --backup-name")
+		}
+		dumpCompression, err := backup.ParseCompression(backupArgs.dumpCompression)
+		if err != nil {
+			return err
+		}
+		binlogCompression, err := parseBinlogCompression(backupArgs.binlogCompression)
+		if err != nil {
+			return err
+		}
+
 		b, err := makeBucket(bucketName)
 		if err != nil {
 			return fmt.Errorf("failed to create a bucket interface: %w", err)
 		}
+		secondaries, err := makeSecondaryBuckets()
+		if err != nil {
+			return err
+		}
 
 		cfg, err := ctrl.GetConfig()
 		if err != nil {
 			return fmt.Errorf("failed to get config for Kubernetes: %w", err)
 		}
 
-		bm, err := backup.NewBackupManager(cfg, b, commonArgs.workDir, namespace, name, mysqlPassword, commonArgs.threads)
+		retention := backup.Retention{
+			KeepLast:    backupArgs.keepLast,
+			KeepFor:     backupArgs.keepFor,
+			KeepDaily:   backupArgs.keepDaily,
+			KeepWeekly:  backupArgs.keepWeekly,
+			KeepMonthly: backupArgs.keepMonthly,
+		}
+		opts := []backup.BackupOption{
+			backup.WithRetention(retention),
+			backup.WithCompression(dumpCompression, binlogCompression),
+		}
+		if backupArgs.backupName != "" {
+			opts = append(opts, backup.WithBackupName(backupArgs.backupName))
+		}
+		if filter != nil {
+			opts = append(opts, backup.WithBackupFilter(filter))
+		}
+		if policy := makeSourcePolicy(); policy != nil {
+			opts = append(opts, backup.WithSourcePolicy(policy))
+		}
+		if backupArgs.hooks != "" {
+			hooks := &mocov1beta2.BackupHooks{}
+			if err := json.Unmarshal([]byte(backupArgs.hooks), hooks); err != nil {
+				return fmt.Errorf("failed to parse hooks: %w", err)
+			}
+			opts = append(opts, backup.WithHooks(hooks))
+		}
+		if backupArgs.stream {
+			opts = append(opts, backup.WithStreaming())
+		}
+		if backupArgs.snapshot {
+			opts = append(opts, backup.WithSnapshot(backupArgs.snapshotClass))
+		}
+		if backupArgs.binlogOnly {
+			opts = append(opts, backup.WithBinlogOnly())
+		}
+		if len(secondaries) > 0 {
+			opts = append(opts, backup.WithSecondaryBuckets(secondaries))
+		}
+		bm, err := backup.NewBackupManager(cfg, b, commonArgs.workDir, namespace, name, mysqlPassword, commonArgs.threads, opts...)
 		if err != nil {
 			return fmt.Errorf("failed to create a backup manager: %w", err)
 		}
@@ -41,5 +136,44 @@ NAME:      The name of the MySQLCluster.`,
 }
 
 func init() {
+	fs := backupCmd.Flags()
+	fs.IntVar(&backupArgs.keepLast, "keep-last", 0, "Keep the last N backups")
+	fs.DurationVar(&backupArgs.keepFor, "keep-for", 0, "Keep backups to restore data to any point within the duration")
+	fs.IntVar(&backupArgs.keepDaily, "keep-daily", 0, "Keep the last backup of each day for the last N days")
+	fs.IntVar(&backupArgs.keepWeekly, "keep-weekly", 0, "Keep the last backup of each week for the last N weeks")
+	fs.IntVar(&backupArgs.keepMonthly, "keep-monthly", 0, "Keep the last backup of each month for the last N months")
+	fs.StringVar(&backupArgs.backupName, "backup-name", "", "The name of the MySQLBackup to record the result")
+	fs.StringVar(&backupArgs.dumpCompression, "dump-compression", string(backup.CompressionFastest), "The compression level of the dump: none, fastest, default, better, or best")
+	fs.StringVar(&backupArgs.binlogCompression, "binlog-compression", string(backup.CompressionDefault), "The compression level of binlogs: fastest, default, better, or best")
+	fs.IntVar(&backupArgs.sourceIndex, "source-index", -1, "Take backups only from the instance of this index")
+	fs.StringVar(&backupArgs.sourceZone, "source-zone", "", "Take backups only from instances in this zone")
+	fs.BoolVar(&backupArgs.excludePrimary, "exclude-primary", false, "Fail instead of taking backups from the primary instance")
+	fs.BoolVar(&backupArgs.preferLeastLag, "prefer-least-lag", false, "Take backups from the replica with the lowest replication lag")
+	fs.StringVar(&backupArgs.hooks, "hooks", "", "The hooks run before and after the backup in JSON")
+	fs.BoolVar(&backupArgs.stream, "stream", false, "Upload the files of the dump while taking it instead of archiving them afterwards")
+	fs.BoolVar(&backupArgs.snapshot, "snapshot", false, "Take a VolumeSnapshot of the data volume of a replica instead of a full dump")
+	fs.StringVar(&backupArgs.snapshotClass, "snapshot-class", "", "The name of the VolumeSnapshotClass for --snapshot")
+	fs.BoolVar(&backupArgs.binlogOnly, "binlog-only", false, "Upload only the binlogs since the last backup without taking a full dump")
+	addFilterFlags(fs)
+	addSecondaryBucketsFlag(fs)
+
 	rootCmd.AddCommand(backupCmd)
 }
+
+// makeSourcePolicy returns nil if no flag for the source policy is given.
+func makeSourcePolicy() *backup.SourcePolicy {
+	if backupArgs.sourceIndex < 0 && backupArgs.sourceZone == "" && !backupArgs.excludePrimary && !backupArgs.preferLeastLag {
+		return nil
+	}
+
+	policy := &backup.SourcePolicy{
+		Zone:           backupArgs.sourceZone,
+		ExcludePrimary: backupArgs.excludePrimary,
+		PreferLeastLag: backupArgs.preferLeastLag,
+	}
+	if backupArgs.sourceIndex >= 0 {
+		index := backupArgs.sourceIndex
+		policy.Index = &index
+	}
+	return policy
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/backup"
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var drillArgs struct {
+	assertions string
+	timeout    time.Duration
+}
+
+var drillCmd = &cobra.Command{
+	Use:   "drill NAMESPACE NAME",
+	Short: "verify the latest backup by restoring it into a scratch MySQLCluster",
+	Long: `Restore the latest backup of a MySQLCluster into a scratch MySQLCluster,
+run assertions on the restored data, and delete the scratch MySQLCluster.
+The result is recorded in the status of the MySQLCluster.
+
+NAMESPACE: The namespace of the MySQLCluster.
+NAME:      The name of the MySQLCluster.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		namespace := args[0]
+		name := args[1]
+
+		var assertions []mocov1beta2.RestoreDrillAssertion
+		if drillArgs.assertions != "" {
+			if err := json.Unmarshal([]byte(drillArgs.assertions), &assertions); err != nil {
+				return fmt.Errorf("failed to parse assertions: %w", err)
+			}
+		}
+		if drillArgs.timeout <= 0 {
+			return fmt.Errorf("--timeout must be positive")
+		}
+
+		cfg, err := ctrl.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config for Kubernetes: %w", err)
+		}
+
+		dm, err := backup.NewDrillManager(cfg, namespace, name, assertions, drillArgs.timeout)
+		if err != nil {
+			return fmt.Errorf("failed to create a drill manager: %w", err)
+		}
+		return dm.Drill(cmd.Context())
+	},
+}
+
+func init() {
+	fs := drillCmd.Flags()
+	fs.StringVar(&drillArgs.assertions, "assertions", "", "The assertions run on the restored data in JSON")
+	fs.DurationVar(&drillArgs.timeout, "timeout", 6*time.Hour, "The time limit for the restoration and the assertions")
+
+	rootCmd.AddCommand(drillCmd)
+}
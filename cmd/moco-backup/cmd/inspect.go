@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cybozu-go/moco/backup"
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect BUCKET NAMESPACE NAME YYYYMMDD-hhmmss",
+	Short: "show the backup that a restoration would use",
+	Long: `Show the backup that a restoration to the given point-in-time would use.
+
+BUCKET:          The bucket name.
+NAMESPACE:       The namespace of the source MySQLCluster.
+NAME:            The name of the source MySQLCluster.
+YYYYMMDD-hhmmss: The point-in-time to restore data.  e.g. 20210523-150423`,
+	Args: cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		restorePoint, err := time.Parse(constants.BackupTimeFormat, args[3])
+		if err != nil {
+			return fmt.Errorf("invalid restore point %s: %w", args[3], err)
+		}
+
+		b, err := makeBucket(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to create a bucket interface: %w", err)
+		}
+
+		backups, err := backup.ListBackups(cmd.Context(), b, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		bi := backup.FindBackup(backups, restorePoint)
+		if bi == nil {
+			return fmt.Errorf("no available backup")
+		}
+
+		applyBinlog := !bi.Time.Equal(restorePoint) && (bi.BinlogKey != "" || len(bi.IncrementalBinlogKeys) > 0)
+		var segments int
+		if !bi.Time.Equal(restorePoint) {
+			for _, t := range bi.Segments {
+				segments++
+				if !t.Before(restorePoint) {
+					break
+				}
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Restore point:\t%s\n", restorePoint.Format(constants.BackupTimeFormat))
+		fmt.Fprintf(w, "Backup time:\t%s\n", bi.Time.Format(constants.BackupTimeFormat))
+		fmt.Fprintf(w, "Restorable until:\t%s\n", bi.Until.Format(constants.BackupTimeFormat))
+		if bi.VolumeSnapshotName != "" {
+			fmt.Fprintf(w, "Snapshot:\t%s\n", bi.VolumeSnapshotName)
+		} else {
+			fmt.Fprintf(w, "Dump:\t%s%s\n", bi.DumpKey, sizeNote(bi.DumpSize()))
+		}
+		if bi.BinlogKey != "" {
+			fmt.Fprintf(w, "Binlog:\t%s%s\n", bi.BinlogKey, sizeNote(bi.ObjectSize(constants.BinlogFilename)))
+		} else {
+			fmt.Fprintf(w, "Binlog:\tnone\n")
+		}
+		for _, key := range bi.IncrementalBinlogKeys {
+			fmt.Fprintf(w, "Binlog-only backup:\t%s%s\n", key, sizeNote(bi.ObjectSize(path.Base(key))))
+		}
+		fmt.Fprintf(w, "Apply binlog:\t%v\n", applyBinlog)
+		fmt.Fprintf(w, "Binlog segments to apply:\t%d of %d\n", segments, len(bi.Segments))
+		if m := bi.Manifest; m != nil {
+			fmt.Fprintf(w, "Source:\t%s/%s (index %d, server_uuid %s)\n", m.Namespace, m.Name, m.SourceIndex, m.SourceUUID)
+			fmt.Fprintf(w, "MySQL version:\t%s\n", m.MySQLVersion)
+			fmt.Fprintf(w, "Binlog filename:\t%s\n", m.BinlogFilename)
+			fmt.Fprintf(w, "GTID set:\t%s\n", m.GTIDSet)
+		} else {
+			fmt.Fprintf(w, "Manifest:\tnone\n")
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if restorePoint.After(bi.Until) {
+			fmt.Fprintf(os.Stderr, "WARNING: transactions after %s cannot be restored\n", bi.Until.Format(constants.BackupTimeFormat))
+		}
+		return nil
+	},
+}
+
+func sizeNote(size int64) string {
+	if size < 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d bytes)", size)
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+}
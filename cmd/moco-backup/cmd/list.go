@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/cybozu-go/moco/backup"
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list BUCKET NAMESPACE NAME",
+	Short: "list backups of a MySQLCluster",
+	Long: `List backups of a MySQLCluster stored in a bucket.
+
+BUCKET:    The bucket name.
+NAMESPACE: The namespace of the MySQLCluster.
+NAME:      The name of the MySQLCluster.
+
+Each backup can restore data to any point-in-time between TIME and UNTIL.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := makeBucket(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to create a bucket interface: %w", err)
+		}
+
+		backups, err := backup.ListBackups(cmd.Context(), b, args[1], args[2])
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tUNTIL\tDUMP SIZE\tBINLOG SIZE\tGTID SET")
+		for _, bi := range backups {
+			gtid := "-"
+			if bi.Manifest != nil {
+				gtid = bi.Manifest.GTIDSet
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				bi.Time.Format(constants.BackupTimeFormat),
+				bi.Until.Format(constants.BackupTimeFormat),
+				formatDumpSize(bi),
+				formatBinlogSize(bi),
+				gtid)
+		}
+		return w.Flush()
+	},
+}
+
+func formatSize(size int64) string {
+	if size < 0 {
+		return "-"
+	}
+	return fmt.Sprint(size)
+}
+
+func formatDumpSize(bi *backup.BackupInfo) string {
+	if bi.VolumeSnapshotName != "" {
+		return "snapshot"
+	}
+	return formatSize(bi.DumpSize())
+}
+
+func formatBinlogSize(bi *backup.BackupInfo) string {
+	if bi.BinlogKey == "" {
+		return "none"
+	}
+	return formatSize(bi.ObjectSize(constants.BinlogFilename))
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
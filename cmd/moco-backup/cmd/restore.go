@@ -6,11 +6,17 @@ import (
 	"time"
 
 	"github.com/cybozu-go/moco/backup"
+	"github.com/cybozu-go/moco/pkg/bkop"
 	"github.com/cybozu-go/moco/pkg/constants"
 	"github.com/spf13/cobra"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+var restoreArgs struct {
+	stopGTID string
+	snapshot string
+}
+
 var restoreCmd = &cobra.Command{
 	Use:   "restore BUCKET SOURCE_NAMESPACE SOURCE_NAME NAMESPACE NAME YYYYMMDD-hhmmss",
 	Short: "restore MySQL data from a backup",
@@ -24,6 +30,10 @@ NAME:             The target MySQLCluster's name.
 YYYYMMDD-hhmmss:  The point-in-time to restore data.  e.g. 20210523-150423`,
 	Args: cobra.ExactArgs(6),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkMySQLPassword(); err != nil {
+			return err
+		}
+
 		maxRetry := 3
 		for i := 0; i < maxRetry; i++ {
 			if err := runRestore(cmd, args); err != backup.ErrBadConnection {
@@ -60,6 +70,28 @@ func runRestore(cmd *cobra.Command, args []string) (e error) {
 		return fmt.Errorf("invalid restore point %s: %w", args[5], err)
 	}
 
+	var opts []backup.RestoreOption
+	if restoreArgs.stopGTID != "" {
+		set, err := bkop.ParseGTIDSet(restoreArgs.stopGTID)
+		if err != nil {
+			return fmt.Errorf("invalid stop GTID set %s: %w", restoreArgs.stopGTID, err)
+		}
+		opts = append(opts, backup.WithStopGTIDSet(set))
+	}
+	filter, err := makeFilter()
+	if err != nil {
+		return err
+	}
+	if filter != nil {
+		opts = append(opts, backup.WithRestoreFilter(filter))
+	}
+	if restoreArgs.snapshot != "" {
+		if filter != nil {
+			return fmt.Errorf("--snapshot cannot be used with filters")
+		}
+		opts = append(opts, backup.WithRestoreSnapshot(restoreArgs.snapshot))
+	}
+
 	b, err := makeBucket(bucketName)
 	if err != nil {
 		return fmt.Errorf("failed to create a bucket interface: %w", err)
@@ -75,7 +107,8 @@ func runRestore(cmd *cobra.Command, args []string) (e error) {
 		namespace, name,
 		mysqlPassword,
 		commonArgs.threads,
-		restorePoint)
+		restorePoint,
+		opts...)
 	if err != nil {
 		return fmt.Errorf("failed to create a restore manager: %w", err)
 	}
@@ -83,5 +116,10 @@ func runRestore(cmd *cobra.Command, args []string) (e error) {
 }
 
 func init() {
+	fs := restoreCmd.Flags()
+	fs.StringVar(&restoreArgs.stopGTID, "stop-gtid", "", "Stop before the first transaction in the GTID set")
+	fs.StringVar(&restoreArgs.snapshot, "snapshot", "", "The VolumeSnapshot from which the data volume has been provisioned; only binlogs are applied")
+	addFilterFlags(fs)
+
 	rootCmd.AddCommand(restoreCmd)
 }
@@ -1,41 +1,278 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/cybozu-go/moco"
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/backup"
+	"github.com/cybozu-go/moco/pkg/bkop"
 	"github.com/cybozu-go/moco/pkg/bucket"
+	"github.com/cybozu-go/moco/pkg/constants"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/api/option"
 )
 
 var commonArgs struct {
-	workDir      string
-	threads      int
+	workDir string
+	threads int
+	keyDir  string
+	keyID   string
+
+	bucketArgs
+}
+
+// bucketArgs is a set of parameters to access a bucket.
+type bucketArgs struct {
 	region       string
 	endpointURL  string
 	usePathStyle bool
+	backendType  string
+	bucketDir    string
+	accountName  string
+
+	uploadConcurrency int
+	partSize          int64
+	rateLimit         int64
+
+	sse                 string
+	kmsKeyID            string
+	storageClass        string
+	tags                map[string]string
+	objectLockMode      string
+	objectLockRetainFor time.Duration
+
+	caBundle   string
+	clientCert string
+	clientKey  string
 }
 
 func makeBucket(bucketName string) (bucket.Bucket, error) {
+	return makeBucketWithArgs(&commonArgs.bucketArgs, bucketName)
+}
+
+func makeBucketWithArgs(ba *bucketArgs, bucketName string) (bucket.Bucket, error) {
+	b, err := ba.makeBackendBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if ba.rateLimit > 0 {
+		b = bucket.NewThrottledBucket(b, ba.rateLimit)
+	}
+	if commonArgs.keyDir == "" {
+		if commonArgs.keyID != "" {
+			return nil, errors.New("--encryption-key-id requires --encryption-key-dir")
+		}
+		return b, nil
+	}
+
+	keys, err := bucket.ReadKeyDir(commonArgs.keyDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption keys: %w", err)
+	}
+	return bucket.NewEncryptedBucket(b, commonArgs.keyID, keys)
+}
+
+func (ba *bucketArgs) makeBackendBucket(bucketName string) (bucket.Bucket, error) {
+	switch ba.backendType {
+	case constants.BackendTypeS3:
+		return ba.makeS3Bucket(bucketName)
+	case constants.BackendTypeGCS:
+		return ba.makeGCSBucket(bucketName)
+	case constants.BackendTypeAzure:
+		return ba.makeAzureBucket(bucketName)
+	case constants.BackendTypeFile:
+		return bucket.NewFileBucket(filepath.Join(ba.bucketDir, bucketName))
+	}
+	return nil, fmt.Errorf("unknown backend type: %s", ba.backendType)
+}
+
+func (ba *bucketArgs) makeS3Bucket(bucketName string) (bucket.Bucket, error) {
 	var opts []func(*s3.Options)
-	if len(commonArgs.region) > 0 {
-		opts = append(opts, bucket.WithRegion(commonArgs.region))
+	if len(ba.region) > 0 {
+		opts = append(opts, bucket.WithRegion(ba.region))
 	}
-	if len(commonArgs.endpointURL) > 0 {
-		opts = append(opts, bucket.WithEndpointURL(commonArgs.endpointURL))
+	if len(ba.endpointURL) > 0 {
+		opts = append(opts, bucket.WithEndpointURL(ba.endpointURL))
 	}
-	if commonArgs.usePathStyle {
+	if ba.usePathStyle {
 		opts = append(opts, bucket.WithPathStyle())
 	}
-	return bucket.NewS3Bucket(bucketName, opts...)
+	if ba.caBundle != "" || ba.clientCert != "" {
+		c, err := bucket.NewTLSHTTPClient(ba.caBundle, ba.clientCert, ba.clientKey)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, bucket.WithHTTPClient(c))
+	}
+	uo := bucket.S3UploadOptions{
+		Concurrency:          ba.uploadConcurrency,
+		PartSize:             ba.partSize,
+		ServerSideEncryption: ba.sse,
+		KMSKeyID:             ba.kmsKeyID,
+		StorageClass:         ba.storageClass,
+		Tags:                 ba.tags,
+		ObjectLockMode:       ba.objectLockMode,
+		ObjectLockRetention:  ba.objectLockRetainFor,
+	}
+	return bucket.NewS3BucketWithUploadOptions(bucketName, uo, opts...)
+}
+
+func (ba *bucketArgs) makeGCSBucket(bucketName string) (bucket.Bucket, error) {
+	var opts []option.ClientOption
+	if len(ba.endpointURL) > 0 {
+		opts = append(opts, option.WithEndpoint(ba.endpointURL))
+	}
+	return bucket.NewGCSBucket(bucketName, opts...)
+}
+
+func (ba *bucketArgs) makeAzureBucket(bucketName string) (bucket.Bucket, error) {
+	if len(ba.accountName) == 0 {
+		return nil, errors.New("--account-name is required for azure backend")
+	}
+	serviceURL := ba.endpointURL
+	if len(serviceURL) == 0 {
+		serviceURL = fmt.Sprintf("https://%s.blob.core.windows.net/", ba.accountName)
+	}
+	containerURL := strings.TrimSuffix(serviceURL, "/") + "/" + bucketName
+
+	if key := os.Getenv("AZURE_STORAGE_KEY"); len(key) > 0 {
+		return bucket.NewAzureBucketWithSharedKey(containerURL, ba.accountName, key)
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+	return bucket.NewAzureBucket(containerURL, cred)
+}
+
+var secondaryBucketsArg string
+
+// addSecondaryBucketsFlag adds a flag to copy backups to secondary buckets.
+func addSecondaryBucketsFlag(fs *pflag.FlagSet) {
+	fs.StringVar(&secondaryBucketsArg, "secondary-buckets", "", "The buckets to copy backups to in JSON")
+}
+
+// makeSecondaryBuckets creates the buckets given by --secondary-buckets.
+// They use the same encryption keys as the primary bucket.
+func makeSecondaryBuckets() ([]backup.SecondaryBucket, error) {
+	if secondaryBucketsArg == "" {
+		return nil, nil
+	}
+
+	var specs []mocov1beta2.SecondaryBucket
+	if err := json.Unmarshal([]byte(secondaryBucketsArg), &specs); err != nil {
+		return nil, fmt.Errorf("failed to parse secondary buckets: %w", err)
+	}
+
+	buckets := make([]backup.SecondaryBucket, 0, len(specs))
+	for _, spec := range specs {
+		ba, err := secondaryBucketArgs(spec.BucketConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secondary bucket %s: %w", spec.Name, err)
+		}
+		b, err := makeBucketWithArgs(ba, spec.BucketConfig.BucketName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create secondary bucket %s: %w", spec.Name, err)
+		}
+		buckets = append(buckets, backup.SecondaryBucket{Name: spec.Name, Bucket: b})
+	}
+	return buckets, nil
+}
+
+// secondaryBucketArgs converts the config of a secondary bucket to the parameters.
+func secondaryBucketArgs(bc mocov1beta2.BucketConfig) (*bucketArgs, error) {
+	if bc.BackendType == constants.BackendTypeFile || bc.TLS != nil {
+		return nil, errors.New("file backend and tls are not supported")
+	}
+
+	ba := &bucketArgs{
+		region:            bc.Region,
+		endpointURL:       bc.EndpointURL,
+		usePathStyle:      bc.UsePathStyle,
+		backendType:       bc.BackendType,
+		accountName:       bc.AccountName,
+		uploadConcurrency: bc.UploadConcurrency,
+		sse:               bc.ServerSideEncryption,
+		kmsKeyID:          bc.KMSKeyID,
+		storageClass:      bc.StorageClass,
+		tags:              bc.Tags,
+	}
+	if ba.backendType == "" {
+		ba.backendType = constants.BackendTypeS3
+	}
+	if ba.uploadConcurrency == 0 {
+		ba.uploadConcurrency = 1
+	}
+	if bc.PartSize != nil {
+		ba.partSize = bc.PartSize.Value()
+	}
+	if bc.RateLimit != nil {
+		ba.rateLimit = bc.RateLimit.Value()
+	}
+	if bc.ObjectLock != nil {
+		ba.objectLockMode = bc.ObjectLock.Mode
+		ba.objectLockRetainFor = bc.ObjectLock.RetainFor.Duration
+	}
+	return ba, nil
+}
+
+var filterArgs bkop.Filter
+
+// addFilterFlags adds flags to filter schemas and tables for backup and restore.
+func addFilterFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&filterArgs.IncludeSchemas, "include-schemas", nil, "The schemas to be included")
+	fs.StringSliceVar(&filterArgs.ExcludeSchemas, "exclude-schemas", nil, "The schemas to be excluded")
+	fs.StringSliceVar(&filterArgs.IncludeTables, "include-tables", nil, "The tables to be included in the form of SCHEMA.TABLE")
+	fs.StringSliceVar(&filterArgs.ExcludeTables, "exclude-tables", nil, "The tables to be excluded in the form of SCHEMA.TABLE")
+}
+
+// makeFilter returns the filter given by the flags, or nil.
+func makeFilter() (*bkop.Filter, error) {
+	if filterArgs.IsEmpty() {
+		return nil, nil
+	}
+	for _, t := range append(filterArgs.IncludeTables, filterArgs.ExcludeTables...) {
+		if !strings.Contains(t, ".") {
+			return nil, fmt.Errorf("invalid table name %s; tables must be specified in the form of SCHEMA.TABLE", t)
+		}
+	}
+	return &filterArgs, nil
+}
+
+// parseBinlogCompression parses the compression level of binlog archives.
+// Binlog archives are always compressed as their keys end with ".zst".
+func parseBinlogCompression(s string) (backup.Compression, error) {
+	c, err := backup.ParseCompression(s)
+	if err != nil {
+		return "", err
+	}
+	if c == backup.CompressionNone {
+		return "", errors.New("binlog archives must be compressed")
+	}
+	return c, nil
 }
 
 var mysqlPassword = os.Getenv("MYSQL_PASSWORD")
 
+// checkMySQLPassword should be called by subcommands that connect to mysqld.
+func checkMySQLPassword() error {
+	if len(mysqlPassword) == 0 {
+		return errors.New("no MYSQL_PASSWORD environment variable")
+	}
+	return nil
+}
+
 var rootCmd = &cobra.Command{
 	Use:     "moco-backup",
 	Version: moco.Version,
@@ -44,9 +281,25 @@ var rootCmd = &cobra.Command{
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
-		if len(mysqlPassword) == 0 {
-			return errors.New("no MYSQL_PASSWORD environment variable")
+		if commonArgs.uploadConcurrency < 0 {
+			return fmt.Errorf("invalid upload concurrency %d", commonArgs.uploadConcurrency)
+		}
+		if commonArgs.partSize != 0 && (commonArgs.partSize < 5<<20 || commonArgs.partSize > 5<<30) {
+			return fmt.Errorf("invalid part size %d; it must be between 5 MiB and 5 GiB", commonArgs.partSize)
+		}
+		if commonArgs.rateLimit < 0 {
+			return fmt.Errorf("invalid rate limit %d", commonArgs.rateLimit)
 		}
+		if commonArgs.kmsKeyID != "" && commonArgs.sse != "aws:kms" {
+			return errors.New("--kms-key-id requires --sse=aws:kms")
+		}
+		if commonArgs.objectLockMode != "" && commonArgs.objectLockRetainFor <= 0 {
+			return errors.New("--object-lock-mode requires positive --object-lock-retain-for")
+		}
+		if (commonArgs.clientCert == "") != (commonArgs.clientKey == "") {
+			return errors.New("--client-cert and --client-key must be specified together")
+		}
+
 		if len(commonArgs.endpointURL) > 0 {
 			_, err := url.Parse(commonArgs.endpointURL)
 			if err != nil {
@@ -74,6 +327,23 @@ func init() {
 	pf.StringVar(&commonArgs.workDir, "work-dir", "/work", "The writable working directory")
 	pf.IntVar(&commonArgs.threads, "threads", 4, "The number of threads to be used")
 	pf.StringVar(&commonArgs.region, "region", "", "AWS region")
-	pf.StringVar(&commonArgs.endpointURL, "endpoint", "", "S3 API endpoint URL")
+	pf.StringVar(&commonArgs.endpointURL, "endpoint", "", "S3, GCS, or Azure Blob API endpoint URL")
 	pf.BoolVar(&commonArgs.usePathStyle, "use-path-style", false, "Use path-style S3 API")
+	pf.StringVar(&commonArgs.backendType, "backend-type", constants.BackendTypeS3, "The storage backend type: s3, gcs, azure, or file")
+	pf.StringVar(&commonArgs.accountName, "account-name", "", "Azure storage account name")
+	pf.StringVar(&commonArgs.bucketDir, "bucket-dir", constants.BucketVolumeMountPath, "The directory where the volume for file backend is mounted")
+	pf.StringVar(&commonArgs.keyDir, "encryption-key-dir", "", "The directory of encryption key files.  If set, encrypted backups can be read")
+	pf.StringVar(&commonArgs.keyID, "encryption-key-id", "", "The ID of the key to encrypt backups")
+	pf.IntVar(&commonArgs.uploadConcurrency, "upload-concurrency", 1, "The number of parts uploaded in parallel to S3")
+	pf.Int64Var(&commonArgs.partSize, "part-size", 0, "The part size in bytes of multi-part uploads to S3.  If 0, it is decided from the object size")
+	pf.Int64Var(&commonArgs.rateLimit, "rate-limit", 0, "The limit of the transfer rate in bytes per second.  If 0, the rate is not limited")
+	pf.StringVar(&commonArgs.sse, "sse", "", "The server-side encryption algorithm of S3 objects: AES256 or aws:kms")
+	pf.StringVar(&commonArgs.kmsKeyID, "kms-key-id", "", "The ID of the AWS KMS key for aws:kms server-side encryption")
+	pf.StringVar(&commonArgs.storageClass, "storage-class", "", "The storage class of S3 objects")
+	pf.StringToStringVar(&commonArgs.tags, "tags", nil, "The tags of S3 objects in the form of KEY=VALUE")
+	pf.StringVar(&commonArgs.objectLockMode, "object-lock-mode", "", "The S3 Object Lock retention mode: GOVERNANCE or COMPLIANCE")
+	pf.DurationVar(&commonArgs.objectLockRetainFor, "object-lock-retain-for", 0, "The period to retain S3 objects with Object Lock")
+	pf.StringVar(&commonArgs.caBundle, "ca-bundle", "", "The file of PEM-encoded CA certificates to verify the S3 API endpoint")
+	pf.StringVar(&commonArgs.clientCert, "client-cert", "", "The file of the PEM-encoded client certificate for the S3 API endpoint")
+	pf.StringVar(&commonArgs.clientKey, "client-key", "", "The file of the PEM-encoded private key of the client certificate")
 }
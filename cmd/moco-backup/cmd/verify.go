@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cybozu-go/moco/backup"
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify BUCKET NAMESPACE NAME [YYYYMMDD-hhmmss]",
+	Short: "verify the integrity of backups",
+	Long: `Download backups of a MySQLCluster and verify their integrity.
+
+BUCKET:          The bucket name.
+NAMESPACE:       The namespace of the MySQLCluster.
+NAME:            The name of the MySQLCluster.
+YYYYMMDD-hhmmss: The time of the backup to be verified.
+                 If not given, all backups are verified.`,
+	Args: cobra.RangeArgs(3, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var target time.Time
+		if len(args) == 4 {
+			t, err := time.Parse(constants.BackupTimeFormat, args[3])
+			if err != nil {
+				return fmt.Errorf("invalid backup time %s: %w", args[3], err)
+			}
+			target = t
+		}
+
+		b, err := makeBucket(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to create a bucket interface: %w", err)
+		}
+
+		backups, err := backup.ListBackups(cmd.Context(), b, args[1], args[2])
+		if err != nil {
+			return err
+		}
+
+		verified := 0
+		failed := 0
+		for _, bi := range backups {
+			if !target.IsZero() && !bi.Time.Equal(target) {
+				continue
+			}
+
+			verified++
+			if err := backup.VerifyBackup(cmd.Context(), b, bi, commonArgs.workDir); err != nil {
+				failed++
+				fmt.Printf("%s: NG: %v\n", bi.Time.Format(constants.BackupTimeFormat), err)
+				continue
+			}
+			if bi.Manifest == nil {
+				fmt.Printf("%s: OK (no manifest)\n", bi.Time.Format(constants.BackupTimeFormat))
+				continue
+			}
+			fmt.Printf("%s: OK\n", bi.Time.Format(constants.BackupTimeFormat))
+		}
+
+		if verified == 0 {
+			return fmt.Errorf("no backup found")
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d backups are broken", failed, verified)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
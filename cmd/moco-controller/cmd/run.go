@@ -102,6 +102,16 @@ func subMain(ns, addr string, port int) error {
 		return err
 	}
 
+	if err = (&controllers.MySQLBackupReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		BackupImage: config.backupImage,
+		APIReader:   mgr.GetAPIReader(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MySQLBackup")
+		return err
+	}
+
 	if err = (&controllers.PodWatcher{
 		Client:         mgr.GetClient(),
 		ClusterManager: clusterMgr,
@@ -125,6 +135,11 @@ func subMain(ns, addr string, port int) error {
 		return err
 	}
 
+	if err = (&mocov1beta2.MySQLBackup{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup webhook", "webhook", "MySQLBackup")
+		return err
+	}
+
 	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		return err
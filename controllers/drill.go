@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// reconcileV1RestoreDrillCluster creates the scratch MySQLCluster for a restore drill
+// while the drill Job requests it in the status of `cluster`, and deletes it afterwards.
+//
+// The restore drill Job is not allowed to create MySQLClusters because
+// `spec.restore.jobConfig` of a MySQLCluster lets it run a Job with any ServiceAccount.
+// A MySQLCluster that has the name of the scratch MySQLCluster but is not
+// controlled by `cluster` is never touched.  Its PVCs are deleted by the garbage
+// collector as they are owned by the scratch MySQLCluster.
+func (r *MySQLClusterReconciler) reconcileV1RestoreDrillCluster(ctx context.Context, cluster *mocov1beta2.MySQLCluster, bp *mocov1beta2.BackupPolicy) error {
+	log := crlog.FromContext(ctx)
+
+	name := cluster.RestoreDrillClusterName()
+	scratch := &mocov1beta2.MySQLCluster{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, scratch)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get MySQLCluster %s/%s: %w", cluster.Namespace, name, err)
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(scratch, cluster) {
+		if cluster.Status.RestoreDrillRequest != nil {
+			log.Info("MySQLCluster for the restore drill is not controlled by this cluster", "name", name)
+		}
+		return nil
+	}
+
+	req := cluster.Status.RestoreDrillRequest
+	if bp == nil || bp.Spec.RestoreDrill == nil || req == nil {
+		if !exists || scratch.DeletionTimestamp != nil {
+			return nil
+		}
+		if err := r.Delete(ctx, scratch); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the scratch MySQLCluster %s/%s: %w", cluster.Namespace, name, err)
+		}
+		log.Info("deleted the scratch MySQLCluster for restore drill", "name", name)
+		return nil
+	}
+	if exists {
+		return nil
+	}
+
+	scratch = newScratchCluster(cluster, bp, req.RestorePoint.Time)
+	if err := ctrl.SetControllerReference(cluster, scratch, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set ownerReference to MySQLCluster %s/%s: %w", cluster.Namespace, name, err)
+	}
+	if err := r.Create(ctx, scratch); err != nil {
+		return fmt.Errorf("failed to create the scratch MySQLCluster %s/%s: %w", cluster.Namespace, name, err)
+	}
+	log.Info("created the scratch MySQLCluster for restore drill", "name", name, "restorePoint", req.RestorePoint)
+	return nil
+}
+
+// newScratchCluster returns a MySQLCluster with one instance that restores
+// the backup of `cluster` to `restorePoint`.
+func newScratchCluster(cluster *mocov1beta2.MySQLCluster, bp *mocov1beta2.BackupPolicy, restorePoint time.Time) *mocov1beta2.MySQLCluster {
+	scratch := &mocov1beta2.MySQLCluster{}
+	scratch.Namespace = cluster.Namespace
+	scratch.Name = cluster.RestoreDrillClusterName()
+	scratch.Spec = mocov1beta2.MySQLClusterSpec{
+		Replicas:             1,
+		PodTemplate:          *cluster.Spec.PodTemplate.DeepCopy(),
+		VolumeClaimTemplates: cluster.Spec.DeepCopy().VolumeClaimTemplates,
+		MySQLConfigMapName:   cluster.Spec.MySQLConfigMapName,
+		ServerIDBase:         cluster.Spec.ServerIDBase,
+		StartupWaitSeconds:   cluster.Spec.StartupWaitSeconds,
+		Restore: &mocov1beta2.RestoreSpec{
+			SourceName:         cluster.Name,
+			SourceNamespace:    cluster.Namespace,
+			RestorePoint:       metav1.NewTime(restorePoint),
+			VolumeSnapshotName: cluster.Status.Backup.VolumeSnapshotName,
+			JobConfig:          *bp.Spec.JobConfig.DeepCopy(),
+		},
+	}
+	return scratch
+}
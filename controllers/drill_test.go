@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+)
+
+func TestReconcileRestoreDrillCluster(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := mocov1beta2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	newCluster := func(name string) *mocov1beta2.MySQLCluster {
+		cluster := &mocov1beta2.MySQLCluster{}
+		cluster.Namespace = "test"
+		cluster.Name = name
+		cluster.UID = types.UID("uid-" + name)
+		cluster.Spec.Replicas = 3
+		return cluster
+	}
+	bp := &mocov1beta2.BackupPolicy{}
+	bp.Spec.RestoreDrill = &mocov1beta2.RestoreDrillSpec{}
+	bp.Spec.JobConfig.ServiceAccountName = "backup"
+	restorePoint := metav1.NewTime(time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC))
+
+	getScratch := func(c client.Client) *mocov1beta2.MySQLCluster {
+		t.Helper()
+		scratch := &mocov1beta2.MySQLCluster{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: "test", Name: "moco-scratch-test"}, scratch)
+		if err != nil {
+			return nil
+		}
+		return scratch
+	}
+
+	cluster := newCluster("test")
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+	r := &MySQLClusterReconciler{Client: c, Scheme: scheme}
+
+	// nothing is requested.
+	if err := r.reconcileV1RestoreDrillCluster(ctx, cluster, bp); err != nil {
+		t.Fatal(err)
+	}
+	if getScratch(c) != nil {
+		t.Fatal("the scratch MySQLCluster should not be created")
+	}
+
+	// the drill Job requests the scratch MySQLCluster.
+	cluster.Status.RestoreDrillRequest = &mocov1beta2.RestoreDrillRequest{RestorePoint: restorePoint}
+	if err := r.reconcileV1RestoreDrillCluster(ctx, cluster, bp); err != nil {
+		t.Fatal(err)
+	}
+	scratch := getScratch(c)
+	if scratch == nil {
+		t.Fatal("the scratch MySQLCluster is not created")
+	}
+	if !metav1.IsControlledBy(scratch, cluster) {
+		t.Errorf("the scratch MySQLCluster is not controlled by the source: %+v", scratch.OwnerReferences)
+	}
+	if scratch.Spec.Restore == nil || !scratch.Spec.Restore.RestorePoint.Equal(&restorePoint) {
+		t.Errorf("unexpected restore spec: %+v", scratch.Spec.Restore)
+	}
+
+	// the drill Job withdraws the request.
+	cluster.Status.RestoreDrillRequest = nil
+	if err := r.reconcileV1RestoreDrillCluster(ctx, cluster, bp); err != nil {
+		t.Fatal(err)
+	}
+	if getScratch(c) != nil {
+		t.Fatal("the scratch MySQLCluster is not deleted")
+	}
+
+	// a MySQLCluster of users that happens to have the name is left as is.
+	foreign := newCluster("moco-scratch-test")
+	if err := c.Create(ctx, foreign); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reconcileV1RestoreDrillCluster(ctx, cluster, nil); err != nil {
+		t.Fatal(err)
+	}
+	cluster.Status.RestoreDrillRequest = &mocov1beta2.RestoreDrillRequest{RestorePoint: restorePoint}
+	if err := r.reconcileV1RestoreDrillCluster(ctx, cluster, bp); err != nil {
+		t.Fatal(err)
+	}
+	scratch = getScratch(c)
+	if scratch == nil || scratch.Spec.Restore != nil {
+		t.Errorf("the MySQLCluster of users is modified: %+v", scratch)
+	}
+}
+
+func TestNewScratchCluster(t *testing.T) {
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.Namespace = "test"
+	cluster.Name = "test"
+	cluster.Spec.Replicas = 3
+	cluster.Spec.BackupPolicyName = pointer.String("bp")
+	cluster.Spec.MySQLConfigMapName = pointer.String("mycnf")
+	cluster.Spec.ReplicationSourceSecretName = pointer.String("source")
+	cluster.Status.Backup.VolumeSnapshotName = "snap"
+
+	bp := &mocov1beta2.BackupPolicy{}
+	bp.Spec.JobConfig.ServiceAccountName = "backup"
+
+	restorePoint := time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC)
+	scratch := newScratchCluster(cluster, bp, restorePoint)
+	if scratch.Namespace != "test" || scratch.Name != "moco-scratch-test" {
+		t.Errorf("unexpected name: %s/%s", scratch.Namespace, scratch.Name)
+	}
+	if scratch.Spec.Replicas != 1 {
+		t.Errorf("unexpected replicas: %d", scratch.Spec.Replicas)
+	}
+	if scratch.Spec.BackupPolicyName != nil || scratch.Spec.ReplicationSourceSecretName != nil {
+		t.Error("the scratch MySQLCluster should not take backups nor replicate from the source")
+	}
+	if scratch.Spec.MySQLConfigMapName == nil || *scratch.Spec.MySQLConfigMapName != "mycnf" {
+		t.Errorf("unexpected config map: %v", scratch.Spec.MySQLConfigMapName)
+	}
+
+	r := scratch.Spec.Restore
+	if r == nil {
+		t.Fatal("restore is not set")
+	}
+	if r.SourceNamespace != "test" || r.SourceName != "test" || !r.RestorePoint.Time.Equal(restorePoint) {
+		t.Errorf("unexpected restore spec: %+v", r)
+	}
+	if r.VolumeSnapshotName != "snap" || r.JobConfig.ServiceAccountName != "backup" {
+		t.Errorf("unexpected restore spec: %+v", r)
+	}
+}
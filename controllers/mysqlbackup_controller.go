@@ -0,0 +1,402 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/constants"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	batchv1ac "k8s.io/client-go/applyconfigurations/batch/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// MySQLBackupReconciler reconciles a MySQLBackup object
+type MySQLBackupReconciler struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	BackupImage string
+
+	// APIReader reads Jobs and MySQLBackups directly from the API server
+	// to decide whether a backup can be started.
+	APIReader client.Reader
+}
+
+//+kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlbackups,verbs=get;list;watch
+//+kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlbackups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=moco.cybozu.com,resources=backuppolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile launches a Job to take the backup and tracks its progress.
+//
+// The details of the backup such as the backup time and object keys are
+// recorded in the status by the Job itself when it succeeds.
+func (r *MySQLBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	backup := &mocov1beta2.MySQLBackup{}
+	if err := r.Get(ctx, req.NamespacedName, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch MySQLBackup")
+		return ctrl.Result{}, err
+	}
+
+	if backup.DeletionTimestamp != nil || backup.Status.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.JobName()}, job)
+	switch {
+	case apierrors.IsNotFound(err):
+		if backup.Status.StartTime != nil {
+			return ctrl.Result{}, r.updateStatus(ctx, backup, mocov1beta2.MySQLBackupFailed, "the backup job was deleted")
+		}
+		return ctrl.Result{}, r.startBackup(ctx, backup)
+	case err != nil:
+		return ctrl.Result{}, fmt.Errorf("failed to get Job %s/%s: %w", backup.Namespace, backup.JobName(), err)
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobFailed:
+			return ctrl.Result{}, r.updateStatus(ctx, backup, mocov1beta2.MySQLBackupFailed, cond.Message)
+		case batchv1.JobComplete:
+			// the job should have already recorded the result.
+			return ctrl.Result{}, r.updateStatus(ctx, backup, mocov1beta2.MySQLBackupSucceeded, "")
+		}
+	}
+
+	if job.Status.Active > 0 && backup.Status.Phase != mocov1beta2.MySQLBackupRunning {
+		return ctrl.Result{}, r.updateStatus(ctx, backup, mocov1beta2.MySQLBackupRunning, "")
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *MySQLBackupReconciler) startBackup(ctx context.Context, backup *mocov1beta2.MySQLBackup) error {
+	cluster := &mocov1beta2.MySQLCluster{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.ClusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.updateStatus(ctx, backup, mocov1beta2.MySQLBackupFailed, "MySQLCluster not found")
+		}
+		return fmt.Errorf("failed to get MySQLCluster %s/%s: %w", backup.Namespace, backup.Spec.ClusterName, err)
+	}
+
+	if cluster.Spec.BackupPolicyName == nil {
+		return r.updateStatus(ctx, backup, mocov1beta2.MySQLBackupFailed, "MySQLCluster has no backup policy")
+	}
+	bpName := *cluster.Spec.BackupPolicyName
+	bp := &mocov1beta2.BackupPolicy{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: bpName}, bp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.updateStatus(ctx, backup, mocov1beta2.MySQLBackupFailed, fmt.Sprintf("BackupPolicy %s not found", bpName))
+		}
+		return fmt.Errorf("failed to get backup policy %s/%s: %w", cluster.Namespace, bpName, err)
+	}
+
+	// backups of the same cluster must not run concurrently because each one
+	// saves the binary logs since the previous backup.  The cache may not have
+	// the Jobs just created, so they are read from the API server.
+	running, err := r.runningBackupJob(ctx, backup, cluster)
+	if err != nil {
+		return err
+	}
+	if running != "" {
+		return r.waitForBackup(ctx, backup, fmt.Sprintf("waiting for backup Job %s to finish", running))
+	}
+
+	// pending MySQLBackups of the same cluster are started one by one from the oldest.
+	older, err := r.olderPendingBackup(ctx, backup)
+	if err != nil {
+		return err
+	}
+	if older != "" {
+		return r.waitForBackup(ctx, backup, fmt.Sprintf("waiting for MySQLBackup %s to start", older))
+	}
+
+	if err := r.reconcileV1Job(ctx, backup, cluster, bp); err != nil {
+		return err
+	}
+	return r.updateStatus(ctx, backup, mocov1beta2.MySQLBackupPending, "")
+}
+
+// runningBackupJob returns the name of an unfinished backup Job of the cluster,
+// or an empty string if there is none.
+// Backup Jobs are those created by the backup CronJobs and by other MySQLBackups.
+func (r *MySQLBackupReconciler) runningBackupJob(ctx context.Context, backup *mocov1beta2.MySQLBackup, cluster *mocov1beta2.MySQLCluster) (string, error) {
+	jobs := &batchv1.JobList{}
+	if err := r.APIReader.List(ctx, jobs, client.InNamespace(cluster.Namespace), client.MatchingLabels(labelSetForJob(cluster))); err != nil {
+		return "", fmt.Errorf("failed to list Jobs: %w", err)
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if job.Name == backup.JobName() || isJobFinished(job) {
+			continue
+		}
+		owner := metav1.GetControllerOf(job)
+		if owner == nil {
+			continue
+		}
+		switch {
+		case owner.Kind == "MySQLBackup":
+		case owner.Kind == "CronJob" && (owner.Name == cluster.BackupCronJobName() || owner.Name == cluster.BinlogBackupCronJobName()):
+		default:
+			continue
+		}
+		return job.Name, nil
+	}
+	return "", nil
+}
+
+// olderPendingBackup returns the name of a MySQLBackup of the same cluster
+// that is created before `backup` and has not been started yet,
+// or an empty string if there is none.
+func (r *MySQLBackupReconciler) olderPendingBackup(ctx context.Context, backup *mocov1beta2.MySQLBackup) (string, error) {
+	backups := &mocov1beta2.MySQLBackupList{}
+	if err := r.APIReader.List(ctx, backups, client.InNamespace(backup.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list MySQLBackups: %w", err)
+	}
+
+	for i := range backups.Items {
+		b := &backups.Items[i]
+		if b.Name == backup.Name || b.Spec.ClusterName != backup.Spec.ClusterName {
+			continue
+		}
+		if b.DeletionTimestamp != nil || b.Status.StartTime != nil || b.Status.IsFinished() {
+			continue
+		}
+		if isOlderBackup(b, backup) {
+			return b.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// isOlderBackup returns true if `a` is created before `b`.
+// MySQLBackups created in the same second are ordered by their names.
+func isOlderBackup(a, b *mocov1beta2.MySQLBackup) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// waitForBackup keeps the MySQLBackup pending without recording the start time.
+// It is reconciled again when a backup Job of the cluster changes.
+func (r *MySQLBackupReconciler) waitForBackup(ctx context.Context, backup *mocov1beta2.MySQLBackup, message string) error {
+	log := crlog.FromContext(ctx)
+
+	if backup.Status.Phase == mocov1beta2.MySQLBackupPending && backup.Status.Message == message {
+		return nil
+	}
+
+	backup.Status.Phase = mocov1beta2.MySQLBackupPending
+	backup.Status.Message = message
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return fmt.Errorf("failed to update status of MySQLBackup %s/%s: %w", backup.Namespace, backup.Name, err)
+	}
+	log.Info("waiting for another backup", "message", message)
+	return nil
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		if cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MySQLBackupReconciler) reconcileV1Job(ctx context.Context, backup *mocov1beta2.MySQLBackup, cluster *mocov1beta2.MySQLCluster, bp *mocov1beta2.BackupPolicy) error {
+	log := crlog.FromContext(ctx)
+
+	jc := bp.Spec.JobConfig.DeepCopy()
+	if backup.Spec.BucketConfig != nil {
+		jc.BucketConfig = *backup.Spec.BucketConfig.DeepCopy()
+	}
+
+	args := []string{
+		constants.BackupSubcommand,
+		fmt.Sprintf("--threads=%d", jc.Threads),
+		"--backup-name=" + backup.Name,
+	}
+	args = append(args, sourceArgs(bp.Spec.Source)...)
+	hooks, err := hookArgs(bp.Spec.Hooks)
+	if err != nil {
+		return err
+	}
+	args = append(args, hooks...)
+	args = append(args, filterArgs(jc.Filter)...)
+	args = append(args, compressionArgs(jc.Compression)...)
+	if jc.Streaming {
+		args = append(args, "--stream")
+	}
+	args = append(args, snapshotArgs(bp.Spec.Snapshot)...)
+	// a standalone backup into another bucket is not copied.
+	if backup.Spec.BucketConfig == nil {
+		secondaries, err := secondaryBucketArgs(bp.Spec.SecondaryBuckets)
+		if err != nil {
+			return err
+		}
+		args = append(args, secondaries...)
+	}
+	args = append(args, encryptionArgs(jc.Encryption)...)
+	args = append(args, bucketArgs(jc.BucketConfig)...)
+	args = append(args, cluster.Namespace, cluster.Name)
+
+	container := backupContainer(r.BackupImage, cluster, jc, args).WithEnv(hookEnv(bp.Spec.Hooks)...)
+
+	jobName := backup.JobName()
+	job := batchv1ac.Job(jobName, backup.Namespace).
+		WithLabels(labelSetForJob(cluster)).
+		WithSpec(batchv1ac.JobSpec().
+			WithTemplate(corev1ac.PodTemplateSpec().
+				WithLabels(labelSetForJob(cluster)).
+				WithSpec(corev1ac.PodSpec().
+					WithRestartPolicy(corev1.RestartPolicyNever).
+					WithServiceAccountName(jc.ServiceAccountName).
+					WithVolumes(backupVolumes(jc)...).
+					WithContainers(container),
+				),
+			),
+		)
+
+	updatePodTemplateWithJobConfig(job.Spec.Template, jc)
+
+	if bp.Spec.ActiveDeadlineSeconds != nil {
+		job.Spec.WithActiveDeadlineSeconds(*bp.Spec.ActiveDeadlineSeconds)
+	}
+	if bp.Spec.BackoffLimit != nil {
+		job.Spec.WithBackoffLimit(*bp.Spec.BackoffLimit)
+	}
+
+	if err := setControllerReferenceWithBackupJob(backup, job, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set ownerReference to Job %s/%s: %w", backup.Namespace, jobName, err)
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(job)
+	if err != nil {
+		return fmt.Errorf("failed to convert Job %s/%s to unstructured: %w", backup.Namespace, jobName, err)
+	}
+	patch := &unstructured.Unstructured{
+		Object: obj,
+	}
+
+	err = r.Patch(ctx, patch, client.Apply, &client.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        pointer.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile %s Job for backup: %w", jobName, err)
+	}
+
+	log.Info("reconciled Job for on-demand backup", "jobName", jobName)
+	return nil
+}
+
+func (r *MySQLBackupReconciler) updateStatus(ctx context.Context, backup *mocov1beta2.MySQLBackup, phase mocov1beta2.MySQLBackupPhase, message string) error {
+	log := crlog.FromContext(ctx)
+
+	now := metav1.Now()
+	backup.Status.Phase = phase
+	backup.Status.Message = message
+	if backup.Status.StartTime == nil {
+		backup.Status.StartTime = &now
+	}
+	if backup.Status.IsFinished() && backup.Status.CompletionTime == nil {
+		backup.Status.CompletionTime = &now
+	}
+
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return fmt.Errorf("failed to update status of MySQLBackup %s/%s: %w", backup.Namespace, backup.Name, err)
+	}
+	log.Info("updated status of MySQLBackup", "phase", phase, "message", message)
+	return nil
+}
+
+func setControllerReferenceWithBackupJob(backup *mocov1beta2.MySQLBackup, job *batchv1ac.JobApplyConfiguration, scheme *runtime.Scheme) error {
+	gvk, err := apiutil.GVKForObject(backup, scheme)
+	if err != nil {
+		return err
+	}
+	job.WithOwnerReferences(metav1ac.OwnerReference().
+		WithAPIVersion(gvk.GroupVersion().String()).
+		WithKind(gvk.Kind).
+		WithName(backup.Name).
+		WithUID(backup.GetUID()).
+		WithBlockOwnerDeletion(true).
+		WithController(true))
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MySQLBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// wake up MySQLBackups waiting for other backup Jobs of the cluster.
+	jobHandler := handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+		labels := a.GetLabels()
+		if labels[constants.LabelAppName] != constants.AppNameBackup || labels[constants.LabelAppCreatedBy] != constants.AppCreator {
+			return nil
+		}
+		return r.pendingBackupRequests(a.GetNamespace(), labels[constants.LabelAppInstance])
+	})
+
+	// wake up MySQLBackups waiting for an older one that is deleted or started.
+	backupHandler := handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+		backup, ok := a.(*mocov1beta2.MySQLBackup)
+		if !ok {
+			return nil
+		}
+		return r.pendingBackupRequests(backup.Namespace, backup.Spec.ClusterName)
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mocov1beta2.MySQLBackup{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &batchv1.Job{}}, jobHandler).
+		Watches(&source.Kind{Type: &mocov1beta2.MySQLBackup{}}, backupHandler).
+		Complete(r)
+}
+
+// pendingBackupRequests returns the requests for MySQLBackups of the cluster that have not been started.
+func (r *MySQLBackupReconciler) pendingBackupRequests(namespace, clusterName string) []reconcile.Request {
+	backups := &mocov1beta2.MySQLBackupList{}
+	if err := r.List(context.Background(), backups, client.InNamespace(namespace)); err != nil {
+		return nil
+	}
+	var req []reconcile.Request
+	for _, b := range backups.Items {
+		if b.Spec.ClusterName != clusterName {
+			continue
+		}
+		if b.Status.StartTime != nil || b.Status.IsFinished() {
+			continue
+		}
+		req = append(req, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&b)})
+	}
+	return req
+}
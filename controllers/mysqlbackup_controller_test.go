@@ -0,0 +1,324 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func testNewMySQLBackup(ns, name string) *mocov1beta2.MySQLBackup {
+	backup := &mocov1beta2.MySQLBackup{}
+	backup.Namespace = ns
+	backup.Name = name
+	backup.Spec.ClusterName = "test"
+	return backup
+}
+
+var _ = Describe("MySQLBackup reconciler", func() {
+	ctx := context.Background()
+	var stopFunc func()
+
+	BeforeEach(func() {
+		err := k8sClient.DeleteAllOf(ctx, &mocov1beta2.MySQLBackup{}, client.InNamespace("default"))
+		Expect(err).NotTo(HaveOccurred())
+		err = k8sClient.DeleteAllOf(ctx, &mocov1beta2.MySQLCluster{}, client.InNamespace("default"))
+		Expect(err).NotTo(HaveOccurred())
+		err = k8sClient.DeleteAllOf(ctx, &mocov1beta2.BackupPolicy{}, client.InNamespace("default"))
+		Expect(err).NotTo(HaveOccurred())
+		err = k8sClient.DeleteAllOf(ctx, &batchv1.Job{}, client.InNamespace("default"))
+		Expect(err).NotTo(HaveOccurred())
+
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme:             scheme,
+			LeaderElection:     false,
+			MetricsBindAddress: "0",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		backupr := &MySQLBackupReconciler{
+			Client:      mgr.GetClient(),
+			Scheme:      scheme,
+			BackupImage: testBackupImage,
+			APIReader:   mgr.GetAPIReader(),
+		}
+		err = backupr.SetupWithManager(mgr)
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(ctx)
+		stopFunc = cancel
+		go func() {
+			err := mgr.Start(ctx)
+			if err != nil {
+				panic(err)
+			}
+		}()
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	AfterEach(func() {
+		stopFunc()
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	createClusterAndPolicy := func() {
+		cluster := testNewMySQLCluster("default")
+		cluster.Finalizers = nil
+		cluster.Spec.BackupPolicyName = pointer.String("test-policy")
+		err := k8sClient.Create(ctx, cluster)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+		bp := &mocov1beta2.BackupPolicy{}
+		bp.Namespace = "default"
+		bp.Name = "test-policy"
+		bp.Spec.Schedule = "*/5 * * * *"
+		bp.Spec.BackoffLimit = pointer.Int32(1)
+		jc := &bp.Spec.JobConfig
+		jc.Threads = 3
+		jc.ServiceAccountName = "foo"
+		jc.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{
+			EmptyDir: &corev1ac.EmptyDirVolumeSourceApplyConfiguration{},
+		}
+		jc.BucketConfig.BucketName = "mybucket"
+		err = k8sClient.Create(ctx, bp)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	}
+
+	It("should create a Job for the backup", func() {
+		createClusterAndPolicy()
+
+		backup := testNewMySQLBackup("default", "before-migration")
+		backup.Spec.Labels = map[string]string{"reason": "migration"}
+		err := k8sClient.Create(ctx, backup)
+		Expect(err).NotTo(HaveOccurred())
+
+		var job *batchv1.Job
+		Eventually(func() error {
+			job = &batchv1.Job{}
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: backup.JobName()}, job)
+		}).Should(Succeed())
+
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		Expect(job.OwnerReferences[0].Kind).To(Equal("MySQLBackup"))
+		Expect(job.OwnerReferences[0].Name).To(Equal("before-migration"))
+		Expect(job.Spec.BackoffLimit).To(Equal(pointer.Int32(1)))
+		ps := &job.Spec.Template.Spec
+		Expect(ps.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(ps.ServiceAccountName).To(Equal("foo"))
+		Expect(ps.Containers).To(HaveLen(1))
+		c := &ps.Containers[0]
+		Expect(c.Image).To(Equal(testBackupImage))
+		Expect(c.Args).To(Equal([]string{
+			"backup",
+			"--threads=3",
+			"--backup-name=before-migration",
+			"mybucket",
+			"default",
+			"test",
+		}))
+
+		Eventually(func() mocov1beta2.MySQLBackupPhase {
+			backup = &mocov1beta2.MySQLBackup{}
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "before-migration"}, backup)
+			if err != nil {
+				return ""
+			}
+			return backup.Status.Phase
+		}).Should(Equal(mocov1beta2.MySQLBackupPending))
+		Expect(backup.Status.StartTime).NotTo(BeNil())
+
+		By("failing the Job")
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+		}
+		err = k8sClient.Status().Update(ctx, job)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() mocov1beta2.MySQLBackupPhase {
+			backup = &mocov1beta2.MySQLBackup{}
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "before-migration"}, backup)
+			if err != nil {
+				return ""
+			}
+			return backup.Status.Phase
+		}).Should(Equal(mocov1beta2.MySQLBackupFailed))
+		Expect(backup.Status.Message).To(Equal("BackoffLimitExceeded"))
+		Expect(backup.Status.CompletionTime).NotTo(BeNil())
+	})
+
+	It("should use the bucket given by MySQLBackup", func() {
+		createClusterAndPolicy()
+
+		backup := testNewMySQLBackup("default", "another-bucket")
+		backup.Spec.BucketConfig = &mocov1beta2.BucketConfig{
+			BucketName:  "another",
+			EndpointURL: "https://example.com",
+		}
+		err := k8sClient.Create(ctx, backup)
+		Expect(err).NotTo(HaveOccurred())
+
+		var job *batchv1.Job
+		Eventually(func() error {
+			job = &batchv1.Job{}
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: backup.JobName()}, job)
+		}).Should(Succeed())
+
+		Expect(job.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{
+			"backup",
+			"--threads=3",
+			"--backup-name=another-bucket",
+			"--endpoint=https://example.com",
+			"another",
+			"default",
+			"test",
+		}))
+	})
+
+	It("should wait for a running backup Job of the cluster", func() {
+		createClusterAndPolicy()
+
+		cluster := &mocov1beta2.MySQLCluster{}
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test"}, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		running := &batchv1.Job{}
+		running.Namespace = "default"
+		running.Name = "scheduled"
+		running.Labels = labelSetForJob(cluster)
+		running.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "batch/v1beta1",
+			Kind:       "CronJob",
+			Name:       cluster.BackupCronJobName(),
+			UID:        "uid",
+			Controller: pointer.Bool(true),
+		}}
+		running.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+		running.Spec.Template.Spec.Containers = []corev1.Container{{Name: "backup", Image: testBackupImage}}
+		err = k8sClient.Create(ctx, running)
+		Expect(err).NotTo(HaveOccurred())
+
+		backup := testNewMySQLBackup("default", "waiting")
+		err = k8sClient.Create(ctx, backup)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() string {
+			backup = &mocov1beta2.MySQLBackup{}
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "waiting"}, backup)
+			if err != nil {
+				return ""
+			}
+			return backup.Status.Message
+		}).Should(Equal("waiting for backup Job scheduled to finish"))
+		Expect(backup.Status.Phase).To(Equal(mocov1beta2.MySQLBackupPending))
+		Expect(backup.Status.StartTime).To(BeNil())
+
+		job := &batchv1.Job{}
+		Consistently(func() bool {
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: backup.JobName()}, job)
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue())
+
+		By("finishing the running Job")
+		running.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+		err = k8sClient.Status().Update(ctx, running)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() error {
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: backup.JobName()}, job)
+		}).Should(Succeed())
+	})
+
+	It("should start pending MySQLBackups one by one from the oldest", func() {
+		createClusterAndPolicy()
+
+		cluster := &mocov1beta2.MySQLCluster{}
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test"}, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		running := &batchv1.Job{}
+		running.Namespace = "default"
+		running.Name = "scheduled"
+		running.Labels = labelSetForJob(cluster)
+		running.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "batch/v1beta1",
+			Kind:       "CronJob",
+			Name:       cluster.BackupCronJobName(),
+			UID:        "uid",
+			Controller: pointer.Bool(true),
+		}}
+		running.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+		running.Spec.Template.Spec.Containers = []corev1.Container{{Name: "backup", Image: testBackupImage}}
+		err = k8sClient.Create(ctx, running)
+		Expect(err).NotTo(HaveOccurred())
+
+		first := testNewMySQLBackup("default", "a-first")
+		err = k8sClient.Create(ctx, first)
+		Expect(err).NotTo(HaveOccurred())
+		second := testNewMySQLBackup("default", "b-second")
+		err = k8sClient.Create(ctx, second)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() string {
+			second = &mocov1beta2.MySQLBackup{}
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "b-second"}, second)
+			if err != nil {
+				return ""
+			}
+			return second.Status.Message
+		}).ShouldNot(BeEmpty())
+
+		By("finishing the running Job")
+		running.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+		err = k8sClient.Status().Update(ctx, running)
+		Expect(err).NotTo(HaveOccurred())
+
+		job := &batchv1.Job{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: first.JobName()}, job)
+		}).Should(Succeed())
+		Consistently(func() bool {
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: second.JobName()}, job)
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue())
+
+		second = &mocov1beta2.MySQLBackup{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "b-second"}, second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.Status.Phase).To(Equal(mocov1beta2.MySQLBackupPending))
+		Expect(second.Status.StartTime).To(BeNil())
+	})
+
+	It("should fail if the cluster does not exist", func() {
+		backup := testNewMySQLBackup("default", "no-cluster")
+		err := k8sClient.Create(ctx, backup)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() mocov1beta2.MySQLBackupPhase {
+			backup = &mocov1beta2.MySQLBackup{}
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "no-cluster"}, backup)
+			if err != nil {
+				return ""
+			}
+			return backup.Status.Phase
+		}).Should(Equal(mocov1beta2.MySQLBackupFailed))
+		Expect(backup.Status.Message).To(Equal("MySQLCluster not found"))
+
+		job := &batchv1.Job{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: backup.JobName()}, job)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -3,11 +3,13 @@ package controllers
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/fnv"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -86,6 +88,15 @@ func labelSetForJob(cluster *mocov1beta2.MySQLCluster) map[string]string {
 	return labels
 }
 
+func labelSetForBinlogArchiver(cluster *mocov1beta2.MySQLCluster) map[string]string {
+	labels := map[string]string{
+		constants.LabelAppName:      constants.AppNameArchiver,
+		constants.LabelAppInstance:  cluster.Name,
+		constants.LabelAppCreatedBy: constants.AppCreator,
+	}
+	return labels
+}
+
 func mergeMap(m1, m2 map[string]string) map[string]string {
 	m := make(map[string]string)
 	for k, v := range m1 {
@@ -113,12 +124,13 @@ type MySQLClusterReconciler struct {
 	ClusterManager  clustering.ClusterManager
 }
 
-//+kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlclusters,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlclusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=moco.cybozu.com,resources=backuppolicies,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=secrets/status,verbs=get
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
@@ -128,10 +140,11 @@ type MySQLClusterReconciler struct {
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=configmaps/status,verbs=get
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;update;patch
-//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="storage.k8s.io",resources=storageclasses,verbs=get;list;watch
 //+kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups="snapshot.storage.k8s.io",resources=volumesnapshots,verbs=get;create;delete
 //+kubebuilder:rbac:groups="batch",resources=cronjobs;jobs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 
@@ -236,6 +249,11 @@ func (r *MySQLClusterReconciler) reconcileV1(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileV1SnapshotPVC(ctx, req, cluster); err != nil {
+		log.Error(err, "failed to reconcile PVC from snapshot")
+		return ctrl.Result{}, err
+	}
+
 	if err := r.reconcileV1StatefulSet(ctx, req, cluster, mycnf); err != nil {
 		log.Error(err, "failed to reconcile stateful set")
 		return ctrl.Result{}, err
@@ -273,7 +291,7 @@ func (r *MySQLClusterReconciler) reconcileV1Secret(ctx context.Context, req ctrl
 	secret := &corev1.Secret{}
 	err := r.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: name}, secret)
 	if apierrors.IsNotFound(err) {
-		passwd, err := password.NewMySQLPassword()
+		passwd, err := r.newMySQLPassword(ctx, cluster)
 		if err != nil {
 			return err
 		}
@@ -1080,60 +1098,301 @@ func bucketArgs(bc mocov1beta2.BucketConfig) []string {
 	if bc.UsePathStyle {
 		args = append(args, "--use-path-style")
 	}
+	if bc.BackendType != "" && bc.BackendType != constants.BackendTypeS3 {
+		args = append(args, "--backend-type="+bc.BackendType)
+	}
+	if bc.AccountName != "" {
+		args = append(args, "--account-name="+bc.AccountName)
+	}
+	if bc.UploadConcurrency > 0 {
+		args = append(args, fmt.Sprintf("--upload-concurrency=%d", bc.UploadConcurrency))
+	}
+	if bc.PartSize != nil {
+		args = append(args, fmt.Sprintf("--part-size=%d", bc.PartSize.Value()))
+	}
+	if bc.RateLimit != nil {
+		args = append(args, fmt.Sprintf("--rate-limit=%d", bc.RateLimit.Value()))
+	}
+	if bc.ServerSideEncryption != "" {
+		args = append(args, "--sse="+bc.ServerSideEncryption)
+	}
+	if bc.KMSKeyID != "" {
+		args = append(args, "--kms-key-id="+bc.KMSKeyID)
+	}
+	if bc.StorageClass != "" {
+		args = append(args, "--storage-class="+bc.StorageClass)
+	}
+	tagKeys := make([]string, 0, len(bc.Tags))
+	for k := range bc.Tags {
+		tagKeys = append(tagKeys, k)
+	}
+	sort.Strings(tagKeys)
+	for _, k := range tagKeys {
+		args = append(args, fmt.Sprintf("--tags=%s=%s", k, bc.Tags[k]))
+	}
+	if bc.ObjectLock != nil {
+		args = append(args, "--object-lock-mode="+bc.ObjectLock.Mode)
+		args = append(args, "--object-lock-retain-for="+bc.ObjectLock.RetainFor.Duration.String())
+	}
+	if bc.TLS != nil && bc.TLS.CABundle != nil {
+		args = append(args, "--ca-bundle="+filepath.Join(constants.BucketCAMountPath, constants.BucketCAFilename))
+	}
+	if bc.TLS != nil && bc.TLS.ClientCertSecretName != "" {
+		args = append(args, "--client-cert="+filepath.Join(constants.BucketClientCertMountPath, corev1.TLSCertKey))
+		args = append(args, "--client-key="+filepath.Join(constants.BucketClientCertMountPath, corev1.TLSPrivateKeyKey))
+	}
 	return append(args, bc.BucketName)
 }
 
-func (r *MySQLClusterReconciler) reconcileV1BackupJob(ctx context.Context, req ctrl.Request, cluster *mocov1beta2.MySQLCluster) error {
-	log := crlog.FromContext(ctx)
+func retentionArgs(r *mocov1beta2.RetentionPolicy) []string {
+	if r == nil {
+		return nil
+	}
 
-	if cluster.Spec.BackupPolicyName == nil {
-		cj := &batchv1beta1.CronJob{}
-		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.BackupCronJobName()}, cj)
-		if err == nil {
-			if err := r.Delete(ctx, cj); err != nil {
-				log.Error(err, "failed to delete CronJob")
-				return err
-			}
-		} else if !apierrors.IsNotFound(err) {
-			return err
-		}
+	var args []string
+	if r.KeepLast > 0 {
+		args = append(args, fmt.Sprintf("--keep-last=%d", r.KeepLast))
+	}
+	if r.KeepFor != nil {
+		args = append(args, "--keep-for="+r.KeepFor.Duration.String())
+	}
+	if r.KeepDaily > 0 {
+		args = append(args, fmt.Sprintf("--keep-daily=%d", r.KeepDaily))
+	}
+	if r.KeepWeekly > 0 {
+		args = append(args, fmt.Sprintf("--keep-weekly=%d", r.KeepWeekly))
+	}
+	if r.KeepMonthly > 0 {
+		args = append(args, fmt.Sprintf("--keep-monthly=%d", r.KeepMonthly))
+	}
+	return args
+}
 
-		role := &rbacv1.Role{}
-		err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.BackupRoleName()}, role)
-		if err == nil {
-			if err := r.Delete(ctx, role); err != nil {
-				log.Error(err, "failed to delete Role")
-				return err
+func sourceArgs(s *mocov1beta2.BackupSourcePolicy) []string {
+	if s == nil {
+		return nil
+	}
+
+	var args []string
+	if s.Index != nil {
+		args = append(args, fmt.Sprintf("--source-index=%d", *s.Index))
+	}
+	if s.Zone != "" {
+		args = append(args, "--source-zone="+s.Zone)
+	}
+	if s.ExcludePrimary {
+		args = append(args, "--exclude-primary")
+	}
+	if s.PreferLeastLag {
+		args = append(args, "--prefer-least-lag")
+	}
+	return args
+}
+
+func hookArgs(h *mocov1beta2.BackupHooks) ([]string, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal hooks: %w", err)
+	}
+	return []string{"--hooks=" + string(data)}, nil
+}
+
+// hookEnv returns the environment variables that hold the values of
+// the headers of HTTP hooks.  The values are read from Secrets so that
+// they never appear in the arguments of the container.
+func hookEnv(h *mocov1beta2.BackupHooks) []*corev1ac.EnvVarApplyConfiguration {
+	if h == nil {
+		return nil
+	}
+
+	var env []*corev1ac.EnvVarApplyConfiguration
+	add := func(phase string, hooks []mocov1beta2.BackupHook) {
+		for i, hook := range hooks {
+			if hook.HTTP == nil {
+				continue
 			}
-		} else if !apierrors.IsNotFound(err) {
-			return err
-		}
-		rolebinding := &rbacv1.RoleBinding{}
-		err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.BackupRoleName()}, rolebinding)
-		if err == nil {
-			if err := r.Delete(ctx, rolebinding); err != nil {
-				log.Error(err, "failed to delete RoleBinding")
-				return err
+			for j, hdr := range hook.HTTP.Headers {
+				ref := hdr.ValueFrom.SecretKeyRef
+				sel := corev1ac.SecretKeySelector().
+					WithName(ref.Name).
+					WithKey(ref.Key)
+				if ref.Optional != nil {
+					sel.WithOptional(*ref.Optional)
+				}
+				env = append(env, corev1ac.EnvVar().
+					WithName(mocov1beta2.HookHeaderEnvName(phase, i, j)).
+					WithValueFrom(corev1ac.EnvVarSource().WithSecretKeyRef(sel)))
 			}
-		} else if !apierrors.IsNotFound(err) {
-			return err
 		}
+	}
+	add("preBackup", h.PreBackup)
+	add("postBackup", h.PostBackup)
+	return env
+}
 
+func filterArgs(f *mocov1beta2.FilterConfig) []string {
+	if f == nil {
 		return nil
 	}
 
-	bpName := *cluster.Spec.BackupPolicyName
-	bp := &mocov1beta2.BackupPolicy{}
-	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: bpName}, bp); err != nil {
-		return fmt.Errorf("failed to get backup policy %s/%s: %w", cluster.Namespace, bpName, err)
+	var args []string
+	if len(f.IncludeSchemas) > 0 {
+		args = append(args, "--include-schemas="+strings.Join(f.IncludeSchemas, ","))
+	}
+	if len(f.ExcludeSchemas) > 0 {
+		args = append(args, "--exclude-schemas="+strings.Join(f.ExcludeSchemas, ","))
 	}
+	if len(f.IncludeTables) > 0 {
+		args = append(args, "--include-tables="+strings.Join(f.IncludeTables, ","))
+	}
+	if len(f.ExcludeTables) > 0 {
+		args = append(args, "--exclude-tables="+strings.Join(f.ExcludeTables, ","))
+	}
+	return args
+}
 
-	jc := &bp.Spec.JobConfig
+func compressionArgs(c *mocov1beta2.CompressionConfig) []string {
+	if c == nil {
+		return nil
+	}
 
-	args := []string{constants.BackupSubcommand, fmt.Sprintf("--threads=%d", jc.Threads)}
-	args = append(args, bucketArgs(jc.BucketConfig)...)
-	args = append(args, cluster.Namespace, cluster.Name)
+	var args []string
+	if c.Dump != "" {
+		args = append(args, "--dump-compression="+c.Dump)
+	}
+	if c.Binlog != "" {
+		args = append(args, "--binlog-compression="+c.Binlog)
+	}
+	return args
+}
+
+func snapshotArgs(s *mocov1beta2.SnapshotSpec) []string {
+	if s == nil {
+		return nil
+	}
+
+	args := []string{"--snapshot"}
+	if s.VolumeSnapshotClassName != "" {
+		args = append(args, "--snapshot-class="+s.VolumeSnapshotClassName)
+	}
+	return args
+}
+
+func secondaryBucketArgs(bs []mocov1beta2.SecondaryBucket) ([]string, error) {
+	if len(bs) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(bs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal secondary buckets: %w", err)
+	}
+	return []string{"--secondary-buckets=" + string(data)}, nil
+}
+
+func encryptionArgs(e *mocov1beta2.EncryptionConfig) []string {
+	if e == nil {
+		return nil
+	}
+
+	args := []string{"--encryption-key-dir=" + constants.EncryptionKeyMountPath}
+	if e.KeyID != "" {
+		args = append(args, "--encryption-key-id="+e.KeyID)
+	}
+	return args
+}
+
+func encryptionVolumes(e *mocov1beta2.EncryptionConfig) []*corev1ac.VolumeApplyConfiguration {
+	if e == nil {
+		return nil
+	}
+	return []*corev1ac.VolumeApplyConfiguration{
+		corev1ac.Volume().
+			WithName("encryption-keys").
+			WithSecret(corev1ac.SecretVolumeSource().
+				WithSecretName(e.SecretName)),
+	}
+}
+
+func encryptionVolumeMounts(e *mocov1beta2.EncryptionConfig) []*corev1ac.VolumeMountApplyConfiguration {
+	if e == nil {
+		return nil
+	}
+	return []*corev1ac.VolumeMountApplyConfiguration{
+		corev1ac.VolumeMount().
+			WithName("encryption-keys").
+			WithMountPath(constants.EncryptionKeyMountPath).
+			WithReadOnly(true),
+	}
+}
+
+func bucketVolumes(bc mocov1beta2.BucketConfig) []*corev1ac.VolumeApplyConfiguration {
+	var volumes []*corev1ac.VolumeApplyConfiguration
+	if bc.BackendType == constants.BackendTypeFile && bc.Volume != nil {
+		volumes = append(volumes, &corev1ac.VolumeApplyConfiguration{
+			Name:                           pointer.String("bucket"),
+			VolumeSourceApplyConfiguration: corev1ac.VolumeSourceApplyConfiguration(*bc.Volume.DeepCopy()),
+		})
+	}
+	if bc.TLS == nil {
+		return volumes
+	}
+
+	if ca := bc.TLS.CABundle; ca != nil {
+		item := corev1ac.KeyToPath().WithKey(ca.Key).WithPath(constants.BucketCAFilename)
+		v := corev1ac.Volume().WithName("bucket-ca")
+		if ca.ConfigMapName != "" {
+			v.WithConfigMap(corev1ac.ConfigMapVolumeSource().
+				WithName(ca.ConfigMapName).
+				WithItems(item))
+		} else {
+			v.WithSecret(corev1ac.SecretVolumeSource().
+				WithSecretName(ca.SecretName).
+				WithItems(item))
+		}
+		volumes = append(volumes, v)
+	}
+	if bc.TLS.ClientCertSecretName != "" {
+		volumes = append(volumes, corev1ac.Volume().
+			WithName("bucket-client-cert").
+			WithSecret(corev1ac.SecretVolumeSource().
+				WithSecretName(bc.TLS.ClientCertSecretName)))
+	}
+	return volumes
+}
+
+func bucketVolumeMounts(bc mocov1beta2.BucketConfig) []*corev1ac.VolumeMountApplyConfiguration {
+	var mounts []*corev1ac.VolumeMountApplyConfiguration
+	if bc.BackendType == constants.BackendTypeFile && bc.Volume != nil {
+		mounts = append(mounts, corev1ac.VolumeMount().
+			WithName("bucket").
+			WithMountPath(constants.BucketVolumeMountPath))
+	}
+	if bc.TLS == nil {
+		return mounts
+	}
 
+	if bc.TLS.CABundle != nil {
+		mounts = append(mounts, corev1ac.VolumeMount().
+			WithName("bucket-ca").
+			WithMountPath(constants.BucketCAMountPath).
+			WithReadOnly(true))
+	}
+	if bc.TLS.ClientCertSecretName != "" {
+		mounts = append(mounts, corev1ac.VolumeMount().
+			WithName("bucket-client-cert").
+			WithMountPath(constants.BucketClientCertMountPath).
+			WithReadOnly(true))
+	}
+	return mounts
+}
+
+// backupContainer returns the container to run moco-backup for backup and binlog archiving.
+func backupContainer(image string, cluster *mocov1beta2.MySQLCluster, jc *mocov1beta2.JobConfig, args []string) *corev1ac.ContainerApplyConfiguration {
 	resources := corev1ac.ResourceRequirements()
 	if jc.Memory != nil {
 		resources.WithRequests(corev1.ResourceList{
@@ -1158,7 +1417,7 @@ func (r *MySQLClusterReconciler) reconcileV1BackupJob(ctx context.Context, req c
 
 	container := corev1ac.Container().
 		WithName("backup").
-		WithImage(r.BackupImage).
+		WithImage(image).
 		WithArgs(args...).
 		WithEnv(corev1ac.EnvVar().
 			WithName("MYSQL_PASSWORD").
@@ -1189,17 +1448,226 @@ func (r *MySQLClusterReconciler) reconcileV1BackupJob(ctx context.Context, req c
 			WithName("work").
 			WithMountPath("/work"),
 		).
+		WithVolumeMounts(bucketVolumeMounts(jc.BucketConfig)...).
+		WithVolumeMounts(encryptionVolumeMounts(jc.Encryption)...).
 		WithSecurityContext(corev1ac.SecurityContext().WithReadOnlyRootFilesystem(true)).
 		WithResources(resources)
 
 	updateContainerWithSecurityContext(container)
+	return container
+}
+
+// workVolume returns the volume for the working directory.
+// An emptyDir volume is used if the job config does not specify one.
+func workVolume(jc *mocov1beta2.JobConfig) *corev1ac.VolumeApplyConfiguration {
+	if jc.WorkVolume == (mocov1beta2.VolumeSourceApplyConfiguration{}) {
+		return corev1ac.Volume().
+			WithName("work").
+			WithEmptyDir(corev1ac.EmptyDirVolumeSource())
+	}
+	return &corev1ac.VolumeApplyConfiguration{
+		Name:                           pointer.String("work"),
+		VolumeSourceApplyConfiguration: corev1ac.VolumeSourceApplyConfiguration(*jc.WorkVolume.DeepCopy()),
+	}
+}
+
+func backupVolumes(jc *mocov1beta2.JobConfig) []*corev1ac.VolumeApplyConfiguration {
+	volumes := []*corev1ac.VolumeApplyConfiguration{workVolume(jc)}
+	volumes = append(volumes, bucketVolumes(jc.BucketConfig)...)
+	return append(volumes, encryptionVolumes(jc.Encryption)...)
+}
+
+// archiverVolumes is the same as backupVolumes except that the working directory
+// is always an emptyDir.  The work volume of the job config may be a PVC used by
+// backup Jobs, which the long-running archiver must not share.
+func archiverVolumes(jc *mocov1beta2.JobConfig) []*corev1ac.VolumeApplyConfiguration {
+	volumes := []*corev1ac.VolumeApplyConfiguration{
+		corev1ac.Volume().
+			WithName("work").
+			WithEmptyDir(corev1ac.EmptyDirVolumeSource()),
+	}
+	volumes = append(volumes, bucketVolumes(jc.BucketConfig)...)
+	return append(volumes, encryptionVolumes(jc.Encryption)...)
+}
+
+// updatePodTemplateWithJobConfig merges the Pod template of the job config into `tmpl`.
+// Labels and security settings made by MOCO take precedence.
+func updatePodTemplateWithJobConfig(tmpl *corev1ac.PodTemplateSpecApplyConfiguration, jc *mocov1beta2.JobConfig) {
+	t := jc.PodTemplate
+	if t == nil {
+		return
+	}
+
+	for k, v := range t.Labels {
+		if _, ok := tmpl.Labels[k]; !ok {
+			tmpl.WithLabels(map[string]string{k: v})
+		}
+	}
+	tmpl.WithAnnotations(t.Annotations)
+
+	spec := tmpl.Spec
+	if len(t.NodeSelector) > 0 {
+		spec.WithNodeSelector(t.NodeSelector)
+	}
+	if t.Affinity != nil {
+		spec.WithAffinity((*corev1ac.AffinityApplyConfiguration)(t.Affinity.DeepCopy()))
+	}
+	for i := range t.Tolerations {
+		spec.WithTolerations((*corev1ac.TolerationApplyConfiguration)(t.Tolerations[i].DeepCopy()))
+	}
+	if t.PriorityClassName != "" {
+		spec.WithPriorityClassName(t.PriorityClassName)
+	}
+	if t.SecurityContext != nil {
+		spec.WithSecurityContext((*corev1ac.PodSecurityContextApplyConfiguration)(t.SecurityContext.DeepCopy()))
+	}
+	for i := range t.Volumes {
+		spec.WithVolumes((*corev1ac.VolumeApplyConfiguration)(t.Volumes[i].DeepCopy()))
+	}
+
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		for j := range t.VolumeMounts {
+			c.WithVolumeMounts((*corev1ac.VolumeMountApplyConfiguration)(t.VolumeMounts[j].DeepCopy()))
+		}
+		if t.ContainerSecurityContext == nil {
+			continue
+		}
+		sc := (*corev1ac.SecurityContextApplyConfiguration)(t.ContainerSecurityContext.DeepCopy())
+		if c.SecurityContext != nil {
+			if c.SecurityContext.ReadOnlyRootFilesystem != nil {
+				sc.ReadOnlyRootFilesystem = c.SecurityContext.ReadOnlyRootFilesystem
+			}
+			if c.SecurityContext.RunAsUser != nil {
+				sc.RunAsUser = c.SecurityContext.RunAsUser
+			}
+			if c.SecurityContext.RunAsGroup != nil {
+				sc.RunAsGroup = c.SecurityContext.RunAsGroup
+			}
+		}
+		c.SecurityContext = sc
+	}
+}
+
+func (r *MySQLClusterReconciler) reconcileV1BackupJob(ctx context.Context, req ctrl.Request, cluster *mocov1beta2.MySQLCluster) error {
+	log := crlog.FromContext(ctx)
+
+	if cluster.Spec.BackupPolicyName == nil {
+		cj := &batchv1beta1.CronJob{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.BackupCronJobName()}, cj)
+		if err == nil {
+			if err := r.Delete(ctx, cj); err != nil {
+				log.Error(err, "failed to delete CronJob")
+				return err
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		role := &rbacv1.Role{}
+		err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.BackupRoleName()}, role)
+		if err == nil {
+			if err := r.Delete(ctx, role); err != nil {
+				log.Error(err, "failed to delete Role")
+				return err
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+		rolebinding := &rbacv1.RoleBinding{}
+		err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.BackupRoleName()}, rolebinding)
+		if err == nil {
+			if err := r.Delete(ctx, rolebinding); err != nil {
+				log.Error(err, "failed to delete RoleBinding")
+				return err
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		if err := r.deleteV1BinlogBackupJob(ctx, cluster); err != nil {
+			return err
+		}
+		if err := r.deleteV1RestoreDrillJob(ctx, cluster); err != nil {
+			return err
+		}
+		if err := r.reconcileV1RestoreDrillCluster(ctx, cluster, nil); err != nil {
+			return err
+		}
+		return r.deleteV1BinlogArchiver(ctx, cluster)
+	}
+
+	bpName := *cluster.Spec.BackupPolicyName
+	bp := &mocov1beta2.BackupPolicy{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: bpName}, bp); err != nil {
+		return fmt.Errorf("failed to get backup policy %s/%s: %w", cluster.Namespace, bpName, err)
+	}
+
+	jc := &bp.Spec.JobConfig
+
+	args := []string{constants.BackupSubcommand, fmt.Sprintf("--threads=%d", jc.Threads)}
+	args = append(args, retentionArgs(bp.Spec.Retention)...)
+	args = append(args, sourceArgs(bp.Spec.Source)...)
+	hooks, err := hookArgs(bp.Spec.Hooks)
+	if err != nil {
+		return err
+	}
+	args = append(args, hooks...)
+	args = append(args, filterArgs(jc.Filter)...)
+	args = append(args, compressionArgs(jc.Compression)...)
+	if jc.Streaming {
+		args = append(args, "--stream")
+	}
+	args = append(args, snapshotArgs(bp.Spec.Snapshot)...)
+	secondaries, err := secondaryBucketArgs(bp.Spec.SecondaryBuckets)
+	if err != nil {
+		return err
+	}
+	args = append(args, secondaries...)
+	args = append(args, encryptionArgs(jc.Encryption)...)
+	args = append(args, bucketArgs(jc.BucketConfig)...)
+	args = append(args, cluster.Namespace, cluster.Name)
+
+	if err := r.applyV1BackupCronJob(ctx, cluster, bp, cluster.BackupCronJobName(), bp.Spec.Schedule, bp.Spec.ConcurrencyPolicy, args, hookEnv(bp.Spec.Hooks)); err != nil {
+		return err
+	}
+
+	if err := r.reconcileV1BinlogBackupJob(ctx, cluster, bp); err != nil {
+		return err
+	}
+
+	if err := r.reconcileV1RestoreDrillJob(ctx, cluster, bp); err != nil {
+		return err
+	}
+
+	if err := r.reconcileV1RestoreDrillCluster(ctx, cluster, bp); err != nil {
+		return err
+	}
+
+	if err := r.reconcileV1BackupJobRole(ctx, req, cluster, bp); err != nil {
+		return err
+	}
+
+	if err := r.reconcileV1BackupJobRoleBinding(ctx, req, cluster, bp); err != nil {
+		return err
+	}
+
+	return r.reconcileV1BinlogArchiver(ctx, req, cluster, bp)
+}
+
+// applyV1BackupCronJob applies a CronJob that runs moco-backup with `args` on `schedule`.
+func (r *MySQLClusterReconciler) applyV1BackupCronJob(ctx context.Context, cluster *mocov1beta2.MySQLCluster, bp *mocov1beta2.BackupPolicy,
+	cronJobName, schedule string, concurrencyPolicy batchv1beta1.ConcurrencyPolicy, args []string, env []*corev1ac.EnvVarApplyConfiguration) error {
+	log := crlog.FromContext(ctx)
+	jc := &bp.Spec.JobConfig
+
+	container := backupContainer(r.BackupImage, cluster, jc, args).WithEnv(env...)
 
-	cronJobName := cluster.BackupCronJobName()
 	cronJob := batchv1beta1ac.CronJob(cronJobName, cluster.Namespace).
 		WithLabels(labelSetForJob(cluster)).
 		WithSpec(batchv1beta1ac.CronJobSpec().
-			WithSchedule(bp.Spec.Schedule).
-			WithConcurrencyPolicy(bp.Spec.ConcurrencyPolicy).
+			WithSchedule(schedule).
+			WithConcurrencyPolicy(concurrencyPolicy).
 			WithJobTemplate(batchv1beta1ac.JobTemplateSpec().
 				WithLabels(labelSetForJob(cluster)).
 				WithSpec(batchv1ac.JobSpec().
@@ -1208,10 +1676,7 @@ func (r *MySQLClusterReconciler) reconcileV1BackupJob(ctx context.Context, req c
 						WithSpec(corev1ac.PodSpec().
 							WithRestartPolicy(corev1.RestartPolicyNever).
 							WithServiceAccountName(bp.Spec.JobConfig.ServiceAccountName).
-							WithVolumes(&corev1ac.VolumeApplyConfiguration{
-								Name:                           pointer.String("work"),
-								VolumeSourceApplyConfiguration: corev1ac.VolumeSourceApplyConfiguration(*jc.WorkVolume.DeepCopy()),
-							}).
+							WithVolumes(backupVolumes(jc)...).
 							WithContainers(container),
 						),
 					),
@@ -1219,6 +1684,8 @@ func (r *MySQLClusterReconciler) reconcileV1BackupJob(ctx context.Context, req c
 			),
 		)
 
+	updatePodTemplateWithJobConfig(cronJob.Spec.JobTemplate.Spec.Template, jc)
+
 	if bp.Spec.StartingDeadlineSeconds != nil {
 		cronJob.Spec.WithStartingDeadlineSeconds(*bp.Spec.StartingDeadlineSeconds)
 	}
@@ -1283,19 +1750,98 @@ func (r *MySQLClusterReconciler) reconcileV1BackupJob(ctx context.Context, req c
 	}
 
 	log.Info("reconciled CronJob for backup", "cronJobName", cronJobName)
+	return nil
+}
 
-	if err := r.reconcileV1BackupJobRole(ctx, req, cluster); err != nil {
+// reconcileV1BinlogBackupJob creates the CronJob for binlog-only backups if
+// `spec.binlogSchedule` is specified.  ForbidConcurrent prevents overlaps only
+// among binlog-only backups; the Job skips itself while a full backup is running.
+func (r *MySQLClusterReconciler) reconcileV1BinlogBackupJob(ctx context.Context, cluster *mocov1beta2.MySQLCluster, bp *mocov1beta2.BackupPolicy) error {
+	if bp.Spec.BinlogSchedule == "" {
+		return r.deleteV1BinlogBackupJob(ctx, cluster)
+	}
+
+	jc := &bp.Spec.JobConfig
+
+	args := []string{constants.BackupSubcommand, "--binlog-only", fmt.Sprintf("--threads=%d", jc.Threads)}
+	args = append(args, sourceArgs(bp.Spec.Source)...)
+	if jc.Compression != nil && jc.Compression.Binlog != "" {
+		args = append(args, "--binlog-compression="+jc.Compression.Binlog)
+	}
+	secondaries, err := secondaryBucketArgs(bp.Spec.SecondaryBuckets)
+	if err != nil {
 		return err
 	}
+	args = append(args, secondaries...)
+	args = append(args, encryptionArgs(jc.Encryption)...)
+	args = append(args, bucketArgs(jc.BucketConfig)...)
+	args = append(args, cluster.Namespace, cluster.Name)
 
-	if err := r.reconcileV1BackupJobRoleBinding(ctx, req, cluster, bp); err != nil {
+	// two binlog-only backups must not update the manifest at the same time.
+	return r.applyV1BackupCronJob(ctx, cluster, bp, cluster.BinlogBackupCronJobName(), bp.Spec.BinlogSchedule, batchv1beta1.ForbidConcurrent, args, nil)
+}
+
+func (r *MySQLClusterReconciler) deleteV1BinlogBackupJob(ctx context.Context, cluster *mocov1beta2.MySQLCluster) error {
+	log := crlog.FromContext(ctx)
+
+	cj := &batchv1beta1.CronJob{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.BinlogBackupCronJobName()}, cj)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
 		return err
 	}
 
+	if err := r.Delete(ctx, cj); err != nil {
+		log.Error(err, "failed to delete CronJob")
+		return err
+	}
+	log.Info("deleted CronJob for binlog-only backup", "cronJobName", cj.Name)
 	return nil
 }
 
-func (r *MySQLClusterReconciler) reconcileV1BackupJobRole(ctx context.Context, req ctrl.Request, cluster *mocov1beta2.MySQLCluster) error {
+func (r *MySQLClusterReconciler) reconcileV1RestoreDrillJob(ctx context.Context, cluster *mocov1beta2.MySQLCluster, bp *mocov1beta2.BackupPolicy) error {
+	drill := bp.Spec.RestoreDrill
+	if drill == nil {
+		return r.deleteV1RestoreDrillJob(ctx, cluster)
+	}
+
+	args := []string{constants.DrillSubcommand, "--timeout=" + drill.Timeout.Duration.String()}
+	if len(drill.Assertions) > 0 {
+		data, err := json.Marshal(drill.Assertions)
+		if err != nil {
+			return fmt.Errorf("failed to marshal assertions: %w", err)
+		}
+		args = append(args, "--assertions="+string(data))
+	}
+	args = append(args, cluster.Namespace, cluster.Name)
+
+	// two drills would use the same scratch MySQLCluster.
+	return r.applyV1BackupCronJob(ctx, cluster, bp, cluster.RestoreDrillCronJobName(), drill.Schedule, batchv1beta1.ForbidConcurrent, args, nil)
+}
+
+func (r *MySQLClusterReconciler) deleteV1RestoreDrillJob(ctx context.Context, cluster *mocov1beta2.MySQLCluster) error {
+	log := crlog.FromContext(ctx)
+
+	cj := &batchv1beta1.CronJob{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.RestoreDrillCronJobName()}, cj)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.Delete(ctx, cj); err != nil {
+		log.Error(err, "failed to delete CronJob")
+		return err
+	}
+	log.Info("deleted CronJob for restore drill", "cronJobName", cj.Name)
+	return nil
+}
+
+func (r *MySQLClusterReconciler) reconcileV1BackupJobRole(ctx context.Context, req ctrl.Request, cluster *mocov1beta2.MySQLCluster, bp *mocov1beta2.BackupPolicy) error {
 	log := crlog.FromContext(ctx)
 
 	name := cluster.BackupRoleName()
@@ -1307,15 +1853,52 @@ func (r *MySQLClusterReconciler) reconcileV1BackupJobRole(ctx context.Context, r
 				WithResources("mysqlclusters", "mysqlclusters/status").
 				WithVerbs("get", "update").
 				WithResourceNames(cluster.Name),
+			rbacv1ac.PolicyRule().
+				WithAPIGroups(mocov1beta2.GroupVersion.Group).
+				WithResources("mysqlbackups", "mysqlbackups/status").
+				WithVerbs("get", "update"),
 			rbacv1ac.PolicyRule().
 				WithAPIGroups("").
 				WithResources("pods").
 				WithVerbs("get", "list", "watch"),
+			rbacv1ac.PolicyRule().
+				WithAPIGroups("batch").
+				WithResources("jobs").
+				WithVerbs("get", "list"),
 			rbacv1ac.PolicyRule().
 				WithAPIGroups("").
 				WithResources("events").
 				WithVerbs("create", "update", "patch"),
+			rbacv1ac.PolicyRule().
+				WithAPIGroups(volumeSnapshotGroup).
+				WithResources("volumesnapshots").
+				WithVerbs("get", "create", "delete"),
+		)
+
+	// restore drills watch the scratch MySQLCluster created by moco-controller
+	// and connect to it.  They are not allowed to create or delete anything.
+	if bp.Spec.RestoreDrill != nil {
+		scratchName := cluster.RestoreDrillClusterName()
+		scratch := &mocov1beta2.MySQLCluster{}
+		scratch.Name = scratchName
+		role.WithRules(
+			rbacv1ac.PolicyRule().
+				WithAPIGroups(mocov1beta2.GroupVersion.Group).
+				WithResources("mysqlclusters", "mysqlclusters/status").
+				WithVerbs("get").
+				WithResourceNames(scratchName),
+			rbacv1ac.PolicyRule().
+				WithAPIGroups("").
+				WithResources("secrets").
+				WithVerbs("get").
+				WithResourceNames(scratch.UserSecretName()),
+			rbacv1ac.PolicyRule().
+				WithAPIGroups("").
+				WithResources("persistentvolumeclaims").
+				WithVerbs("get").
+				WithResourceNames(cluster.RestoreDrillVolumeClaimNames()...),
 		)
+	}
 
 	if err := setControllerReferenceWithRole(cluster, role, r.Scheme); err != nil {
 		return fmt.Errorf("failed to set ownerReference to Role %s/%s: %w", cluster.Namespace, name, err)
@@ -1436,6 +2019,128 @@ func (r *MySQLClusterReconciler) reconcileV1BackupJobRoleBinding(ctx context.Con
 	return nil
 }
 
+func (r *MySQLClusterReconciler) reconcileV1BinlogArchiver(ctx context.Context, req ctrl.Request, cluster *mocov1beta2.MySQLCluster, bp *mocov1beta2.BackupPolicy) error {
+	log := crlog.FromContext(ctx)
+
+	if bp.Spec.BinlogArchive == nil {
+		return r.deleteV1BinlogArchiver(ctx, cluster)
+	}
+
+	jc := &bp.Spec.JobConfig
+
+	args := []string{
+		constants.ArchiveBinlogSubcommand,
+		fmt.Sprintf("--threads=%d", jc.Threads),
+		"--interval=" + bp.Spec.BinlogArchive.Interval.Duration.String(),
+	}
+	if jc.Compression != nil && jc.Compression.Binlog != "" {
+		args = append(args, "--binlog-compression="+jc.Compression.Binlog)
+	}
+	secondaries, err := secondaryBucketArgs(bp.Spec.SecondaryBuckets)
+	if err != nil {
+		return err
+	}
+	args = append(args, secondaries...)
+	args = append(args, encryptionArgs(jc.Encryption)...)
+	args = append(args, bucketArgs(jc.BucketConfig)...)
+	args = append(args, cluster.Namespace, cluster.Name)
+
+	container := backupContainer(r.BackupImage, cluster, jc, args).WithName("archiver")
+
+	name := cluster.BinlogArchiverName()
+	deployment := appsv1ac.Deployment(name, cluster.Namespace).
+		WithLabels(labelSetForBinlogArchiver(cluster)).
+		WithSpec(appsv1ac.DeploymentSpec().
+			WithReplicas(1).
+			WithSelector(metav1ac.LabelSelector().
+				WithMatchLabels(labelSetForBinlogArchiver(cluster))).
+			// two archivers must not run at the same time.
+			WithStrategy(appsv1ac.DeploymentStrategy().
+				WithType(appsv1.RecreateDeploymentStrategyType)).
+			WithTemplate(corev1ac.PodTemplateSpec().
+				WithLabels(labelSetForBinlogArchiver(cluster)).
+				WithSpec(corev1ac.PodSpec().
+					WithServiceAccountName(jc.ServiceAccountName).
+					WithVolumes(archiverVolumes(jc)...).
+					WithContainers(container),
+				),
+			),
+		)
+
+	updatePodTemplateWithJobConfig(deployment.Spec.Template, jc)
+
+	if err := setControllerReferenceWithDeployment(cluster, deployment, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set ownerReference to Deployment %s/%s: %w", cluster.Namespace, name, err)
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+	if err != nil {
+		return fmt.Errorf("failed to convert Deployment %s/%s to unstructured: %w", cluster.Namespace, name, err)
+	}
+	patch := &unstructured.Unstructured{
+		Object: obj,
+	}
+
+	var orig appsv1.Deployment
+	err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &orig)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Deployment %s/%s: %w", cluster.Namespace, name, err)
+	}
+
+	origApplyConfig, err := appsv1ac.ExtractDeployment(&orig, fieldManager)
+	if err != nil {
+		return fmt.Errorf("failed to extract Deployment %s/%s: %w", cluster.Namespace, name, err)
+	}
+
+	if equality.Semantic.DeepEqual(deployment, origApplyConfig) {
+		return nil
+	}
+
+	err = r.Patch(ctx, patch, client.Apply, &client.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        pointer.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile %s Deployment for binlog archiving: %w", name, err)
+	}
+
+	if debugController {
+		var updated appsv1.Deployment
+
+		if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &updated); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get Deployment %s/%s: %w", cluster.Namespace, name, err)
+		}
+
+		if diff := cmp.Diff(orig, updated); len(diff) > 0 {
+			fmt.Println(diff)
+		}
+	}
+
+	log.Info("reconciled Deployment for binlog archiving", "deploymentName", name)
+
+	return nil
+}
+
+func (r *MySQLClusterReconciler) deleteV1BinlogArchiver(ctx context.Context, cluster *mocov1beta2.MySQLCluster) error {
+	log := crlog.FromContext(ctx)
+
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.BinlogArchiverName()}, deployment)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.Delete(ctx, deployment); err != nil {
+		log.Error(err, "failed to delete Deployment")
+		return err
+	}
+	log.Info("deleted Deployment for binlog archiving", "deploymentName", deployment.Name)
+	return nil
+}
+
 func (r *MySQLClusterReconciler) reconcileV1RestoreJob(ctx context.Context, req ctrl.Request, cluster *mocov1beta2.MySQLCluster) error {
 	// `spec.restore` is not editable, so we can safely return early if it is nil.
 	if cluster.Spec.Restore == nil {
@@ -1456,9 +2161,21 @@ func (r *MySQLClusterReconciler) reconcileV1RestoreJob(ctx context.Context, req
 		}
 
 		jc := &cluster.Spec.Restore.JobConfig
+		bc, err := r.restoreBucketConfig(ctx, cluster.Spec.Restore)
+		if err != nil {
+			return err
+		}
 
 		args := []string{constants.RestoreSubcommand, fmt.Sprintf("--threads=%d", jc.Threads)}
-		args = append(args, bucketArgs(jc.BucketConfig)...)
+		if cluster.Spec.Restore.StopGTID != "" {
+			args = append(args, "--stop-gtid="+cluster.Spec.Restore.StopGTID)
+		}
+		if cluster.Spec.Restore.VolumeSnapshotName != "" {
+			args = append(args, "--snapshot="+cluster.Spec.Restore.VolumeSnapshotName)
+		}
+		args = append(args, filterArgs(jc.Filter)...)
+		args = append(args, encryptionArgs(jc.Encryption)...)
+		args = append(args, bucketArgs(bc)...)
 		args = append(args, cluster.Spec.Restore.SourceNamespace, cluster.Spec.Restore.SourceName)
 		args = append(args, cluster.Namespace, cluster.Name)
 		args = append(args, cluster.Spec.Restore.RestorePoint.UTC().Format(constants.BackupTimeFormat))
@@ -1517,6 +2234,8 @@ func (r *MySQLClusterReconciler) reconcileV1RestoreJob(ctx context.Context, req
 			WithVolumeMounts(corev1ac.VolumeMount().
 				WithName("work").
 				WithMountPath("/work")).
+			WithVolumeMounts(bucketVolumeMounts(bc)...).
+			WithVolumeMounts(encryptionVolumeMounts(jc.Encryption)...).
 			WithSecurityContext(corev1ac.SecurityContext().WithReadOnlyRootFilesystem(true)).
 			WithResources(resources)
 
@@ -1530,15 +2249,16 @@ func (r *MySQLClusterReconciler) reconcileV1RestoreJob(ctx context.Context, req
 					WithSpec(corev1ac.PodSpec().
 						WithRestartPolicy(corev1.RestartPolicyNever).
 						WithServiceAccountName(cluster.Spec.Restore.JobConfig.ServiceAccountName).
-						WithVolumes(&corev1ac.VolumeApplyConfiguration{
-							Name:                           pointer.String("work"),
-							VolumeSourceApplyConfiguration: corev1ac.VolumeSourceApplyConfiguration(*cluster.Spec.Restore.JobConfig.WorkVolume.DeepCopy()),
-						}).
+						WithVolumes(workVolume(&cluster.Spec.Restore.JobConfig)).
+						WithVolumes(bucketVolumes(bc)...).
+						WithVolumes(encryptionVolumes(jc.Encryption)...).
 						WithContainers(container),
 					),
 				),
 			)
 
+		updatePodTemplateWithJobConfig(job.Spec.Template, jc)
+
 		if err := setControllerReferenceWithJob(cluster, job, r.Scheme); err != nil {
 			return fmt.Errorf("failed to set ownerReference to Job %s/%s: %w", cluster.Namespace, jobName, err)
 		}
@@ -1600,6 +2320,45 @@ func (r *MySQLClusterReconciler) reconcileV1RestoreJob(ctx context.Context, req
 	return nil
 }
 
+// restoreBucketConfig returns the config of the bucket to restore from.
+// If a secondary bucket is chosen, it is looked up in the BackupPolicy of the source cluster.
+// When the source cluster or the BackupPolicy is gone, e.g. with the source region,
+// `jobConfig.bucketConfig` is used instead.
+func (r *MySQLClusterReconciler) restoreBucketConfig(ctx context.Context, rs *mocov1beta2.RestoreSpec) (mocov1beta2.BucketConfig, error) {
+	if rs.SecondaryBucketName == "" {
+		return rs.JobConfig.BucketConfig, nil
+	}
+
+	log := crlog.FromContext(ctx)
+
+	source := &mocov1beta2.MySQLCluster{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: rs.SourceNamespace, Name: rs.SourceName}, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("the source MySQLCluster does not exist; using jobConfig.bucketConfig", "secondaryBucket", rs.SecondaryBucketName)
+			return rs.JobConfig.BucketConfig, nil
+		}
+		return mocov1beta2.BucketConfig{}, fmt.Errorf("failed to get the source MySQLCluster %s/%s: %w", rs.SourceNamespace, rs.SourceName, err)
+	}
+	if source.Spec.BackupPolicyName == nil {
+		log.Info("the source MySQLCluster has no backup policy; using jobConfig.bucketConfig", "secondaryBucket", rs.SecondaryBucketName)
+		return rs.JobConfig.BucketConfig, nil
+	}
+	bpName := *source.Spec.BackupPolicyName
+	bp := &mocov1beta2.BackupPolicy{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: rs.SourceNamespace, Name: bpName}, bp); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("the backup policy does not exist; using jobConfig.bucketConfig", "backuppolicy", bpName, "secondaryBucket", rs.SecondaryBucketName)
+			return rs.JobConfig.BucketConfig, nil
+		}
+		return mocov1beta2.BucketConfig{}, fmt.Errorf("failed to get backup policy %s/%s: %w", rs.SourceNamespace, bpName, err)
+	}
+	sb := bp.Spec.FindSecondaryBucket(rs.SecondaryBucketName)
+	if sb == nil {
+		return mocov1beta2.BucketConfig{}, fmt.Errorf("backup policy %s/%s has no secondary bucket %s", rs.SourceNamespace, bpName, rs.SecondaryBucketName)
+	}
+	return sb.BucketConfig, nil
+}
+
 func (r *MySQLClusterReconciler) reconcileV1RestoreJobRole(ctx context.Context, req ctrl.Request, cluster *mocov1beta2.MySQLCluster) error {
 	log := crlog.FromContext(ctx)
 
@@ -1620,6 +2379,10 @@ func (r *MySQLClusterReconciler) reconcileV1RestoreJobRole(ctx context.Context,
 				WithAPIGroups("").
 				WithResources("events").
 				WithVerbs("create"),
+			rbacv1ac.PolicyRule().
+				WithAPIGroups(volumeSnapshotGroup).
+				WithResources("volumesnapshots").
+				WithVerbs("get"),
 		)
 
 	if err := setControllerReferenceWithRole(cluster, role, r.Scheme); err != nil {
@@ -1821,6 +2584,21 @@ func setControllerReferenceWithStatefulSet(cluster *mocov1beta2.MySQLCluster, st
 	return nil
 }
 
+func setControllerReferenceWithDeployment(cluster *mocov1beta2.MySQLCluster, deployment *appsv1ac.DeploymentApplyConfiguration, scheme *runtime.Scheme) error {
+	gvk, err := apiutil.GVKForObject(cluster, scheme)
+	if err != nil {
+		return err
+	}
+	deployment.WithOwnerReferences(metav1ac.OwnerReference().
+		WithAPIVersion(gvk.GroupVersion().String()).
+		WithKind(gvk.Kind).
+		WithName(cluster.Name).
+		WithUID(cluster.GetUID()).
+		WithBlockOwnerDeletion(true).
+		WithController(true))
+	return nil
+}
+
 func setControllerReferenceWithPVC(cluster *mocov1beta2.MySQLCluster, pvc *corev1ac.PersistentVolumeClaimApplyConfiguration, origPVC *corev1.PersistentVolumeClaim, scheme *runtime.Scheme) error {
 	gvk, err := apiutil.GVKForObject(cluster, scheme)
 	if err != nil {
@@ -2007,6 +2785,7 @@ func (r *MySQLClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
 		Owns(&batchv1.Job{}).
+		Owns(&appsv1.Deployment{}).
 		Watches(&source.Kind{Type: certificateObj}, certHandler).
 		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, configMapHandler).
 		Watches(&source.Kind{Type: &mocov1beta2.BackupPolicy{}}, backupPolicyHandler).
@@ -9,6 +9,7 @@ import (
 
 	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
 	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/cybozu-go/moco/pkg/password"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
@@ -98,6 +99,10 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = k8sClient.DeleteAllOf(ctx, &policyv1beta1.PodDisruptionBudget{}, client.InNamespace("test"))
 		Expect(err).NotTo(HaveOccurred())
+		err = k8sClient.DeleteAllOf(ctx, &mocov1beta2.BackupPolicy{}, client.InNamespace("test"))
+		Expect(err).NotTo(HaveOccurred())
+		err = k8sClient.DeleteAllOf(ctx, &appsv1.Deployment{}, client.InNamespace("test"))
+		Expect(err).NotTo(HaveOccurred())
 
 		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 			Scheme:             scheme,
@@ -1011,6 +1016,9 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		bp.Spec.Schedule = "*/5 * * * *"
 		bp.Spec.SuccessfulJobsHistoryLimit = pointer.Int32(1)
 		bp.Spec.FailedJobsHistoryLimit = pointer.Int32(2)
+		bp.Spec.SecondaryBuckets = []mocov1beta2.SecondaryBucket{
+			{Name: "dr", BucketConfig: mocov1beta2.BucketConfig{BucketName: "dr-bucket", Region: "us-west-2"}},
+		}
 		jc := &bp.Spec.JobConfig
 		jc.Threads = 3
 		jc.ServiceAccountName = "foo"
@@ -1026,13 +1034,75 @@ var _ = Describe("MySQLCluster reconciler", func() {
 				},
 			},
 		}
-		jc.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{
-			EmptyDir: &corev1ac.EmptyDirVolumeSourceApplyConfiguration{},
-		}
+		// an emptyDir volume is used for the working directory.
+		jc.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{}
+		jc.Streaming = true
 		jc.BucketConfig.BucketName = "mybucket"
 		jc.BucketConfig.EndpointURL = "https://foo.bar.baz"
 		jc.BucketConfig.Region = "us-east-1"
 		jc.BucketConfig.UsePathStyle = true
+		jc.Compression = &mocov1beta2.CompressionConfig{
+			Dump: "default",
+		}
+		jc.BucketConfig.UploadConcurrency = 4
+		jc.BucketConfig.PartSize = resource.NewQuantity(16<<20, resource.BinarySI)
+		jc.BucketConfig.RateLimit = resource.NewQuantity(100<<20, resource.BinarySI)
+		jc.BucketConfig.ServerSideEncryption = "aws:kms"
+		jc.BucketConfig.KMSKeyID = "key"
+		jc.BucketConfig.StorageClass = "STANDARD_IA"
+		jc.BucketConfig.Tags = map[string]string{"team": "db", "env": "prod"}
+		jc.BucketConfig.ObjectLock = &mocov1beta2.ObjectLockConfig{Mode: "GOVERNANCE", RetainFor: metav1.Duration{Duration: 720 * time.Hour}}
+		jc.BucketConfig.TLS = &mocov1beta2.BucketTLSConfig{
+			CABundle:             &mocov1beta2.CABundleSource{ConfigMapName: "bucket-ca", Key: "ca.pem"},
+			ClientCertSecretName: "bucket-client-cert",
+		}
+		jc.PodTemplate = &mocov1beta2.JobPodTemplate{
+			ObjectMeta: mocov1beta2.ObjectMeta{
+				Labels:      map[string]string{"foo": "bar", constants.LabelAppName: "ignored"},
+				Annotations: map[string]string{"ann": "value"},
+			},
+			NodeSelector:      map[string]string{"node-role": "backup"},
+			Tolerations:       []mocov1beta2.TolerationApplyConfiguration{{Key: pointer.String("backup"), Operator: (*corev1.TolerationOperator)(pointer.String("Exists"))}},
+			PriorityClassName: "backup",
+			SecurityContext:   &mocov1beta2.PodSecurityContextApplyConfiguration{RunAsNonRoot: pointer.Bool(true)},
+			ContainerSecurityContext: &mocov1beta2.SecurityContextApplyConfiguration{
+				AllowPrivilegeEscalation: pointer.Bool(false),
+				ReadOnlyRootFilesystem:   pointer.Bool(false),
+			},
+			Volumes: []mocov1beta2.VolumeApplyConfiguration{{
+				Name:                           pointer.String("tmp"),
+				VolumeSourceApplyConfiguration: corev1ac.VolumeSourceApplyConfiguration{EmptyDir: corev1ac.EmptyDirVolumeSource()},
+			}},
+			VolumeMounts: []mocov1beta2.VolumeMountApplyConfiguration{{
+				Name:      pointer.String("tmp"),
+				MountPath: pointer.String("/tmp"),
+			}},
+		}
+		bp.Spec.Retention = &mocov1beta2.RetentionPolicy{
+			KeepLast: 3,
+			KeepFor:  &metav1.Duration{Duration: 72 * time.Hour},
+		}
+		bp.Spec.Source = &mocov1beta2.BackupSourcePolicy{
+			Zone:           "zone-a",
+			PreferLeastLag: true,
+		}
+		bp.Spec.Hooks = &mocov1beta2.BackupHooks{
+			PreBackup: []mocov1beta2.BackupHook{
+				{Name: "flush", SQL: []string{"FLUSH TABLES"}},
+			},
+			PostBackup: []mocov1beta2.BackupHook{
+				{Name: "notify", HTTP: &mocov1beta2.HTTPHook{
+					URL: "https://catalog.example.com/",
+					Headers: []mocov1beta2.HTTPHeader{{
+						Name: "Authorization",
+						ValueFrom: mocov1beta2.HTTPHeaderSource{SecretKeyRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "catalog"},
+							Key:                  "token",
+						}},
+					}},
+				}},
+			},
+		}
 		err = k8sClient.Create(ctx, bp)
 		Expect(err).NotTo(HaveOccurred())
 
@@ -1066,11 +1136,26 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		js := &cj.Spec.JobTemplate.Spec
 		Expect(js.ActiveDeadlineSeconds).To(Equal(pointer.Int64(100)))
 		Expect(js.BackoffLimit).To(Equal(pointer.Int32(1)))
-		Expect(js.Template.Labels).NotTo(BeEmpty())
+		Expect(js.Template.Labels).To(HaveKeyWithValue("foo", "bar"))
+		Expect(js.Template.Labels).To(HaveKeyWithValue(constants.LabelAppName, constants.AppNameBackup))
+		Expect(js.Template.Annotations).To(HaveKeyWithValue("ann", "value"))
 		Expect(js.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
 		Expect(js.Template.Spec.ServiceAccountName).To(Equal("foo"))
-		Expect(js.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(js.Template.Spec.NodeSelector).To(Equal(map[string]string{"node-role": "backup"}))
+		Expect(js.Template.Spec.Tolerations).To(Equal([]corev1.Toleration{{Key: "backup", Operator: corev1.TolerationOpExists}}))
+		Expect(js.Template.Spec.PriorityClassName).To(Equal("backup"))
+		Expect(js.Template.Spec.SecurityContext).NotTo(BeNil())
+		Expect(js.Template.Spec.SecurityContext.RunAsNonRoot).To(Equal(pointer.Bool(true)))
+		Expect(js.Template.Spec.Volumes).To(HaveLen(4))
+		Expect(js.Template.Spec.Volumes[3].Name).To(Equal("tmp"))
 		Expect(js.Template.Spec.Volumes[0].EmptyDir).NotTo(BeNil())
+		Expect(js.Template.Spec.Volumes[1].Name).To(Equal("bucket-ca"))
+		Expect(js.Template.Spec.Volumes[1].ConfigMap).NotTo(BeNil())
+		Expect(js.Template.Spec.Volumes[1].ConfigMap.Name).To(Equal("bucket-ca"))
+		Expect(js.Template.Spec.Volumes[1].ConfigMap.Items).To(Equal([]corev1.KeyToPath{{Key: "ca.pem", Path: "ca.crt"}}))
+		Expect(js.Template.Spec.Volumes[2].Name).To(Equal("bucket-client-cert"))
+		Expect(js.Template.Spec.Volumes[2].Secret).NotTo(BeNil())
+		Expect(js.Template.Spec.Volumes[2].Secret.SecretName).To(Equal("bucket-client-cert"))
 		Expect(js.Template.Spec.Containers).To(HaveLen(1))
 		c := &js.Template.Spec.Containers[0]
 		Expect(c.Name).To(Equal("backup"))
@@ -1078,16 +1163,50 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		Expect(c.Args).To(Equal([]string{
 			"backup",
 			"--threads=3",
+			"--keep-last=3",
+			"--keep-for=72h0m0s",
+			"--source-zone=zone-a",
+			"--prefer-least-lag",
+			`--hooks={"preBackup":[{"name":"flush","sql":["FLUSH TABLES"],"failurePolicy":"Abort","timeout":"1m0s"}],` +
+				`"postBackup":[{"name":"notify","http":{"url":"https://catalog.example.com/","headers":[{"name":"Authorization","valueFrom":{"secretKeyRef":{"name":"catalog","key":"token"}}}]},"failurePolicy":"Abort","timeout":"1m0s"}]}`,
+			"--dump-compression=default",
+			"--stream",
+			`--secondary-buckets=[{"name":"dr","bucketConfig":{"bucketName":"dr-bucket","region":"us-west-2"}}]`,
 			"--region=us-east-1",
 			"--endpoint=https://foo.bar.baz",
 			"--use-path-style",
+			"--upload-concurrency=4",
+			"--part-size=16777216",
+			"--rate-limit=104857600",
+			"--sse=aws:kms",
+			"--kms-key-id=key",
+			"--storage-class=STANDARD_IA",
+			"--tags=env=prod",
+			"--tags=team=db",
+			"--object-lock-mode=GOVERNANCE",
+			"--object-lock-retain-for=720h0m0s",
+			"--ca-bundle=/bucket-ca/ca.crt",
+			"--client-cert=/bucket-client-cert/tls.crt",
+			"--client-key=/bucket-client-cert/tls.key",
 			"mybucket",
 			"test",
 			"test",
 		}))
 		Expect(c.EnvFrom).To(HaveLen(1))
-		Expect(c.Env).To(HaveLen(2))
-		Expect(c.VolumeMounts).To(HaveLen(1))
+		Expect(c.Env).To(HaveLen(3))
+		Expect(c.Env[2].Name).To(Equal("MOCO_HOOK_POSTBACKUP_0_HEADER_0"))
+		Expect(c.Env[2].Value).To(BeEmpty())
+		Expect(c.Env[2].ValueFrom).NotTo(BeNil())
+		Expect(c.Env[2].ValueFrom.SecretKeyRef).To(Equal(&corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "catalog"},
+			Key:                  "token",
+		}))
+		Expect(c.VolumeMounts).To(HaveLen(4))
+		Expect(c.VolumeMounts[3].MountPath).To(Equal("/tmp"))
+		Expect(c.SecurityContext).NotTo(BeNil())
+		Expect(c.SecurityContext.AllowPrivilegeEscalation).To(Equal(pointer.Bool(false)))
+		Expect(c.SecurityContext.ReadOnlyRootFilesystem).To(Equal(pointer.Bool(true)))
+		Expect(c.SecurityContext.RunAsUser).To(Equal(pointer.Int64(constants.ContainerUID)))
 		cpuReq := c.Resources.Requests[corev1.ResourceCPU]
 		Expect(cpuReq.Value()).To(BeNumerically("==", 3))
 		memReq := c.Resources.Requests[corev1.ResourceMemory]
@@ -1115,6 +1234,12 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		bp.Spec.Schedule = "*/5 1 * * *"
 		bp.Spec.SuccessfulJobsHistoryLimit = nil
 		bp.Spec.FailedJobsHistoryLimit = nil
+		bp.Spec.Retention = nil
+		bp.Spec.Hooks = nil
+		bp.Spec.Source = &mocov1beta2.BackupSourcePolicy{
+			Index:          pointer.Int32(1),
+			ExcludePrimary: true,
+		}
 		jc = &bp.Spec.JobConfig
 		jc.Threads = 1
 		jc.ServiceAccountName = "oof"
@@ -1127,10 +1252,26 @@ var _ = Describe("MySQLCluster reconciler", func() {
 				Path: pointer.String("/host"),
 			},
 		}
-		jc.BucketConfig.BucketName = "mybucket2"
-		jc.BucketConfig.EndpointURL = ""
-		jc.BucketConfig.Region = ""
-		jc.BucketConfig.UsePathStyle = false
+		jc.BucketConfig = mocov1beta2.BucketConfig{
+			BucketName:  "mybucket2",
+			BackendType: constants.BackendTypeFile,
+			Volume: &mocov1beta2.VolumeSourceApplyConfiguration{
+				PersistentVolumeClaim: &corev1ac.PersistentVolumeClaimVolumeSourceApplyConfiguration{
+					ClaimName: pointer.String("backup-pvc"),
+				},
+			},
+		}
+		jc.Compression = nil
+		jc.PodTemplate = nil
+		jc.Streaming = false
+		jc.Encryption = &mocov1beta2.EncryptionConfig{
+			SecretName: "backup-keys",
+			KeyID:      "key2",
+		}
+		jc.Filter = &mocov1beta2.FilterConfig{
+			IncludeSchemas: []string{"foo", "bar"},
+			ExcludeTables:  []string{"foo.cache"},
+		}
 		err = k8sClient.Update(ctx, bp)
 		Expect(err).NotTo(HaveOccurred())
 
@@ -1153,20 +1294,38 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		Expect(js.ActiveDeadlineSeconds).To(BeNil())
 		Expect(js.BackoffLimit).To(BeNil())
 		Expect(js.Template.Spec.ServiceAccountName).To(Equal("oof"))
-		Expect(js.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(js.Template.Spec.NodeSelector).To(BeEmpty())
+		Expect(js.Template.Spec.Volumes).To(HaveLen(3))
 		Expect(js.Template.Spec.Volumes[0].EmptyDir).To(BeNil())
 		Expect(js.Template.Spec.Volumes[0].HostPath).NotTo(BeNil())
+		Expect(js.Template.Spec.Volumes[1].Name).To(Equal("bucket"))
+		Expect(js.Template.Spec.Volumes[1].PersistentVolumeClaim).NotTo(BeNil())
+		Expect(js.Template.Spec.Volumes[1].PersistentVolumeClaim.ClaimName).To(Equal("backup-pvc"))
+		Expect(js.Template.Spec.Volumes[2].Name).To(Equal("encryption-keys"))
+		Expect(js.Template.Spec.Volumes[2].Secret).NotTo(BeNil())
+		Expect(js.Template.Spec.Volumes[2].Secret.SecretName).To(Equal("backup-keys"))
 		Expect(js.Template.Spec.Containers).To(HaveLen(1))
 		c = &js.Template.Spec.Containers[0]
 		Expect(c.Args).To(Equal([]string{
 			"backup",
 			"--threads=1",
+			"--source-index=1",
+			"--exclude-primary",
+			"--include-schemas=foo,bar",
+			"--exclude-tables=foo.cache",
+			"--encryption-key-dir=/encryption-keys",
+			"--encryption-key-id=key2",
+			"--backend-type=file",
 			"mybucket2",
 			"test",
 			"test",
 		}))
 		Expect(c.EnvFrom).To(BeEmpty())
 		Expect(c.Env).To(HaveLen(1))
+		Expect(c.VolumeMounts).To(HaveLen(3))
+		Expect(c.VolumeMounts[1].MountPath).To(Equal(constants.BucketVolumeMountPath))
+		Expect(c.VolumeMounts[2].MountPath).To(Equal(constants.EncryptionKeyMountPath))
+		Expect(c.VolumeMounts[2].ReadOnly).To(BeTrue())
 		cpuReq = c.Resources.Requests[corev1.ResourceCPU]
 		Expect(cpuReq.Value()).To(BeNumerically("==", 1))
 		memReq = c.Resources.Requests[corev1.ResourceMemory]
@@ -1214,6 +1373,325 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		}).Should(BeTrue())
 	})
 
+	It("should reconcile a deployment for binlog archiving", func() {
+		cluster := testNewMySQLCluster("test")
+		cluster.Spec.BackupPolicyName = pointer.String("test-policy")
+		err := k8sClient.Create(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("creating a backup policy with binlog archiving")
+		bp := &mocov1beta2.BackupPolicy{}
+		bp.Namespace = "test"
+		bp.Name = "test-policy"
+		bp.Spec.Schedule = "*/5 * * * *"
+		jc := &bp.Spec.JobConfig
+		jc.Threads = 3
+		jc.ServiceAccountName = "foo"
+		// the archiver must not share the work volume with backup Jobs.
+		jc.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{
+			PersistentVolumeClaim: &corev1ac.PersistentVolumeClaimVolumeSourceApplyConfiguration{
+				ClaimName: pointer.String("backup-work"),
+			},
+		}
+		jc.BucketConfig.BucketName = "mybucket"
+		jc.Encryption = &mocov1beta2.EncryptionConfig{
+			SecretName: "backup-keys",
+			KeyID:      "key1",
+		}
+		jc.Compression = &mocov1beta2.CompressionConfig{
+			Dump:   "none",
+			Binlog: "best",
+		}
+		bp.Spec.BinlogArchive = &mocov1beta2.BinlogArchiveSpec{
+			Interval: metav1.Duration{Duration: 3 * time.Minute},
+		}
+		bp.Spec.SecondaryBuckets = []mocov1beta2.SecondaryBucket{
+			{Name: "dr", BucketConfig: mocov1beta2.BucketConfig{BucketName: "dr-bucket"}},
+		}
+		err = k8sClient.Create(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+
+		var deploy *appsv1.Deployment
+		Eventually(func() error {
+			deploy = &appsv1.Deployment{}
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BinlogArchiverName()}, deploy)
+		}).Should(Succeed())
+
+		Expect(deploy.Labels).To(HaveKeyWithValue(constants.LabelAppName, constants.AppNameArchiver))
+		Expect(deploy.OwnerReferences).NotTo(BeEmpty())
+		Expect(deploy.Spec.Replicas).To(Equal(pointer.Int32(1)))
+		Expect(deploy.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+		Expect(deploy.Spec.Selector.MatchLabels).To(Equal(deploy.Spec.Template.Labels))
+		ps := &deploy.Spec.Template.Spec
+		Expect(ps.ServiceAccountName).To(Equal("foo"))
+		Expect(ps.Volumes).To(HaveLen(2))
+		Expect(ps.Volumes[0].Name).To(Equal("work"))
+		Expect(ps.Volumes[0].EmptyDir).NotTo(BeNil())
+		Expect(ps.Volumes[0].PersistentVolumeClaim).To(BeNil())
+		Expect(ps.Volumes[1].Name).To(Equal("encryption-keys"))
+		Expect(ps.Containers).To(HaveLen(1))
+		c := &ps.Containers[0]
+		Expect(c.Name).To(Equal("archiver"))
+		Expect(c.Image).To(Equal(testBackupImage))
+		Expect(c.Args).To(Equal([]string{
+			"archive-binlog",
+			"--threads=3",
+			"--interval=3m0s",
+			"--binlog-compression=best",
+			`--secondary-buckets=[{"name":"dr","bucketConfig":{"bucketName":"dr-bucket"}}]`,
+			"--encryption-key-dir=/encryption-keys",
+			"--encryption-key-id=key1",
+			"mybucket",
+			"test",
+			"test",
+		}))
+		Expect(c.Env).To(HaveLen(1))
+		Expect(c.VolumeMounts).To(HaveLen(2))
+
+		By("disabling binlog archiving")
+		bp = &mocov1beta2.BackupPolicy{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "test-policy"}, bp)
+		Expect(err).NotTo(HaveOccurred())
+		bp.Spec.BinlogArchive = nil
+		err = k8sClient.Update(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() bool {
+			deploy = &appsv1.Deployment{}
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BinlogArchiverName()}, deploy)
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue())
+
+		By("enabling binlog archiving again")
+		bp = &mocov1beta2.BackupPolicy{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "test-policy"}, bp)
+		Expect(err).NotTo(HaveOccurred())
+		bp.Spec.BinlogArchive = &mocov1beta2.BinlogArchiveSpec{
+			Interval: metav1.Duration{Duration: 3 * time.Minute},
+		}
+		err = k8sClient.Update(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() error {
+			deploy = &appsv1.Deployment{}
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BinlogArchiverName()}, deploy)
+		}).Should(Succeed())
+
+		By("disabling backup")
+		cluster = &mocov1beta2.MySQLCluster{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "test"}, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Spec.BackupPolicyName = nil
+		err = k8sClient.Update(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() bool {
+			deploy = &appsv1.Deployment{}
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BinlogArchiverName()}, deploy)
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue())
+	})
+
+	It("should reconcile a CronJob for binlog-only backup", func() {
+		cluster := testNewMySQLCluster("test")
+		cluster.Spec.BackupPolicyName = pointer.String("test-policy")
+		err := k8sClient.Create(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("creating a backup policy with a binlog schedule")
+		bp := &mocov1beta2.BackupPolicy{}
+		bp.Namespace = "test"
+		bp.Name = "test-policy"
+		bp.Spec.Schedule = "0 0 * * *"
+		bp.Spec.BinlogSchedule = "*/10 * * * *"
+		bp.Spec.ConcurrencyPolicy = batchv1beta1.AllowConcurrent
+		jc := &bp.Spec.JobConfig
+		jc.Threads = 3
+		jc.ServiceAccountName = "foo"
+		jc.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{
+			EmptyDir: &corev1ac.EmptyDirVolumeSourceApplyConfiguration{},
+		}
+		jc.BucketConfig.BucketName = "mybucket"
+		jc.Compression = &mocov1beta2.CompressionConfig{
+			Dump:   "none",
+			Binlog: "best",
+		}
+		bp.Spec.Retention = &mocov1beta2.RetentionPolicy{KeepLast: 3}
+		err = k8sClient.Create(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+
+		var cj *batchv1beta1.CronJob
+		Eventually(func() error {
+			cj = &batchv1beta1.CronJob{}
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BinlogBackupCronJobName()}, cj)
+		}).Should(Succeed())
+
+		Expect(cj.OwnerReferences).NotTo(BeEmpty())
+		Expect(cj.Spec.Schedule).To(Equal("*/10 * * * *"))
+		Expect(cj.Spec.ConcurrencyPolicy).To(Equal(batchv1beta1.ForbidConcurrent))
+		ps := &cj.Spec.JobTemplate.Spec.Template.Spec
+		Expect(ps.ServiceAccountName).To(Equal("foo"))
+		Expect(ps.Containers).To(HaveLen(1))
+		c := &ps.Containers[0]
+		Expect(c.Image).To(Equal(testBackupImage))
+		Expect(c.Args).To(Equal([]string{
+			"backup",
+			"--binlog-only",
+			"--threads=3",
+			"--binlog-compression=best",
+			"mybucket",
+			"test",
+			"test",
+		}))
+
+		full := &batchv1beta1.CronJob{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BackupCronJobName()}, full)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(full.Spec.Schedule).To(Equal("0 0 * * *"))
+		Expect(full.Spec.ConcurrencyPolicy).To(Equal(batchv1beta1.AllowConcurrent))
+
+		By("removing the binlog schedule")
+		bp = &mocov1beta2.BackupPolicy{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "test-policy"}, bp)
+		Expect(err).NotTo(HaveOccurred())
+		bp.Spec.BinlogSchedule = ""
+		err = k8sClient.Update(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() bool {
+			cj = &batchv1beta1.CronJob{}
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BinlogBackupCronJobName()}, cj)
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue())
+
+		By("setting the binlog schedule again")
+		bp = &mocov1beta2.BackupPolicy{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "test-policy"}, bp)
+		Expect(err).NotTo(HaveOccurred())
+		bp.Spec.BinlogSchedule = "*/10 * * * *"
+		err = k8sClient.Update(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() error {
+			cj = &batchv1beta1.CronJob{}
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BinlogBackupCronJobName()}, cj)
+		}).Should(Succeed())
+
+		By("disabling backup")
+		cluster = &mocov1beta2.MySQLCluster{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "test"}, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Spec.BackupPolicyName = nil
+		err = k8sClient.Update(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() bool {
+			cj = &batchv1beta1.CronJob{}
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BinlogBackupCronJobName()}, cj)
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue())
+	})
+
+	It("should reconcile a CronJob for restore drills", func() {
+		cluster := testNewMySQLCluster("test")
+		cluster.Spec.BackupPolicyName = pointer.String("test-policy")
+		err := k8sClient.Create(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("creating a backup policy with a restore drill")
+		bp := &mocov1beta2.BackupPolicy{}
+		bp.Namespace = "test"
+		bp.Name = "test-policy"
+		bp.Spec.Schedule = "0 0 * * *"
+		bp.Spec.RestoreDrill = &mocov1beta2.RestoreDrillSpec{
+			Schedule: "0 12 * * 6",
+			Assertions: []mocov1beta2.RestoreDrillAssertion{
+				{Name: "count", Query: "SELECT COUNT(*) FROM db.t", Min: pointer.Int64(1)},
+			},
+			Timeout: metav1.Duration{Duration: 3 * time.Hour},
+		}
+		jc := &bp.Spec.JobConfig
+		jc.Threads = 3
+		jc.ServiceAccountName = "foo"
+		jc.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{
+			EmptyDir: &corev1ac.EmptyDirVolumeSourceApplyConfiguration{},
+		}
+		jc.BucketConfig.BucketName = "mybucket"
+		err = k8sClient.Create(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+
+		var cj *batchv1beta1.CronJob
+		Eventually(func() error {
+			cj = &batchv1beta1.CronJob{}
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.RestoreDrillCronJobName()}, cj)
+		}).Should(Succeed())
+
+		Expect(cj.OwnerReferences).NotTo(BeEmpty())
+		Expect(cj.Spec.Schedule).To(Equal("0 12 * * 6"))
+		Expect(cj.Spec.ConcurrencyPolicy).To(Equal(batchv1beta1.ForbidConcurrent))
+		ps := &cj.Spec.JobTemplate.Spec.Template.Spec
+		Expect(ps.ServiceAccountName).To(Equal("foo"))
+		Expect(ps.Containers).To(HaveLen(1))
+		c := &ps.Containers[0]
+		Expect(c.Image).To(Equal(testBackupImage))
+		Expect(c.Args).To(Equal([]string{
+			"drill",
+			"--timeout=3h0m0s",
+			`--assertions=[{"name":"count","query":"SELECT COUNT(*) FROM db.t","min":1}]`,
+			"test",
+			"test",
+		}))
+
+		var role *rbacv1.Role
+		Eventually(func() []string {
+			role = &rbacv1.Role{}
+			if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BackupRoleName()}, role); err != nil {
+				return nil
+			}
+			var resources []string
+			for _, rule := range role.Rules {
+				resources = append(resources, rule.Resources...)
+			}
+			return resources
+		}).Should(ContainElements("secrets", "persistentvolumeclaims"))
+		for _, rule := range role.Rules {
+			if len(rule.Resources) == 1 && rule.Resources[0] == "persistentvolumeclaims" {
+				Expect(rule.ResourceNames).To(Equal([]string{"mysql-data-moco-moco-scratch-test-0"}))
+			}
+			// the scratch MySQLCluster is created by moco-controller, not by the Job.
+			if len(rule.APIGroups) == 1 && rule.APIGroups[0] == mocov1beta2.GroupVersion.Group {
+				Expect(rule.Verbs).NotTo(ContainElement("create"), "rule: %+v", rule)
+				Expect(rule.Verbs).NotTo(ContainElement("delete"), "rule: %+v", rule)
+			}
+		}
+
+		By("removing the restore drill")
+		bp = &mocov1beta2.BackupPolicy{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "test-policy"}, bp)
+		Expect(err).NotTo(HaveOccurred())
+		bp.Spec.RestoreDrill = nil
+		err = k8sClient.Update(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() bool {
+			cj = &batchv1beta1.CronJob{}
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.RestoreDrillCronJobName()}, cj)
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue())
+
+		Eventually(func() []string {
+			role = &rbacv1.Role{}
+			if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.BackupRoleName()}, role); err != nil {
+				return nil
+			}
+			var resources []string
+			for _, rule := range role.Rules {
+				resources = append(resources, rule.Resources...)
+			}
+			return resources
+		}).ShouldNot(ContainElement("persistentvolumeclaims"))
+	})
+
 	It("should reconcile restore related resources", func() {
 		By("creating a MySQLCluster with restore spec")
 		now := metav1.Now()
@@ -1222,6 +1700,7 @@ var _ = Describe("MySQLCluster reconciler", func() {
 			SourceName:      "single",
 			SourceNamespace: "ns",
 			RestorePoint:    now,
+			StopGTID:        "3e11fa47-71ca-11e1-9e33-c80aa9429562:23",
 		}
 		jc := &cluster.Spec.Restore.JobConfig
 		jc.Threads = 3
@@ -1245,6 +1724,8 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		jc.BucketConfig.EndpointURL = "https://foo.bar.baz"
 		jc.BucketConfig.Region = "us-east-1"
 		jc.BucketConfig.UsePathStyle = true
+		jc.Encryption = &mocov1beta2.EncryptionConfig{SecretName: "backup-keys"}
+		jc.Filter = &mocov1beta2.FilterConfig{IncludeSchemas: []string{"foo"}}
 		err := k8sClient.Create(ctx, cluster)
 		Expect(err).NotTo(HaveOccurred())
 
@@ -1274,8 +1755,10 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		Expect(js.Template.Labels).NotTo(BeEmpty())
 		Expect(js.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
 		Expect(js.Template.Spec.ServiceAccountName).To(Equal("foo"))
-		Expect(js.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(js.Template.Spec.Volumes).To(HaveLen(2))
 		Expect(js.Template.Spec.Volumes[0].EmptyDir).NotTo(BeNil())
+		Expect(js.Template.Spec.Volumes[1].Secret).NotTo(BeNil())
+		Expect(js.Template.Spec.Volumes[1].Secret.SecretName).To(Equal("backup-keys"))
 		Expect(js.Template.Spec.Containers).To(HaveLen(1))
 		c := &js.Template.Spec.Containers[0]
 		Expect(c.Name).To(Equal("restore"))
@@ -1283,6 +1766,9 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		Expect(c.Args).To(Equal([]string{
 			"restore",
 			"--threads=3",
+			"--stop-gtid=3e11fa47-71ca-11e1-9e33-c80aa9429562:23",
+			"--include-schemas=foo",
+			"--encryption-key-dir=/encryption-keys",
 			"--region=us-east-1",
 			"--endpoint=https://foo.bar.baz",
 			"--use-path-style",
@@ -1295,7 +1781,7 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		}))
 		Expect(c.EnvFrom).To(HaveLen(1))
 		Expect(c.Env).To(HaveLen(2))
-		Expect(c.VolumeMounts).To(HaveLen(1))
+		Expect(c.VolumeMounts).To(HaveLen(2))
 		cpuReq := c.Resources.Requests[corev1.ResourceCPU]
 		Expect(cpuReq.Value()).To(BeNumerically("==", 3))
 		memReq := c.Resources.Requests[corev1.ResourceMemory]
@@ -1355,6 +1841,150 @@ var _ = Describe("MySQLCluster reconciler", func() {
 		}, 5).Should(BeTrue())
 	})
 
+	It("should restore from a VolumeSnapshot", func() {
+		By("creating the controller Secret of the source cluster")
+		passwd, err := password.NewMySQLPassword()
+		Expect(err).NotTo(HaveOccurred())
+		source := passwd.ToSecret()
+		source.Namespace = testMocoSystemNamespace
+		source.Name = "mysql-test.single"
+		err = k8sClient.Create(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+		defer k8sClient.Delete(ctx, source)
+
+		By("creating a MySQLCluster with restore spec")
+		now := metav1.Now()
+		cluster := testNewMySQLCluster("test")
+		cluster.Spec.Restore = &mocov1beta2.RestoreSpec{
+			SourceName:         "single",
+			SourceNamespace:    "test",
+			RestorePoint:       now,
+			VolumeSnapshotName: "moco-single-20211201-000000",
+		}
+		jc := &cluster.Spec.Restore.JobConfig
+		jc.ServiceAccountName = "foo"
+		jc.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{
+			EmptyDir: &corev1ac.EmptyDirVolumeSourceApplyConfiguration{},
+		}
+		jc.BucketConfig.BucketName = "mybucket"
+		err = k8sClient.Create(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		var job *batchv1.Job
+		var pvc *corev1.PersistentVolumeClaim
+		Eventually(func() error {
+			job = &batchv1.Job{}
+			if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.RestoreJobName()}, job); err != nil {
+				return err
+			}
+			pvc = &corev1.PersistentVolumeClaim{}
+			if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: "mysql-data-moco-test-0"}, pvc); err != nil {
+				return err
+			}
+			return nil
+		}).Should(Succeed())
+		defer k8sClient.Delete(ctx, pvc)
+
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+		Expect(job.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--snapshot=moco-single-20211201-000000"))
+
+		Expect(pvc.Labels).To(HaveKeyWithValue(constants.LabelAppInstance, "test"))
+		Expect(pvc.OwnerReferences).NotTo(BeEmpty())
+		Expect(pvc.Spec.DataSource).To(Equal(&corev1.TypedLocalObjectReference{
+			APIGroup: pointer.String("snapshot.storage.k8s.io"),
+			Kind:     "VolumeSnapshot",
+			Name:     "moco-single-20211201-000000",
+		}))
+
+		By("checking the passwords are taken over from the source cluster")
+		secret := &corev1.Secret{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: testMocoSystemNamespace, Name: "mysql-test.test"}, secret)
+		}).Should(Succeed())
+		restored, err := password.NewMySQLPasswordFromSecret(secret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored.Admin()).To(Equal(passwd.Admin()))
+	})
+
+	It("should restore from a secondary bucket", func() {
+		By("creating the source cluster with a backup policy")
+		bp := &mocov1beta2.BackupPolicy{}
+		bp.Namespace = "test"
+		bp.Name = "single-policy"
+		bp.Spec.Schedule = "*/5 * * * *"
+		bp.Spec.JobConfig.ServiceAccountName = "foo"
+		bp.Spec.JobConfig.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{
+			EmptyDir: &corev1ac.EmptyDirVolumeSourceApplyConfiguration{},
+		}
+		bp.Spec.JobConfig.BucketConfig.BucketName = "mybucket"
+		bp.Spec.SecondaryBuckets = []mocov1beta2.SecondaryBucket{
+			{Name: "dr", BucketConfig: mocov1beta2.BucketConfig{BucketName: "dr-bucket", Region: "us-west-2"}},
+		}
+		err := k8sClient.Create(ctx, bp)
+		Expect(err).NotTo(HaveOccurred())
+
+		source := testNewMySQLCluster("test")
+		source.Name = "single"
+		source.Spec.BackupPolicyName = pointer.String("single-policy")
+		err = k8sClient.Create(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("creating a MySQLCluster restoring from the secondary bucket")
+		cluster := testNewMySQLCluster("test")
+		cluster.Spec.Restore = &mocov1beta2.RestoreSpec{
+			SourceName:          "single",
+			SourceNamespace:     "test",
+			RestorePoint:        metav1.Now(),
+			SecondaryBucketName: "dr",
+		}
+		jc := &cluster.Spec.Restore.JobConfig
+		jc.ServiceAccountName = "foo"
+		jc.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{
+			EmptyDir: &corev1ac.EmptyDirVolumeSourceApplyConfiguration{},
+		}
+		jc.BucketConfig.BucketName = "mybucket"
+		err = k8sClient.Create(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		var job *batchv1.Job
+		Eventually(func() error {
+			job = &batchv1.Job{}
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.RestoreJobName()}, job)
+		}).Should(Succeed())
+
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+		args := job.Spec.Template.Spec.Containers[0].Args
+		Expect(args).To(ContainElements("--region=us-west-2", "dr-bucket"))
+		Expect(args).NotTo(ContainElement("mybucket"))
+	})
+
+	It("should restore from jobConfig.bucketConfig if the source is gone", func() {
+		cluster := testNewMySQLCluster("test")
+		cluster.Spec.Restore = &mocov1beta2.RestoreSpec{
+			SourceName:          "lost",
+			SourceNamespace:     "test",
+			RestorePoint:        metav1.Now(),
+			SecondaryBucketName: "dr",
+		}
+		jc := &cluster.Spec.Restore.JobConfig
+		jc.ServiceAccountName = "foo"
+		jc.WorkVolume = mocov1beta2.VolumeSourceApplyConfiguration{
+			EmptyDir: &corev1ac.EmptyDirVolumeSourceApplyConfiguration{},
+		}
+		jc.BucketConfig.BucketName = "dr-bucket"
+		err := k8sClient.Create(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		var job *batchv1.Job
+		Eventually(func() error {
+			job = &batchv1.Job{}
+			return k8sClient.Get(ctx, client.ObjectKey{Namespace: "test", Name: cluster.RestoreJobName()}, job)
+		}).Should(Succeed())
+
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+		Expect(job.Spec.Template.Spec.Containers[0].Args).To(ContainElement("dr-bucket"))
+	})
+
 	It("should have a correct status.reconcileInfo value", func() {
 		cluster := testNewMySQLCluster("test")
 		err := k8sClient.Create(ctx, cluster)
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/pkg/constants"
+	"github.com/cybozu-go/moco/pkg/password"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// volumeSnapshotGroup is the API group of CSI VolumeSnapshot.
+const volumeSnapshotGroup = "snapshot.storage.k8s.io"
+
+// isRestoringFromSnapshot returns true if the cluster is to be restored from a VolumeSnapshot.
+func isRestoringFromSnapshot(cluster *mocov1beta2.MySQLCluster) bool {
+	return cluster.Spec.Restore != nil && cluster.Spec.Restore.VolumeSnapshotName != "" && cluster.Status.RestoredTime == nil
+}
+
+// newMySQLPassword generates the passwords for a new cluster.
+//
+// A cluster restored from a snapshot uses the passwords of the source cluster
+// because the users in the snapshot have them.
+func (r *MySQLClusterReconciler) newMySQLPassword(ctx context.Context, cluster *mocov1beta2.MySQLCluster) (*password.MySQLPassword, error) {
+	if !isRestoringFromSnapshot(cluster) {
+		return password.NewMySQLPassword()
+	}
+
+	source := &mocov1beta2.MySQLCluster{}
+	source.Namespace = cluster.Spec.Restore.SourceNamespace
+	source.Name = cluster.Spec.Restore.SourceName
+	name := source.ControllerSecretName()
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get the controller Secret %s of the source cluster: %w", name, err)
+	}
+	return password.NewMySQLPasswordFromSecret(secret)
+}
+
+// reconcileV1SnapshotPVC creates the data volume of the first instance from
+// the VolumeSnapshot to restore from.  This must be done before the StatefulSet
+// is created so that the StatefulSet uses the PVC instead of creating an empty one.
+//
+// Other instances are provisioned as usual and clone the data from the first instance.
+func (r *MySQLClusterReconciler) reconcileV1SnapshotPVC(ctx context.Context, req ctrl.Request, cluster *mocov1beta2.MySQLCluster) error {
+	log := crlog.FromContext(ctx)
+
+	if !isRestoringFromSnapshot(cluster) {
+		return nil
+	}
+
+	var sts appsv1.StatefulSet
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.PrefixedName()}, &sts)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get StatefulSet %s/%s: %w", cluster.Namespace, cluster.PrefixedName(), err)
+	}
+
+	name := constants.MySQLDataVolumeName + "-" + cluster.PodName(0)
+	var orig corev1.PersistentVolumeClaim
+	err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &orig)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get PVC %s/%s: %w", cluster.Namespace, name, err)
+	}
+
+	var pvc *corev1ac.PersistentVolumeClaimApplyConfiguration
+	for _, v := range cluster.Spec.VolumeClaimTemplates {
+		if v.Name == constants.MySQLDataVolumeName {
+			pvc = v.ToCoreV1()
+			break
+		}
+	}
+	if pvc == nil {
+		return fmt.Errorf("no volume claim template for %s", constants.MySQLDataVolumeName)
+	}
+
+	snapshotName := cluster.Spec.Restore.VolumeSnapshotName
+	pvc.WithName(name).
+		WithNamespace(cluster.Namespace).
+		WithLabels(labelSet(cluster, false))
+	pvc.Spec.WithDataSource(corev1ac.TypedLocalObjectReference().
+		WithAPIGroup(volumeSnapshotGroup).
+		WithKind("VolumeSnapshot").
+		WithName(snapshotName))
+	pvc.Status = nil
+
+	if err := setControllerReferenceWithPVC(cluster, pvc, nil, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set ownerReference to PVC %s/%s: %w", cluster.Namespace, name, err)
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pvc)
+	if err != nil {
+		return fmt.Errorf("failed to convert PVC %s/%s to unstructured: %w", cluster.Namespace, name, err)
+	}
+	patch := &unstructured.Unstructured{
+		Object: obj,
+	}
+
+	err = r.Patch(ctx, patch, client.Apply, &client.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        pointer.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create PVC %s/%s from VolumeSnapshot %s: %w", cluster.Namespace, name, snapshotName, err)
+	}
+
+	log.Info("created PVC from VolumeSnapshot", "pvcName", name, "snapshotName", snapshotName)
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/cybozu-go/moco/pkg/constants"
 )
 
-func (o operator) DumpFull(ctx context.Context, dir string) error {
+func (o operator) DumpFull(ctx context.Context, dir string, filter *Filter) error {
 	args := []string{
 		fmt.Sprintf("mysql://%s@%s:%d", o.user, o.host, o.port),
 		"--passwords-from-stdin",
@@ -23,6 +23,7 @@ func (o operator) DumpFull(ctx context.Context, dir string) error {
 		"--excludeUsers=" + strings.Join(constants.MocoUsers, ","),
 		"--threads=" + fmt.Sprint(o.threads),
 	}
+	args = append(args, filter.mysqlshArgs()...)
 
 	cmd := exec.CommandContext(ctx, "mysqlsh", args...)
 	cmd.Stdin = strings.NewReader(o.password)
@@ -31,6 +32,27 @@ func (o operator) DumpFull(ctx context.Context, dir string) error {
 	return cmd.Run()
 }
 
+func (o operator) LockForSnapshot(ctx context.Context) (func() error, error) {
+	// the lock is held by the session, so a dedicated connection is used.
+	conn, err := o.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a connection: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, `FLUSH TABLES WITH READ LOCK`); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to flush tables with read lock: %w", err)
+	}
+
+	return func() error {
+		defer conn.Close()
+		if _, err := conn.ExecContext(context.Background(), `UNLOCK TABLES`); err != nil {
+			return fmt.Errorf("failed to unlock tables: %w", err)
+		}
+		return nil
+	}, nil
+}
+
 func (o operator) GetBinlogs(ctx context.Context) ([]string, error) {
 	var binlogs []showBinaryLogs
 	if err := o.db.SelectContext(ctx, &binlogs, `SHOW BINARY LOGS`); err != nil {
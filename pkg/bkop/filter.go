@@ -0,0 +1,239 @@
+package bkop
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Filter specifies schemas and tables to be backed up or restored.
+// Tables are specified in the form of "schema.table".
+//
+// The semantics follow the options of the same names of MySQL shell's
+// dump and load utilities.
+type Filter struct {
+	IncludeSchemas []string `json:"includeSchemas,omitempty"`
+	ExcludeSchemas []string `json:"excludeSchemas,omitempty"`
+	IncludeTables  []string `json:"includeTables,omitempty"`
+	ExcludeTables  []string `json:"excludeTables,omitempty"`
+}
+
+// IsEmpty returns true if the filter includes everything.
+func (f *Filter) IsEmpty() bool {
+	return f == nil ||
+		(len(f.IncludeSchemas) == 0 && len(f.ExcludeSchemas) == 0 &&
+			len(f.IncludeTables) == 0 && len(f.ExcludeTables) == 0)
+}
+
+// MatchSchema returns true if the schema should be processed.
+func (f *Filter) MatchSchema(schema string) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	if len(f.IncludeSchemas) > 0 && !contains(f.IncludeSchemas, schema) {
+		return false
+	}
+	return !contains(f.ExcludeSchemas, schema)
+}
+
+// MatchTable returns true if the table should be processed.
+func (f *Filter) MatchTable(schema, table string) bool {
+	if !f.MatchSchema(schema) {
+		return false
+	}
+	if f.IsEmpty() {
+		return true
+	}
+	name := schema + "." + table
+	if len(f.IncludeTables) > 0 && !contains(f.IncludeTables, name) {
+		return false
+	}
+	return !contains(f.ExcludeTables, name)
+}
+
+// mysqlshArgs returns the options for `util dump-instance` and `util load-dump`.
+func (f *Filter) mysqlshArgs() []string {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	var args []string
+	if len(f.IncludeSchemas) > 0 {
+		args = append(args, "--includeSchemas="+strings.Join(f.IncludeSchemas, ","))
+	}
+	if len(f.ExcludeSchemas) > 0 {
+		args = append(args, "--excludeSchemas="+strings.Join(f.ExcludeSchemas, ","))
+	}
+	if len(f.IncludeTables) > 0 {
+		args = append(args, "--includeTables="+strings.Join(f.IncludeTables, ","))
+	}
+	if len(f.ExcludeTables) > 0 {
+		args = append(args, "--excludeTables="+strings.Join(f.ExcludeTables, ","))
+	}
+	return args
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	tableMapPattern = regexp.MustCompile("Table_map: `((?:[^`]|``)*)`\\.`((?:[^`]|``)*)` mapped to number")
+	usePattern      = regexp.MustCompile("^use `((?:[^`]|``)*)`")
+	tableDDLPattern = regexp.MustCompile("(?i)^\\s*(?:CREATE|ALTER|DROP|TRUNCATE|RENAME)\\s+(?:TEMPORARY\\s+)?TABLE\\s+(?:IF\\s+(?:NOT\\s+)?EXISTS\\s+)?" +
+		"(?:`((?:[^`]|``)*)`|(\\w+))(?:\\.(?:`((?:[^`]|``)*)`|(\\w+)))?")
+	schemaDDLPattern = regexp.MustCompile("(?i)^\\s*(?:CREATE|ALTER|DROP)\\s+(?:DATABASE|SCHEMA)\\s+(?:IF\\s+(?:NOT\\s+)?EXISTS\\s+)?" +
+		"(?:`((?:[^`]|``)*)`|(\\w+))")
+)
+
+const statementDelimiter = "/*!*/;"
+
+var errMixedTables = errors.New("a row event modifies both filtered and unfiltered tables")
+
+// binlogFilter filters the output of mysqlbinlog.
+//
+// mysqlbinlog cannot stop at a GTID nor filter events by multiple schemas or by tables,
+// so its output is processed statement by statement.
+//
+// Row events are filtered by the tables in the preceding Table_map events.
+// Other statements are filtered by their default database, as `mysqlbinlog --database` does,
+// and by the target table or schema of DDL statements.
+type binlogFilter struct {
+	stop   GTIDSet
+	filter *Filter
+
+	db        string
+	tableMaps []bool // whether each table in the pending Table_map events is to be processed.
+}
+
+// copy copies the output of mysqlbinlog from `r` to `w` applying the filter.
+// It returns true if it has stopped at a transaction in the stop GTID set.
+func (f *binlogFilter) copy(w io.Writer, r io.Reader) (bool, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	inStatement := false
+	keep := true
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+		if len(line) == 0 {
+			return false, nil
+		}
+
+		if !inStatement {
+			var stop bool
+			var ferr error
+			keep, stop, ferr = f.startStatement(line)
+			if ferr != nil {
+				return false, ferr
+			}
+			if stop {
+				return true, nil
+			}
+			inStatement = !isComment(line) && strings.TrimSpace(line) != ""
+		}
+		if inStatement && strings.HasSuffix(strings.TrimRight(line, "\r\n"), statementDelimiter) {
+			inStatement = false
+		}
+
+		if keep {
+			if _, err := io.WriteString(w, line); err != nil {
+				return false, err
+			}
+		}
+		if err == io.EOF {
+			return false, nil
+		}
+	}
+}
+
+func isComment(line string) bool {
+	return strings.HasPrefix(line, "#")
+}
+
+// startStatement examines the first line of a statement or a comment line,
+// and decides whether to keep the statement.
+func (f *binlogFilter) startStatement(line string) (keep, stop bool, err error) {
+	switch {
+	case isComment(line):
+		if f.filter.IsEmpty() {
+			return true, false, nil
+		}
+		if m := tableMapPattern.FindStringSubmatch(line); m != nil {
+			f.tableMaps = append(f.tableMaps, f.filter.MatchTable(unquote(m[1]), unquote(m[2])))
+		}
+		return true, false, nil
+
+	case strings.HasPrefix(line, "SET @@SESSION.GTID_NEXT="):
+		if f.stop != nil {
+			if m := gtidNextPattern.FindStringSubmatch(line); m != nil && f.stop.Contains(m[1]) {
+				return false, true, nil
+			}
+		}
+		return true, false, nil
+	}
+
+	if f.filter.IsEmpty() {
+		return true, false, nil
+	}
+
+	switch {
+	case strings.HasPrefix(line, "use "):
+		m := usePattern.FindStringSubmatch(line)
+		if m == nil {
+			return true, false, nil
+		}
+		f.db = unquote(m[1])
+		return f.filter.MatchSchema(f.db), false, nil
+
+	case strings.HasPrefix(line, "BINLOG '"):
+		tableMaps := f.tableMaps
+		f.tableMaps = nil
+		var nKeep int
+		for _, k := range tableMaps {
+			if k {
+				nKeep++
+			}
+		}
+		if nKeep > 0 && nKeep < len(tableMaps) {
+			return false, false, errMixedTables
+		}
+		return len(tableMaps) == 0 || nKeep > 0, false, nil
+
+	case strings.TrimSpace(line) == "",
+		strings.HasPrefix(line, "SET "),
+		strings.HasPrefix(line, "/*!"),
+		strings.HasPrefix(line, "BEGIN"),
+		strings.HasPrefix(line, "COMMIT"),
+		strings.HasPrefix(line, "ROLLBACK"),
+		strings.HasPrefix(line, "DELIMITER"):
+		return true, false, nil
+	}
+
+	if m := schemaDDLPattern.FindStringSubmatch(line); m != nil {
+		return f.filter.MatchSchema(unquote(m[1] + m[2])), false, nil
+	}
+	if f.db != "" && !f.filter.MatchSchema(f.db) {
+		return false, false, nil
+	}
+	if m := tableDDLPattern.FindStringSubmatch(line); m != nil {
+		schema, table := f.db, unquote(m[1]+m[2])
+		if m[3] != "" || m[4] != "" {
+			schema, table = table, unquote(m[3]+m[4])
+		}
+		return f.filter.MatchTable(schema, table), false, nil
+	}
+	return true, false, nil
+}
+
+func unquote(s string) string {
+	return strings.ReplaceAll(s, "``", "`")
+}
@@ -0,0 +1,196 @@
+package bkop
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFilterMatch(t *testing.T) {
+	f := &Filter{
+		IncludeSchemas: []string{"foo", "bar"},
+		ExcludeTables:  []string{"foo.cache"},
+	}
+
+	if !f.MatchSchema("foo") {
+		t.Error("foo should match")
+	}
+	if f.MatchSchema("baz") {
+		t.Error("baz should not match")
+	}
+	if !f.MatchTable("foo", "t") {
+		t.Error("foo.t should match")
+	}
+	if f.MatchTable("foo", "cache") {
+		t.Error("foo.cache should not match")
+	}
+	if !f.MatchTable("bar", "cache") {
+		t.Error("bar.cache should match")
+	}
+
+	f = &Filter{
+		ExcludeSchemas: []string{"foo"},
+		IncludeTables:  []string{"foo.t", "bar.t"},
+	}
+	if f.MatchTable("foo", "t") {
+		t.Error("foo.t should not match")
+	}
+	if !f.MatchTable("bar", "t") {
+		t.Error("bar.t should match")
+	}
+	if f.MatchTable("bar", "u") {
+		t.Error("bar.u should not match")
+	}
+
+	var empty *Filter
+	if !empty.IsEmpty() || !empty.MatchTable("foo", "t") {
+		t.Error("nil filter should match everything")
+	}
+	if len(empty.mysqlshArgs()) != 0 {
+		t.Error("nil filter should have no args")
+	}
+}
+
+func TestFilterMysqlshArgs(t *testing.T) {
+	f := &Filter{
+		IncludeSchemas: []string{"foo", "bar"},
+		ExcludeSchemas: []string{"baz"},
+		IncludeTables:  []string{"foo.t"},
+		ExcludeTables:  []string{"foo.cache", "bar.cache"},
+	}
+	expected := []string{
+		"--includeSchemas=foo,bar",
+		"--excludeSchemas=baz",
+		"--includeTables=foo.t",
+		"--excludeTables=foo.cache,bar.cache",
+	}
+	if args := f.mysqlshArgs(); !cmp.Equal(args, expected) {
+		t.Error("unexpected args", cmp.Diff(args, expected))
+	}
+}
+
+const testBinlogHeader = `/*!50530 SET @@SESSION.PSEUDO_SLAVE_MODE=1*/;
+/*!50003 SET @OLD_COMPLETION_TYPE=@@COMPLETION_TYPE,COMPLETION_TYPE=0*/;
+DELIMITER /*!*/;
+# at 4
+#210526 12:00:00 server id 1  end_log_pos 125 CRC32 0x00000000 	Start: binlog v 4, server v 8.0.28 created 210526 12:00:00
+`
+
+const testBinlogDDL = `# at 200
+#210526 12:00:01 server id 1  end_log_pos 277 CRC32 0x00000000 	GTID	last_committed=0	sequence_number=1	rbr_only=no
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:1'/*!*/;
+# at 277
+#210526 12:00:01 server id 1  end_log_pos 400 CRC32 0x00000000 	Query	thread_id=8	exec_time=0	error_code=0	Xid = 10
+use ` + "`foo`" + `/*!*/;
+SET TIMESTAMP=1622030401/*!*/;
+CREATE TABLE cache (
+  id int PRIMARY KEY
+)
+/*!*/;
+`
+
+const testBinlogFooRows = `# at 400
+#210526 12:00:02 server id 1  end_log_pos 477 CRC32 0x00000000 	GTID	last_committed=1	sequence_number=2	rbr_only=yes
+/*!50718 SET TRANSACTION ISOLATION LEVEL READ COMMITTED*//*!*/;
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:2'/*!*/;
+# at 477
+#210526 12:00:02 server id 1  end_log_pos 552 CRC32 0x00000000 	Query	thread_id=8	exec_time=0	error_code=0
+SET TIMESTAMP=1622030402/*!*/;
+BEGIN
+/*!*/;
+# at 552
+#210526 12:00:02 server id 1  end_log_pos 600 CRC32 0x00000000 	Table_map: ` + "`foo`.`t`" + ` mapped to number 90
+# at 600
+#210526 12:00:02 server id 1  end_log_pos 640 CRC32 0x00000000 	Write_rows: table id 90 flags: STMT_END_F
+
+BINLOG '
+AAAAAA==
+AAAAAA==
+'/*!*/;
+`
+
+const testBinlogCacheRows = `# at 640
+#210526 12:00:02 server id 1  end_log_pos 690 CRC32 0x00000000 	Table_map: ` + "`foo`.`cache`" + ` mapped to number 91
+# at 690
+#210526 12:00:02 server id 1  end_log_pos 730 CRC32 0x00000000 	Write_rows: table id 91 flags: STMT_END_F
+
+BINLOG '
+BBBBBB==
+BBBBBB==
+'/*!*/;
+`
+
+const testBinlogCommit = `# at 730
+#210526 12:00:02 server id 1  end_log_pos 761 CRC32 0x00000000 	Xid = 11
+COMMIT/*!*/;
+`
+
+const testBinlogFooter = `SET @@SESSION.GTID_NEXT= 'AUTOMATIC' /* added by mysqlbinlog */ /*!*/;
+DELIMITER ;
+# End of log file
+/*!50003 SET COMPLETION_TYPE=@OLD_COMPLETION_TYPE*/;
+/*!50530 SET @@SESSION.PSEUDO_SLAVE_MODE=0*/;
+`
+
+const (
+	testCreateCache          = "CREATE TABLE cache (\n  id int PRIMARY KEY\n)\n/*!*/;\n"
+	testBinlogFooRowsEvent   = "BINLOG '\nAAAAAA==\nAAAAAA==\n'/*!*/;\n"
+	testBinlogCacheRowsEvent = "BINLOG '\nBBBBBB==\nBBBBBB==\n'/*!*/;\n"
+)
+
+func TestBinlogFilter(t *testing.T) {
+	input := testBinlogHeader + testBinlogDDL + testBinlogFooRows + testBinlogCacheRows + testBinlogCommit + testBinlogFooter
+
+	testCases := []struct {
+		name     string
+		filter   *Filter
+		expected string
+		isErr    bool
+	}{
+		{"no-filter", nil, input, false},
+		{"exclude-table", &Filter{ExcludeTables: []string{"foo.cache"}},
+			testBinlogHeader +
+				strings.Replace(testBinlogDDL, testCreateCache, "", 1) +
+				testBinlogFooRows +
+				strings.Replace(testBinlogCacheRows, testBinlogCacheRowsEvent, "", 1) +
+				testBinlogCommit + testBinlogFooter, false},
+		{"exclude-schema", &Filter{ExcludeSchemas: []string{"foo"}},
+			testBinlogHeader +
+				strings.Replace(strings.Replace(testBinlogDDL, testCreateCache, "", 1), "use `foo`/*!*/;\n", "", 1) +
+				strings.Replace(testBinlogFooRows, testBinlogFooRowsEvent, "", 1) +
+				strings.Replace(testBinlogCacheRows, testBinlogCacheRowsEvent, "", 1) +
+				testBinlogCommit + testBinlogFooter, false},
+		{"mixed", &Filter{ExcludeTables: []string{"foo.t"}}, "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// put the Table_map events of foo.t and foo.cache in the same statement for "mixed"
+			in := input
+			if tc.isErr {
+				in = testBinlogHeader + strings.Replace(testBinlogFooRows, "# at 600\n", strings.SplitN(testBinlogCacheRows, "# at 690\n", 2)[0]+"# at 600\n", 1)
+			}
+
+			buf := &bytes.Buffer{}
+			bf := &binlogFilter{filter: tc.filter}
+			reached, err := bf.copy(buf, strings.NewReader(in))
+			if tc.isErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reached {
+				t.Error("should not stop")
+			}
+			if buf.String() != tc.expected {
+				t.Error("unexpected output", cmp.Diff(buf.String(), tc.expected))
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // GetGTIDExecuted gets executed GTID set from the dump directory.
@@ -24,3 +27,140 @@ func GetGTIDExecuted(dir string) (string, error) {
 
 	return t.GTIDExecuted, nil
 }
+
+// GTIDSet is a parsed GTID set.  The keys are source UUIDs, optionally
+// followed by ":" and a tag, and the values are the transaction intervals.
+type GTIDSet map[string][]gtidInterval
+
+type gtidInterval struct {
+	start, end int64
+}
+
+// ParseGTIDSet parses a GTID set such as "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5:7,...".
+// Tagged GTIDs such as "3E11FA47-71CA-11E1-9E33-C80AA9429562:tag:1-5" are also accepted.
+func ParseGTIDSet(s string) (GTIDSet, error) {
+	set := make(GTIDSet)
+	for _, elem := range strings.Split(s, ",") {
+		elem = strings.TrimSpace(elem)
+		if elem == "" {
+			continue
+		}
+
+		fields := strings.Split(elem, ":")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid GTID set element: %s", elem)
+		}
+		uuid := strings.ToLower(fields[0])
+		if len(uuid) != 36 {
+			return nil, fmt.Errorf("invalid source UUID: %s", fields[0])
+		}
+
+		key := uuid
+		for _, f := range fields[1:] {
+			if f == "" {
+				return nil, fmt.Errorf("invalid GTID set element: %s", elem)
+			}
+			if c := f[0]; c < '0' || c > '9' {
+				key = uuid + ":" + strings.ToLower(f)
+				continue
+			}
+
+			iv, err := parseGTIDInterval(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid GTID set element %s: %w", elem, err)
+			}
+			set[key] = append(set[key], iv)
+		}
+	}
+	return set, nil
+}
+
+func parseGTIDInterval(s string) (gtidInterval, error) {
+	startStr, endStr := s, s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		startStr, endStr = s[:i], s[i+1:]
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return gtidInterval{}, err
+	}
+	end, err := strconv.ParseInt(endStr, 10, 64)
+	if err != nil {
+		return gtidInterval{}, err
+	}
+	if start < 1 || end < start {
+		return gtidInterval{}, fmt.Errorf("invalid interval: %s", s)
+	}
+	return gtidInterval{start, end}, nil
+}
+
+// Contains returns true if the set contains the GTID such as "3E11FA47-71CA-11E1-9E33-C80AA9429562:23".
+func (s GTIDSet) Contains(gtid string) bool {
+	i := strings.LastIndexByte(gtid, ':')
+	if i < 0 {
+		return false
+	}
+	n, err := strconv.ParseInt(gtid[i+1:], 10, 64)
+	if err != nil {
+		return false
+	}
+	for _, iv := range s[strings.ToLower(gtid[:i])] {
+		if iv.start <= n && n <= iv.end {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersects returns true if the two sets have a common GTID.
+func (s GTIDSet) Intersects(other GTIDSet) bool {
+	for key, ivs := range s {
+		for _, iv := range ivs {
+			for _, iv2 := range other[key] {
+				if iv.start <= iv2.end && iv2.start <= iv.end {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// IsSubsetOf returns true if every GTID in the set is also in `other`.
+func (s GTIDSet) IsSubsetOf(other GTIDSet) bool {
+	for key, ivs := range s {
+		merged := mergeGTIDIntervals(other[key])
+	OUTER:
+		for _, iv := range ivs {
+			for _, iv2 := range merged {
+				if iv2.start <= iv.start && iv.end <= iv2.end {
+					continue OUTER
+				}
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// mergeGTIDIntervals sorts the intervals and merges adjacent or overlapping ones
+// so that "1-5:6-10" is treated as "1-10".
+func mergeGTIDIntervals(ivs []gtidInterval) []gtidInterval {
+	sorted := make([]gtidInterval, len(ivs))
+	copy(sorted, ivs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start < sorted[j].start
+	})
+
+	var merged []gtidInterval
+	for _, iv := range sorted {
+		if n := len(merged); n > 0 && iv.start <= merged[n-1].end+1 {
+			if iv.end > merged[n-1].end {
+				merged[n-1].end = iv.end
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
@@ -0,0 +1,188 @@
+package bkop
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseGTIDSet(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected GTIDSet
+		isErr    bool
+	}{
+		{"empty", "", GTIDSet{}, false},
+		{"single", "3E11FA47-71CA-11E1-9E33-C80AA9429562:23",
+			GTIDSet{"3e11fa47-71ca-11e1-9e33-c80aa9429562": {{23, 23}}}, false},
+		{"intervals", "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:7,\n4d1e7e8f-71ca-11e1-9e33-c80aa9429562:3-4",
+			GTIDSet{
+				"3e11fa47-71ca-11e1-9e33-c80aa9429562": {{1, 5}, {7, 7}},
+				"4d1e7e8f-71ca-11e1-9e33-c80aa9429562": {{3, 4}},
+			}, false},
+		{"tagged", "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:Foo:1-2",
+			GTIDSet{
+				"3e11fa47-71ca-11e1-9e33-c80aa9429562":     {{1, 5}},
+				"3e11fa47-71ca-11e1-9e33-c80aa9429562:foo": {{1, 2}},
+			}, false},
+		{"no-interval", "3e11fa47-71ca-11e1-9e33-c80aa9429562", nil, true},
+		{"bad-uuid", "3e11fa47:1-5", nil, true},
+		{"bad-interval", "3e11fa47-71ca-11e1-9e33-c80aa9429562:5-1", nil, true},
+		{"zero", "3e11fa47-71ca-11e1-9e33-c80aa9429562:0", nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			set, err := ParseGTIDSet(tc.input)
+			if tc.isErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(set, tc.expected, cmp.AllowUnexported(gtidInterval{})) {
+				t.Error("unexpected result", cmp.Diff(set, tc.expected, cmp.AllowUnexported(gtidInterval{})))
+			}
+		})
+	}
+}
+
+func TestGTIDSetContains(t *testing.T) {
+	set, err := ParseGTIDSet("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:7,3e11fa47-71ca-11e1-9e33-c80aa9429562:foo:3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		gtid     string
+		expected bool
+	}{
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:1", true},
+		{"3E11FA47-71CA-11E1-9E33-C80AA9429562:5", true},
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:6", false},
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:7", true},
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:foo:3", true},
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:foo:1", false},
+		{"4d1e7e8f-71ca-11e1-9e33-c80aa9429562:1", false},
+		{"AUTOMATIC", false},
+	}
+
+	for _, tc := range testCases {
+		if set.Contains(tc.gtid) != tc.expected {
+			t.Errorf("Contains(%s) should be %v", tc.gtid, tc.expected)
+		}
+	}
+}
+
+func TestGTIDSetIntersects(t *testing.T) {
+	set, err := ParseGTIDSet("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		other    string
+		expected bool
+	}{
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:5", true},
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:6-10", false},
+		{"4d1e7e8f-71ca-11e1-9e33-c80aa9429562:1-5", false},
+		{"4d1e7e8f-71ca-11e1-9e33-c80aa9429562:1-5,3e11fa47-71ca-11e1-9e33-c80aa9429562:3-8", true},
+	}
+
+	for _, tc := range testCases {
+		other, err := ParseGTIDSet(tc.other)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if set.Intersects(other) != tc.expected {
+			t.Errorf("Intersects(%s) should be %v", tc.other, tc.expected)
+		}
+	}
+}
+
+func TestGTIDSetIsSubsetOf(t *testing.T) {
+	set, err := ParseGTIDSet("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:6-10:20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		other    string
+		expected bool
+	}{
+		{"", true},
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10", true},
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:3-8:20", true},
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-21", false},
+		{"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10,4d1e7e8f-71ca-11e1-9e33-c80aa9429562:1-5", false},
+		{"4d1e7e8f-71ca-11e1-9e33-c80aa9429562:1-5", false},
+	}
+
+	for _, tc := range testCases {
+		other, err := ParseGTIDSet(tc.other)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other.IsSubsetOf(set) != tc.expected {
+			t.Errorf("IsSubsetOf(%s) should be %v", tc.other, tc.expected)
+		}
+	}
+}
+
+func TestBinlogFilterStop(t *testing.T) {
+	input := `# at 4
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:1'/*!*/;
+INSERT INTO t VALUES (1)
+/*!*/;
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:2'/*!*/;
+INSERT INTO t VALUES (2)
+/*!*/;
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:3'/*!*/;
+INSERT INTO t VALUES (3)
+/*!*/;
+`
+	set, err := ParseGTIDSet("3e11fa47-71ca-11e1-9e33-c80aa9429562:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	reached, err := (&binlogFilter{stop: set}).copy(buf, strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reached {
+		t.Error("the stop GTID should be found")
+	}
+	expected := `# at 4
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:1'/*!*/;
+INSERT INTO t VALUES (1)
+/*!*/;
+`
+	if buf.String() != expected {
+		t.Error("unexpected output", cmp.Diff(buf.String(), expected))
+	}
+
+	set, err = ParseGTIDSet("3e11fa47-71ca-11e1-9e33-c80aa9429562:4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	reached, err = (&binlogFilter{stop: set}).copy(buf, strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reached {
+		t.Error("the stop GTID should not be found")
+	}
+	if buf.String() != input {
+		t.Error("unexpected output", cmp.Diff(buf.String(), input))
+	}
+}
@@ -20,9 +20,26 @@ type Operator interface {
 	// GetServerStatus fills ServerStatus struct.
 	GetServerStatus(context.Context, *ServerStatus) error
 
+	// ContainsGTIDSet returns true if all transactions in `set` have been executed.
+	ContainsGTIDSet(ctx context.Context, set string) (bool, error)
+
+	// RunSQL executes statements in order in a single session.
+	RunSQL(ctx context.Context, stmts []string) error
+
+	// QueryValue runs a query in a read-only transaction and returns the last
+	// column of the first row as a string.  NULL is returned as "NULL".
+	QueryValue(ctx context.Context, query string) (string, error)
+
 	// DumpFull takes a full dump of the database instance.
+	// Only schemas and tables that match `filter` are dumped.
 	// `dir` should exist before calling this.
-	DumpFull(ctx context.Context, dir string) error
+	DumpFull(ctx context.Context, dir string, filter *Filter) error
+
+	// LockForSnapshot flushes tables and holds a global read lock so that
+	// the data files are consistent with the executed GTID set.
+	// The lock blocks the replication applier of a replica, too.
+	// The returned function releases the lock and must be called.
+	LockForSnapshot(ctx context.Context) (func() error, error)
 
 	// GetBinlogs returns a list of binary log files on the mysql instance.
 	GetBinlogs(context.Context) ([]string, error)
@@ -36,10 +53,21 @@ type Operator interface {
 	PrepareRestore(context.Context) error
 
 	// LoadDump loads data dumped by `DumpFull`.
-	LoadDump(ctx context.Context, dir string) error
+	// Only schemas and tables that match `filter` are loaded.
+	LoadDump(ctx context.Context, dir string, filter *Filter) error
+
+	// LoadDumpStream is like LoadDump, but it can be called while the files of
+	// the dump are still being placed in `dir`.  It waits for more files until
+	// `@.done.json` appears.  Files must be renamed into place once written.
+	// The progress of loading is written to `progressFile` as JSON lines, so that
+	// the caller can remove the data files that have been loaded.
+	LoadDumpStream(ctx context.Context, dir, progressFile string, filter *Filter) error
 
 	// LoadBinLog applies binary logs up to `restorePoint`.
-	LoadBinlog(ctx context.Context, binlogDir, tmpDir string, restorePoint time.Time) error
+	// If `stopGTIDSet` is not nil, it also stops right before the first transaction
+	// in `stopGTIDSet`, and returns true if such a transaction is found.
+	// Only changes to schemas and tables that match `filter` are applied.
+	LoadBinlog(ctx context.Context, binlogDir, tmpDir string, restorePoint time.Time, stopGTIDSet GTIDSet, filter *Filter) (bool, error)
 
 	// FinishRestore sets global variables of the database instance after restoration.
 	FinishRestore(context.Context) error
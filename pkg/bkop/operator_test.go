@@ -111,11 +111,14 @@ var _ = Describe("Operator", func() {
 		Expect(st1.CurrentBinlog).NotTo(BeEmpty())
 		Expect(st1.UUID).NotTo(BeEmpty())
 		Expect(st1.SuperReadOnly).To(BeFalse())
+		Expect(st1.ExecutedGTIDSet).To(Equal(gtid1))
+		Expect(st1.IsReplica).To(BeFalse())
+		Expect(st1.ReplicationLag).To(BeNil())
 
 		dumpDir := filepath.Join(baseDir, "dump")
 		err = os.MkdirAll(dumpDir, 0755)
 		Expect(err).NotTo(HaveOccurred())
-		err = opBk.DumpFull(ctx, dumpDir)
+		err = opBk.DumpFull(ctx, dumpDir, nil)
 		Expect(err).NotTo(HaveOccurred())
 
 		dumpGTID, err := GetGTIDExecuted(dumpDir)
@@ -161,7 +164,7 @@ var _ = Describe("Operator", func() {
 
 		err = opRe.PrepareRestore(ctx)
 		Expect(err).NotTo(HaveOccurred())
-		err = opRe.LoadDump(ctx, dumpDir)
+		err = opRe.LoadDump(ctx, dumpDir, nil)
 		Expect(err).NotTo(HaveOccurred())
 
 		var restoredGTID string
@@ -173,7 +176,7 @@ var _ = Describe("Operator", func() {
 		err = os.MkdirAll(tmpDir, 0755)
 		Expect(err).NotTo(HaveOccurred())
 
-		err = opRe.LoadBinlog(ctx, binlogDir, tmpDir, restorePoint)
+		_, err = opRe.LoadBinlog(ctx, binlogDir, tmpDir, restorePoint, nil, nil)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(restoredGTID).To(Equal(dumpGTID))
 		var maxID int
@@ -190,5 +193,19 @@ var _ = Describe("Operator", func() {
 		err = opRe.(operator).db.Get(&localInFile, `SELECT @@local_infile`)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(localInFile).To(BeFalse())
+
+		v, err := opRe.QueryValue(ctx, `SELECT COUNT(*) FROM foo.t`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal("2"))
+		v, err = opRe.QueryValue(ctx, `CHECKSUM TABLE foo.t`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).NotTo(Equal("NULL"))
+		v, err = opRe.QueryValue(ctx, `SELECT NULL`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal("NULL"))
+		_, err = opRe.QueryValue(ctx, `SELECT i FROM foo.t WHERE i > 100`)
+		Expect(err).To(HaveOccurred())
+		_, err = opRe.QueryValue(ctx, `DELETE FROM foo.t`)
+		Expect(err).To(HaveOccurred())
 	})
 })
@@ -3,9 +3,11 @@ package bkop
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -20,7 +22,21 @@ func (o operator) PrepareRestore(ctx context.Context) error {
 	return nil
 }
 
-func (o operator) LoadDump(ctx context.Context, dir string) error {
+// streamWaitTimeout is the time for which LoadDumpStream waits for new files of the dump.
+const streamWaitTimeout = time.Hour
+
+func (o operator) LoadDump(ctx context.Context, dir string, filter *Filter) error {
+	return o.loadDump(ctx, dir, filter, nil)
+}
+
+func (o operator) LoadDumpStream(ctx context.Context, dir, progressFile string, filter *Filter) error {
+	return o.loadDump(ctx, dir, filter, []string{
+		"--waitDumpTimeout=" + fmt.Sprint(int(streamWaitTimeout.Seconds())),
+		"--progressFile=" + progressFile,
+	})
+}
+
+func (o operator) loadDump(ctx context.Context, dir string, filter *Filter, extraArgs []string) error {
 	args := []string{
 		fmt.Sprintf("mysql://%s@%s:%d", o.user, o.host, o.port),
 		"--passwords-from-stdin",
@@ -37,6 +53,8 @@ func (o operator) LoadDump(ctx context.Context, dir string) error {
 		"--deferTableIndexes=all",
 		"--updateGtidSet=replace",
 	}
+	args = append(args, extraArgs...)
+	args = append(args, filter.mysqlshArgs()...)
 
 	cmd := exec.CommandContext(ctx, "mysqlsh", args...)
 	cmd.Stdin = strings.NewReader(o.password)
@@ -45,10 +63,10 @@ func (o operator) LoadDump(ctx context.Context, dir string) error {
 	return cmd.Run()
 }
 
-func (o operator) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, restorePoint time.Time) error {
+func (o operator) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, restorePoint time.Time, stopGTIDSet GTIDSet, filter *Filter) (bool, error) {
 	dirents, err := os.ReadDir(binlogDir)
 	if err != nil {
-		return err
+		return false, err
 	}
 	var binlogs []string
 	for _, e := range dirents {
@@ -61,7 +79,7 @@ func (o operator) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, rest
 	}
 
 	if len(binlogs) == 0 {
-		return fmt.Errorf("no binlog files in %s", binlogDir)
+		return false, fmt.Errorf("no binlog files in %s", binlogDir)
 	}
 	SortBinlogs(binlogs)
 	binlogFiles := make([]string, len(binlogs))
@@ -71,7 +89,7 @@ func (o operator) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, rest
 
 	pr, pw, err := os.Pipe()
 	if err != nil {
-		return fmt.Errorf("failed to create a pipe: %w", err)
+		return false, fmt.Errorf("failed to create a pipe: %w", err)
 	}
 	defer func() {
 		if pr != nil {
@@ -89,7 +107,6 @@ func (o operator) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, rest
 	// | mysql --binary-mode -h moco-single-primary.bar.svc -u moco-admin -p
 	binlogArgs := append([]string{"--stop-datetime=" + restorePoint.Format("2006-01-02 15:04:05")}, binlogFiles...)
 	binlogCmd := exec.CommandContext(ctx, "mysqlbinlog", binlogArgs...)
-	binlogCmd.Stdout = pw
 	binlogCmd.Stderr = os.Stderr
 	env := os.Environ()
 	env = append(env, "TZ=Etc/UTC")
@@ -97,6 +114,18 @@ func (o operator) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, rest
 	env = append(env, "TMPDIR="+tmpDir)
 	binlogCmd.Env = env
 
+	// mysqlbinlog cannot stop at a GTID nor filter tables, so its output is
+	// filtered when the stop point is given as a GTID set or the filter is given.
+	var binlogOut io.Reader
+	if stopGTIDSet == nil && filter.IsEmpty() {
+		binlogCmd.Stdout = pw
+	} else {
+		binlogOut, err = binlogCmd.StdoutPipe()
+		if err != nil {
+			return false, fmt.Errorf("failed to create a pipe: %w", err)
+		}
+	}
+
 	mysqlArgs := []string{
 		"--binary-mode",
 		"-h", o.host,
@@ -111,19 +140,40 @@ func (o operator) LoadBinlog(ctx context.Context, binlogDir, tmpDir string, rest
 	mysqlCmd.Stderr = os.Stderr
 
 	if err := binlogCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start mysqlbinlog: %w", err)
+		return false, fmt.Errorf("failed to start mysqlbinlog: %w", err)
+	}
+	if err := mysqlCmd.Start(); err != nil {
+		return false, fmt.Errorf("failed to start mysql: %w", err)
+	}
+
+	var reached bool
+	if binlogOut != nil {
+		bf := &binlogFilter{stop: stopGTIDSet, filter: filter}
+		reached, err = bf.copy(pw, binlogOut)
+		if err != nil {
+			return false, fmt.Errorf("failed to read the output of mysqlbinlog: %w", err)
+		}
 	}
 	pw.Close()
 	pw = nil
-	if err := mysqlCmd.Run(); err != nil {
-		return fmt.Errorf("failed to apply binlog: %w", err)
+
+	if err := mysqlCmd.Wait(); err != nil {
+		return false, fmt.Errorf("failed to apply binlog: %w", err)
+	}
+	if reached {
+		// the rest of the output is not needed.
+		cancel()
+		binlogCmd.Wait()
+		return true, nil
 	}
 	if err := binlogCmd.Wait(); err != nil {
-		return fmt.Errorf("mysqlbinlog existed abnormally: %w", err)
+		return false, fmt.Errorf("mysqlbinlog existed abnormally: %w", err)
 	}
-	return nil
+	return false, nil
 }
 
+var gtidNextPattern = regexp.MustCompile(`^SET @@SESSION\.GTID_NEXT= '([^']+)'`)
+
 func (o operator) FinishRestore(ctx context.Context) error {
 	if _, err := o.db.ExecContext(ctx, `SET GLOBAL super_read_only=1`); err != nil {
 		return fmt.Errorf("failed to set super_read_only=1: %w", err)
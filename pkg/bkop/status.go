@@ -2,7 +2,10 @@ package bkop
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 )
 
 func (o operator) GetServerStatus(ctx context.Context, st *ServerStatus) error {
@@ -11,10 +14,92 @@ func (o operator) GetServerStatus(ctx context.Context, st *ServerStatus) error {
 		return fmt.Errorf("failed to show master status: %w", err)
 	}
 
-	if err := o.db.GetContext(ctx, st, `SELECT @@super_read_only, @@server_uuid`); err != nil {
+	if err := o.db.GetContext(ctx, st, `SELECT @@super_read_only, @@server_uuid, @@version`); err != nil {
 		return fmt.Errorf("failed to get global variables: %w", err)
 	}
 
 	st.CurrentBinlog = ms.File
+	st.ExecutedGTIDSet = ms.ExecutedGTIDSet
+
+	ss := &showSlaveStatus{}
+	// SHOW SLAVE STATUS returns many columns, so unknown columns are ignored.
+	err := o.db.Unsafe().GetContext(ctx, ss, `SHOW SLAVE STATUS`)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		st.IsReplica = false
+		st.ReplicationLag = nil
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to show slave status: %w", err)
+	}
+
+	st.IsReplica = true
+	st.ReplicationLag = nil
+	if ss.SecondsBehindMaster.Valid {
+		lag := time.Duration(ss.SecondsBehindMaster.Int64) * time.Second
+		st.ReplicationLag = &lag
+	}
+	return nil
+}
+
+func (o operator) ContainsGTIDSet(ctx context.Context, set string) (bool, error) {
+	var ok bool
+	if err := o.db.GetContext(ctx, &ok, `SELECT GTID_SUBSET(?, @@gtid_executed)`, set); err != nil {
+		return false, fmt.Errorf("failed to compare GTID sets: %w", err)
+	}
+	return ok, nil
+}
+
+func (o operator) RunSQL(ctx context.Context, stmts []string) error {
+	conn, err := o.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a connection: %w", err)
+	}
+	defer conn.Close()
+
+	for _, stmt := range stmts {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to execute %q: %w", stmt, err)
+		}
+	}
 	return nil
 }
+
+func (o operator) QueryValue(ctx context.Context, query string) (string, error) {
+	tx, err := o.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute %q: %w", query, err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", fmt.Errorf("failed to get columns of %q: %w", query, err)
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("failed to read the result of %q: %w", query, err)
+		}
+		return "", fmt.Errorf("no rows returned by %q", query)
+	}
+	values := make([]sql.NullString, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", fmt.Errorf("failed to scan the result of %q: %w", query, err)
+	}
+
+	v := values[len(values)-1]
+	if !v.Valid {
+		return "NULL", nil
+	}
+	return v.String, nil
+}
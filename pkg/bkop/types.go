@@ -1,12 +1,28 @@
 package bkop
 
+import (
+	"database/sql"
+	"time"
+)
+
 // ServerStatus defines a struct to retrieve the backup source server status.
 // These information will be used in the next backup to retrieve binary logs
 // since the last backup.
 type ServerStatus struct {
 	SuperReadOnly bool   `db:"@@super_read_only"`
 	UUID          string `db:"@@server_uuid"`
+	Version       string `db:"@@version"`
 	CurrentBinlog string
+
+	// ExecutedGTIDSet is the GTID set of the transactions executed on the server.
+	ExecutedGTIDSet string
+
+	// IsReplica is true if the server is configured as a replica.
+	IsReplica bool
+
+	// ReplicationLag is the replication lag reported as Seconds_Behind_Master.
+	// It is nil if the server is not a replica or the replication is not running.
+	ReplicationLag *time.Duration
 }
 
 type showMasterStatus struct {
@@ -17,6 +33,11 @@ type showMasterStatus struct {
 	ExecutedGTIDSet string `db:"Executed_Gtid_Set"`
 }
 
+// showSlaveStatus has only the columns used by MOCO.
+type showSlaveStatus struct {
+	SecondsBehindMaster sql.NullInt64 `db:"Seconds_Behind_Master"`
+}
+
 type showBinaryLogs struct {
 	LogName   string `db:"Log_name"`
 	FileSize  int64  `db:"File_size"`
@@ -0,0 +1,129 @@
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+)
+
+// AzureMaxBlocks is the maximum number of blocks in a block blob.
+const AzureMaxBlocks = 50000
+
+type azureBucket struct {
+	client azblob.ContainerClient
+}
+
+// NewAzureBucket creates a Bucket that manages blobs in an Azure Blob Storage container.
+// `containerURL` is the URL of the container, e.g. https://ACCOUNT.blob.core.windows.net/CONTAINER
+// Requests are authorized with `cred`.
+func NewAzureBucket(containerURL string, cred azcore.TokenCredential) (Bucket, error) {
+	client, err := azblob.NewContainerClient(containerURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure Blob client: %w", err)
+	}
+	return azureBucket{client: client}, nil
+}
+
+// NewAzureBucketWithSharedKey is the same as NewAzureBucket except that
+// requests are authorized with the storage account key.
+func NewAzureBucketWithSharedKey(containerURL, accountName, accountKey string) (Bucket, error) {
+	cred, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shared key credential: %w", err)
+	}
+	client, err := azblob.NewContainerClientWithSharedKey(containerURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure Blob client: %w", err)
+	}
+	return azureBucket{client: client}, nil
+}
+
+func (b azureBucket) Put(ctx context.Context, key string, data io.Reader, objectSize int64) error {
+	bb := b.client.NewBlockBlobClient(key)
+
+	blockSize := decidePartSize(objectSize)
+	if blockSize == 0 {
+		blockSize = PartSizeUnit
+	}
+	buf := make([]byte, blockSize)
+	var blockIDs []string
+	for {
+		n, err := io.ReadFull(data, buf)
+		if n > 0 {
+			if len(blockIDs) >= AzureMaxBlocks {
+				return fmt.Errorf("too many blocks for %s; object size is larger than expected", key)
+			}
+			id := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", len(blockIDs))))
+			body := streaming.NopCloser(bytes.NewReader(buf[:n]))
+			if _, err := bb.StageBlock(ctx, id, body, nil); err != nil {
+				return fmt.Errorf("failed to stage block for %s: %w", key, err)
+			}
+			blockIDs = append(blockIDs, id)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	mt := contentType(key)
+	_, err := bb.CommitBlockList(ctx, blockIDs, &azblob.CommitBlockListOptions{
+		BlobHTTPHeaders: &azblob.BlobHTTPHeaders{
+			BlobContentType: &mt,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit block list for %s: %w", key, err)
+	}
+	return nil
+}
+
+func (b azureBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := b.client.NewBlobClient(key).Download(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body(&azblob.RetryReaderOptions{MaxRetryRequests: 3}), nil
+}
+
+func (b azureBucket) List(ctx context.Context, prefix string) ([]string, error) {
+	opts := &azblob.ContainerListBlobFlatSegmentOptions{}
+	if len(prefix) > 0 {
+		opts.Prefix = &prefix
+	}
+
+	var keys []string
+	pager := b.client.ListBlobsFlat(opts)
+	for pager.NextPage(ctx) {
+		resp := pager.PageResponse()
+		if resp.Segment == nil {
+			continue
+		}
+		for _, item := range resp.Segment.BlobItems {
+			keys = append(keys, *item.Name)
+		}
+	}
+	if err := pager.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (b azureBucket) Delete(ctx context.Context, key string) error {
+	_, err := b.client.NewBlobClient(key).Delete(ctx, nil)
+	var serr *azblob.StorageError
+	if errors.As(err, &serr) && serr.ErrorCode == azblob.StorageErrorCodeBlobNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// The well-known account of Azurite.
+// https://docs.microsoft.com/en-us/azure/storage/common/storage-use-azurite#well-known-storage-account-and-key
+const (
+	azuriteAccountName = "devstoreaccount1"
+	azuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+	azuriteURL         = "http://localhost:10000/devstoreaccount1/test"
+)
+
+var _ = Describe("AzureBucket", func() {
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		err := exec.Command("docker", "run", "--rm", "--name=moco-azurite", "-d", "-p", "10000:10000",
+			"mcr.microsoft.com/azure-storage/azurite", "azurite-blob", "--blobHost", "0.0.0.0").Run()
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", "localhost:10000")
+			if err != nil {
+				return err
+			}
+			conn.Close()
+			return nil
+		}, 60).Should(Succeed())
+
+		cred, err := azblob.NewSharedKeyCredential(azuriteAccountName, azuriteAccountKey)
+		Expect(err).NotTo(HaveOccurred())
+		client, err := azblob.NewContainerClientWithSharedKey(azuriteURL, cred, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() error {
+			_, err := client.Create(ctx, nil)
+			return err
+		}, 10).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		exec.Command("docker", "kill", "moco-azurite").Run()
+		time.Sleep(1 * time.Second)
+	})
+
+	It("should put and get objects", func() {
+		b, err := NewAzureBucketWithSharedKey(azuriteURL, azuriteAccountName, azuriteAccountKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Put(ctx, "foo/bar", strings.NewReader("01234567890123456789"), 128<<20)
+		Expect(err).NotTo(HaveOccurred())
+
+		r, err := b.Get(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(data).To(Equal([]byte("01234567890123456789")))
+
+		for i := 0; i < 1100; i++ {
+			err = b.Put(ctx, fmt.Sprintf("foo/baz%d", i), strings.NewReader("01234567890123456789"), 128<<20)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		keys, err := b.List(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1101))
+
+		keys, err = b.List(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+
+		err = b.Delete(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		keys, err = b.List(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(BeEmpty())
+		_, err = b.Get(ctx, "foo/bar")
+		Expect(err).To(HaveOccurred())
+
+		err = b.Delete(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should put objects consisting of multiple blocks", func() {
+		b, err := NewAzureBucketWithSharedKey(azuriteURL, azuriteAccountName, azuriteAccountKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		size := int64(PartSizeUnit*2 + 100)
+		err = b.Put(ctx, "large", io.LimitReader(zeroReader{}, size), 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		r, err := b.Get(ctx, "large")
+		Expect(err).NotTo(HaveOccurred())
+		defer r.Close()
+
+		n, err := io.Copy(io.Discard, r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(size))
+
+		err = b.Put(ctx, "empty", strings.NewReader(""), 0)
+		Expect(err).NotTo(HaveOccurred())
+		r2, err := b.Get(ctx, "empty")
+		Expect(err).NotTo(HaveOccurred())
+		defer r2.Close()
+		data, err := io.ReadAll(r2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(BeEmpty())
+	})
+})
@@ -0,0 +1,347 @@
+package bucket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Encrypted objects have the following format.
+//
+//	magic (8 bytes) | key ID length (1 byte) | key ID | wrapped data key (60 bytes) | chunks...
+//
+// The data key is a random 256-bit key generated for each object.  It is encrypted
+// with AES-256-GCM using the key identified by the key ID, and stored as
+// nonce (12 bytes) | ciphertext (32 bytes) | tag (16 bytes).
+//
+// The data is split into chunks of encChunkSize bytes, and each chunk is encrypted
+// with AES-256-GCM using the data key.  The nonce is the chunk counter followed by
+// a flag byte that is 1 only for the last chunk, so reordering, truncation, and
+// extension of chunks are detected.  The header is authenticated as additional data.
+const (
+	encMagic        = "MOCOENC1"
+	encKeySize      = 32
+	encChunkSize    = 64 << 10
+	encWrappedSize  = 12 + encKeySize + 16
+	encMaxKeyIDSize = 255
+)
+
+// ErrUnknownKey is returned when an encrypted object cannot be decrypted
+// because the key used to encrypt it is not available.
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+type encryptedBucket struct {
+	Bucket
+	keyID string
+	keys  map[string][]byte
+}
+
+// NewEncryptedBucket returns a Bucket that encrypts objects with the key identified by `keyID`
+// before putting them into `b`, and decrypts objects transparently when getting them.
+//
+// `keys` is a map from key IDs to 256-bit keys.  All keys in `keys` can be used
+// for decryption, so old keys should be kept after rotation.  If `keyID` is empty,
+// the returned Bucket can only be used to read objects.
+//
+// Objects that are not encrypted are returned as is.
+func NewEncryptedBucket(b Bucket, keyID string, keys map[string][]byte) (Bucket, error) {
+	for id, key := range keys {
+		if len(key) != encKeySize {
+			return nil, fmt.Errorf("key %s must be %d bytes", id, encKeySize)
+		}
+		if len(id) == 0 || len(id) > encMaxKeyIDSize {
+			return nil, fmt.Errorf("invalid key ID %q", id)
+		}
+	}
+	if keyID != "" {
+		if _, ok := keys[keyID]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+		}
+	}
+
+	return encryptedBucket{
+		Bucket: b,
+		keyID:  keyID,
+		keys:   keys,
+	}, nil
+}
+
+// ReadKeyDir reads encryption keys from files in `dir`.
+// The file name is the key ID, and the content is a 256-bit key in raw bytes or base64 encoding.
+// This is intended to read keys from a mounted Secret volume.
+func ReadKeyDir(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string][]byte)
+	for _, e := range entries {
+		// Secret volumes have hidden entries such as "..data".
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		key, err := decodeKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", e.Name(), err)
+		}
+		keys[e.Name()] = key
+	}
+	return keys, nil
+}
+
+func decodeKey(data []byte) ([]byte, error) {
+	if len(data) == encKeySize {
+		return data, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("key must be %d bytes or base64 encoded", encKeySize)
+	}
+	if len(decoded) != encKeySize {
+		return nil, fmt.Errorf("key must be %d bytes", encKeySize)
+	}
+	return decoded, nil
+}
+
+func (b encryptedBucket) Put(ctx context.Context, key string, data io.Reader, objectSize int64) error {
+	if b.keyID == "" {
+		return errors.New("no encryption key is specified")
+	}
+
+	header, aead, err := b.newHeader()
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encryptStream(pw, data, header, aead))
+	}()
+	defer pr.Close()
+
+	overhead := int64(len(header)) + (objectSize/encChunkSize+1)*int64(aead.Overhead())
+	return b.Bucket.Put(ctx, key, pr, objectSize+overhead)
+}
+
+func (b encryptedBucket) newHeader() ([]byte, cipher.AEAD, error) {
+	dataKey := make([]byte, encKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+
+	kek, err := newGCM(b.keys[b.keyID])
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, kek.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	header := make([]byte, 0, len(encMagic)+1+len(b.keyID)+encWrappedSize)
+	header = append(header, encMagic...)
+	header = append(header, byte(len(b.keyID)))
+	header = append(header, b.keyID...)
+	header = append(header, nonce...)
+	header = kek.Seal(header, nonce, dataKey, []byte(b.keyID))
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, aead, nil
+}
+
+func encryptStream(w io.Writer, r io.Reader, header []byte, aead cipher.AEAD) error {
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	br := bufio.NewReaderSize(r, encChunkSize)
+	buf := make([]byte, encChunkSize)
+	out := make([]byte, 0, encChunkSize+aead.Overhead())
+	nonce := make([]byte, aead.NonceSize())
+	for counter := uint64(0); ; counter++ {
+		n, err := io.ReadFull(br, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+		last := err != nil
+		if !last {
+			if _, err := br.Peek(1); err != nil {
+				if !errors.Is(err, io.EOF) {
+					return err
+				}
+				last = true
+			}
+		}
+
+		chunkNonce(nonce, counter, last)
+		out = aead.Seal(out[:0], nonce, buf[:n], header)
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+func (b encryptedBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := b.Bucket.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(rc, encChunkSize+64)
+	magic, err := br.Peek(len(encMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		rc.Close()
+		return nil, err
+	}
+	if !bytes.Equal(magic, []byte(encMagic)) {
+		// not encrypted
+		return readCloser{Reader: br, Closer: rc}, nil
+	}
+
+	dr, err := b.newDecryptReader(br)
+	if err != nil {
+		rc.Close()
+		return nil, fmt.Errorf("failed to decrypt %s: %w", key, err)
+	}
+	return readCloser{Reader: dr, Closer: rc}, nil
+}
+
+func (b encryptedBucket) newDecryptReader(r *bufio.Reader) (io.Reader, error) {
+	header := make([]byte, len(encMagic)+1, len(encMagic)+1+encMaxKeyIDSize+encWrappedSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	idLen := int(header[len(encMagic)])
+	header = header[:len(header)+idLen+encWrappedSize]
+	if _, err := io.ReadFull(r, header[len(encMagic)+1:]); err != nil {
+		return nil, err
+	}
+
+	keyID := string(header[len(encMagic)+1 : len(encMagic)+1+idLen])
+	kekKey, ok := b.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	kek, err := newGCM(kekKey)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := header[len(encMagic)+1+idLen:]
+	dataKey, err := kek.Open(nil, wrapped[:kek.NonceSize()], wrapped[kek.NonceSize():], []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap the data key: %w", err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:      r,
+		aead:   aead,
+		header: header,
+		buf:    make([]byte, encChunkSize+aead.Overhead()),
+		nonce:  make([]byte, aead.NonceSize()),
+	}, nil
+}
+
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	buf     []byte
+	nonce   []byte
+	counter uint64
+	plain   []byte
+	done    bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) next() error {
+	n, err := io.ReadFull(d.r, d.buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	last := err != nil
+	if !last {
+		if _, err := d.r.Peek(1); err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+			last = true
+		}
+	}
+
+	chunkNonce(d.nonce, d.counter, last)
+	plain, err := d.aead.Open(d.buf[:0], d.nonce, d.buf[:n], d.header)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt chunk %d: %w", d.counter, err)
+	}
+	d.plain = plain
+	d.counter++
+	d.done = last
+	return nil
+}
+
+func chunkNonce(nonce []byte, counter uint64, last bool) {
+	for i := range nonce {
+		nonce[i] = 0
+	}
+	binary.BigEndian.PutUint64(nonce[len(nonce)-9:], counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
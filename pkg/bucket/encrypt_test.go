@@ -0,0 +1,175 @@
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EncryptedBucket", func() {
+	ctx := context.Background()
+	var dataDir string
+	var plain Bucket
+
+	newKey := func() []byte {
+		key := make([]byte, encKeySize)
+		_, err := rand.Read(key)
+		Expect(err).NotTo(HaveOccurred())
+		return key
+	}
+
+	get := func(b Bucket, key string) ([]byte, error) {
+		r, err := b.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "")
+		Expect(err).NotTo(HaveOccurred())
+		dataDir = dir
+
+		plain, err = NewFileBucket(filepath.Join(dataDir, "bucket"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dataDir)
+	})
+
+	It("should encrypt and decrypt objects", func() {
+		keys := map[string][]byte{"key1": newKey()}
+		b, err := NewEncryptedBucket(plain, "key1", keys)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, size := range []int{0, 1, encChunkSize - 1, encChunkSize, encChunkSize + 1, 3*encChunkSize + 100} {
+			data := make([]byte, size)
+			_, err := rand.Read(data)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = b.Put(ctx, "foo", bytes.NewReader(data), int64(size))
+			Expect(err).NotTo(HaveOccurred())
+
+			raw, err := get(plain, "foo")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(raw).To(HavePrefix(encMagic + "\x04key1"))
+			// short plaintexts may appear in the ciphertext by chance.
+			if size > 16 {
+				Expect(bytes.Contains(raw, data)).To(BeFalse())
+			}
+
+			got, err := get(b, "foo")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(HaveLen(size))
+			Expect(bytes.Equal(got, data)).To(BeTrue(), "size %d", size)
+		}
+	})
+
+	It("should support key rotation", func() {
+		key1 := newKey()
+		key2 := newKey()
+		b1, err := NewEncryptedBucket(plain, "key1", map[string][]byte{"key1": key1})
+		Expect(err).NotTo(HaveOccurred())
+		err = b1.Put(ctx, "old", strings.NewReader("old data"), 8)
+		Expect(err).NotTo(HaveOccurred())
+
+		b2, err := NewEncryptedBucket(plain, "key2", map[string][]byte{"key1": key1, "key2": key2})
+		Expect(err).NotTo(HaveOccurred())
+		err = b2.Put(ctx, "new", strings.NewReader("new data"), 8)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := get(b2, "old")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("old data"))
+		data, err = get(b2, "new")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("new data"))
+
+		_, err = get(b1, "new")
+		Expect(err).To(MatchError(ErrUnknownKey))
+
+		// read-only bucket
+		ro, err := NewEncryptedBucket(plain, "", map[string][]byte{"key1": key1, "key2": key2})
+		Expect(err).NotTo(HaveOccurred())
+		data, err = get(ro, "new")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("new data"))
+		err = ro.Put(ctx, "foo", strings.NewReader("foo"), 3)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read unencrypted objects as is", func() {
+		b, err := NewEncryptedBucket(plain, "key1", map[string][]byte{"key1": newKey()})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, s := range []string{"", "abc", "plain text data"} {
+			err = plain.Put(ctx, "plain", strings.NewReader(s), int64(len(s)))
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := get(b, "plain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(s))
+		}
+	})
+
+	It("should detect tampering", func() {
+		b, err := NewEncryptedBucket(plain, "key1", map[string][]byte{"key1": newKey()})
+		Expect(err).NotTo(HaveOccurred())
+
+		data := make([]byte, 2*encChunkSize+10)
+		err = b.Put(ctx, "foo", bytes.NewReader(data), int64(len(data)))
+		Expect(err).NotTo(HaveOccurred())
+		raw, err := get(plain, "foo")
+		Expect(err).NotTo(HaveOccurred())
+
+		// flip a bit
+		modified := append([]byte(nil), raw...)
+		modified[len(modified)-20] ^= 1
+		err = plain.Put(ctx, "foo", bytes.NewReader(modified), int64(len(modified)))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = get(b, "foo")
+		Expect(err).To(HaveOccurred())
+
+		// truncate at a chunk boundary
+		truncated := raw[:len(raw)-(10+16)]
+		err = plain.Put(ctx, "foo", bytes.NewReader(truncated), int64(len(truncated)))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = get(b, "foo")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should validate keys", func() {
+		_, err := NewEncryptedBucket(plain, "key1", map[string][]byte{"key1": []byte("short")})
+		Expect(err).To(HaveOccurred())
+		_, err = NewEncryptedBucket(plain, "key2", map[string][]byte{"key1": newKey()})
+		Expect(err).To(MatchError(ErrUnknownKey))
+	})
+
+	It("should read keys from a directory", func() {
+		keyDir := filepath.Join(dataDir, "keys")
+		Expect(os.MkdirAll(filepath.Join(keyDir, "..data"), 0755)).To(Succeed())
+		key1 := newKey()
+		key2 := newKey()
+		Expect(os.WriteFile(filepath.Join(keyDir, "key1"), key1, 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(keyDir, "key2"), []byte(base64.StdEncoding.EncodeToString(key2)+"\n"), 0600)).To(Succeed())
+
+		keys, err := ReadKeyDir(keyDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(Equal(map[string][]byte{"key1": key1, "key2": key2}))
+
+		Expect(os.WriteFile(filepath.Join(keyDir, "bad"), []byte("bad"), 0600)).To(Succeed())
+		_, err = ReadKeyDir(keyDir)
+		Expect(err).To(HaveOccurred())
+	})
+})
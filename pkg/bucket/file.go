@@ -0,0 +1,172 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// tmpFilePrefix is the prefix of temporary files created in Put.
+const tmpFilePrefix = ".moco-tmp-"
+
+type fileBucket struct {
+	root string
+}
+
+// NewFileBucket creates a Bucket that manages objects as files under `root` directory.
+// `root` is typically a mount point of a PersistentVolume or NFS.
+func NewFileBucket(root string) (Bucket, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", root, err)
+	}
+
+	return fileBucket{root: root}, nil
+}
+
+func (b fileBucket) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || strings.HasSuffix(key, "/") {
+		return "", fmt.Errorf("invalid key: %q", key)
+	}
+	return filepath.Join(b.root, filepath.FromSlash(cleaned)), nil
+}
+
+func (b fileBucket) Put(ctx context.Context, key string, data io.Reader, objectSize int64) error {
+	p, err := b.path(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(p)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	// write data to a temporary file in the same directory, then rename it
+	// so that readers never see a partially written object.
+	f, err := os.CreateTemp(dir, tmpFilePrefix+filepath.Base(p)+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary file: %w", err)
+	}
+	defer func() {
+		if f != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	if _, err := io.Copy(f, &ctxReader{ctx: ctx, r: data}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", key, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", key, err)
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return fmt.Errorf("failed to rename to %s: %w", p, err)
+	}
+	f = nil
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func (b fileBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := b.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+func (b fileBucket) List(ctx context.Context, prefix string) ([]string, error) {
+	// start walking from the deepest directory that contains the prefix.
+	start := b.root
+	if dir := path.Dir(prefix); prefix != "" && dir != "." {
+		p, err := b.path(dir)
+		if err != nil {
+			return nil, err
+		}
+		start = p
+	}
+
+	var keys []string
+	fn := func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tmpFilePrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(b.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	}
+	if err := filepath.WalkDir(start, fn); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (b fileBucket) Delete(ctx context.Context, key string) error {
+	p, err := b.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// remove empty parent directories up to the root.
+	for dir := filepath.Dir(p); dir != b.root; dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// ctxReader is an io.Reader that stops reading when the context is canceled.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
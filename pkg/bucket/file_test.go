@@ -0,0 +1,116 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileBucket", func() {
+	ctx := context.Background()
+	var dataDir string
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "")
+		Expect(err).NotTo(HaveOccurred())
+		dataDir = dir
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dataDir)
+	})
+
+	It("should put and get objects", func() {
+		b, err := NewFileBucket(filepath.Join(dataDir, "test"))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Put(ctx, "foo/bar", strings.NewReader("01234567890123456789"), 128<<20)
+		Expect(err).NotTo(HaveOccurred())
+
+		r, err := b.Get(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(data).To(Equal([]byte("01234567890123456789")))
+
+		err = b.Put(ctx, "foo/bar", strings.NewReader("abc"), 128<<20)
+		Expect(err).NotTo(HaveOccurred())
+		data, err = os.ReadFile(filepath.Join(dataDir, "test", "foo", "bar"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal([]byte("abc")))
+
+		for i := 0; i < 100; i++ {
+			err = b.Put(ctx, fmt.Sprintf("foo/baz/%d", i), strings.NewReader("01234567890123456789"), 128<<20)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		err = b.Put(ctx, "qux", strings.NewReader("01234567890123456789"), 128<<20)
+		Expect(err).NotTo(HaveOccurred())
+
+		// temporary files should be ignored
+		err = os.WriteFile(filepath.Join(dataDir, "test", "foo", tmpFilePrefix+"bar-123"), nil, 0644)
+		Expect(err).NotTo(HaveOccurred())
+
+		keys, err := b.List(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(102))
+
+		keys, err = b.List(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(Equal([]string{"foo/bar"}))
+
+		keys, err = b.List(ctx, "foo/baz/")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(100))
+
+		keys, err = b.List(ctx, "nonexistent/")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(BeEmpty())
+
+		_, err = b.Get(ctx, "foo/nonexistent")
+		Expect(err).To(HaveOccurred())
+
+		err = b.Delete(ctx, "qux")
+		Expect(err).NotTo(HaveOccurred())
+		keys, err = b.List(ctx, "qux")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(BeEmpty())
+
+		err = b.Delete(ctx, "qux")
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 100; i++ {
+			err = b.Delete(ctx, fmt.Sprintf("foo/baz/%d", i))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		_, err = os.Stat(filepath.Join(dataDir, "test", "foo", "baz"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		_, err = os.Stat(filepath.Join(dataDir, "test"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not access files outside the root directory", func() {
+		b, err := NewFileBucket(filepath.Join(dataDir, "test"))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Put(ctx, "../../escaped", strings.NewReader("data"), 4)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = os.Stat(filepath.Join(dataDir, "escaped"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		_, err = os.Stat(filepath.Join(dataDir, "test", "escaped"))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Put(ctx, "", strings.NewReader("data"), 4)
+		Expect(err).To(HaveOccurred())
+		err = b.Put(ctx, "foo/", strings.NewReader("data"), 4)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,91 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// GCSChunkSize is the size of a chunk in a resumable upload to Google Cloud Storage.
+// The Writer buffers a chunk in memory, so this should not be too large.
+const GCSChunkSize = 32 << 20
+
+type gcsBucket struct {
+	name   string
+	client *storage.Client
+}
+
+// NewGCSBucket creates a Bucket that manages objects in Google Cloud Storage.
+//
+// Without options, the client uses Application Default Credentials.
+// On GKE, this means the credentials are given through Workload Identity.
+// If `STORAGE_EMULATOR_HOST` environment variable is set, the client connects to the emulator.
+func NewGCSBucket(name string, opts ...option.ClientOption) (Bucket, error) {
+	client, err := storage.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+
+	return gcsBucket{
+		name:   name,
+		client: client,
+	}, nil
+}
+
+func (b gcsBucket) Put(ctx context.Context, key string, data io.Reader, objectSize int64) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Chunks of a resumable upload can be safely retried.
+	obj := b.client.Bucket(b.name).Object(key).Retryer(storage.WithPolicy(storage.RetryAlways))
+	w := obj.NewWriter(ctx)
+	w.ChunkSize = GCSChunkSize
+	w.ContentType = contentType(key)
+
+	if _, err := io.Copy(w, data); err != nil {
+		// cancel the context before closing the writer to abort the upload.
+		cancel()
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (b gcsBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return b.client.Bucket(b.name).Object(key).NewReader(ctx)
+}
+
+func (b gcsBucket) List(ctx context.Context, prefix string) ([]string, error) {
+	q := &storage.Query{Prefix: prefix}
+	if err := q.SetAttrSelection([]string{"Name"}); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	it := b.client.Bucket(b.name).Objects(ctx, q)
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, attrs.Name)
+	}
+
+	return keys, nil
+}
+
+func (b gcsBucket) Delete(ctx context.Context, key string) error {
+	err := b.client.Bucket(b.name).Object(key).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
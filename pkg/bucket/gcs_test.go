@@ -0,0 +1,125 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/option"
+)
+
+var _ = Describe("GCSBucket", func() {
+	ctx := context.Background()
+	var dataDir string
+	opts := []option.ClientOption{
+		option.WithEndpoint("http://localhost:4443/storage/v1/"),
+		option.WithoutAuthentication(),
+	}
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "")
+		Expect(err).NotTo(HaveOccurred())
+		dataDir = dir
+		err = exec.Command("docker", "run", "--rm", "--name=moco-fake-gcs", "-d", "-p", "4443:4443",
+			"-v", fmt.Sprintf("%s:/storage", dir),
+			"fsouza/fake-gcs-server", "-scheme", "http", "-port", "4443",
+			"-external-url", "http://localhost:4443").Run()
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", "localhost:4443")
+			if err != nil {
+				return err
+			}
+			conn.Close()
+			return nil
+		}, 60).Should(Succeed())
+
+		client, err := storage.NewClient(ctx, opts...)
+		Expect(err).NotTo(HaveOccurred())
+		defer client.Close()
+		err = client.Bucket("test").Create(ctx, "test-project", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		exec.Command("docker", "kill", "moco-fake-gcs").Run()
+		time.Sleep(1 * time.Second)
+		os.RemoveAll(dataDir)
+	})
+
+	It("should put and get objects", func() {
+		b, err := NewGCSBucket("test", opts...)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = b.Put(ctx, "foo/bar", strings.NewReader("01234567890123456789"), 128<<20)
+		Expect(err).NotTo(HaveOccurred())
+
+		r, err := b.Get(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(data).To(Equal([]byte("01234567890123456789")))
+
+		for i := 0; i < 1100; i++ {
+			err = b.Put(ctx, fmt.Sprintf("foo/baz%d", i), strings.NewReader("01234567890123456789"), 128<<20)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		keys, err := b.List(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1101))
+
+		keys, err = b.List(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+
+		err = b.Delete(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		keys, err = b.List(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(BeEmpty())
+		_, err = b.Get(ctx, "foo/bar")
+		Expect(err).To(HaveOccurred())
+
+		err = b.Delete(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should put objects larger than a chunk", func() {
+		b, err := NewGCSBucket("test", opts...)
+		Expect(err).NotTo(HaveOccurred())
+
+		size := int64(GCSChunkSize*2 + 100)
+		err = b.Put(ctx, "large", io.LimitReader(zeroReader{}, size), size)
+		Expect(err).NotTo(HaveOccurred())
+
+		r, err := b.Get(ctx, "large")
+		Expect(err).NotTo(HaveOccurred())
+		defer r.Close()
+
+		n, err := io.Copy(io.Discard, r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(size))
+	})
+})
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
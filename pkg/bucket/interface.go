@@ -3,6 +3,7 @@ package bucket
 import (
 	"context"
 	"io"
+	"strings"
 )
 
 // Bucket represents the interface to access an object storage bucket.
@@ -15,4 +16,19 @@ type Bucket interface {
 
 	// List lists the matching object keys that have `prefix`.
 	List(ctx context.Context, prefix string) ([]string, error)
+
+	// Delete deletes an object by `key`.
+	// It returns nil if the object does not exist.
+	Delete(ctx context.Context, key string) error
+}
+
+// contentType returns the media type of an object from the suffix of `key`.
+func contentType(key string) string {
+	switch {
+	case strings.HasSuffix(key, ".tar"):
+		return "application/x-tar"
+	case strings.HasSuffix(key, ".zst"):
+		return "application/zstd"
+	}
+	return "application/octet-stream"
 }
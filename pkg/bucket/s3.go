@@ -4,12 +4,14 @@ import (
 	"context"
 	"io"
 	"net/http"
-	"strings"
+	"net/url"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 const (
@@ -55,13 +57,91 @@ func WithHTTPClient(c *http.Client) func(*s3.Options) {
 	}
 }
 
+// S3UploadOptions is a set of parameters for multi-part uploads.
+type S3UploadOptions struct {
+	// Concurrency is the number of parts uploaded in parallel.
+	// Each part is buffered in memory, so the memory usage is about Concurrency * PartSize.
+	// If zero, parts are uploaded one by one.
+	Concurrency int
+
+	// PartSize is the size of each part in bytes.
+	// It is increased if needed so that the object fits in UploadParts parts.
+	// If zero, the size is decided by the object size in units of PartSizeUnit.
+	PartSize int64
+
+	// ServerSideEncryption is the server-side encryption algorithm, "AES256" or "aws:kms".
+	// If empty, the default encryption of the bucket applies.
+	ServerSideEncryption string
+
+	// KMSKeyID is the ID of the AWS KMS key for "aws:kms" encryption.
+	KMSKeyID string
+
+	// StorageClass is the storage class of uploaded objects.
+	StorageClass string
+
+	// Tags are the tags set to uploaded objects.
+	Tags map[string]string
+
+	// ObjectLockMode is the Object Lock retention mode, "GOVERNANCE" or "COMPLIANCE".
+	// If empty, the retention is not set.
+	ObjectLockMode string
+
+	// ObjectLockRetention is the period to retain uploaded objects.
+	ObjectLockRetention time.Duration
+}
+
+// apply sets the object parameters to pi.
+func (o S3UploadOptions) apply(pi *s3.PutObjectInput, now time.Time) {
+	if o.ServerSideEncryption != "" {
+		pi.ServerSideEncryption = types.ServerSideEncryption(o.ServerSideEncryption)
+	}
+	if o.KMSKeyID != "" {
+		pi.SSEKMSKeyId = aws.String(o.KMSKeyID)
+	}
+	if o.StorageClass != "" {
+		pi.StorageClass = types.StorageClass(o.StorageClass)
+	}
+	if len(o.Tags) > 0 {
+		v := url.Values{}
+		for k, val := range o.Tags {
+			v.Set(k, val)
+		}
+		pi.Tagging = aws.String(v.Encode())
+	}
+	if o.ObjectLockMode != "" {
+		pi.ObjectLockMode = types.ObjectLockMode(o.ObjectLockMode)
+		pi.ObjectLockRetainUntilDate = aws.Time(now.Add(o.ObjectLockRetention))
+		// S3 requires a checksum of objects uploaded with a retention period.
+		pi.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+	}
+}
+
+func (o S3UploadOptions) partSize(objectSize int64) int64 {
+	if o.PartSize == 0 {
+		return decidePartSize(objectSize)
+	}
+
+	partSize := (objectSize + UploadParts - 1) / UploadParts
+	if partSize < o.PartSize {
+		partSize = o.PartSize
+	}
+	return partSize
+}
+
 type s3Bucket struct {
 	name   string
 	client *s3.Client
+	upload S3UploadOptions
 }
 
 // NewS3Bucket creates a Bucket that manage object in S3.
 func NewS3Bucket(name string, optFns ...func(*s3.Options)) (Bucket, error) {
+	return NewS3BucketWithUploadOptions(name, S3UploadOptions{}, optFns...)
+}
+
+// NewS3BucketWithUploadOptions creates a Bucket that manage object in S3
+// with the given parameters for multi-part uploads.
+func NewS3BucketWithUploadOptions(name string, upload S3UploadOptions, optFns ...func(*s3.Options)) (Bucket, error) {
 	cfg, err := config.LoadDefaultConfig(context.Background())
 	if err != nil {
 		return nil, err
@@ -70,22 +150,19 @@ func NewS3Bucket(name string, optFns ...func(*s3.Options)) (Bucket, error) {
 	return s3Bucket{
 		name:   name,
 		client: s3.NewFromConfig(cfg, optFns...),
+		upload: upload,
 	}, nil
 }
 
 func (b s3Bucket) Put(ctx context.Context, key string, data io.Reader, objectSize int64) error {
-	mt := "application/octet-stream"
-	switch {
-	case strings.HasSuffix(key, ".tar"):
-		mt = "application/x-tar"
-	case strings.HasSuffix(key, ".zst"):
-		mt = "application/zstd"
-	}
-
+	mt := contentType(key)
 	uploader := manager.NewUploader(b.client, func(u *manager.Uploader) {
 		u.Concurrency = 1
+		if b.upload.Concurrency > 1 {
+			u.Concurrency = b.upload.Concurrency
+		}
 		u.LeavePartsOnError = false
-		u.PartSize = decidePartSize(objectSize)
+		u.PartSize = b.upload.partSize(objectSize)
 	})
 	pi := &s3.PutObjectInput{
 		Bucket:      &b.name,
@@ -93,6 +170,7 @@ func (b s3Bucket) Put(ctx context.Context, key string, data io.Reader, objectSiz
 		Body:        data,
 		ContentType: &mt,
 	}
+	b.upload.apply(pi, time.Now())
 	_, err := uploader.Upload(ctx, pi)
 	return err
 }
@@ -134,6 +212,15 @@ func (b s3Bucket) List(ctx context.Context, prefix string) ([]string, error) {
 	return keys, nil
 }
 
+func (b s3Bucket) Delete(ctx context.Context, key string) error {
+	di := &s3.DeleteObjectInput{
+		Bucket: &b.name,
+		Key:    &key,
+	}
+	_, err := b.client.DeleteObject(ctx, di)
+	return err
+}
+
 func decidePartSize(objectSize int64) int64 {
 	var partSize int64
 	partSize = (objectSize + UploadParts - 1) / UploadParts                  // Round up the result of dividing objectSize by uploadPart.
@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -97,6 +98,17 @@ var _ = Describe("S3Bucket", func() {
 		keys, err = b.List(ctx, "foo/bar")
 		Expect(err).NotTo(HaveOccurred())
 		Expect(keys).To(HaveLen(1))
+
+		err = b.Delete(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		keys, err = b.List(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(BeEmpty())
+		_, err = b.Get(ctx, "foo/bar")
+		Expect(err).To(HaveOccurred())
+
+		err = b.Delete(ctx, "foo/bar")
+		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should put unseekable objects", func() {
@@ -165,5 +177,42 @@ var _ = Describe("S3Bucket", func() {
 
 		partSize = decidePartSize(PartSizeUnit*2*UploadParts + 1)
 		Expect(partSize).Should(BeNumerically("==", PartSizeUnit*3))
+
+		opts := S3UploadOptions{PartSize: 16 << 20}
+		partSize = opts.partSize(1)
+		Expect(partSize).Should(BeNumerically("==", 16<<20))
+
+		partSize = opts.partSize(16 << 20 * UploadParts)
+		Expect(partSize).Should(BeNumerically("==", 16<<20))
+
+		partSize = opts.partSize(16<<20*UploadParts + 1)
+		Expect(partSize).Should(BeNumerically("==", 16<<20+1))
+	})
+})
+
+var _ = Describe("S3UploadOptions", func() {
+	It("should set the object parameters", func() {
+		now := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+
+		pi := &s3.PutObjectInput{}
+		S3UploadOptions{}.apply(pi, now)
+		Expect(pi).To(Equal(&s3.PutObjectInput{}))
+
+		opts := S3UploadOptions{
+			ServerSideEncryption: "aws:kms",
+			KMSKeyID:             "key",
+			StorageClass:         "STANDARD_IA",
+			Tags:                 map[string]string{"team": "db", "env": "prod&test"},
+			ObjectLockMode:       "COMPLIANCE",
+			ObjectLockRetention:  24 * time.Hour,
+		}
+		opts.apply(pi, now)
+		Expect(pi.ServerSideEncryption).To(Equal(types.ServerSideEncryptionAwsKms))
+		Expect(pi.SSEKMSKeyId).To(Equal(aws.String("key")))
+		Expect(pi.StorageClass).To(Equal(types.StorageClassStandardIa))
+		Expect(pi.Tagging).To(Equal(aws.String("env=prod%26test&team=db")))
+		Expect(pi.ObjectLockMode).To(Equal(types.ObjectLockModeCompliance))
+		Expect(pi.ObjectLockRetainUntilDate).To(Equal(aws.Time(now.Add(24 * time.Hour))))
+		Expect(pi.ChecksumAlgorithm).To(Equal(types.ChecksumAlgorithmSha256))
 	})
 })
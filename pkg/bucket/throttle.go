@@ -0,0 +1,109 @@
+package bucket
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// throttleChunkSize is the maximum size of a read between waits.
+const throttleChunkSize = 64 << 10
+
+type throttledBucket struct {
+	Bucket
+	limiter *rateLimiter
+}
+
+// NewThrottledBucket returns a Bucket that limits the total transfer rate of
+// Put and Get of `b` to `bytesPerSecond`.
+func NewThrottledBucket(b Bucket, bytesPerSecond int64) Bucket {
+	return throttledBucket{
+		Bucket:  b,
+		limiter: &rateLimiter{rate: bytesPerSecond},
+	}
+}
+
+func (b throttledBucket) Put(ctx context.Context, key string, data io.Reader, objectSize int64) error {
+	return b.Bucket.Put(ctx, key, &throttledReader{ctx: ctx, r: data, limiter: b.limiter}, objectSize)
+}
+
+func (b throttledBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := b.Bucket.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &throttledReadCloser{
+		throttledReader: throttledReader{ctx: ctx, r: rc, limiter: b.limiter},
+		closer:          rc,
+	}, nil
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rateLimiter
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleChunkSize {
+		p = p[:throttleChunkSize]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.limiter.wait(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+type throttledReadCloser struct {
+	throttledReader
+	closer io.Closer
+}
+
+func (r *throttledReadCloser) Close() error {
+	return r.closer.Close()
+}
+
+// rateLimiter delays callers so that the average rate does not exceed `rate` bytes per second.
+// Up to one second of unused allowance can be consumed at once after an idle period.
+type rateLimiter struct {
+	rate int64
+
+	mu    sync.Mutex
+	start time.Time
+	total int64
+}
+
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.start.IsZero() || now.Sub(l.due()) > time.Second {
+		l.start = now.Add(-time.Second)
+		l.total = 0
+	}
+	l.total += int64(n)
+	due := l.due()
+	l.mu.Unlock()
+
+	d := time.Until(due)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// due returns the time when `total` bytes are allowed to be transferred.
+// The lock must be held.
+func (l *rateLimiter) due() time.Time {
+	return l.start.Add(time.Duration(float64(l.total) / float64(l.rate) * float64(time.Second)))
+}
@@ -0,0 +1,64 @@
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ThrottledBucket", func() {
+	ctx := context.Background()
+	var dataDir string
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "")
+		Expect(err).NotTo(HaveOccurred())
+		dataDir = dir
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dataDir)
+	})
+
+	It("should limit the transfer rate", func() {
+		fb, err := NewFileBucket(filepath.Join(dataDir, "test"))
+		Expect(err).NotTo(HaveOccurred())
+		b := NewThrottledBucket(fb, 1<<20)
+
+		// the first second of the transfer is not throttled.
+		data := bytes.Repeat([]byte("0123456789abcdef"), (3<<20)/16)
+		start := time.Now()
+		err = b.Put(ctx, "foo", bytes.NewReader(data), int64(len(data)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically(">=", 1900*time.Millisecond))
+
+		time.Sleep(2 * time.Second)
+		start = time.Now()
+		r, err := b.Get(ctx, "foo")
+		Expect(err).NotTo(HaveOccurred())
+		defer r.Close()
+		got, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(Equal(data))
+		Expect(time.Since(start)).To(BeNumerically(">=", 1900*time.Millisecond))
+		Expect(time.Since(start)).To(BeNumerically("<", 3*time.Second))
+	})
+
+	It("should stop waiting when the context is canceled", func() {
+		fb, err := NewFileBucket(filepath.Join(dataDir, "test"))
+		Expect(err).NotTo(HaveOccurred())
+		b := NewThrottledBucket(fb, 1<<10)
+
+		ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		data := make([]byte, 1<<20)
+		err = b.Put(ctx, "foo", bytes.NewReader(data), int64(len(data)))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,115 @@
+package bucket
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// writeSelfSignedCert creates a self-signed client certificate and writes it and its key in PEM.
+func writeSelfSignedCert(certFile, keyFile string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "moco-backup"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	Expect(err).NotTo(HaveOccurred())
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	Expect(err).NotTo(HaveOccurred())
+	return cert
+}
+
+var _ = Describe("NewTLSHTTPClient", func() {
+	var dir string
+
+	BeforeEach(func() {
+		d, err := os.MkdirTemp("", "")
+		Expect(err).NotTo(HaveOccurred())
+		dir = d
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should trust the CA bundle and present the client certificate", func() {
+		certFile := filepath.Join(dir, "tls.crt")
+		keyFile := filepath.Join(dir, "tls.key")
+		clientCert := writeSelfSignedCert(certFile, keyFile)
+
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(clientCert)
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		srv.TLS = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+		}
+		srv.StartTLS()
+		defer srv.Close()
+
+		caFile := filepath.Join(dir, "ca.crt")
+		err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0644)
+		Expect(err).NotTo(HaveOccurred())
+
+		c, err := NewTLSHTTPClient(caFile, certFile, keyFile)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := c.Get(srv.URL)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		// without the client certificate
+		c, err = NewTLSHTTPClient(caFile, "", "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.Get(srv.URL)
+		Expect(err).To(HaveOccurred())
+
+		// without the CA bundle
+		c, err = NewTLSHTTPClient("", certFile, keyFile)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.Get(srv.URL)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject invalid parameters", func() {
+		_, err := NewTLSHTTPClient("", filepath.Join(dir, "tls.crt"), "")
+		Expect(err).To(HaveOccurred())
+
+		_, err = NewTLSHTTPClient(filepath.Join(dir, "none"), "", "")
+		Expect(err).To(HaveOccurred())
+
+		caFile := filepath.Join(dir, "ca.crt")
+		err = os.WriteFile(caFile, []byte("not a certificate"), 0644)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = NewTLSHTTPClient(caFile, "", "")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -2,10 +2,53 @@ package constants
 
 // moco-backup related constants
 const (
-	BackupSubcommand  = "backup"
-	RestoreSubcommand = "restore"
+	BackupSubcommand        = "backup"
+	RestoreSubcommand       = "restore"
+	ArchiveBinlogSubcommand = "archive-binlog"
+	DrillSubcommand         = "drill"
 
 	BackupTimeFormat = "20060102-150405"
 	DumpFilename     = "dump.tar"
 	BinlogFilename   = "binlog.tar.zst"
+	ManifestFilename = "manifest.json"
+
+	// IncrementalBinlogPrefix and IncrementalBinlogSuffix enclose the time of a
+	// binlog-only backup in the name of its archive.  The archive is stored in
+	// the directory of the last backup, and is followed by BinlogFilename.
+	IncrementalBinlogPrefix = "binlog-"
+	IncrementalBinlogSuffix = ".tar.zst"
+
+	// DumpDirname is the directory under a backup directory to store the files of
+	// a streamed full dump.  The dump is complete when DumpDoneFilename exists.
+	DumpDirname      = "dump"
+	DumpDoneFilename = "@.done.json"
+
+	// BinlogArchiveDir is the directory under the prefix of a cluster to store
+	// binlog segments uploaded by the binlog archiver.
+	BinlogArchiveDir = "binlog"
+	SegmentFilename  = "segment.tar.zst"
+)
+
+// storage backend types for moco-backup
+const (
+	BackendTypeS3    = "s3"
+	BackendTypeGCS   = "gcs"
+	BackendTypeAzure = "azure"
+	BackendTypeFile  = "file"
+
+	// BucketVolumeMountPath is the directory where the volume for "file" backend is mounted.
+	BucketVolumeMountPath = "/bucket"
+
+	// EncryptionKeyMountPath is the directory where the Secret of encryption keys is mounted.
+	EncryptionKeyMountPath = "/encryption-keys"
+
+	// BucketCAMountPath is the directory where the CA bundle to access the bucket is mounted.
+	BucketCAMountPath = "/bucket-ca"
+
+	// BucketCAFilename is the file name of the CA bundle in BucketCAMountPath.
+	BucketCAFilename = "ca.crt"
+
+	// BucketClientCertMountPath is the directory where the Secret of the client certificate
+	// to access the bucket is mounted.
+	BucketClientCertMountPath = "/bucket-client-cert"
 )
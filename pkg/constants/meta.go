@@ -7,6 +7,8 @@ const (
 	LabelAppName      = "app.kubernetes.io/name"
 	AppNameMySQL      = "mysql"
 	AppNameBackup     = "mysql-backup"
+	AppNameArchiver   = "mysql-binlog-archiver"
+	AppNameSnapshot   = "mysql-snapshot"
 	LabelAppCreatedBy = "app.kubernetes.io/created-by"
 	AppCreator        = "moco"
 
@@ -19,6 +21,15 @@ const (
 const (
 	AnnDemote        = "moco.cybozu.com/demote"
 	AnnSecretVersion = "moco.cybozu.com/secret-version"
+
+	// AnnBackupTime and AnnGTIDSet record the time and the GTID set of
+	// a snapshot backup in its VolumeSnapshot.
+	AnnBackupTime = "moco.cybozu.com/backup-time"
+	AnnGTIDSet    = "moco.cybozu.com/gtid-set"
+
+	// AnnZone records the zone of the Node where a MySQL Pod is running.
+	// Backup Jobs read it because they cannot get cluster-scoped Nodes.
+	AnnZone = "moco.cybozu.com/zone"
 )
 
 // MySQLClusterFinalizer is the finalizer specifier for MySQLCluster.
@@ -97,9 +97,29 @@ var (
 		Reason:  "BackupNoBinlog",
 		Message: "Backup created w/o binlog files",
 	}
+	BinlogBackupCreated = MOCOEvent{
+		Type:    corev1.EventTypeNormal,
+		Reason:  "BinlogBackupCreated",
+		Message: "Binlog-only backup created",
+	}
 	Restored = MOCOEvent{
 		Type:    corev1.EventTypeNormal,
 		Reason:  "Restored",
 		Message: "Successfully restored data from backup",
 	}
+	RestoreFailed = MOCOEvent{
+		Type:    corev1.EventTypeWarning,
+		Reason:  "RestoreFailed",
+		Message: "Failed to restore data from backup: %v",
+	}
+	RestoreDrillSucceeded = MOCOEvent{
+		Type:    corev1.EventTypeNormal,
+		Reason:  "RestoreDrillSucceeded",
+		Message: "Restore drill succeeded in %s",
+	}
+	RestoreDrillFailed = MOCOEvent{
+		Type:    corev1.EventTypeWarning,
+		Reason:  "RestoreDrillFailed",
+		Message: "Restore drill failed: %v",
+	}
 )
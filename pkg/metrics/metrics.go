@@ -8,6 +8,7 @@ const (
 	metricsNamespace    = "moco"
 	clusteringSubsystem = "cluster"
 	backupSubsystem     = "backup"
+	drillSubsystem      = "restore_drill"
 )
 
 // Clustering related metrics
@@ -36,6 +37,18 @@ var (
 	BackupBinlogSize   *prometheus.GaugeVec
 	BackupWorkDirUsage *prometheus.GaugeVec
 	BackupWarnings     *prometheus.GaugeVec
+
+	BackupEarliestRestorable *prometheus.GaugeVec
+	BackupLatestRestorable   *prometheus.GaugeVec
+	BackupRestorableGaps     *prometheus.GaugeVec
+)
+
+// Restore drill related metrics
+var (
+	RestoreDrillTimestamp   *prometheus.GaugeVec
+	RestoreDrillSuccess     *prometheus.GaugeVec
+	RestoreDrillElapsed     *prometheus.GaugeVec
+	RestoreDrillLastSuccess *prometheus.GaugeVec
 )
 
 // Register registers Prometheus metrics vectors to the registry.
@@ -160,6 +173,62 @@ func Register(registry prometheus.Registerer) {
 	}, []string{"name", "namespace"})
 	registry.MustRegister(BackupWarnings)
 
+	BackupEarliestRestorable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: backupSubsystem,
+		Name:      "earliest_restorable_timestamp",
+		Help:      "The earliest point-in-time to which data can be restored",
+	}, []string{"name", "namespace"})
+	registry.MustRegister(BackupEarliestRestorable)
+
+	BackupLatestRestorable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: backupSubsystem,
+		Name:      "latest_restorable_timestamp",
+		Help:      "The latest point-in-time to which data can be restored",
+	}, []string{"name", "namespace"})
+	registry.MustRegister(BackupLatestRestorable)
+
+	BackupRestorableGaps = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: backupSubsystem,
+		Name:      "restorable_gaps",
+		Help:      "The number of periods to which data cannot be restored because binlogs are missing",
+	}, []string{"name", "namespace"})
+	registry.MustRegister(BackupRestorableGaps)
+
+	RestoreDrillTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: drillSubsystem,
+		Name:      "timestamp",
+		Help:      "The timestamp of the last restore drill",
+	}, []string{"name", "namespace"})
+	registry.MustRegister(RestoreDrillTimestamp)
+
+	RestoreDrillSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: drillSubsystem,
+		Name:      "success",
+		Help:      "1 if the last restore drill succeeded, 0 otherwise",
+	}, []string{"name", "namespace"})
+	registry.MustRegister(RestoreDrillSuccess)
+
+	RestoreDrillElapsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: drillSubsystem,
+		Name:      "elapsed_seconds",
+		Help:      "The time taken for the last restore drill",
+	}, []string{"name", "namespace"})
+	registry.MustRegister(RestoreDrillElapsed)
+
+	RestoreDrillLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: drillSubsystem,
+		Name:      "last_success_timestamp",
+		Help:      "The timestamp of the last successful restore drill",
+	}, []string{"name", "namespace"})
+	registry.MustRegister(RestoreDrillLastSuccess)
+
 	VolumeResizedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: metricsNamespace,
 		Subsystem: clusteringSubsystem,